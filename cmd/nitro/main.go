@@ -1,14 +1,13 @@
 package main
 
 import (
-	"os"
-
 	"github.com/craftcms/nitro/command/nitro"
+	"github.com/craftcms/nitro/pkg/terminal"
 )
 
 func main() {
-	// execute the nitro root command
+	// execute the nitro root command and exit with the code for the error
 	if err := nitro.NewCommand().Execute(); err != nil {
-		os.Exit(1)
+		terminal.Exit(err)
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"log"
 	"net"
+	"net/http"
 	"os"
 
 	"google.golang.org/grpc"
@@ -19,6 +20,8 @@ func main() {
 	// assign the port as a flag with a default
 	port := flag.String("port", "5000", "which port API should listen on")
 	addr := flag.String("addr", "http://127.0.0.1:2019", "the address for the Caddy API")
+	askPort := flag.String("ask-port", "5001", "which port the on demand TLS ask endpoint should listen on")
+	tmpDir := flag.String("tmp-dir", os.Getenv("NITRO_TMP_DIR"), "the directory used to stage database imports")
 	flag.Parse()
 
 	// create the network listener
@@ -30,7 +33,23 @@ func main() {
 	// create the grpc server
 	s := grpc.NewServer()
 
-	protob.RegisterNitroServer(s, api.NewService(*addr))
+	svc := api.NewService(*addr)
+	svc.AskAddr = "http://127.0.0.1:" + *askPort + "/ask"
+	svc.TempDir = *tmpDir
+
+	protob.RegisterNitroServer(s, svc)
+
+	// serve the ask endpoint caddy uses for on demand tls, only caddy needs access
+	go func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/ask", svc.Ask)
+
+		log.Println("ask endpoint listening on port", *askPort)
+
+		if err := http.ListenAndServe("127.0.0.1:"+*askPort, mux); err != nil {
+			log.Println("error when running the ask endpoint", err)
+		}
+	}()
 
 	log.Println("gRPC API listening on port", *port)
 
@@ -120,7 +120,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				output.Info("Adding alias:")
 			}
 			for _, a := range parts {
-				output.Info("  ", a)
+				output.Info("  ", validate.Unicode(a))
 
 				// set the alias
 				if err := cfg.SetSiteAlias(site.Hostname, a); err != nil {
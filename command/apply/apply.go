@@ -1,27 +1,38 @@
 package apply
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
+	"github.com/rodaine/table"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/craftcms/nitro/command/apply/internal/customcontainer"
 	"github.com/craftcms/nitro/command/apply/internal/databasecontainer"
 	"github.com/craftcms/nitro/command/apply/internal/sitecontainer"
 	"github.com/craftcms/nitro/pkg/backup"
+	"github.com/craftcms/nitro/pkg/browser"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/dockerhost"
+	"github.com/craftcms/nitro/pkg/pathexists"
 	"github.com/craftcms/nitro/pkg/wsl"
 
 	"github.com/craftcms/nitro/pkg/datetime"
@@ -33,6 +44,7 @@ import (
 	"github.com/craftcms/nitro/pkg/svc/minio"
 	"github.com/craftcms/nitro/pkg/svc/redis"
 	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/pkg/validate"
 	"github.com/craftcms/nitro/protob"
 )
 
@@ -48,6 +60,12 @@ const exampleText = `  # apply changes from a config
   # skip editing the hosts file
   nitro apply --skip-hosts
 
+  # wait up to 30 seconds for the proxy to apply changes
+  nitro apply --timeout 30s
+
+  # open the site in your browser after applying changes
+  nitro apply --open
+
   # you can also set the environment variable "NITRO_EDIT_HOSTS" to "false"`
 
 // NewCommand returns the command used to apply configuration file changes to a nitro environment.
@@ -58,8 +76,8 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 		Example: exampleText,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			// is the docker api alive?
-			if _, err := docker.Ping(cmd.Context()); err != nil {
-				return fmt.Errorf("Couldn’t connect to Docker; please make sure Docker is running.")
+			if err := dockerhost.CheckRunning(cmd.Context(), docker, runtime.GOOS); err != nil {
+				return err
 			}
 
 			return nil
@@ -78,6 +96,17 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 				return err
 			}
 
+			for _, p := range cfg.Projects {
+				output.Info("Merged the sites from the project config", p)
+			}
+
+			// keep the project config for the working directory in the config
+			if cfg.ProjectAdded() {
+				if err := cfg.Save(); err != nil {
+					return err
+				}
+			}
+
 			// store all of the known container names
 			names := map[string]bool{}
 
@@ -131,6 +160,17 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 				output.Info("Cleaning up…")
 			}
 
+			// site containers for sites removed from the config are only removed after confirmation
+			orphans := orphanedSites(containers, cfg)
+			if err := removeOrphanedSites(ctx, docker, output, orphans); err != nil {
+				return err
+			}
+
+			handled := map[string]bool{}
+			for _, c := range orphans {
+				handled[c.ID] = true
+			}
+
 			for _, c := range containers {
 				// start the container if not running
 				if c.State != "running" {
@@ -148,6 +188,11 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 					continue
 				}
 
+				// skip the orphaned site containers, they were already handled
+				if handled[c.ID] {
+					continue
+				}
+
 				// set the container name
 				name := strings.TrimLeft(c.Names[0], "/")
 
@@ -238,6 +283,10 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 				return err
 			}
 
+			if err := (&validate.RestartPolicy{}).Validate(cfg.RestartPolicy); err != nil {
+				return fmt.Errorf("invalid restart_policy in the config, %w", err)
+			}
+
 			// create a filter for the environment
 			filter := filters.NewArgs()
 			filter.Add("label", containerlabels.Nitro+"=true")
@@ -275,10 +324,10 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 			output.Info("Checking proxy…")
 
 			// check the proxy and ensure its started
-			_, err = proxycontainer.FindAndStart(ctx, docker)
+			proxy, err := proxycontainer.FindAndStart(ctx, docker)
 			if errors.Is(err, proxycontainer.ErrNoProxyContainer) {
 				// create the proxy
-				if err := proxycontainer.Create(ctx, docker, output, network.ID); err != nil {
+				if err := proxycontainer.Create(ctx, docker, output, network.ID, cfg.RestartPolicy, cfg.HTTP3, cfg.ProxyPorts); err != nil {
 					output.Info("unable to find the nitro proxy…\n run `nitro init` to resolve")
 					return err
 				}
@@ -287,6 +336,22 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 				return err
 			}
 
+			// check the ports were not changed after the proxy was created
+			var recreated bool
+			if err == nil {
+				recreated, err = checkProxyPorts(ctx, docker, output, proxy.ID, network.ID, cfg)
+				if err != nil {
+					return err
+				}
+			}
+
+			// recreate the proxy if HTTP/3 is enabled and the udp port is not bound
+			if err == nil && cfg.HTTP3 && !recreated {
+				if err := recreateProxyForHTTP3(ctx, docker, output, proxy.ID, network.ID, cfg); err != nil {
+					return err
+				}
+			}
+
 			output.Success("proxy ready")
 
 			output.Info("Checking databases…")
@@ -436,35 +501,113 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 				output.Info("Checking sites…")
 
 				// get the envs for the sites
+				ids := make(map[string]string)
 				for _, site := range cfg.Sites {
 					output.Pending("checking", site.Hostname)
 
+					// make sure the front controller exists before creating the container
+					if err := site.ValidateIndex(home); err != nil {
+						output.Warning()
+						return fmt.Errorf("site %s has an invalid index, %w", site.Hostname, err)
+					}
+
+					if err := site.FPM.Validate(); err != nil {
+						output.Warning()
+						return fmt.Errorf("site %s has invalid php-fpm settings, %w", site.Hostname, err)
+					}
+
 					// start, update or create the site container
-					_, err := sitecontainer.StartOrCreate(ctx, docker, home, network.ID, site, cfg)
+					id, err := sitecontainer.StartOrCreate(ctx, docker, home, network.ID, site, cfg)
 					if err != nil {
 						output.Warning()
 						return err
 					}
 
+					ids[site.Hostname] = id
+
 					output.Done()
 				}
+
+				// make sure the site containers are running before routing traffic to them
+				for _, site := range cfg.Sites {
+					if err := sitecontainer.VerifyRunning(ctx, docker, site.Hostname, ids[site.Hostname]); err != nil {
+						return err
+					}
+				}
+
+				// warn about sites that will not serve anything useful
+				if missing := missingWebroots(home, cfg.Sites); len(missing) > 0 {
+					output.Info("Warning: the webroot does not exist for these sites, check the path and webroot or build the project:")
+
+					for _, m := range missing {
+						output.Info("  " + m)
+					}
+				}
 			}
 
 			output.Info("Checking proxy…")
 
 			output.Pending("updating proxy")
 
-			if err := updateProxy(ctx, docker, nitrod, cfg); err != nil {
+			timeout, err := cmd.Flags().GetDuration("timeout")
+			if err != nil {
+				return err
+			}
+
+			dump, err := cmd.Flags().GetString("dump-config")
+			if err != nil {
+				return err
+			}
+
+			statuses, err := updateProxy(ctx, home, docker, nitrod, cfg, timeout, dump)
+			if err != nil {
 				output.Warning()
 				return err
 			}
 
 			output.Done()
 
+			if dump != "" {
+				output.Info("Saved the proxy config to", dump)
+			}
+
+			// show the route status and urls for each of the sites
+			if len(cfg.Sites) > 0 {
+				output.Info("Sites:")
+
+				tbl := table.New("Site", "Status", "URL").WithWriter(cmd.ErrOrStderr()).WithPadding(2)
+
+				var failed []*protob.SiteStatus
+				for _, s := range cfg.Sites {
+					state := "applied"
+					if st, ok := statuses[s.Hostname]; ok {
+						state = st.GetStatus()
+
+						// the site could not be routed
+						if state == "error" {
+							failed = append(failed, st)
+						}
+					}
+
+					tbl.AddRow(validate.Unicode(s.Hostname), state, "https://"+validate.Unicode(s.Hostname))
+
+					for _, a := range s.Aliases {
+						tbl.AddRow("", "", "https://"+validate.Unicode(a))
+					}
+				}
+
+				tbl.Print()
+
+				// flag the sites that could not be routed
+				for _, f := range failed {
+					output.Info(fmt.Sprintf("Unable to route %s, %s", validate.Unicode(f.GetHostname()), f.GetMessage()))
+				}
+			}
+
 			// should we update the hosts file?
 			if os.Getenv("NITRO_EDIT_HOSTS") == "false" || cmd.Flag("skip-hosts").Value.String() == "true" {
 				// skip updating the hosts file
-				return nil
+				return openSite(cmd, home, cfg, output)
 			}
 
 			// get all possible hostnames
@@ -525,25 +668,398 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 				}
 			}
 
-			return nil
+			return openSite(cmd, home, cfg, output)
 		},
 	}
 
 	// add flag to skip pulling images
 	cmd.Flags().Bool("skip-hosts", false, "skip modifying the hosts file")
+	cmd.Flags().Duration("timeout", 10*time.Second, "how long to wait for the proxy to apply changes")
+	cmd.Flags().Bool("open", false, "open the site in the default browser")
+	cmd.Flags().String("dump-config", "", "write the config sent to Caddy to a file, e.g. caddy.json")
 
 	return cmd
 }
 
-func updateProxy(ctx context.Context, docker client.ContainerAPIClient, nitrod protob.NitroClient, cfg *config.Config) error {
+// openSite opens the site for the current directory in the default browser when the
+// open flag is set. If there is more than one site, the user is prompted for the site.
+func openSite(cmd *cobra.Command, home string, cfg *config.Config, output terminal.Outputer) error {
+	if cmd.Flag("open").Value.String() != "true" {
+		return nil
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	// get a context aware list of sites
+	sites := cfg.ListOfSitesByDirectory(home, wd)
+	if len(sites) == 0 {
+		sites = cfg.Sites
+	}
+
+	var site config.Site
+	switch len(sites) {
+	case 0:
+		return fmt.Errorf("there are no sites to open")
+	case 1:
+		site = sites[0]
+	default:
+		var options []string
+		for _, s := range sites {
+			options = append(options, s.Hostname)
+		}
+
+		selected, err := output.Select(cmd.InOrStdin(), "Select a site to open: ", options)
+		if err != nil {
+			return err
+		}
+
+		site = sites[selected]
+	}
+
+	output.Info("Opening https://" + site.Hostname)
+
+	if err := browser.Open("https://" + site.Hostname); err != nil {
+		return fmt.Errorf("unable to open the browser, %w", err)
+	}
+
+	return nil
+}
+
+// duplicateHosts returns an error when a hostname or alias is used by more than one site,
+// including two sites with the same hostname.
+func duplicateHosts(sites []config.Site) error {
+	used := make(map[string]int)
+	for i, s := range sites {
+		for _, h := range append([]string{s.Hostname}, s.Aliases...) {
+			h = strings.ToLower(strings.TrimSpace(h))
+			if h == "" {
+				continue
+			}
+
+			if existing, ok := used[h]; ok && existing != i {
+				if sites[existing].Hostname == s.Hostname {
+					return fmt.Errorf("the hostname %q is used by more than one site", s.Hostname)
+				}
+
+				return fmt.Errorf("the host %q is used by the sites %q and %q", h, sites[existing].Hostname, s.Hostname)
+			}
+
+			used[h] = i
+		}
+	}
+
+	return nil
+}
+
+// orphanedSites returns the site containers for sites that are no longer in the config.
+// Database, custom, and proxy containers, and the containers for sites from a project
+// config, are never returned.
+func orphanedSites(containers []types.Container, cfg *config.Config) []types.Container {
+	sites := map[string]bool{}
+	for _, s := range cfg.Sites {
+		sites[s.Hostname] = true
+	}
+
+	var orphans []types.Container
+	for _, c := range containers {
+		host := c.Labels[containerlabels.Host]
+
+		switch {
+		case host == "", sites[host]:
+			continue
+		case c.Labels[containerlabels.DatabaseEngine] != "", c.Labels[containerlabels.NitroContainer] != "", c.Labels[containerlabels.Proxy] != "":
+			continue
+		case c.Labels[containerlabels.Project] != "":
+			continue
+		}
+
+		orphans = append(orphans, c)
+	}
+
+	return orphans
+}
+
+// removeOrphanedSites lists the site containers for sites that were removed from the
+// config and, after confirmation, stops and removes them.
+func removeOrphanedSites(ctx context.Context, docker client.CommonAPIClient, output terminal.Outputer, orphans []types.Container) error {
+	if len(orphans) == 0 {
+		return nil
+	}
+
+	output.Info("The following site containers are not in the config:")
+
+	for _, c := range orphans {
+		output.Info("  ", c.Labels[containerlabels.Host])
+	}
+
+	confirm, err := output.Confirm("Remove the site containers?", true, "")
+	if err != nil {
+		return err
+	}
+
+	if !confirm {
+		output.Info("Skipping, the site containers were not removed")
+
+		return nil
+	}
+
+	for _, c := range orphans {
+		output.Pending("removing", c.Labels[containerlabels.Host])
+
+		if err := docker.ContainerStop(ctx, c.ID, nil); err != nil {
+			output.Warning()
+
+			return fmt.Errorf("unable to stop the site container, %w", err)
+		}
+
+		if err := docker.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{}); err != nil {
+			output.Warning()
+
+			return fmt.Errorf("unable to remove the site container, %w", err)
+		}
+
+		output.Done()
+	}
+
+	return nil
+}
+
+// checkProxyPorts warns when the ports bound by the proxy container do not match the
+// environment, such as NITRO_HTTP_PORT or the proxy_ports being changed after the proxy
+// was created, and offers to recreate the proxy with the new ports. It returns true when
+// the proxy was recreated.
+func checkProxyPorts(ctx context.Context, docker client.CommonAPIClient, output terminal.Outputer, id, networkID string, cfg *config.Config) (bool, error) {
+	diffs, err := proxycontainer.PortMismatches(ctx, docker, id, cfg.ProxyPorts)
+	if err != nil || len(diffs) == 0 {
+		return false, err
+	}
+
+	for _, d := range diffs {
+		output.Info("  ", d)
+	}
+
+	confirm, err := output.Confirm("The proxy ports do not match the environment, recreate the proxy?", false, "")
+	if err != nil {
+		return false, err
+	}
+
+	if !confirm {
+		output.Info("Skipping, the sites are served on the existing ports")
+
+		return false, nil
+	}
+
+	output.Pending("recreating proxy for the new ports")
+
+	if err := removeProxy(ctx, docker, id); err != nil {
+		output.Warning()
+
+		return false, err
+	}
+
+	output.Done()
+
+	return true, proxycontainer.Create(ctx, docker, output, networkID, cfg.RestartPolicy, cfg.HTTP3, cfg.ProxyPorts)
+}
+
+// removeProxy stops and removes the proxy container, the proxy data is stored in a
+// volume so the certificates are kept.
+func removeProxy(ctx context.Context, docker client.CommonAPIClient, id string) error {
+	if err := docker.ContainerStop(ctx, id, nil); err != nil {
+		return fmt.Errorf("unable to stop the proxy container, %w", err)
+	}
+
+	if err := docker.ContainerRemove(ctx, id, types.ContainerRemoveOptions{}); err != nil {
+		return fmt.Errorf("unable to remove the proxy container, %w", err)
+	}
+
+	return nil
+}
+
+// recreateProxyForHTTP3 replaces the proxy container when it does not bind the HTTPS port
+// over udp. The proxy data is stored in a volume so the certificates are kept.
+func recreateProxyForHTTP3(ctx context.Context, docker client.CommonAPIClient, output terminal.Outputer, id, networkID string, cfg *config.Config) error {
+	bound, err := proxycontainer.HasHTTP3Port(ctx, docker, id)
+	if err != nil || bound {
+		return err
+	}
+
+	output.Pending("recreating proxy for HTTP/3")
+
+	if err := removeProxy(ctx, docker, id); err != nil {
+		output.Warning()
+
+		return err
+	}
+
+	output.Done()
+
+	return proxycontainer.Create(ctx, docker, output, networkID, cfg.RestartPolicy, true, cfg.ProxyPorts)
+}
+
+// dumpConfig writes the config sent to Caddy, indented for reading, to the file. The
+// directory for the file is created if it does not exist.
+func dumpConfig(home, file, content string) error {
+	if strings.HasPrefix(file, "~") {
+		file = strings.Replace(file, "~", home, 1)
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(content), "", "  "); err != nil {
+		return fmt.Errorf("unable to format the proxy config, %w", err)
+	}
+
+	buf.WriteString("\n")
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return fmt.Errorf("unable to create the directory for the proxy config, %w", err)
+	}
+
+	if err := ioutil.WriteFile(file, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("unable to write the proxy config, %w", err)
+	}
+
+	return nil
+}
+
+// missingWebroots returns the hostname and directory of the sites whose webroot does not
+// exist on the host.
+func missingWebroots(home string, sites []config.Site) []string {
+	var missing []string
+	for _, s := range sites {
+		dir, err := s.GetAbsWebroot(home)
+		if err != nil || !pathexists.IsDirectory(dir) {
+			missing = append(missing, fmt.Sprintf("%s: %s", s.Hostname, dir))
+		}
+	}
+
+	return missing
+}
+
+// updateProxy sends the sites to the api to configure the proxy routes and returns the
+// route status of each site by hostname.
+func updateProxy(ctx context.Context, home string, docker client.ContainerAPIClient, nitrod protob.NitroClient, cfg *config.Config, timeout time.Duration, dump string) (map[string]*protob.SiteStatus, error) {
+	// the maintenance page is sent to the proxy with the sites in maintenance mode
+	maintenanceBody, err := cfg.MaintenanceBody(home)
+	if err != nil {
+		return nil, err
+	}
+
+	// the not found page is shown for hosts that do not match a site
+	notFoundBody, err := cfg.NotFoundBody(home)
+	if err != nil {
+		return nil, err
+	}
+
+	// the request is keyed by hostname, so check the sites before duplicates are collapsed
+	if err := duplicateHosts(cfg.Sites); err != nil {
+		return nil, err
+	}
+
 	// convert the sites into the gRPC API Apply request
 	sites := make(map[string]*protob.Site)
 	for _, s := range cfg.Sites {
+		// validate the path prefixes for the site
+		var paths []*protob.SitePath
+		for _, p := range s.Paths {
+			if err := (&validate.PathPrefix{}).Validate(p.Prefix); err != nil {
+				return nil, fmt.Errorf("site %s has an invalid path, %w", s.Hostname, err)
+			}
+
+			if _, _, err := net.SplitHostPort(p.Upstream); err != nil {
+				return nil, fmt.Errorf("site %s has an invalid upstream for path %s, %w", s.Hostname, p.Prefix, err)
+			}
+
+			paths = append(paths, &protob.SitePath{Prefix: p.Prefix, Upstream: p.Upstream})
+		}
+
+		// validate the error pages and read the files from the webroot
+		if err := s.ValidateErrorPages(); err != nil {
+			return nil, fmt.Errorf("site %s has an invalid error page, %w", s.Hostname, err)
+		}
+
+		var errorPages []*protob.SiteErrorPage
+		for _, e := range s.ErrorPages {
+			body, err := s.ErrorPageBody(home, e)
+			if err != nil {
+				return nil, fmt.Errorf("site %s has an invalid error page, %w", s.Hostname, err)
+			}
+
+			errorPages = append(errorPages, &protob.SiteErrorPage{Status: int32(e.Status), Uri: e.Path, Body: body})
+		}
+
+		// validate the response headers for the site
+		for name, value := range s.Headers {
+			if err := (&validate.HeaderName{}).Validate(name); err != nil {
+				return nil, fmt.Errorf("site %s has an invalid header, %w", s.Hostname, err)
+			}
+
+			if strings.ContainsAny(value, "\r\n") {
+				return nil, fmt.Errorf("site %s has an invalid value for the header %s, values must not include a new line", s.Hostname, name)
+			}
+		}
+
+		for _, name := range s.RemoveHeaders {
+			if err := (&validate.HeaderName{}).Validate(name); err != nil {
+				return nil, fmt.Errorf("site %s has an invalid header to remove, %w", s.Hostname, err)
+			}
+		}
+
+		// validate the early hints and create the link headers
+		earlyHints, err := s.EarlyHintLinks()
+		if err != nil {
+			return nil, fmt.Errorf("site %s has an invalid early hint, %w", s.Hostname, err)
+		}
+
+		// validate the request body limit
+		maxRequestBody, err := s.MaxRequestBodySize()
+		if err != nil {
+			return nil, fmt.Errorf("site %s has an invalid max request body, %w", s.Hostname, err)
+		}
+
+		// read and validate the CA for client certificates
+		clientCA, err := s.ClientCACert(home)
+		if err != nil {
+			return nil, fmt.Errorf("site %s has an invalid client CA, %w", s.Hostname, err)
+		}
+
+		// caddy matches hosts using the punycode form of internationalized hostnames
+		hostname, err := validate.Punycode(s.Hostname)
+		if err != nil {
+			return nil, err
+		}
+
+		var aliases []string
+		for _, a := range s.Aliases {
+			alias, err := validate.Punycode(a)
+			if err != nil {
+				return nil, fmt.Errorf("site %s has an invalid alias, %w", s.Hostname, err)
+			}
+
+			aliases = append(aliases, alias)
+		}
+
 		// create the site
 		sites[s.Hostname] = &protob.Site{
-			Hostname: s.Hostname,
-			Aliases:  strings.Join(s.Aliases, ","),
-			Port:     8080,
+			Hostname:       hostname,
+			Aliases:        strings.Join(aliases, ","),
+			Port:           8080,
+			HttpsRedirect:  true,
+			Paths:          paths,
+			Maintenance:    s.Maintenance,
+			ErrorPages:     errorPages,
+			ClientCa:       clientCA,
+			Headers:        s.Headers,
+			RemoveHeaders:  s.RemoveHeaders,
+			EarlyHints:     earlyHints,
+			MaxRequestBody: maxRequestBody,
+		}
+
+		if s.Maintenance {
+			sites[s.Hostname].MaintenanceBody = maintenanceBody
 		}
 	}
 
@@ -575,7 +1091,7 @@ func updateProxy(ctx context.Context, docker client.ContainerAPIClient, nitrod p
 
 	// if there are no sites, we are done
 	if len(sites) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	// wait for the api to be ready
@@ -586,15 +1102,51 @@ func updateProxy(ctx context.Context, docker client.ContainerAPIClient, nitrod p
 		}
 	}
 
-	// configure the proxy with the sites
-	resp, err := nitrod.Apply(ctx, &protob.ApplyRequest{Sites: sites})
+	// the additional ports of the proxy container are proxied to the same port on the sites
+	extraPorts, err := proxycontainer.ExtraPorts(cfg.ProxyPorts)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	var proxyPorts []int32
+	for _, m := range extraPorts {
+		port, err := strconv.Atoi(m.Container)
+		if err != nil {
+			return nil, err
+		}
+
+		proxyPorts = append(proxyPorts, int32(port))
+	}
+
+	// set a deadline so an unresponsive proxy does not block forever
+	applyCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// configure the proxy with the sites, access logs are written to the proxy volume when enabled
+	resp, err := nitrod.Apply(applyCtx, &protob.ApplyRequest{Sites: sites, AccessLogs: cfg.AccessLogs, OnDemandTLS: cfg.OnDemandTLS, Http3: cfg.HTTP3, NotFoundBody: notFoundBody, ReturnConfig: dump != "", ProxyPorts: proxyPorts})
+	if status.Code(err) == codes.DeadlineExceeded {
+		return nil, fmt.Errorf("the proxy did not respond within %s, check the proxy logs or increase the --timeout", timeout)
+	}
+
+	// save the config before checking the errors, so rejected configs can be inspected
+	if dump != "" && resp.GetConfig() != "" {
+		if err := dumpConfig(home, dump, resp.GetConfig()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err != nil {
+		return nil, err
 	}
 
 	if resp.Error {
-		return fmt.Errorf("unable to update the proxy, %s", resp.GetMessage())
+		return nil, fmt.Errorf("unable to update the proxy, %s", resp.GetMessage())
 	}
 
-	return nil
+	statuses := make(map[string]*protob.SiteStatus)
+	for _, s := range resp.GetSites() {
+		statuses[s.GetHostname()] = s
+	}
+
+	return statuses, nil
 }
@@ -0,0 +1,101 @@
+package apply
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+)
+
+func TestOrphanedSites(t *testing.T) {
+	cfg := &config.Config{Sites: []config.Site{{Hostname: "craft-dev.nitro"}}}
+
+	tests := []struct {
+		name       string
+		containers []types.Container
+		want       []string
+	}{
+		{
+			name: "sites in the config are not orphans",
+			containers: []types.Container{
+				{ID: "site", Labels: map[string]string{containerlabels.Nitro: "true", containerlabels.Host: "craft-dev.nitro"}},
+			},
+		},
+		{
+			name: "sites removed from the config are orphans",
+			containers: []types.Container{
+				{ID: "site", Labels: map[string]string{containerlabels.Nitro: "true", containerlabels.Host: "craft-dev.nitro"}},
+				{ID: "removed", Labels: map[string]string{containerlabels.Nitro: "true", containerlabels.Host: "removed.nitro"}},
+			},
+			want: []string{"removed"},
+		},
+		{
+			name: "database, custom, and proxy containers are not orphans",
+			containers: []types.Container{
+				{ID: "mysql", Labels: map[string]string{containerlabels.Nitro: "true", containerlabels.Host: "mysql-8.0-3306.database.nitro", containerlabels.DatabaseEngine: "mysql"}},
+				{ID: "custom", Labels: map[string]string{containerlabels.Nitro: "true", containerlabels.Host: "elasticsearch.containers.nitro", containerlabels.NitroContainer: "elasticsearch"}},
+				{ID: "proxy", Labels: map[string]string{containerlabels.Nitro: "true", containerlabels.Host: "nitro-proxy", containerlabels.Proxy: "true"}},
+				{ID: "composer", Labels: map[string]string{containerlabels.Nitro: "true", containerlabels.Type: "composer"}},
+			},
+		},
+		{
+			name: "sites from a project config are not orphans",
+			containers: []types.Container{
+				{ID: "project", Labels: map[string]string{containerlabels.Nitro: "true", containerlabels.Host: "docs.nitro", containerlabels.Project: "/home/user/dev/docs/.nitro.yaml"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range orphanedSites(tt.containers, cfg) {
+				got = append(got, c.ID)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("orphanedSites() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDuplicateHosts(t *testing.T) {
+	tests := []struct {
+		name    string
+		sites   []config.Site
+		wantErr string
+	}{
+		{
+			name:  "unique hosts do not return an error",
+			sites: []config.Site{{Hostname: "craftdev.nitro", Aliases: []string{"alias.nitro"}}, {Hostname: "another.nitro"}},
+		},
+		{
+			name:    "sites with the same hostname return an error",
+			sites:   []config.Site{{Hostname: "craftdev.nitro", Path: "~/dev/craftdev"}, {Hostname: "craftdev.nitro", Path: "~/dev/other"}},
+			wantErr: `the hostname "craftdev.nitro" is used by more than one site`,
+		},
+		{
+			name:    "aliases used by another site return an error",
+			sites:   []config.Site{{Hostname: "craftdev.nitro", Aliases: []string{"alias.nitro"}}, {Hostname: "Alias.nitro"}},
+			wantErr: `the host "alias.nitro" is used by the sites "craftdev.nitro" and "Alias.nitro"`,
+		},
+		{
+			name:  "a site can repeat its own hostname as an alias",
+			sites: []config.Site{{Hostname: "craftdev.nitro", Aliases: []string{"craftdev.nitro"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := duplicateHosts(tt.sites)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("duplicateHosts() error = %v", err)
+			case tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr):
+				t.Errorf("duplicateHosts() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package customcontainer
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"io/ioutil"
@@ -11,6 +10,7 @@ import (
 	"github.com/craftcms/nitro/command/apply/internal/match"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/imagepull"
 	"github.com/craftcms/nitro/pkg/pathexists"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -83,14 +83,8 @@ func create(ctx context.Context, docker client.CommonAPIClient, home, networkID
 	image := fmt.Sprintf("%s:%s", c.Image, c.Tag)
 
 	// pull the image
-	rdr, err := docker.ImagePull(ctx, image, types.ImagePullOptions{All: false})
-	if err != nil {
-		return "", fmt.Errorf("unable to pull the image, %w", err)
-	}
-
-	buf := &bytes.Buffer{}
-	if _, err := buf.ReadFrom(rdr); err != nil {
-		return "", fmt.Errorf("unable to read output from pulling image %s, %w", image, err)
+	if err := imagepull.Pull(ctx, docker, image); err != nil {
+		return "", err
 	}
 
 	// get the containers custom environment variables from the file
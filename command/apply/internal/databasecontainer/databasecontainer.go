@@ -1,15 +1,21 @@
 package databasecontainer
 
 import (
-	"bytes"
+	"archive/tar"
 	"context"
 	"database/sql"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
 	"strings"
 	"time"
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/database"
+	"github.com/craftcms/nitro/pkg/imagepull"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -57,6 +63,16 @@ func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, networkID
 
 	// if there is a container, we should start it and return
 	if len(containers) == 1 {
+		// containers created without the named volume need their data moved
+		if !hasNamedVolume(containers[0], hostname, DataDirectory(db.Engine)) {
+			id, err := migrate(ctx, docker, networkID, db, hostname, containers[0], output)
+			if err != nil {
+				return "", "", err
+			}
+
+			return start(ctx, docker, id, hostname, db)
+		}
+
 		// check if the container is running
 		if containers[0].State != "running" {
 			// start the container
@@ -68,6 +84,194 @@ func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, networkID
 		return containers[0].ID, hostname, nil
 	}
 
+	id, err := create(ctx, docker, networkID, db, hostname, output)
+	if err != nil {
+		return "", "", err
+	}
+
+	return start(ctx, docker, id, hostname, db)
+}
+
+// DataDirectory returns the directory in the container the engine stores its data.
+func DataDirectory(engine string) string {
+	if engine == "postgres" {
+		return "/var/lib/postgresql/data"
+	}
+
+	return "/var/lib/mysql"
+}
+
+// hasNamedVolume returns true if the containers data directory is mounted from the named
+// volume for the database.
+func hasNamedVolume(c types.Container, hostname, target string) bool {
+	for _, m := range c.Mounts {
+		if m.Destination == target {
+			return m.Type == mount.TypeVolume && m.Name == hostname
+		}
+	}
+
+	return false
+}
+
+// migrate moves the data from a database container that was created with an anonymous volume,
+// or without a volume, into a new container using the named volume so the container can be
+// recreated without losing the databases. The previous volume is not removed. The named
+// volume has to be new or empty, so existing data is never overwritten.
+func migrate(ctx context.Context, docker client.CommonAPIClient, networkID string, db config.Database, hostname string, c types.Container, output terminal.Outputer) (string, error) {
+	target := DataDirectory(db.Engine)
+
+	output.Pending("moving", hostname, "data to a named volume")
+
+	empty, err := volumeEmpty(ctx, docker, hostname, c.Image)
+	if err != nil {
+		output.Warning()
+
+		return "", err
+	}
+
+	if !empty {
+		output.Warning()
+
+		return "", fmt.Errorf("unable to move the data for %s, the volume %s already has data, remove the volume with `docker volume rm %s` or remove the container %s to use the data in the volume", hostname, hostname, hostname, hostname)
+	}
+
+	// stop the container so the data is consistent
+	if c.State == "running" {
+		if err := docker.ContainerStop(ctx, c.ID, nil); err != nil {
+			output.Warning()
+
+			return "", fmt.Errorf("unable to stop the database container, %w", err)
+		}
+	}
+
+	// copy the data directory to a temp file, it may be too large to keep in memory
+	rdr, _, err := docker.CopyFromContainer(ctx, c.ID, target)
+	if err != nil {
+		output.Warning()
+
+		return "", fmt.Errorf("unable to copy the data from the database container, %w", err)
+	}
+	defer rdr.Close()
+
+	f, err := ioutil.TempFile("", "nitro-database-data-")
+	if err != nil {
+		output.Warning()
+
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if _, err := io.Copy(f, rdr); err != nil {
+		output.Warning()
+
+		return "", fmt.Errorf("unable to copy the data from the database container, %w", err)
+	}
+
+	// keep the previous container until the data is copied so the name can be reused
+	if err := docker.ContainerRename(ctx, c.ID, hostname+"-previous"); err != nil {
+		output.Warning()
+
+		return "", fmt.Errorf("unable to rename the database container, %w", err)
+	}
+
+	id, err := create(ctx, docker, networkID, db, hostname, output)
+	if err != nil {
+		output.Warning()
+
+		// restore the previous container name
+		_ = docker.ContainerRename(ctx, c.ID, hostname)
+
+		return "", err
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		output.Warning()
+
+		return "", err
+	}
+
+	// the archive contains the data directory, so copy it into the parent
+	if err := docker.CopyToContainer(ctx, id, path.Dir(target), f, types.CopyToContainerOptions{CopyUIDGID: true}); err != nil {
+		output.Warning()
+
+		return "", fmt.Errorf("unable to copy the data into the named volume, %w", err)
+	}
+
+	// remove the previous container but keep its volume in case the copy was incomplete
+	if err := docker.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{}); err != nil {
+		output.Warning()
+
+		return "", fmt.Errorf("unable to remove the previous database container, %w", err)
+	}
+
+	output.Done()
+
+	return id, nil
+}
+
+// volumeEmpty returns true if the volume does not exist or does not have any files. The
+// files are listed using a container for the image that is created but not started.
+func volumeEmpty(ctx context.Context, docker client.CommonAPIClient, name, image string) (bool, error) {
+	if _, err := docker.VolumeInspect(ctx, name); err != nil {
+		if client.IsErrNotFound(err) {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("unable to inspect the volume, %w", err)
+	}
+
+	resp, err := docker.ContainerCreate(ctx, &container.Config{Image: image, Entrypoint: []string{"true"}}, &container.HostConfig{
+		Mounts: []mount.Mount{{Type: mount.TypeVolume, Source: name, Target: "/volume"}},
+	}, nil, nil, "")
+	if err != nil {
+		return false, fmt.Errorf("unable to check the volume, %w", err)
+	}
+	defer docker.ContainerRemove(ctx, resp.ID, types.ContainerRemoveOptions{})
+
+	rdr, _, err := docker.CopyFromContainer(ctx, resp.ID, "/volume")
+	if err != nil {
+		return false, fmt.Errorf("unable to check the volume, %w", err)
+	}
+	defer rdr.Close()
+
+	// the archive contains the directory for the volume and the files in it
+	tr := tar.NewReader(rdr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return true, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("unable to check the volume, %w", err)
+		}
+
+		if strings.Trim(hdr.Name, "/") != "volume" {
+			return false, nil
+		}
+	}
+}
+
+// start starts the database container and, for mysql compatible engines, waits for the
+// database to be ready.
+func start(ctx context.Context, docker client.CommonAPIClient, id, hostname string, db config.Database) (string, string, error) {
+	if err := docker.ContainerStart(ctx, id, types.ContainerStartOptions{}); err != nil {
+		return "", "", fmt.Errorf("unable to start the container, %w", err)
+	}
+
+	// if the container is mysql compatible
+	if db.Engine == "mysql" || db.Engine == "mariadb" {
+		if err := waitForMySQLContainer(ctx, docker, id, db); err != nil {
+			return "", "", err
+		}
+	}
+
+	return id, hostname, nil
+}
+
+// create creates the named volume, labeled with the engine and version, and the container
+// for the database. It does not start the container.
+func create(ctx context.Context, docker client.CommonAPIClient, networkID string, db config.Database, hostname string, output terminal.Outputer) (string, error) {
 	// create the database labels for the new container
 	labels := map[string]string{
 		containerlabels.Nitro:           "true",
@@ -90,20 +294,24 @@ func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, networkID
 		labels[containerlabels.DatabaseCompatibility] = "postgres"
 	}
 
-	// create the volume
-	volume, err := docker.VolumeCreate(ctx, volumetypes.VolumeCreateBody{Driver: "local", Name: hostname, Labels: labels})
+	// create the volume, an existing volume with the same name is reused
+	volumeLabels := map[string]string{containerlabels.Volume: hostname}
+	for k, v := range labels {
+		volumeLabels[k] = v
+	}
+
+	volume, err := docker.VolumeCreate(ctx, volumetypes.VolumeCreateBody{Driver: "local", Name: hostname, Labels: volumeLabels})
 	if err != nil {
-		return "", "", fmt.Errorf("unable to create the volume, %w", err)
+		return "", fmt.Errorf("unable to create the volume, %w", err)
 	}
 
 	// determine the image name
 	image := fmt.Sprintf(DatabaseImage, db.Engine, db.Version)
 
 	// set mounts and environment based on the database type
-	target := "/var/lib/mysql"
+	target := DataDirectory(db.Engine)
 	var envs []string
 	if strings.Contains(image, "postgres") {
-		target = "/var/lib/postgresql/data"
 		envs = []string{"POSTGRES_USER=nitro", "POSTGRES_DB=nitro", "POSTGRES_PASSWORD=nitro"}
 	} else {
 		envs = []string{"MYSQL_ROOT_PASSWORD=nitro", "MYSQL_DATABASE=nitro", "MYSQL_USER=nitro", "MYSQL_PASSWORD=nitro"}
@@ -118,7 +326,7 @@ func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, networkID
 	// look for the image
 	images, err := docker.ImageList(ctx, types.ImageListOptions{Filters: imageFilter, All: true})
 	if err != nil {
-		return "", "", fmt.Errorf("unable to get a list of images, %w", err)
+		return "", fmt.Errorf("unable to get a list of images, %w", err)
 	}
 
 	// if there are no images, pull one
@@ -126,18 +334,10 @@ func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, networkID
 		output.Pending("downloading", image)
 
 		// pull the image
-		rdr, err := docker.ImagePull(ctx, image, types.ImagePullOptions{All: false})
-		if err != nil {
+		if err := imagepull.Pull(ctx, docker, image); err != nil {
 			output.Warning()
 
-			return "", "", fmt.Errorf("unable to pull image %s, %w", image, err)
-		}
-
-		// read the output to pull the image
-		buf := &bytes.Buffer{}
-		if _, err := buf.ReadFrom(rdr); err != nil {
-			output.Warning()
-			return "", "", fmt.Errorf("unable to read output from pulling image %s, %w", image, err)
+			return "", err
 		}
 	}
 
@@ -147,12 +347,12 @@ func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, networkID
 	case "postgres":
 		port, err = nat.NewPort("tcp", "5432")
 		if err != nil {
-			return "", "", fmt.Errorf("unable to create the port, %w", err)
+			return "", fmt.Errorf("unable to create the port, %w", err)
 		}
 	default:
 		port, err = nat.NewPort("tcp", "3306")
 		if err != nil {
-			return "", "", fmt.Errorf("unable to create the port, %w", err)
+			return "", fmt.Errorf("unable to create the port, %w", err)
 		}
 	}
 
@@ -200,22 +400,10 @@ func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, networkID
 	// create the container for the database
 	resp, err := docker.ContainerCreate(ctx, containerConfig, hostConfig, networkConfig, nil, hostname)
 	if err != nil {
-		return "", "", fmt.Errorf("unable to create the container, %w", err)
-	}
-
-	// start the container
-	if err := docker.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
-		return "", "", fmt.Errorf("unable to start the container, %w", err)
-	}
-
-	// if the container is mysql compatible
-	if db.Engine == "mysql" || db.Engine == "mariadb" {
-		if err := waitForMySQLContainer(ctx, docker, resp.ID, db); err != nil {
-			return "", "", err
-		}
+		return "", fmt.Errorf("unable to create the container, %w", err)
 	}
 
-	return resp.ID, hostname, nil
+	return resp.ID, nil
 }
 
 func waitForMySQLContainer(ctx context.Context, docker client.CommonAPIClient, containerID string, d config.Database) error {
@@ -244,18 +432,7 @@ func waitForMySQLContainer(ctx context.Context, docker client.CommonAPIClient, c
 	time.Sleep(wait)
 
 	// setup the commands
-	commands := [][]string{
-		{"mysql", "-uroot", "-pnitro", fmt.Sprintf(`-e CREATE USER IF NOT EXISTS '%s'@'%s' IDENTIFIED BY 'nitro';`, "nitro", "localhost")},
-		{"mysql", "-uroot", "-pnitro", fmt.Sprintf(`-e GRANT ALL PRIVILEGES ON *.* TO '%s'@'%s' WITH GRANT OPTION;`, "nitro", "%")},
-		{"mysql", "-uroot", "-pnitro", fmt.Sprintf(`-e GRANT ALL PRIVILEGES ON *.* TO '%s'@'%s' WITH GRANT OPTION;`, "nitro", "localhost")},
-		{"mysql", "-uroot", "-pnitro", `-e FLUSH PRIVILEGES;`},
-	}
-
-	// for mysql 8.0 images
-	// ALTER USER ‘username’@‘ip_address’ IDENTIFIED WITH mysql_native_password BY ‘password’
-	if strings.Contains(d.Version, "8.0") {
-		commands = append(commands, []string{"mysql", "-uroot", "-pnitro", fmt.Sprintf(`-e ALTER USER '%s'@'%s' IDENTIFIED WITH mysql_native_password BY 'nitro';`, "nitro", "%")})
-	}
+	commands := database.MySQLGrants(d.Version)
 
 	for _, c := range commands {
 		// create the exec
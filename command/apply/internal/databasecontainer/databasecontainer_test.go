@@ -0,0 +1,148 @@
+package databasecontainer
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func Test_hasNamedVolume(t *testing.T) {
+	tests := []struct {
+		name   string
+		mounts []types.MountPoint
+		engine string
+		want   bool
+	}{
+		{
+			name:   "named volumes at the data directory do not need to be migrated",
+			mounts: []types.MountPoint{{Type: mount.TypeVolume, Name: "mysql-8.0-3306.database.nitro", Destination: "/var/lib/mysql"}},
+			engine: "mysql",
+			want:   true,
+		},
+		{
+			name:   "postgres uses the postgres data directory",
+			mounts: []types.MountPoint{{Type: mount.TypeVolume, Name: "mysql-8.0-3306.database.nitro", Destination: "/var/lib/postgresql/data"}},
+			engine: "postgres",
+			want:   true,
+		},
+		{
+			name:   "anonymous volumes need to be migrated",
+			mounts: []types.MountPoint{{Type: mount.TypeVolume, Name: "3f4a9c1b2e", Destination: "/var/lib/mysql"}},
+			engine: "mysql",
+			want:   false,
+		},
+		{
+			name:   "bind mounts need to be migrated",
+			mounts: []types.MountPoint{{Type: mount.TypeBind, Source: "/tmp/mysql", Destination: "/var/lib/mysql"}},
+			engine: "mysql",
+			want:   false,
+		},
+		{
+			name:   "containers without a volume need to be migrated",
+			engine: "mysql",
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := types.Container{Mounts: tt.mounts}
+			if got := hasNamedVolume(c, "mysql-8.0-3306.database.nitro", DataDirectory(tt.engine)); got != tt.want {
+				t.Errorf("hasNamedVolume() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type mockVolumeClient struct {
+	client.CommonAPIClient
+
+	volumeExists bool
+	files        []string
+	removed      []string
+}
+
+func (m *mockVolumeClient) VolumeInspect(ctx context.Context, volumeID string) (types.Volume, error) {
+	if !m.volumeExists {
+		return types.Volume{}, errdefs.NotFound(errors.New("no such volume"))
+	}
+
+	return types.Volume{Name: volumeID}, nil
+}
+
+func (m *mockVolumeClient) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *v1.Platform, containerName string) (container.ContainerCreateCreatedBody, error) {
+	return container.ContainerCreateCreatedBody{ID: "check"}, nil
+}
+
+func (m *mockVolumeClient) ContainerRemove(ctx context.Context, containerID string, options types.ContainerRemoveOptions) error {
+	m.removed = append(m.removed, containerID)
+
+	return nil
+}
+
+func (m *mockVolumeClient) CopyFromContainer(ctx context.Context, containerID, srcPath string) (io.ReadCloser, types.ContainerPathStat, error) {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	_ = tw.WriteHeader(&tar.Header{Name: "volume/", Typeflag: tar.TypeDir, Mode: 0755})
+	for _, f := range m.files {
+		_ = tw.WriteHeader(&tar.Header{Name: "volume/" + f, Typeflag: tar.TypeReg, Mode: 0644})
+	}
+	_ = tw.Close()
+
+	return ioutil.NopCloser(buf), types.ContainerPathStat{}, nil
+}
+
+func Test_volumeEmpty(t *testing.T) {
+	tests := []struct {
+		name        string
+		mock        *mockVolumeClient
+		want        bool
+		wantRemoved []string
+	}{
+		{
+			name: "new volumes are empty",
+			mock: &mockVolumeClient{},
+			want: true,
+		},
+		{
+			name:        "existing volumes without files are empty",
+			mock:        &mockVolumeClient{volumeExists: true},
+			want:        true,
+			wantRemoved: []string{"check"},
+		},
+		{
+			name:        "existing volumes with files are not empty",
+			mock:        &mockVolumeClient{volumeExists: true, files: []string{"ibdata1"}},
+			want:        false,
+			wantRemoved: []string{"check"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := volumeEmpty(context.Background(), tt.mock, "mysql-8.0-3306.database.nitro", "mysql:8.0")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.want {
+				t.Errorf("volumeEmpty() = %v, want %v", got, tt.want)
+			}
+
+			if !reflect.DeepEqual(tt.mock.removed, tt.wantRemoved) {
+				t.Errorf("expected the containers %v to be removed, got %v", tt.wantRemoved, tt.mock.removed)
+			}
+		})
+	}
+}
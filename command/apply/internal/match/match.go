@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/mount"
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
@@ -82,6 +83,16 @@ func Site(home string, site config.Site, container types.ContainerJSON, blackfir
 		return false
 	}
 
+	// check the front controller matches, sites using index.php are not labeled
+	if container.Config.Labels[containerlabels.Index] != site.Index {
+		return false
+	}
+
+	// check the php-fpm process manager settings match
+	if container.Config.Labels[containerlabels.FPM] != site.FPM.String() {
+		return false
+	}
+
 	// check the sites hostname using the label
 	if container.Config.Labels[containerlabels.Host] != site.Hostname {
 		return false
@@ -105,6 +116,11 @@ func Site(home string, site config.Site, container types.ContainerJSON, blackfir
 		}
 	}
 
+	// check the extra mounts
+	if !checkMounts(home, site, container.Mounts) {
+		return false
+	}
+
 	// TODO(jasonmccallister) check the labels for php extensions and write tests
 	switch len(site.Extensions) > 0 {
 	case false:
@@ -121,6 +137,37 @@ func Site(home string, site config.Site, container types.ContainerJSON, blackfir
 	return checkEnvs(site, blackfire, container.Config.Env)
 }
 
+// checkMounts verifies the extra bind mounts on the container match the mounts for the site.
+func checkMounts(home string, site config.Site, mounts []types.MountPoint) bool {
+	existing := make(map[string]types.MountPoint)
+	for _, m := range mounts {
+		// ignore the sites path and volumes from the image
+		if m.Type != mount.TypeBind || m.Destination == "/app" {
+			continue
+		}
+
+		existing[m.Destination] = m
+	}
+
+	if len(existing) != len(site.Mounts) {
+		return false
+	}
+
+	for _, m := range site.Mounts {
+		source, err := m.GetAbsSource(home)
+		if err != nil {
+			return false
+		}
+
+		e, ok := existing[m.Target]
+		if !ok || e.Source != source || e.RW == m.ReadOnly {
+			return false
+		}
+	}
+
+	return true
+}
+
 func checkEnvs(site config.Site, blackfire config.Blackfire, envs []string) bool {
 	// check the environment variables
 	for _, e := range envs {
@@ -188,6 +235,11 @@ func checkEnvs(site config.Site, blackfire config.Blackfire, envs []string) bool
 				if !site.Xdebug && val != config.DefaultEnvs[env] {
 					return false
 				}
+
+				// check the mode for sites using xdebug 3
+				if site.Xdebug && val != "xdebug2" && val != site.GetXdebugMode() {
+					return false
+				}
 			}
 		}
 	}
@@ -9,6 +9,7 @@ import (
 	"github.com/craftcms/nitro/pkg/containerlabels"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
 )
 
 func Test_checkEnvs(t *testing.T) {
@@ -238,6 +239,110 @@ func Test_checkEnvs(t *testing.T) {
 	}
 }
 
+func Test_checkMounts(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		site   config.Site
+		mounts []types.MountPoint
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "sites without mounts ignore the site path",
+			args: args{
+				site: config.Site{},
+				mounts: []types.MountPoint{
+					{
+						Type:        mount.TypeBind,
+						Source:      filepath.Join(wd, "testdata", "example-site"),
+						Destination: "/app",
+						RW:          true,
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "matching mounts return true",
+			args: args{
+				site: config.Site{
+					Mounts: []config.Mount{
+						{Source: "testdata/example-site", Target: "/shared", ReadOnly: true},
+					},
+				},
+				mounts: []types.MountPoint{
+					{
+						Type:        mount.TypeBind,
+						Source:      filepath.Join(wd, "testdata", "example-site"),
+						Destination: "/shared",
+						RW:          false,
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "missing mounts return false",
+			args: args{
+				site: config.Site{
+					Mounts: []config.Mount{
+						{Source: "testdata/example-site", Target: "/shared"},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "removed mounts return false",
+			args: args{
+				site: config.Site{},
+				mounts: []types.MountPoint{
+					{
+						Type:        mount.TypeBind,
+						Source:      filepath.Join(wd, "testdata", "example-site"),
+						Destination: "/shared",
+						RW:          true,
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "mismatched read only returns false",
+			args: args{
+				site: config.Site{
+					Mounts: []config.Mount{
+						{Source: "testdata/example-site", Target: "/shared", ReadOnly: true},
+					},
+				},
+				mounts: []types.MountPoint{
+					{
+						Type:        mount.TypeBind,
+						Source:      filepath.Join(wd, "testdata", "example-site"),
+						Destination: "/shared",
+						RW:          true,
+					},
+				},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkMounts("", tt.args.site, tt.args.mounts); got != tt.want {
+				t.Errorf("checkMounts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSite(t *testing.T) {
 	wd, err := os.Getwd()
 	if err != nil {
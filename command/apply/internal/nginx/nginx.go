@@ -7,7 +7,7 @@ var conf = `server {
     listen      [::]:8080 default_server;
     server_name _;
     set         $base /app;
-    root        $base/%s;
+    root        $base/%[1]s;
 
     proxy_send_timeout 240s;
     proxy_read_timeout 240s;
@@ -21,11 +21,11 @@ var conf = `server {
     include     /app/*nitro.conf;
 
     # index.php
-    index       index.php;
+    index       %[2]s;
 
     # index.php fallback
     location / {
-        try_files $uri $uri/ /index.php?$query_string;
+        try_files $uri $uri/ /%[2]s?$query_string;
     }
 
     # additional config
@@ -47,12 +47,18 @@ var conf = `server {
     }
 }`
 
-// Generate takes a root directory generates a nginx configuration file
-func Generate(root string) string {
+// Generate takes a root directory and the front controller and generates a nginx
+// configuration file
+func Generate(root, index string) string {
 	// if the root was not provided, default to web
 	if root == "" {
-		return fmt.Sprintf(conf, "web")
+		root = "web"
 	}
 
-	return fmt.Sprintf(conf, root)
+	// if the index was not provided, default to index.php
+	if index == "" {
+		index = "index.php"
+	}
+
+	return fmt.Sprintf(conf, root, index)
 }
@@ -1,10 +1,14 @@
 package nginx
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestGenerate(t *testing.T) {
 	type args struct {
-		root string
+		root  string
+		index string
 	}
 	tests := []struct {
 		name string
@@ -18,10 +22,18 @@ func TestGenerate(t *testing.T) {
 			},
 			want: defaultConf,
 		},
+		{
+			name: "uses the root and index",
+			args: args{
+				root:  "public",
+				index: "app.php",
+			},
+			want: strings.NewReplacer("$base/web", "$base/public", "index       index.php", "index       app.php", "/index.php?", "/app.php?").Replace(defaultConf),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := Generate(tt.args.root); got != tt.want {
+			if got := Generate(tt.args.root, tt.args.index); got != tt.want {
 				t.Errorf("Generate() = %v, want %v", got, tt.want)
 			}
 		})
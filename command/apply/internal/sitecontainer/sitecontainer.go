@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"runtime"
 	"strings"
@@ -12,10 +13,13 @@ import (
 	"github.com/craftcms/nitro/command/apply/internal/nginx"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/imagepull"
+	"github.com/craftcms/nitro/pkg/phpext"
 	"github.com/craftcms/nitro/pkg/wsl"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/archive"
@@ -30,6 +34,13 @@ type command struct {
 var (
 	// NginxImage is the image used for sites, with the PHP version
 	NginxImage = "docker.io/craftcms/nginx:%s-dev"
+
+	// FPMPoolDirectory is the directory in the site container for the php-fpm pool configs
+	FPMPoolDirectory = "/usr/local/etc/php-fpm.d"
+
+	// FPMPoolFile is the pool config for the process manager settings, it is named to be
+	// loaded after the default www.conf so the settings override the image defaults
+	FPMPoolFile = "zz-nitro.conf"
 )
 
 // StartOrCreate is responsible for finding a sites existing container or creating a new one based on the values from the configuration file.
@@ -64,8 +75,8 @@ func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, home, net
 		return "", err
 	}
 
-	// if the container is out of date
-	if !match.Site(home, site, details, cfg.Blackfire) {
+	// if the container is out of date, or the host user option changed
+	if !match.Site(home, site, details, cfg.Blackfire) || details.Config.Labels[containerlabels.User] != cfg.ContainerUser() {
 		fmt.Print("- updating… ")
 
 		// stop container
@@ -81,9 +92,86 @@ func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, home, net
 		return create(ctx, docker, home, networkID, site, cfg)
 	}
 
+	// the restart policy can be updated without recreating the container
+	if restartPolicyName(details.HostConfig.RestartPolicy.Name) != restartPolicyName(cfg.RestartPolicy) {
+		if err := updateRestartPolicy(ctx, docker, container.ID, cfg.RestartPolicy); err != nil {
+			return "", err
+		}
+	}
+
 	return container.ID, nil
 }
 
+// PoolConf returns the php-fpm pool config with the process manager settings and, when
+// the user (e.g. 1000:1000) is not empty, the uid and gid the workers run as. The master
+// process keeps running as root, so nginx and supervisor are not changed.
+func PoolConf(fpm config.FPM, user string) string {
+	conf := fpm.Conf()
+	if user == "" {
+		return conf
+	}
+
+	if conf == "" {
+		conf = "[www]\n"
+	}
+
+	uid, gid := user, user
+	if sp := strings.SplitN(user, ":", 2); len(sp) == 2 {
+		uid, gid = sp[0], sp[1]
+	}
+
+	return conf + fmt.Sprintf("user = %s\ngroup = %s\n", uid, gid)
+}
+
+// VerifyRunning makes sure the sites container is running before the proxy routes
+// traffic to it. If the container stopped, it will try to start it once and return
+// an error if the container still is not running (e.g. the container exits on start).
+func VerifyRunning(ctx context.Context, docker client.ContainerAPIClient, hostname, id string) error {
+	for attempt := 0; attempt < 2; attempt++ {
+		details, err := docker.ContainerInspect(ctx, id)
+		if err != nil {
+			return fmt.Errorf("unable to inspect the container for %s, %w", hostname, err)
+		}
+
+		if details.State != nil && details.State.Running {
+			return nil
+		}
+
+		if attempt > 0 {
+			exitCode := 0
+			if details.State != nil {
+				exitCode = details.State.ExitCode
+			}
+
+			return fmt.Errorf("the container for %s is not running (exit code %d), run `nitro logs` for details", hostname, exitCode)
+		}
+
+		if err := docker.ContainerStart(ctx, id, types.ContainerStartOptions{}); err != nil {
+			return fmt.Errorf("unable to start the container for %s, %w", hostname, err)
+		}
+	}
+
+	return nil
+}
+
+// restartPolicyName returns the policy as docker reports it, which is no for an empty policy.
+func restartPolicyName(policy string) string {
+	if policy == "" {
+		return "no"
+	}
+
+	return policy
+}
+
+// updateRestartPolicy sets the restart policy (e.g. unless-stopped) on an existing container.
+func updateRestartPolicy(ctx context.Context, docker client.ContainerAPIClient, id, policy string) error {
+	if _, err := docker.ContainerUpdate(ctx, id, container.UpdateConfig{RestartPolicy: container.RestartPolicy{Name: policy}}); err != nil {
+		return fmt.Errorf("unable to update the restart policy, %w", err)
+	}
+
+	return nil
+}
+
 func create(ctx context.Context, docker client.CommonAPIClient, home, networkID string, site config.Site, cfg *config.Config) (string, error) {
 	// create the container
 	image := fmt.Sprintf(NginxImage, site.Version)
@@ -91,14 +179,8 @@ func create(ctx context.Context, docker client.CommonAPIClient, home, networkID
 	// pull the image if we are not in a development environment
 	_, dev := os.LookupEnv("NITRO_DEVELOPMENT")
 	if !dev {
-		rdr, err := docker.ImagePull(ctx, image, types.ImagePullOptions{All: false})
-		if err != nil {
-			return "", fmt.Errorf("unable to pull the image, %w", err)
-		}
-
-		buf := &bytes.Buffer{}
-		if _, err := buf.ReadFrom(rdr); err != nil {
-			return "", fmt.Errorf("unable to read output from pulling image %s, %w", image, err)
+		if err := imagepull.Pull(ctx, docker, image); err != nil {
+			return "", err
 		}
 	}
 
@@ -108,6 +190,27 @@ func create(ctx context.Context, docker client.CommonAPIClient, home, networkID
 		return "", err
 	}
 
+	// add any extra mounts for the site
+	var mounts []mount.Mount
+	for _, m := range site.Mounts {
+		source, err := m.GetAbsSource(home)
+		if err != nil {
+			return "", err
+		}
+
+		// make sure the source exists
+		if _, err := os.Stat(source); err != nil {
+			return "", fmt.Errorf("unable to find the source %s for the mount %s, %w", source, m.Target, err)
+		}
+
+		mounts = append(mounts, mount.Mount{
+			Type:     mount.TypeBind,
+			Source:   source,
+			Target:   m.Target,
+			ReadOnly: m.ReadOnly,
+		})
+	}
+
 	// add the site itself and any aliases to the extra hosts
 	extraHosts := []string{fmt.Sprintf("%s:%s", site.Hostname, "127.0.0.1")}
 	for _, s := range site.Aliases {
@@ -133,6 +236,13 @@ func create(ctx context.Context, docker client.CommonAPIClient, home, networkID
 
 	// set the labels
 	labels := containerlabels.ForSite(site)
+
+	// php runs as the host user so the files it creates are owned by the user
+	user := cfg.ContainerUser()
+	if user != "" {
+		labels[containerlabels.User] = user
+	}
+
 	// create the container
 	resp, err := docker.ContainerCreate(
 		ctx,
@@ -142,8 +252,10 @@ func create(ctx context.Context, docker client.CommonAPIClient, home, networkID
 			Env:    envs,
 		},
 		&container.HostConfig{
-			Binds:      []string{fmt.Sprintf("%s:/app:rw", path)},
-			ExtraHosts: extraHosts,
+			Binds:         []string{fmt.Sprintf("%s:/app:rw", path)},
+			Mounts:        mounts,
+			ExtraHosts:    extraHosts,
+			RestartPolicy: container.RestartPolicy{Name: cfg.RestartPolicy},
 		},
 		&network.NetworkingConfig{
 			EndpointsConfig: map[string]*network.EndpointSettings{
@@ -167,10 +279,10 @@ func create(ctx context.Context, docker client.CommonAPIClient, home, networkID
 	// post installation commands
 	var commands []command
 
-	// check for a custom root and copt the template to the container
-	if site.Webroot != "web" {
+	// check for a custom root or front controller and copy the template to the container
+	if site.Webroot != "web" || site.GetIndex() != config.DefaultIndex {
 		// create the nginx file
-		conf := nginx.Generate(site.Webroot)
+		conf := nginx.Generate(site.Webroot, site.GetIndex())
 
 		// create the temp file
 		tr, err := archive.Generate("default.conf", conf)
@@ -187,15 +299,45 @@ func create(ctx context.Context, docker client.CommonAPIClient, home, networkID
 		commands = append(commands, command{Commands: []string{"chmod", "0644", "/etc/nginx/conf.d/default.conf"}})
 	}
 
+	// write the process manager settings and user to the php-fpm pool config
+	fpm := PoolConf(site.FPM, user)
+	if fpm != "" {
+		tr, err := archive.Generate(FPMPoolFile, fpm)
+		if err != nil {
+			return "", err
+		}
+
+		if err := docker.CopyToContainer(ctx, resp.ID, FPMPoolDirectory, tr, types.CopyToContainerOptions{AllowOverwriteDirWithFile: false}); err != nil {
+			return "", err
+		}
+	}
+
 	// check if there are custom extensions
 	for _, ext := range site.Extensions {
-		commands = append(commands, command{Name: "installing-" + ext + "-extension", Commands: []string{"docker-php-ext-install", ext}})
+		commands = append(commands, command{Name: "installing-" + ext + "-extension", Commands: phpext.InstallCommand(ext)})
 	}
 
 	// run the commands
+	if err := runCommands(ctx, docker, resp.ID, commands); err != nil {
+		return "", err
+	}
+
+	// restart the container so php-fpm loads the pool config
+	if fpm != "" {
+		if err := docker.ContainerRestart(ctx, resp.ID, nil); err != nil {
+			return "", fmt.Errorf("unable to restart the container, %w", err)
+		}
+	}
+
+	return resp.ID, nil
+}
+
+// runCommands runs the post installation commands in the site container as root and
+// returns an error with the output of the first command that fails.
+func runCommands(ctx context.Context, docker client.CommonAPIClient, id string, commands []command) error {
 	for _, c := range commands {
 		// create the exec
-		exec, err := docker.ContainerExecCreate(ctx, resp.ID, types.ExecConfig{
+		exec, err := docker.ContainerExecCreate(ctx, id, types.ExecConfig{
 			User:         "root",
 			AttachStdout: true,
 			AttachStderr: true,
@@ -203,7 +345,7 @@ func create(ctx context.Context, docker client.CommonAPIClient, home, networkID
 			Cmd:          c.Commands,
 		})
 		if err != nil {
-			return "", err
+			return err
 		}
 
 		// attach to the container
@@ -211,47 +353,55 @@ func create(ctx context.Context, docker client.CommonAPIClient, home, networkID
 			Tty: false,
 		})
 		if err != nil {
-			return "", err
+			return err
 		}
 		defer attach.Close()
 
+		// keep the output to return it when the command fails
+		buf := &bytes.Buffer{}
+
 		// if the option is for a php extension, don't show output
 		if strings.Contains(c.Name, "-extension") {
 			// read the output to pull the image
-			fmt.Print("installing ", c.Commands[len(c.Commands)-1], "… ")
+			fmt.Print("installing ", strings.TrimSuffix(strings.TrimPrefix(c.Name, "installing-"), "-extension"), "… ")
 
-			buf := &bytes.Buffer{}
-			if _, err := buf.ReadFrom(attach.Reader); err != nil {
-				return "", fmt.Errorf("unable to read output from container exec attach, %w", err)
+			if _, err := stdcopy.StdCopy(buf, buf, attach.Reader); err != nil {
+				return fmt.Errorf("unable to read output from container exec attach, %w", err)
 			}
 		} else {
 			// show the output to stdout and stderr
-			if _, err := stdcopy.StdCopy(os.Stdout, os.Stderr, attach.Reader); err != nil {
-				return "", fmt.Errorf("unable to copy the output of container, %w", err)
+			if _, err := stdcopy.StdCopy(io.MultiWriter(os.Stdout, buf), io.MultiWriter(os.Stderr, buf), attach.Reader); err != nil {
+				return fmt.Errorf("unable to copy the output of container, %w", err)
 			}
 		}
 
 		// start the exec
 		if err := docker.ContainerExecStart(ctx, exec.ID, types.ExecStartCheck{}); err != nil {
-			return "", fmt.Errorf("unable to start the container, %w", err)
+			return fmt.Errorf("unable to start the container, %w", err)
 		}
 
 		// wait for the container exec to complete
 		waiting := true
+		exitCode := 0
 		for waiting {
-			resp, err := docker.ContainerExecInspect(ctx, exec.ID)
+			inspect, err := docker.ContainerExecInspect(ctx, exec.ID)
 			if err != nil {
-				return "", err
+				return err
 			}
 
-			waiting = resp.Running
+			waiting = inspect.Running
+			exitCode = inspect.ExitCode
+		}
+
+		if exitCode != 0 {
+			return fmt.Errorf("unable to run %q in the container, exit code %d\n%s", strings.Join(c.Commands, " "), exitCode, strings.TrimSpace(buf.String()))
 		}
 
 		// start the container
-		if err := docker.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
-			return "", fmt.Errorf("unable to start the container, %w", err)
+		if err := docker.ContainerStart(ctx, id, types.ContainerStartOptions{}); err != nil {
+			return fmt.Errorf("unable to start the container, %w", err)
 		}
 	}
 
-	return resp.ID, nil
+	return nil
 }
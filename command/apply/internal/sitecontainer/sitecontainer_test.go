@@ -0,0 +1,205 @@
+package sitecontainer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+type mockContainerClient struct {
+	client.ContainerAPIClient
+
+	// states are returned in order by each call to inspect
+	states  []*types.ContainerState
+	inspect int
+	started int
+}
+
+func (c *mockContainerClient) ContainerInspect(ctx context.Context, id string) (types.ContainerJSON, error) {
+	state := c.states[c.inspect]
+	c.inspect++
+
+	return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{ID: id, State: state}}, nil
+}
+
+func (c *mockContainerClient) ContainerStart(ctx context.Context, id string, options types.ContainerStartOptions) error {
+	c.started++
+
+	return nil
+}
+
+func TestVerifyRunning(t *testing.T) {
+	tests := []struct {
+		name        string
+		states      []*types.ContainerState
+		wantStarted int
+		wantErr     bool
+	}{
+		{
+			name:        "running containers are not started",
+			states:      []*types.ContainerState{{Running: true}},
+			wantStarted: 0,
+		},
+		{
+			name:        "stopped containers are started",
+			states:      []*types.ContainerState{{Running: false}, {Running: true}},
+			wantStarted: 1,
+		},
+		{
+			name:        "containers that exit on start return an error",
+			states:      []*types.ContainerState{{Running: false, ExitCode: 1}, {Running: false, ExitCode: 1}},
+			wantStarted: 1,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockContainerClient{states: tt.states}
+
+			err := VerifyRunning(context.Background(), mock, "tutorial.nitro", "testingid")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyRunning() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if mock.started != tt.wantStarted {
+				t.Errorf("expected the container to be started %d times, got %d", tt.wantStarted, mock.started)
+			}
+		})
+	}
+}
+
+func TestPoolConf(t *testing.T) {
+	tests := []struct {
+		name string
+		fpm  config.FPM
+		user string
+		want string
+	}{
+		{
+			name: "no settings or user returns an empty config",
+		},
+		{
+			name: "the user is added to an empty pool",
+			user: "1000:1001",
+			want: "[www]\nuser = 1000\ngroup = 1001\n",
+		},
+		{
+			name: "the user is added after the process manager settings",
+			fpm:  config.FPM{MaxChildren: 10},
+			user: "1000:1000",
+			want: "[www]\npm.max_children = 10\nuser = 1000\ngroup = 1000\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PoolConf(tt.fpm, tt.user); got != tt.want {
+				t.Errorf("PoolConf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+type mockExecClient struct {
+	client.CommonAPIClient
+
+	exitCodes map[string]int
+	output    string
+	execs     [][]string
+}
+
+func (c *mockExecClient) ContainerExecCreate(ctx context.Context, container string, config types.ExecConfig) (types.IDResponse, error) {
+	c.execs = append(c.execs, config.Cmd)
+
+	return types.IDResponse{ID: strings.Join(config.Cmd, " ")}, nil
+}
+
+func (c *mockExecClient) ContainerExecAttach(ctx context.Context, execID string, config types.ExecStartCheck) (types.HijackedResponse, error) {
+	buf := &bytes.Buffer{}
+	_, _ = stdcopy.NewStdWriter(buf, stdcopy.Stderr).Write([]byte(c.output))
+
+	conn, _ := net.Pipe()
+
+	return types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(buf)}, nil
+}
+
+func (c *mockExecClient) ContainerExecStart(ctx context.Context, execID string, config types.ExecStartCheck) error {
+	return nil
+}
+
+func (c *mockExecClient) ContainerExecInspect(ctx context.Context, execID string) (types.ContainerExecInspect, error) {
+	return types.ContainerExecInspect{ExecID: execID, ExitCode: c.exitCodes[execID]}, nil
+}
+
+func (c *mockExecClient) ContainerStart(ctx context.Context, id string, options types.ContainerStartOptions) error {
+	return nil
+}
+
+func TestRunCommands(t *testing.T) {
+	commands := []command{
+		{Name: "installing-soap-extension", Commands: []string{"install-php-extensions", "soap"}},
+		{Commands: []string{"chmod", "0644", "/etc/nginx/conf.d/default.conf"}},
+	}
+
+	tests := []struct {
+		name      string
+		exitCodes map[string]int
+		wantExecs int
+		wantErr   string
+	}{
+		{
+			name:      "all of the commands are run",
+			wantExecs: 2,
+		},
+		{
+			name:      "failed commands return the output and stop the commands",
+			exitCodes: map[string]int{"install-php-extensions soap": 1},
+			wantExecs: 1,
+			wantErr:   "configure: error: libxml2 not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockExecClient{exitCodes: tt.exitCodes, output: "configure: error: libxml2 not found\n"}
+
+			err := runCommands(context.Background(), mock, "site", commands)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("runCommands() error = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("expected the error to contain %q, got %v", tt.wantErr, err)
+			}
+
+			if len(mock.execs) != tt.wantExecs {
+				t.Errorf("expected %d commands to run, got %v", tt.wantExecs, mock.execs)
+			}
+		})
+	}
+}
+
+func TestRestartPolicyName(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		want   string
+	}{
+		{name: "empty policies are no", policy: "", want: "no"},
+		{name: "no is not changed", policy: "no", want: "no"},
+		{name: "other policies are not changed", policy: "unless-stopped", want: "unless-stopped"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := restartPolicyName(tt.policy); got != tt.want {
+				t.Errorf("restartPolicyName(%q) = %q, want %q", tt.policy, got, tt.want)
+			}
+		})
+	}
+}
@@ -65,7 +65,7 @@ func offCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			// ensure the blackfire credentials are set
 			if cfg.Blackfire.ServerToken == "" {
 				// ask for the server token
-				token, err := output.Ask("Enter your Blackfire Server Token", "", ":", nil)
+				token, err := output.AskSecret("Enter your Blackfire Server Token", ":", nil)
 				if err != nil {
 					return err
 				}
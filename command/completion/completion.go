@@ -2,7 +2,6 @@ package completion
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -30,29 +29,51 @@ $ echo "autoload -U compinit; compinit" >> ~/.zshrc
 $ nitro completion zsh > "${fpath[1]}/_nitro"
 
 # You will need to start a new shell for this setup to take effect.
+
+Fish:
+
+$ nitro completion fish | source
+
+# To load completions for each session, execute once:
+$ nitro completion fish > ~/.config/fish/completions/nitro.fish
+
+PowerShell:
+
+PS> nitro completion powershell | Out-String | Invoke-Expression
+
+# To load completions for each session, add the output to your profile:
+PS> nitro completion powershell >> $PROFILE
 `
 
 // NewCommand returns the command used for generating completion shells
 func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:       "completion",
-		Short:     "Enables shell completion.",
-		ValidArgs: []string{"bash", "zsh"},
-		Example:   exampleText,
+		Use:                   "completion [bash|zsh|fish|powershell]",
+		Short:                 "Enables shell completion.",
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		Args:                  cobra.MaximumNArgs(1),
+		DisableFlagsInUseLine: true,
+		Example:               exampleText,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// print the help if not defined
 			if len(args) == 0 {
 				return cmd.Help()
 			}
 
+			out := cmd.OutOrStdout()
+
 			switch args[0] {
 			case "zsh":
-				return cmd.Root().GenZshCompletion(os.Stdout)
+				return cmd.Root().GenZshCompletion(out)
 			case "bash":
-				return cmd.Root().GenBashCompletion(os.Stdout)
+				return cmd.Root().GenBashCompletion(out)
+			case "fish":
+				return cmd.Root().GenFishCompletion(out, true)
+			case "powershell":
+				return cmd.Root().GenPowerShellCompletion(out)
 			}
 
-			return fmt.Errorf("unknown shell requested")
+			return fmt.Errorf("unknown shell %q requested, use bash, zsh, fish, or powershell", args[0])
 		},
 	}
 
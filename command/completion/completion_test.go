@@ -0,0 +1,47 @@
+package completion
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestCompletionGeneratesScripts(t *testing.T) {
+	tests := []struct {
+		name    string
+		shell   string
+		wantErr bool
+	}{
+		{name: "bash", shell: "bash"},
+		{name: "zsh", shell: "zsh"},
+		{name: "fish", shell: "fish"},
+		{name: "powershell", shell: "powershell"},
+		{name: "unknown shells return an error", shell: "tcsh", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the scripts are generated from the root command
+			root := &cobra.Command{Use: "nitro"}
+			root.AddCommand(NewCommand())
+
+			out := &bytes.Buffer{}
+			root.SetOut(out)
+			root.SetErr(&bytes.Buffer{})
+			root.SetArgs([]string{"completion", tt.shell})
+
+			err := root.Execute()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if out.Len() == 0 {
+				t.Errorf("expected the %s completion script to not be empty", tt.shell)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package composer
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"os"
@@ -17,7 +16,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/craftcms/nitro/pkg/composer"
+	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/imagepull"
 	"github.com/craftcms/nitro/pkg/pathexists"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/craftcms/nitro/pkg/volumename"
@@ -37,7 +38,7 @@ const exampleText = `  # run composer install in a current directory using a con
 // NewCommand returns a new command that runs composer install or update for a directory.
 // This command allows users to skip installing composer on the host machine and will run
 // all the commands in a disposable docker container.
-func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:                "composer",
 		Short:              "Runs a Composer command.",
@@ -100,14 +101,8 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 
 			// if we don't have the image, pull it
 			if len(images) == 0 {
-				rdr, err := docker.ImagePull(ctx, image, types.ImagePullOptions{All: false})
-				if err != nil {
-					return fmt.Errorf("unable to pull the docker image, %w", err)
-				}
-
-				buf := &bytes.Buffer{}
-				if _, err := buf.ReadFrom(rdr); err != nil {
-					return fmt.Errorf("unable to read the output from pulling the image, %w", err)
+				if err := imagepull.Pull(ctx, docker, image); err != nil {
+					return err
 				}
 			}
 
@@ -165,6 +160,12 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 				pathVolume = volume
 			}
 
+			// run as the host user when the option is enabled, the config is optional
+			var user string
+			if cfg, err := config.Load(home); err == nil {
+				user = cfg.ContainerUser()
+			}
+
 			// build the container options
 			opts := &composer.Options{
 				Image:    image,
@@ -176,6 +177,7 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 				},
 				Volume: &pathVolume,
 				Path:   path,
+				User:   user,
 				NetworkConfig: &network.NetworkingConfig{
 					EndpointsConfig: map[string]*network.EndpointSettings{
 						"nitro-network": {
@@ -0,0 +1,28 @@
+package configuration
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+const exampleText = `  # restore a previous version of the config
+  nitro config restore`
+
+// NewCommand returns the commands for managing the config file.
+func NewCommand(home string, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "config",
+		Short:   "Manages the config file.",
+		Example: exampleText,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		restoreCommand(home, output),
+	)
+
+	return cmd
+}
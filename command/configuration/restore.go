@@ -0,0 +1,104 @@
+package configuration
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/prompt"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+const restoreExampleText = `  # select a previous version of the config to restore
+  nitro config restore
+
+  # restore a specific backup
+  nitro config restore nitro-20210301-101500.000000000.yaml`
+
+func restoreCommand(home string, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "restore",
+		Short:   "Restores a previous config.",
+		Args:    cobra.MaximumNArgs(1),
+		Example: restoreExampleText,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			backups, err := config.Backups(home)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			var options []string
+			for _, b := range backups {
+				options = append(options, filepath.Base(b))
+			}
+
+			return options, cobra.ShellCompDirectiveNoFileComp
+		},
+		PostRunE: func(cmd *cobra.Command, args []string) error {
+			return prompt.RunApply(cmd, args, false, output)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			backups, err := config.Backups(home)
+			if err != nil {
+				return err
+			}
+
+			if len(backups) == 0 {
+				return fmt.Errorf("there are no backups of the config to restore")
+			}
+
+			var backup string
+			switch len(args) {
+			case 0:
+				var options []string
+				for _, b := range backups {
+					options = append(options, backupName(b))
+				}
+
+				selected, err := output.Select(cmd.InOrStdin(), "Select a config to restore: ", options)
+				if err != nil {
+					return err
+				}
+
+				backup = backups[selected]
+			default:
+				for _, b := range backups {
+					if filepath.Base(b) == args[0] {
+						backup = b
+					}
+				}
+
+				if backup == "" {
+					return fmt.Errorf("unable to find the backup %q", args[0])
+				}
+			}
+
+			if err := config.Restore(home, backup); err != nil {
+				return err
+			}
+
+			output.Info("Restored the config from", backupName(backup))
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// backupName returns the time the backup was created, or the file name if
+// the time can't be parsed.
+func backupName(backup string) string {
+	name := filepath.Base(backup)
+
+	t, err := time.ParseInLocation("20060102-150405.000000000", strings.TrimSuffix(strings.TrimPrefix(name, "nitro-"), ".yaml"), time.Local)
+	if err != nil {
+		return name
+	}
+
+	return fmt.Sprintf("%s (%s)", t.Format("Jan 2, 2006 3:04:05 PM"), name)
+}
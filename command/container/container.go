@@ -1,9 +1,16 @@
 package container
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 
+	"github.com/craftcms/nitro/pkg/containerlabels"
 	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
@@ -15,7 +22,13 @@ const exampleText = `  # manage custom containers
   nitro container new
 
   # ssh into a custom container
-  nitro container ssh`
+  nitro container ssh
+
+  # view the logs for a custom container
+  nitro container logs
+
+  # run a command in a custom container
+  nitro container exec my-container -- ls -la`
 
 func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
@@ -34,7 +47,58 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		newCommand(home, docker, output),
 		sshCommand(home, docker, output),
 		removeCommand(home, docker, output),
+		logsCommand(docker, output),
+		execCommand(docker, output),
 	)
 
 	return cmd
 }
+
+// findContainer takes an optional name for a custom container and returns the container. If
+// the name is empty, the user is prompted to select one of the custom containers.
+func findContainer(cmd *cobra.Command, docker client.CommonAPIClient, output terminal.Outputer, name string) (*types.Container, error) {
+	// add filters to show only the custom containers
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro)
+	filter.Add("label", containerlabels.Type+"=custom")
+
+	// if we have the name, only look for that container
+	if name != "" {
+		filter.Add("label", containerlabels.NitroContainer+"="+name)
+	}
+
+	// get a list of all the containers
+	containers, err := docker.ContainerList(cmd.Context(), types.ContainerListOptions{Filters: filter, All: true})
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(containers) {
+	case 0:
+		if name != "" {
+			return nil, fmt.Errorf("unable to find the custom container %s", name)
+		}
+
+		return nil, fmt.Errorf("there are no custom containers")
+	case 1:
+		return &containers[0], nil
+	}
+
+	// sort containers by the name
+	sort.SliceStable(containers, func(i, j int) bool {
+		return containers[i].Names[0] < containers[j].Names[0]
+	})
+
+	var containerList []string
+	for _, c := range containers {
+		containerList = append(containerList, strings.TrimLeft(c.Names[0], "/"))
+	}
+
+	// prompt for the container
+	selected, err := output.Select(cmd.InOrStdin(), "Select a container: ", containerList)
+	if err != nil {
+		return nil, err
+	}
+
+	return &containers[selected], nil
+}
@@ -0,0 +1,106 @@
+package container
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+var execExampleText = `  # run a command in a custom container
+  nitro container exec -- ls -la
+
+  # run a command in a custom container by name
+  nitro container exec my-container -- env`
+
+func execCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "exec [name] -- <command>",
+		Short:   "Runs a command in a custom container.",
+		Example: execExampleText,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if cmd.ArgsLenAtDash() == -1 || len(args) == cmd.ArgsLenAtDash() {
+				return fmt.Errorf("a command to run is required after --")
+			}
+
+			if cmd.ArgsLenAtDash() > 1 {
+				return fmt.Errorf("only one container name is allowed")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// split the container name and the command
+			var name string
+			if cmd.ArgsLenAtDash() == 1 {
+				name = args[0]
+			}
+			commands := args[cmd.ArgsLenAtDash():]
+
+			container, err := findContainer(cmd, docker, output, name)
+			if err != nil {
+				return err
+			}
+
+			if container.State != "running" {
+				return fmt.Errorf("the container %s is not running", strings.TrimLeft(container.Names[0], "/"))
+			}
+
+			// create the exec
+			e, err := docker.ContainerExecCreate(cmd.Context(), container.ID, types.ExecConfig{
+				AttachStdout: true,
+				AttachStderr: true,
+				Tty:          false,
+				Cmd:          commands,
+			})
+			if err != nil {
+				return err
+			}
+
+			// attach to the container
+			resp, err := docker.ContainerExecAttach(cmd.Context(), e.ID, types.ExecStartCheck{
+				Tty: false,
+			})
+			if err != nil {
+				return err
+			}
+			defer resp.Close()
+
+			// start the exec
+			if err := docker.ContainerExecStart(cmd.Context(), e.ID, types.ExecStartCheck{}); err != nil {
+				return fmt.Errorf("unable to start the container, %w", err)
+			}
+
+			// show the output
+			if _, err := stdcopy.StdCopy(cmd.OutOrStdout(), cmd.ErrOrStderr(), resp.Reader); err != nil {
+				return err
+			}
+
+			// wait for the container exec to complete
+			waiting := true
+			exitCode := 0
+			for waiting {
+				resp, err := docker.ContainerExecInspect(cmd.Context(), e.ID)
+				if err != nil {
+					return err
+				}
+
+				waiting = resp.Running
+				exitCode = resp.ExitCode
+			}
+
+			if exitCode != 0 {
+				return fmt.Errorf("command exited with code %d", exitCode)
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,83 @@
+package container
+
+import (
+	"strconv"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+var logsExampleText = `  # view the logs for a custom container
+  nitro container logs
+
+  # view the logs for a custom container by name
+  nitro container logs my-container
+
+  # show only the last 5 minutes and don't follow
+  nitro container logs my-container --since 5m --follow=false`
+
+func logsCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "logs [name]",
+		Short:   "Displays custom container logs.",
+		Example: logsExampleText,
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var name string
+			if len(args) > 0 {
+				name = args[0]
+			}
+
+			container, err := findContainer(cmd, docker, output, name)
+			if err != nil {
+				return err
+			}
+
+			// set the options for logging based on the command flags
+			opts := types.ContainerLogsOptions{
+				ShowStdout: true,
+				ShowStderr: true,
+			}
+
+			// parse the flags
+			timestamps, err := strconv.ParseBool(cmd.Flag("timestamps").Value.String())
+			if err != nil {
+				timestamps = false
+			}
+			opts.Timestamps = timestamps
+
+			follow, err := strconv.ParseBool(cmd.Flag("follow").Value.String())
+			if err != nil {
+				follow = true
+			}
+			opts.Follow = follow
+
+			if cmd.Flag("since").Value.String() != "" {
+				opts.Since = cmd.Flag("since").Value.String()
+			}
+
+			// get the containers logs
+			out, err := docker.ContainerLogs(cmd.Context(), container.ID, opts)
+			if err != nil {
+				return err
+			}
+			defer out.Close()
+
+			// show the output
+			_, err = stdcopy.StdCopy(cmd.OutOrStdout(), cmd.ErrOrStderr(), out)
+
+			return err
+		},
+	}
+
+	// set flags for the command
+	cmd.Flags().Bool("follow", true, "follow log output")
+	cmd.Flags().Bool("timestamps", false, "show timestamps")
+	cmd.Flags().String("since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37Z) or relative (e.g. 42m for 42 minutes)")
+
+	return cmd
+}
@@ -1,7 +1,6 @@
 package container
 
 import (
-	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/imagepull"
 	"github.com/craftcms/nitro/pkg/portavail"
 	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
@@ -92,23 +92,10 @@ func newCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				ref = fmt.Sprintf("docker.io/library/%s:%s", image, tag)
 			}
 
-			output.Pending("downloading", ref)
-
 			// pull the image
-			rc, err := docker.ImagePull(cmd.Context(), ref, types.ImagePullOptions{All: false})
-			if err != nil {
-				output.Warning()
-
+			if err := imagepull.PullWithProgress(cmd.Context(), docker, output, ref); err != nil {
 				return err
 			}
-			defer rc.Close()
-
-			buf := &bytes.Buffer{}
-			if _, err := buf.ReadFrom(rc); err != nil {
-				output.Warning()
-				return fmt.Errorf("unable to read the output from pulling the image, %w", err)
-			}
-			output.Done()
 
 			// inspect the recently pulled image
 			imageSpecs, _, err := docker.ImageInspectWithRaw(cmd.Context(), ref)
@@ -0,0 +1,125 @@
+package cp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containercopy"
+	"github.com/craftcms/nitro/pkg/containerfind"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+const exampleText = `  # copy a file into a sites container
+  nitro cp ./fixtures.sql tutorial.nitro:/app/storage
+
+  # copy a directory from a sites container
+  nitro cp tutorial.nitro:/app/storage/logs ./logs`
+
+// NewCommand returns the command to copy files or directories between the host and a sites
+// container. The container path is prefixed with the sites hostname (e.g. tutorial.nitro:/app).
+func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "cp <src> <dest>",
+		Short:   "Copies files to or from a site.",
+		Args:    cobra.ExactArgs(2),
+		Example: exampleText,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			cfg, err := config.Load(home)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+
+			var options []string
+			for _, s := range cfg.Sites {
+				options = append(options, s.Hostname+":/app/")
+			}
+
+			return options, cobra.ShellCompDirectiveDefault | cobra.ShellCompDirectiveNoSpace
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			// load the config
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			sites := make(map[string]bool)
+			for _, s := range cfg.Sites {
+				sites[s.Hostname] = true
+			}
+
+			srcSite, src := split(args[0], sites)
+			dstSite, dst := split(args[1], sites)
+
+			switch {
+			case srcSite != "" && dstSite != "":
+				return fmt.Errorf("copying between sites is not supported")
+			case srcSite == "" && dstSite == "":
+				return fmt.Errorf("one of the paths must be a site path, e.g. tutorial.nitro:/app")
+			}
+
+			hostname := srcSite
+			if hostname == "" {
+				hostname = dstSite
+			}
+
+			// find the sites container
+			c, err := containerfind.Site(ctx, docker, hostname)
+			if errors.Is(err, containerfind.ErrNotFound) {
+				return fmt.Errorf("unable to find the container for %s, run `nitro apply` to create it", hostname)
+			}
+			if err != nil {
+				return err
+			}
+
+			if c.State != "running" {
+				return fmt.Errorf("the container for %s is not running, run `nitro start %s`", hostname, hostname)
+			}
+
+			var n int64
+			switch srcSite {
+			case "":
+				output.Pending("copying", src, "to", args[1])
+
+				n, err = containercopy.To(ctx, docker, c.ID, src, dst)
+			default:
+				output.Pending("copying", args[0], "to", dst)
+
+				n, err = containercopy.From(ctx, docker, c.ID, src, dst)
+			}
+			if err != nil {
+				output.Warning()
+
+				return err
+			}
+
+			output.Done()
+
+			output.Info("Copied", units.HumanSize(float64(n)))
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// split takes a path argument and returns the site hostname and path when the argument
+// is prefixed with a known site (e.g. tutorial.nitro:/app), otherwise it returns the
+// argument as a path on the host.
+func split(arg string, sites map[string]bool) (string, string) {
+	i := strings.Index(arg, ":")
+	if i <= 0 || !sites[arg[:i]] {
+		return "", arg
+	}
+
+	return arg[:i], arg[i+1:]
+}
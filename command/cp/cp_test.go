@@ -0,0 +1,47 @@
+package cp
+
+import "testing"
+
+func Test_split(t *testing.T) {
+	sites := map[string]bool{"tutorial.nitro": true}
+
+	tests := []struct {
+		name     string
+		arg      string
+		wantSite string
+		wantPath string
+	}{
+		{
+			name:     "site paths return the hostname and path",
+			arg:      "tutorial.nitro:/app/storage",
+			wantSite: "tutorial.nitro",
+			wantPath: "/app/storage",
+		},
+		{
+			name:     "host paths return the path",
+			arg:      "./fixtures.sql",
+			wantPath: "./fixtures.sql",
+		},
+		{
+			name:     "unknown sites are treated as host paths",
+			arg:      "unknown.nitro:/app",
+			wantPath: "unknown.nitro:/app",
+		},
+		{
+			name:     "windows paths are host paths",
+			arg:      `C:\Users\nitro\fixtures.sql`,
+			wantPath: `C:\Users\nitro\fixtures.sql`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site, path := split(tt.arg, sites)
+			if site != tt.wantSite {
+				t.Errorf("split() site = %v, want %v", site, tt.wantSite)
+			}
+			if path != tt.wantPath {
+				t.Errorf("split() path = %v, want %v", path, tt.wantPath)
+			}
+		})
+	}
+}
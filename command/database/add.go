@@ -20,13 +20,34 @@ import (
 )
 
 var addExampleTest = `  # add a new database
-  nitro db add`
+  nitro db add
+
+  # add a new database with a user that can only access the database
+  nitro db add --user craft`
+
+var (
+	addUserFlag     string
+	addPasswordFlag string
+)
 
 func addCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "add",
 		Short:   "Adds a new database.",
 		Example: addExampleTest,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if addUserFlag != "" {
+				if err := (&validate.DatabaseUser{}).Validate(addUserFlag); err != nil {
+					return err
+				}
+			}
+
+			if addPasswordFlag != "" && addUserFlag == "" {
+				return fmt.Errorf("the --password flag requires the --user flag")
+			}
+
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// add filters to show only the environment and database containers
 			filter := filters.NewArgs()
@@ -68,14 +89,22 @@ func addCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, output
 				return err
 			}
 
+			// ask for the password of the user to create
+			password := addPasswordFlag
+			if addUserFlag != "" && password == "" {
+				password, err = output.AskSecret(fmt.Sprintf("Enter the password for %s", addUserFlag), ":", &validate.Password{})
+				if err != nil {
+					return err
+				}
+			}
+
 			output.Pending("creating database", db)
 
 			// wait for the api to be ready
-			for {
-				_, err := nitrod.Ping(cmd.Context(), &protob.PingRequest{})
-				if err == nil {
-					break
-				}
+			if err := waitForAPI(cmd.Context(), nitrod, apiTimeout); err != nil {
+				output.Warning()
+
+				return err
 			}
 
 			// get the containers details
@@ -100,6 +129,8 @@ func addCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, output
 					Version:  version,
 					Port:     port,
 					Database: db,
+					User:     addUserFlag,
+					Password: password,
 				},
 			})
 			// check if the error code is unimplemented
@@ -140,5 +171,8 @@ func addCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, output
 		},
 	}
 
+	cmd.Flags().StringVar(&addUserFlag, "user", "", "Create a user with all privileges on the new database")
+	cmd.Flags().StringVar(&addPasswordFlag, "password", "", "The password for the user, prompts when not set")
+
 	return cmd
 }
@@ -1,7 +1,9 @@
 package database
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -18,13 +20,30 @@ import (
 	"github.com/craftcms/nitro/pkg/containerlabels"
 	"github.com/craftcms/nitro/pkg/datetime"
 	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/pkg/validate"
+	"github.com/craftcms/nitro/protob"
 )
 
 var backupExampleText = `  # backup a database
-  nitro db backup`
+  nitro db backup
+
+  # backup the rows updated in the last 7 days (mysql and mariadb only)
+  nitro db backup --since 7d
+
+  # backup the rows updated since a date using a specific timestamp column
+  nitro db backup --since 2021-03-01 --since-column updated_at
+
+  # backup a database that is only reachable from the proxy container
+  nitro db backup --remote postgres://craft@db.internal:5432/craft`
+
+var (
+	sinceFlag        string
+	sinceColumnFlag  string
+	backupRemoteFlag string
+)
 
 // backupCommand is the command for backing up an individual database or
-func backupCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+func backupCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "backup",
 		Short:   "Backs up a database.",
@@ -32,6 +51,31 @@ func backupCommand(home string, docker client.CommonAPIClient, output terminal.O
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 
+			// parse the time for incremental backups before prompting
+			var since time.Time
+			if sinceFlag != "" {
+				t, err := datetime.ParseSince(sinceFlag, time.Now())
+				if err != nil {
+					return err
+				}
+
+				since = t
+			}
+
+			// remote databases are backed up by the proxy, which can reach databases the host can't
+			if backupRemoteFlag != "" {
+				if sinceFlag != "" {
+					return fmt.Errorf("the --remote and --since flags cannot be used together")
+				}
+
+				remote, err := parseRemote(backupRemoteFlag)
+				if err != nil {
+					return fmt.Errorf("invalid --remote, %w", err)
+				}
+
+				return backupRemote(ctx, home, nitrod, remote, output)
+			}
+
 			// add filters to show only the environment and database containers
 			filter := filters.NewArgs()
 			filter.Add("label", containerlabels.Nitro)
@@ -74,8 +118,30 @@ func backupCommand(home string, docker client.CommonAPIClient, output terminal.O
 			}
 
 			// create the backup command based on the compatibility type
-			switch compatibility {
-			case "postgres":
+			switch {
+			case !since.IsZero():
+				if compatibility == "postgres" {
+					return fmt.Errorf("incremental backups are only supported for mysql and mariadb databases")
+				}
+
+				// get the credentials from the container
+				info, err := docker.ContainerInspect(ctx, containerID)
+				if err != nil {
+					return err
+				}
+
+				column, tables, err := backup.IncrementalTables(ctx, docker, containerID, db, sinceColumnFlag, info.Config.Env)
+				if err != nil {
+					return err
+				}
+
+				output.Info(fmt.Sprintf("Backing up %d tables with rows where %s is after %s", len(tables), column, since.Format("2006-01-02 15:04:05")))
+
+				opts.BackupName = fmt.Sprintf("%s-%s-incremental.sql", db, datetime.Parse(time.Now()))
+				user, env := backup.IncrementalCredentials(info.Config.Env)
+				opts.Commands = backup.IncrementalCommands(db, opts.BackupName, column, user, since, tables)
+				opts.Env = env
+			case compatibility == "postgres":
 				opts.Commands = []string{"pg_dump", "--username=nitro", db, "-f", "/tmp/" + opts.BackupName}
 			default:
 				opts.Commands = []string{"mysqldump", "--user=nitro", "-pnitro", db, "--result-file=" + "/tmp/" + opts.BackupName}
@@ -98,5 +164,99 @@ func backupCommand(home string, docker client.CommonAPIClient, output terminal.O
 		},
 	}
 
+	cmd.Flags().StringVar(&sinceFlag, "since", "", "Only backup the rows updated since a duration (e.g. 7d) or date (e.g. 2021-03-01)")
+	cmd.Flags().StringVar(&backupRemoteFlag, "remote", "", "The url of a database that is not a nitro container to backup through the proxy, the password can be set with NITRO_REMOTE_PASSWORD (e.g. mysql://user@db.example.com:3306/craft)")
+	cmd.Flags().StringVar(&sinceColumnFlag, "since-column", "", "The timestamp column used with --since (default is dateUpdated, updated_at, date_updated, or modified)")
+
 	return cmd
 }
+
+// backupRemote streams a backup of the remote database from the proxy, using the export
+// tools in the proxy container, and saves it in the backups directory for the host.
+func backupRemote(ctx context.Context, home string, nitrod protob.NitroClient, remote *remoteDatabase, output terminal.Outputer) error {
+	db := remote.Database
+	if db == "" {
+		input, err := output.Ask("Enter the database name", "", ":", &validate.DatabaseName{})
+		if err != nil {
+			return err
+		}
+
+		db = input
+	}
+
+	// wait for the api to be ready
+	if err := waitForAPI(ctx, nitrod, apiTimeout); err != nil {
+		return err
+	}
+
+	stream, err := nitrod.ExportDatabase(ctx, &protob.ExportDatabaseRequest{
+		Database: &protob.DatabaseInfo{
+			Engine:   remote.Engine,
+			Hostname: remote.Hostname,
+			Port:     remote.Port,
+			Database: db,
+			User:     remote.User,
+			Password: remote.Password,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to backup the database, %w", err)
+	}
+
+	// the details of the backup are sent before the data
+	if _, err := stream.Recv(); err != nil {
+		return fmt.Errorf("unable to backup the database, %w", err)
+	}
+
+	dir := filepath.Join(home, config.DirectoryName, "backups", remote.Hostname)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.sql", db, datetime.Parse(time.Now()))
+
+	output.Pending("creating backup", name)
+
+	if err := saveStream(stream, filepath.Join(dir, name)); err != nil {
+		output.Warning()
+
+		return fmt.Errorf("unable to backup the database, %w", err)
+	}
+
+	output.Done()
+
+	output.Info("Backup saved in", dir, "💾")
+
+	return nil
+}
+
+// saveStream writes the data from the export stream to the file, the file is removed
+// when the stream returns an error so an incomplete backup is not kept.
+func saveStream(stream protob.Nitro_ExportDatabaseClient, file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			f.Close()
+			os.Remove(file)
+
+			return err
+		}
+
+		if _, err := f.Write(resp.GetData()); err != nil {
+			f.Close()
+			os.Remove(file)
+
+			return err
+		}
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
+)
+
+// fakeExportStream returns the responses and then the error.
+type fakeExportStream struct {
+	protob.Nitro_ExportDatabaseClient
+	responses []*protob.ExportDatabaseResponse
+	err       error
+}
+
+func (s *fakeExportStream) Recv() (*protob.ExportDatabaseResponse, error) {
+	if len(s.responses) == 0 {
+		return nil, s.err
+	}
+
+	resp := s.responses[0]
+	s.responses = s.responses[1:]
+
+	return resp, nil
+}
+
+// fakeExportNitro returns the stream for the export and keeps the request.
+type fakeExportNitro struct {
+	fakeNitro
+	stream  *fakeExportStream
+	request *protob.ExportDatabaseRequest
+}
+
+func (n *fakeExportNitro) ExportDatabase(ctx context.Context, in *protob.ExportDatabaseRequest, opts ...grpc.CallOption) (protob.Nitro_ExportDatabaseClient, error) {
+	n.request = in
+
+	return n.stream, nil
+}
+
+func TestBackupRemote(t *testing.T) {
+	remote := &remoteDatabase{Engine: "mysql", Hostname: "db.internal", Port: "3306", User: "craft", Password: "secret", Database: "craft"}
+
+	responses := func() []*protob.ExportDatabaseResponse {
+		return []*protob.ExportDatabaseResponse{
+			{Payload: &protob.ExportDatabaseResponse_Database{Database: &protob.DatabaseInfo{Engine: "mysql", Database: "craft"}}},
+			{Payload: &protob.ExportDatabaseResponse_Data{Data: []byte("CREATE TABLE users;\n")}},
+			{Payload: &protob.ExportDatabaseResponse_Data{Data: []byte("INSERT INTO users;\n")}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		want     string
+		wantFile bool
+	}{
+		{
+			name:     "the backup is saved in the directory for the host",
+			err:      io.EOF,
+			want:     "CREATE TABLE users;\nINSERT INTO users;\n",
+			wantFile: true,
+		},
+		{
+			name: "incomplete backups are removed",
+			err:  errors.New("the export tool exited"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home, err := ioutil.TempDir("", "nitro-backup")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(home)
+
+			nitrod := &fakeExportNitro{fakeNitro: fakeNitro{ready: 1}, stream: &fakeExportStream{responses: responses(), err: tt.err}}
+
+			err = backupRemote(context.Background(), home, nitrod, remote, terminal.NewWithWriter(ioutil.Discard))
+			if tt.wantFile && err != nil {
+				t.Fatal(err)
+			}
+			if !tt.wantFile && err == nil {
+				t.Fatal("expected an error")
+			}
+
+			if nitrod.request.GetDatabase().GetHostname() != "db.internal" || nitrod.request.GetDatabase().GetUser() != "craft" {
+				t.Errorf("expected the remote database to be exported, got %v", nitrod.request.GetDatabase())
+			}
+
+			files, _ := filepath.Glob(filepath.Join(home, config.DirectoryName, "backups", "db.internal", "craft-*.sql"))
+			if !tt.wantFile {
+				if len(files) != 0 {
+					t.Errorf("expected the incomplete backup to be removed, got %v", files)
+				}
+
+				return
+			}
+
+			if len(files) != 1 {
+				t.Fatalf("expected one backup, got %v", files)
+			}
+
+			data, err := ioutil.ReadFile(files[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(data) != tt.want {
+				t.Errorf("expected the backup to be %q, got %q", tt.want, string(data))
+			}
+		})
+	}
+}
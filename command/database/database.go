@@ -1,6 +1,10 @@
 package database
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 
@@ -15,7 +19,16 @@ const exampleText = `  # import a database from a backup
   nitro db backup
 
   # add a new database
-  nitro db add`
+  nitro db add
+
+  # list the databases in each engine
+  nitro db list
+
+  # reset a database and import a fresh backup
+  nitro db reset --file backup.sql`
+
+// apiTimeout is how long to wait for the api to be ready before managing a database.
+var apiTimeout = 30 * time.Second
 
 // NewCommand returns the db commands for importing, backing up, and adding databases
 func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
@@ -31,14 +44,35 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 
 	cmd.AddCommand(
 		importCommand(home, docker, nitrod, output),
-		backupCommand(home, docker, output),
+		backupCommand(home, docker, nitrod, output),
 		addCommand(docker, nitrod, output),
 		sshCommand(home, docker, output),
 		shellCommand(home, docker, output),
 		removeCommand(docker, nitrod, output),
+		resetCommand(home, docker, nitrod, output),
 		newCommand(home, docker, output),
 		destroyCommand(home, docker, output),
+		listCommand(docker, output),
 	)
 
 	return cmd
 }
+
+// waitForAPI pings the api until it is ready and returns an error if it is not ready
+// before the timeout.
+func waitForAPI(ctx context.Context, nitrod protob.NitroClient, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		if _, err := nitrod.Ping(ctx, &protob.PingRequest{}); err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for the api to be ready after %s, check the proxy container is running", timeout)
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
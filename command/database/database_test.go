@@ -0,0 +1,97 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/craftcms/nitro/protob"
+)
+
+// fakeNitro fails to ping until the api has been pinged ready times.
+type fakeNitro struct {
+	protob.NitroClient
+	ready int
+	pings int
+}
+
+func (n *fakeNitro) Ping(ctx context.Context, in *protob.PingRequest, opts ...grpc.CallOption) (*protob.PingResponse, error) {
+	n.pings++
+
+	if n.pings < n.ready {
+		return nil, errors.New("connection refused")
+	}
+
+	return &protob.PingResponse{Pong: "pong"}, nil
+}
+
+func TestWaitForAPI(t *testing.T) {
+	tests := []struct {
+		name    string
+		ready   int
+		wantErr bool
+	}{
+		{
+			name:  "returns when the api is ready",
+			ready: 1,
+		},
+		{
+			name:  "waits for the api to be ready",
+			ready: 3,
+		},
+		{
+			name:    "times out when the api is never ready",
+			ready:   1000,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nitrod := &fakeNitro{ready: tt.ready}
+
+			err := waitForAPI(context.Background(), nitrod, 2*time.Second)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("waitForAPI() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr && !strings.Contains(err.Error(), "timed out") {
+				t.Errorf("expected a timeout error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestUserDatabases(t *testing.T) {
+	tests := []struct {
+		name      string
+		databases []string
+		want      []string
+	}{
+		{
+			name:      "mysql system databases are removed",
+			databases: []string{"information_schema", "project", "mysql", "performance_schema", "sys", "craft"},
+			want:      []string{"craft", "project"},
+		},
+		{
+			name:      "postgres system databases are removed",
+			databases: []string{"postgres", "template0", "template1", "nitro"},
+			want:      []string{"nitro"},
+		},
+		{
+			name:      "engines without databases return nothing",
+			databases: []string{"postgres", ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := userDatabases(tt.databases); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("userDatabases() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
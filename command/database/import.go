@@ -2,25 +2,32 @@ package database
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net/url"
 	"os"
-	"sort"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerfind"
 	"github.com/craftcms/nitro/pkg/containerlabels"
 	"github.com/craftcms/nitro/pkg/database"
+	"github.com/craftcms/nitro/pkg/downloader"
 	"github.com/craftcms/nitro/pkg/filetype"
+	"github.com/craftcms/nitro/pkg/importhistory"
 	"github.com/craftcms/nitro/pkg/pathexists"
+	"github.com/craftcms/nitro/pkg/tempdir"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/craftcms/nitro/pkg/validate"
 	"github.com/craftcms/nitro/protob"
@@ -33,9 +40,89 @@ var importExampleText = `  # import a sql file into a database
   nitro db import ~/Desktop/backup.sql
 
   # use an absolute path
-  nitro db import /Users/oli/Desktop/backup.sql`
+  nitro db import /Users/oli/Desktop/backup.sql
 
-var nameFlag string
+  # download and import a backup from a url
+  nitro db import https://example.com/backups/backup.sql.gz
+
+  # download a backup using basic auth (or set NITRO_IMPORT_USER)
+  nitro db import https://example.com/backups/backup.sql.gz --user username:password
+
+  # check the backup for errors without changing any databases
+  nitro db import backup.sql --validate
+
+  # update the table statistics after importing
+  nitro db import backup.sql --analyze
+
+  # keep the uploaded backup in the proxy container for debugging
+  nitro db import backup.sql --keep-upload
+
+  # import into the postgres engine without prompting (or set NITRO_DEFAULT_DB_ENGINE)
+  nitro db import backup.sql --engine postgres
+
+  # restore a postgres custom format archive (pg_dump -Fc) using 4 parallel jobs
+  nitro db import backup.dump --jobs 4
+
+  # stage large backups outside of a small /tmp (or set NITRO_TMP_DIR)
+  nitro db import backup.sql --tmp-dir ~/tmp --proxy-tmp-dir /data
+
+  # import a backup again without checking if it was recently imported
+  nitro db import backup.sql --force
+
+  # import into a throwaway container with the same engine and version
+  nitro db import backup.sql --scratch
+
+  # skip the statements that fail and report the number of errors
+  nitro db import backup.sql --on-error continue
+
+  # stop a postgres import at the first error
+  nitro db import backup.sql --on-error stop
+
+  # show the output, including warnings, from the import tool
+  nitro db import backup.sql --show-output
+
+  # replace the prod_ table prefix with craft_ (a textual rewrite of plain sql backups)
+  nitro db import backup.sql --from-prefix prod_ --to-prefix craft_
+
+  # import a backup that selects the production database into the craft database
+  nitro db import backup.sql --rename-from production --rename-to craft
+
+  # import without prompting for the container or database, e.g. in a script
+  nitro db import backup.sql --container mysql-8.0-3306.database.nitro --database craft
+
+  # import a backup without the sessions and search index tables
+  nitro db import backup.sql --exclude-table '*_sessions' --exclude-table '*_searchindex'
+
+  # import into a database that is not a nitro container using the local mysql or psql client
+  nitro db import backup.sql --remote mysql://craft@db.example.com:3306/craft
+
+  # import into a database server on this machine using its socket
+  nitro db import backup.sql --remote 'mysql://root@localhost/craft?socket=/tmp/mysql.sock'`
+
+var (
+	engineFlag      string
+	nameFlag        string
+	userFlag        string
+	tmpDirFlag      string
+	proxyTmpDirFlag string
+	jobsFlag        int
+	analyzeFlag     bool
+	keepUploadFlag  bool
+	validateFlag    bool
+	forceFlag       bool
+	scratchFlag     bool
+	onErrorFlag     string
+	showOutputFlag  bool
+	fromPrefixFlag  string
+	toPrefixFlag    string
+	renameFromFlag  string
+	renameToFlag    string
+	timeoutFlag     time.Duration
+	excludeFlag     []string
+	containerFlag   string
+	databaseFlag    string
+	remoteFlag      string
+)
 
 // importCommand is the command for creating new development environments
 func importCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
@@ -52,10 +139,99 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 			return nil
 		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			return []string{"sql", "gz", "zip"}, cobra.ShellCompDirectiveFilterFileExt
+			return []string{"sql", "gz", "zip", "bz2", "xz", "dump"}, cobra.ShellCompDirectiveFilterFileExt
 		},
 		Example: importExampleText,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// make sure the default engine is known
+			if _, err := defaultEngine(home); err != nil {
+				return err
+			}
+
+			if validateFlag && scratchFlag {
+				return fmt.Errorf("the --validate and --scratch flags cannot be used together")
+			}
+
+			switch onErrorFlag {
+			case "", database.OnErrorStop, database.OnErrorContinue:
+			default:
+				return fmt.Errorf("on-error must be %s or %s, got %q", database.OnErrorStop, database.OnErrorContinue, onErrorFlag)
+			}
+
+			// the new prefix can be empty to remove the prefix
+			if fromPrefixFlag != "" || cmd.Flags().Changed("to-prefix") {
+				if err := database.ValidatePrefixes(fromPrefixFlag, toPrefixFlag); err != nil {
+					return fmt.Errorf("invalid --from-prefix or --to-prefix, %w", err)
+				}
+			}
+
+			if err := database.ValidateExcludeTables(excludeFlag); err != nil {
+				return fmt.Errorf("invalid --exclude-table, %w", err)
+			}
+
+			// the database flag is used to import without prompting
+			if databaseFlag != "" {
+				if nameFlag != "" && nameFlag != databaseFlag {
+					return fmt.Errorf("the --name and --database flags must be the same database, got %q and %q", nameFlag, databaseFlag)
+				}
+
+				nameFlag = databaseFlag
+			}
+
+			// the database in the backup is renamed to the database being imported into
+			if renameToFlag != "" {
+				if renameFromFlag == "" {
+					return fmt.Errorf("the --rename-to flag requires --rename-from")
+				}
+
+				if nameFlag != "" && nameFlag != renameToFlag {
+					return fmt.Errorf("the --name and --rename-to flags must be the same database, got %q and %q", nameFlag, renameToFlag)
+				}
+
+				nameFlag = renameToFlag
+			}
+
+			// remote databases are imported into from the host, without a container
+			if remoteFlag != "" {
+				if scratchFlag || containerFlag != "" {
+					return fmt.Errorf("the --remote flag cannot be used with --scratch or --container")
+				}
+
+				remote, err := parseRemote(remoteFlag)
+				if err != nil {
+					return fmt.Errorf("invalid --remote, %w", err)
+				}
+
+				if remote.Database != "" && nameFlag != "" && nameFlag != remote.Database {
+					return fmt.Errorf("the --remote url and --name flags must be the same database, got %q and %q", remote.Database, nameFlag)
+				}
+			}
+
+			if renameFromFlag != "" {
+				if err := database.ValidateRenameFrom(renameFromFlag); err != nil {
+					return err
+				}
+			}
+
+			// make sure the jobs are positive
+			if cmd.Flags().Changed("jobs") && jobsFlag < 1 {
+				return fmt.Errorf("jobs must be a positive number, got %d", jobsFlag)
+			}
+
+			if timeoutFlag <= 0 {
+				return fmt.Errorf("timeout must be a positive duration, got %s", timeoutFlag)
+			}
+
+			// make sure the temp directory exists and is writable
+			if err := tempdir.Check(tmpDir(home), 0); err != nil {
+				return err
+			}
+
+			// backups from a url are downloaded first
+			if strings.Contains(args[0], "://") {
+				return validateURL(args[0])
+			}
+
 			// make sure the file exists
 			if exists := pathexists.IsFile(args[0]); !exists {
 				output.Info(cmd.UsageString())
@@ -68,22 +244,61 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// replace the relative path with the full directory
 			path := args[0]
-			if strings.HasPrefix(path, "~") {
+			switch {
+			case strings.Contains(path, "://"):
+				downloaded, err := download(cmd, home, path, output)
+				if err != nil {
+					return err
+				}
+
+				defer os.Remove(downloaded)
+
+				path = downloaded
+			case strings.HasPrefix(path, "~"):
 				path = strings.Replace(path, "~", home, 1)
 			}
 
-			// check if this is a zip file
-			var compressed bool
-			kind, err := filetype.Determine(path)
+			// the api only supports gzip and zip, so bzip2 and xz backups are decompressed first
+			decompressed, decompression, err := database.Decompress(path, tmpDir(home))
 			if err != nil {
 				return err
 			}
 
-			var compressionType string
-			switch kind {
-			case "zip", "tar":
-				compressed = true
-				compressionType = kind
+			if decompression != "" {
+				output.Info("Decompressed", decompression, "backup")
+
+				defer os.Remove(decompressed)
+
+				path = decompressed
+			}
+
+			// converting the encoding copies the backup into the temp directory
+			if encoding, _ := database.DetectEncoding(path); encoding != "" {
+				if stat, err := os.Stat(path); err == nil {
+					if err := tempdir.Check(tmpDir(home), stat.Size()); err != nil {
+						return err
+					}
+				}
+			}
+
+			// convert utf-16 and byte order marked files to plain utf-8
+			converted, encoding, err := database.ConvertToUTF8(path, tmpDir(home))
+			if err != nil {
+				return err
+			}
+
+			if encoding != "" {
+				output.Info("Converted backup from", encoding, "to utf-8")
+
+				defer os.Remove(converted)
+
+				path = converted
+			}
+
+			// check if this is a compressed file
+			compressed, compressionType, err := compression(path, output)
+			if err != nil {
+				return err
 			}
 
 			// detect the type of backup if not compressed
@@ -92,42 +307,59 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 				output.Pending("detecting backup type")
 
 				// determine the database engine
-				detected, err = database.DetermineEngine(path)
-				if errors.Is(err, database.ErrUnknownDatabaseEngine) {
+				detection, err := database.DetermineEngine(path)
+				switch {
+				case errors.Is(err, database.ErrUnknownDatabaseEngine):
 					output.Warning()
 
 					output.Info(strings.Title(err.Error()))
-				} else {
+				case err != nil:
+					output.Warning()
+
+					return err
+				case detection.Ambiguous():
+					output.Warning()
+
+					output.Info(fmt.Sprintf("The backup has both mysql (%d) and postgres (%d) signals", detection.MySQL, detection.Postgres))
+
+					// the engine of the container or remote database is used
+					if containerFlag != "" || remoteFlag != "" {
+						break
+					}
+
+					// ask the user which engine the backup is for
+					engines := []string{"mysql", "postgres"}
+					selected, err := output.Select(os.Stdin, "Which database engine is the backup for? ", engines)
+					if err != nil {
+						return err
+					}
+
+					detected = engines[selected]
+				default:
 					output.Done()
 
+					detected = detection.Engine
+
 					output.Info("Detected", detected, "backup")
 				}
 			}
 
-			// add filters to show only the environment and database containers
-			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
-			filter.Add("label", containerlabels.Type+"=database")
+			// there is no container to import into, so the client on the host is used
+			if remoteFlag != "" {
+				remote, err := parseRemote(remoteFlag)
+				if err != nil {
+					return err
+				}
 
-			// if we detected the engine type, add the compatibility label to the filter
-			switch detected {
-			case "mysql":
-				filter.Add("label", containerlabels.DatabaseCompatibility+"=mysql")
-			case "postgres":
-				filter.Add("label", containerlabels.DatabaseCompatibility+"=postgres")
+				return importRemote(cmd, home, path, compressionType, detected, remote, output)
 			}
 
-			// get a list of all the databases
-			containers, err := docker.ContainerList(cmd.Context(), types.ContainerListOptions{Filters: filter, All: true})
+			// get a list of all the databases, if we detected the engine type only show compatible databases
+			containers, err := containerfind.DatabasesByCompatibility(cmd.Context(), docker, detected)
 			if err != nil {
 				return err
 			}
 
-			// sort containers by the name
-			sort.SliceStable(containers, func(i, j int) bool {
-				return containers[i].Names[0] < containers[j].Names[0]
-			})
-
 			// get all of the containers as a list
 			var options []string
 			for _, c := range containers {
@@ -141,16 +373,57 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 					}
 				}
 
-				options = append(options, strings.TrimLeft(c.Names[0], "/"))
+				options = append(options, containerfind.Name(c))
 			}
 
-			// prompt the user for the engine to import the backup into
-			var containerID string
-			selected, err := output.Select(os.Stdin, "Select a database engine: ", options)
+			// check for a default engine to skip the prompt
+			engine, err := defaultEngine(home)
 			if err != nil {
 				return err
 			}
 
+			var matches []int
+			if engine != "" {
+				for i, c := range containers {
+					if c.Labels[containerlabels.DatabaseEngine] == engine {
+						matches = append(matches, i)
+					}
+				}
+			}
+
+			var containerID string
+			var selected int
+			switch {
+			case containerFlag != "":
+				selected, err = namedContainer(cmd.Context(), docker, containers, containerFlag, detected)
+				if err != nil {
+					return err
+				}
+			case len(matches) == 0:
+				// prompt the user for the engine to import the backup into
+				selected, err = output.Select(os.Stdin, "Select a database engine: ", options)
+				if err != nil {
+					return err
+				}
+			case len(matches) == 1:
+				selected = matches[0]
+
+				output.Info("Using the default", engine, "engine", options[selected])
+			default:
+				// only show the engines matching the default
+				var matched []string
+				for _, i := range matches {
+					matched = append(matched, options[i])
+				}
+
+				s, err := output.Select(os.Stdin, "Select a database engine: ", matched)
+				if err != nil {
+					return err
+				}
+
+				selected = matches[s]
+			}
+
 			// set the container id
 			containerID = containers[selected].ID
 			if containerID == "" {
@@ -160,10 +433,16 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 			validator := &validate.DatabaseName{}
 
 			var db string
-			switch nameFlag == "" {
-			case false:
+			switch {
+			case validateFlag:
+				// validating uses a temporary database
+				output.Info("Validating the backup in a temporary database…")
+			case nameFlag != "":
 				// validate the flag value
 				err := validator.Validate(nameFlag)
+				if err != nil && databaseFlag != "" {
+					return fmt.Errorf("invalid --database, %w", err)
+				}
 				if err != nil {
 					// ask the user for the database to import because the flag was not valid
 					input, err := output.Ask("Enter the database name", "", ":", validator)
@@ -187,7 +466,13 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 				db = input
 			}
 
-			output.Info("Preparing import…")
+			if !validateFlag {
+				output.Info("Preparing import…")
+			}
+
+			if validateFlag && onErrorFlag == database.OnErrorContinue {
+				output.Info("Ignoring --on-error, validating stops at the first error")
+			}
 
 			// get the containers info
 			info, err := docker.ContainerInspect(cmd.Context(), containers[selected].ID)
@@ -197,9 +482,25 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 
 			// get the database compatability from the container labelsmake l
 			detected = info.Config.Labels[containerlabels.DatabaseCompatibility]
+
+			// jobs are only used by pg_restore for custom and directory format archives
+			jobs := jobsFlag
+			switch {
+			case jobs > 0 && detected != "postgres":
+				output.Info("Ignoring --jobs, parallel restores are only supported for postgres archives")
+
+				jobs = 0
+			case jobs > 0 && !compressed && database.ArchiveFormat(path) == "":
+				output.Info("Ignoring --jobs, parallel restores are only supported for custom and directory format archives")
+
+				jobs = 0
+			}
 			hostname := strings.TrimLeft(info.Name, "/")
 			version := info.Config.Labels[containerlabels.DatabaseVersion]
 
+			// use the credentials the container was created with
+			user, password := database.Credentials(detected, info.Config.Env)
+
 			var port string
 			// get the port from the container info
 			for p, bind := range info.HostConfig.PortBindings {
@@ -210,7 +511,77 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 				}
 			}
 
-			stream, err := nitrod.ImportDatabase(cmd.Context())
+			// import into a throwaway container instead of the selected engine
+			var scratch *scratchContainer
+			cleanupScratch := true
+			if scratchFlag {
+				output.Pending("creating scratch", info.Config.Labels[containerlabels.DatabaseEngine], version, "database")
+
+				scratch, err = createScratch(cmd.Context(), docker, info)
+				if err != nil {
+					output.Warning()
+
+					return err
+				}
+
+				output.Done()
+
+				// remove the scratch container if the import fails
+				defer func() {
+					if cleanupScratch {
+						_ = removeScratch(context.Background(), docker, scratch)
+					}
+				}()
+
+				hostname = scratch.Hostname
+				port = scratch.Port
+			}
+
+			// check if the same backup was recently imported into the database
+			var hash string
+			if !validateFlag && !scratchFlag && pathexists.IsFile(path) {
+				hash, err = importhistory.Hash(path)
+				if err != nil {
+					return err
+				}
+			}
+
+			if hash != "" && !forceFlag {
+				previous, err := importhistory.Find(home, hash, hostname, db, time.Now().Add(-importhistory.Window))
+				if err != nil {
+					output.Info("Unable to check the import history,", err.Error())
+				}
+
+				if previous != nil {
+					confirm, err := output.Confirm(fmt.Sprintf("This backup was already imported into %q on %s, import it again?", db, previous.Imported.Format("Jan 2 at 3:04pm")), false, "")
+					if err != nil {
+						return err
+					}
+
+					if !confirm {
+						output.Info("Skipping the import")
+
+						return nil
+					}
+				}
+			}
+
+			// the api checks there is enough space to stage the upload
+			var size int64
+			if stat, err := os.Stat(path); err == nil {
+				size = stat.Size()
+			}
+
+			// wait for the api to be ready, it restarts when the proxy is updated
+			if err := waitForAPI(cmd.Context(), nitrod, timeoutFlag); err != nil {
+				return err
+			}
+
+			// cancel the stream on failure so the api discards the partial import
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+
+			stream, err := nitrod.ImportDatabase(ctx)
 			// check if the error code is unimplemented
 			if code := status.Code(err); code == codes.Unimplemented {
 				output.Warning()
@@ -238,22 +609,36 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 				}
 			}
 			if err != nil {
-				return err
+				return fmt.Errorf("unable to open the import stream, %w", err)
+			}
+
+			details := &protob.DatabaseInfo{
+				Compressed:      compressed,
+				CompressionType: compressionType,
+				Database:        db,
+				Engine:          detected,
+				Hostname:        hostname,
+				Port:            port,
+				Version:         version,
+				Validate:        validateFlag,
+				Analyze:         analyzeFlag,
+				KeepUpload:      keepUploadFlag,
+				Jobs:            int32(jobs),
+				TmpDir:          proxyTmpDirFlag,
+				Size:            size,
+				OnError:         onErrorFlag,
+				FromPrefix:      fromPrefixFlag,
+				ToPrefix:        toPrefixFlag,
+				RenameFrom:      renameFromFlag,
+				RenameTo:        db,
+				ExcludeTables:   excludeFlag,
+				User:            user,
+				Password:        password,
 			}
 
 			// create a request with the database information to populate the database info for the import
 			err = stream.Send(&protob.ImportDatabaseRequest{
-				Payload: &protob.ImportDatabaseRequest_Database{
-					Database: &protob.DatabaseInfo{
-						Compressed:      compressed,
-						CompressionType: compressionType,
-						Database:        db,
-						Engine:          detected,
-						Hostname:        hostname,
-						Port:            port,
-						Version:         version,
-					},
-				},
+				Payload: &protob.ImportDatabaseRequest_Database{Database: details},
 			})
 			// check if the error code is unimplemented
 			if code := status.Code(err); code == codes.Unimplemented {
@@ -282,65 +667,499 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 				}
 			}
 			if err != nil {
-				return stream.RecvMsg(nil)
+				return streamError(stream, "send the database details", err)
 			}
 
 			// create a timer
 			start := time.Now()
 
-			// open the file
-			file, err := os.Open(path)
+			msg := fmt.Sprintf("importing database %q into %q", db, hostname)
+			if validateFlag {
+				msg = fmt.Sprintf("validating backup with %q", hostname)
+			}
+
+			// stream to backup file to the api
+			progress := newPendingProgress(output, cmd.ErrOrStderr(), msg)
+			reply, err := sendBackup(stream, path, progress)
+			if incomplete(err) {
+				progress.Warning()
+
+				// the api discards incomplete uploads, so send the backup once more
+				output.Info("The upload was incomplete, retrying…")
+
+				progress = newPendingProgress(output, cmd.ErrOrStderr(), msg)
+				reply, err = resendBackup(ctx, nitrod, details, path, progress)
+				if incomplete(err) {
+					progress.Warning()
+
+					return fmt.Errorf("the backup was not fully uploaded after retrying, %w", err)
+				}
+			}
 			if err != nil {
+				progress.Warning()
+
 				return err
 			}
 
-			// create a buffer to handle large files more gracefully
-			buffer := make([]byte, 1024*20)
-			reader := bufio.NewReader(file)
+			progress.Done()
+
+			output.Info(fmt.Sprintf("%s in %.2f seconds 💪", reply.Message, time.Since(start).Seconds()))
 
-			output.Pending(fmt.Sprintf("importing database %q into %q", db, hostname))
+			// summarize the warnings so they don't go unnoticed
+			importWarnings(cmd, home, reply.GetOutput(), output)
 
-			// stream to backup file to the api
-			for {
-				n, err := reader.Read(buffer)
-				if err == io.EOF {
-					break
+			// record the import to detect duplicates
+			if hash != "" {
+				if err := importhistory.Add(home, importhistory.Record{
+					Hash:     hash,
+					Hostname: hostname,
+					Database: db,
+					File:     args[0],
+					Imported: time.Now(),
+				}); err != nil {
+					output.Info("Unable to record the import,", err.Error())
 				}
+			}
+
+			if scratch != nil {
+				output.Info("Connect to the scratch database using", scratchConnection(scratch, detected, db))
+
+				remove, err := output.Confirm(fmt.Sprintf("Remove the scratch database %q now?", scratch.Hostname), true, "")
 				if err != nil {
-					output.Warning()
+					return err
+				}
+
+				if !remove {
+					cleanupScratch = false
 
-					return stream.RecvMsg(nil)
+					output.Info(fmt.Sprintf("Keeping %q, stopping the container will remove it", scratch.Hostname))
+
+					return nil
 				}
 
-				// send the chunked file data in pieces
-				if err := stream.Send(&protob.ImportDatabaseRequest{
-					Payload: &protob.ImportDatabaseRequest_Data{
-						Data: buffer[:n],
-					},
-				}); err != nil {
+				output.Pending("removing", scratch.Hostname)
+
+				if err := removeScratch(cmd.Context(), docker, scratch); err != nil {
 					output.Warning()
 
-					return err
+					return fmt.Errorf("unable to remove the scratch database, %w", err)
 				}
-			}
 
-			// handle the response
-			reply, err := stream.CloseAndRecv()
-			if err != nil {
-				output.Warning()
+				cleanupScratch = false
 
-				return stream.RecvMsg(nil)
+				output.Done()
 			}
 
-			output.Done()
-
-			output.Info(fmt.Sprintf("%s in %.2f seconds 💪", reply.Message, time.Since(start).Seconds()))
-
 			return nil
 		},
 	}
 
+	cmd.Flags().StringVar(&engineFlag, "engine", "", "The default database engine to import into (mariadb, mysql, or postgres)")
 	cmd.Flags().StringVar(&nameFlag, "name", "", "The database name to import into")
+	cmd.Flags().StringVar(&databaseFlag, "database", "", "The database name to import into without prompting, the same as --name")
+	cmd.Flags().StringVar(&containerFlag, "container", "", "The database container to import into without prompting (e.g. mysql-8.0-3306.database.nitro)")
+	cmd.Flags().StringVar(&remoteFlag, "remote", "", "The url of a database that is not a nitro container to import into using the local mysql or psql client, the password can be set with NITRO_REMOTE_PASSWORD (e.g. postgres://user@db.example.com:5432/craft)")
+	cmd.Flags().StringVar(&userFlag, "user", "", "The username:password used to download a backup from a url")
+	cmd.Flags().BoolVar(&validateFlag, "validate", false, "Validate the backup in a temporary database without importing it")
+	cmd.Flags().IntVar(&jobsFlag, "jobs", 0, "The number of parallel jobs used to restore postgres custom or directory format archives")
+	cmd.Flags().StringVar(&tmpDirFlag, "tmp-dir", "", "The directory used to stage downloaded or converted backups (default is the system temp directory)")
+	cmd.Flags().StringVar(&proxyTmpDirFlag, "proxy-tmp-dir", "", "The directory in the proxy container used to stage the upload, e.g. /data")
+	cmd.Flags().BoolVar(&analyzeFlag, "analyze", false, "Update the table statistics after importing")
+	cmd.Flags().BoolVar(&keepUploadFlag, "keep-upload", false, "Keep the uploaded backup in the proxy container after importing")
+	cmd.Flags().BoolVar(&forceFlag, "force", false, "Import the backup without checking if it was recently imported into the database")
+	cmd.Flags().StringVar(&onErrorFlag, "on-error", "", "Stop the import at the first error or continue and skip the statements that fail (stop or continue), defaults to the behavior of the import tool")
+	cmd.Flags().StringVar(&fromPrefixFlag, "from-prefix", "", "The table prefix in the backup to replace (e.g. prod_)")
+	cmd.Flags().StringVar(&toPrefixFlag, "to-prefix", "", "The table prefix to use instead of --from-prefix, empty to remove the prefix (e.g. craft_)")
+	cmd.Flags().StringVar(&renameFromFlag, "rename-from", "", "The database in the backup (e.g. in USE or \\connect statements) to import into the target database")
+	cmd.Flags().StringVar(&renameToFlag, "rename-to", "", "The database to import into instead of --rename-from, the same as --name")
+	cmd.Flags().BoolVar(&showOutputFlag, "show-output", false, "Show the output, including warnings, from the import tool")
+	cmd.Flags().StringArrayVar(&excludeFlag, "exclude-table", nil, "A table to remove from a plain sql backup before importing, glob patterns such as '*_sessions' are supported (can be used more than once)")
+	cmd.Flags().DurationVar(&timeoutFlag, "timeout", apiTimeout, "How long to wait for the api to be ready before importing")
+	cmd.Flags().BoolVar(&scratchFlag, "scratch", false, "Import into a throwaway container with the same engine and version as the selected database")
+
+	// complete the engines from the running database containers
+	_ = cmd.RegisterFlagCompletionFunc("engine", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		containers, err := containerfind.Databases(context.Background(), docker)
+		if err != nil {
+			return []string{"mariadb", "mysql", "postgres"}, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		var options []string
+		seen := make(map[string]bool)
+		for _, c := range containers {
+			engine := c.Labels[containerlabels.DatabaseEngine]
+			if engine != "" && !seen[engine] {
+				seen[engine] = true
+				options = append(options, engine)
+			}
+		}
+
+		return options, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.RegisterFlagCompletionFunc("on-error", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{database.OnErrorStop, database.OnErrorContinue}, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.RegisterFlagCompletionFunc("tmp-dir", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	})
 
 	return cmd
 }
+
+// importWarnings prints a summary of the warnings in the output from the import tool and
+// saves the output to a log file, in the config directory, for the details.
+func importWarnings(cmd *cobra.Command, home, out string, output terminal.Outputer) {
+	if showOutputFlag && out != "" {
+		fmt.Fprint(cmd.ErrOrStderr(), out)
+	}
+
+	warnings, unique := database.Warnings(out)
+	if len(warnings) == 0 {
+		return
+	}
+
+	msg := fmt.Sprintf("%s, %d unique", plural(len(warnings), "warning"), len(unique))
+	if !showOutputFlag {
+		msg = msg + " — run with --show-output for details"
+	}
+
+	output.Info(msg)
+
+	file, err := saveImportLog(home, out)
+	if err != nil {
+		output.Info("Unable to save the import output,", err.Error())
+
+		return
+	}
+
+	output.Info("The import output was saved to", file)
+}
+
+// saveImportLog writes the output from the import tool to a new file in the logs directory
+// of the config directory and returns the path to the file.
+func saveImportLog(home, out string) (string, error) {
+	dir := filepath.Join(home, config.DirectoryName, "logs")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	file := filepath.Join(dir, fmt.Sprintf("import-%s.log", time.Now().Format("20060102-150405")))
+	if err := ioutil.WriteFile(file, []byte(out), 0644); err != nil {
+		return "", err
+	}
+
+	return file, nil
+}
+
+// plural returns the count and the noun, adding an s unless the count is one.
+func plural(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, noun)
+	}
+
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
+// validateURL verifies the url of a backup to download uses http or https.
+func validateURL(rawurl string) error {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return fmt.Errorf("unable to parse the url %s, %w", rawurl, err)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+	default:
+		return fmt.Errorf("unsupported url scheme %q, use a http or https url (e.g. a presigned url)", u.Scheme)
+	}
+
+	if u.Host == "" {
+		return fmt.Errorf("the url %s is missing the host", rawurl)
+	}
+
+	return nil
+}
+
+// download takes the url for a backup and downloads it into a temp file, using the
+// credentials from the --user flag or NITRO_IMPORT_USER environment variable.
+func download(cmd *cobra.Command, home, rawurl string, output terminal.Outputer) (string, error) {
+	user := userFlag
+	if user == "" {
+		user = os.Getenv("NITRO_IMPORT_USER")
+	}
+
+	opts := downloader.FileOptions{TempDir: tmpDir(home)}
+	if user != "" {
+		sp := strings.SplitN(user, ":", 2)
+		opts.Username = sp[0]
+		if len(sp) > 1 {
+			opts.Password = sp[1]
+		}
+	}
+
+	opts.Progress = terminal.NewProgress(cmd.ErrOrStderr(), "downloading")
+
+	output.Info("Downloading", rawurl)
+
+	file, err := downloader.NewDownloader().File(cmd.Context(), rawurl, opts)
+	if err != nil {
+		return "", fmt.Errorf("unable to download the backup, %w", err)
+	}
+
+	output.Success("downloaded backup")
+
+	return file, nil
+}
+
+// namedContainer returns the index of the container with the name in the compatible
+// database containers. It returns an error if the container does not exist, is not a
+// database container, or is not compatible with the engine of the backup.
+func namedContainer(ctx context.Context, docker client.ContainerAPIClient, containers []types.Container, name, compatibility string) (int, error) {
+	for i, c := range containers {
+		if containerfind.Name(c) == name {
+			return i, nil
+		}
+	}
+
+	// check if the container is a database for another engine
+	databases, err := containerfind.Databases(ctx, docker)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, c := range databases {
+		if containerfind.Name(c) == name {
+			return 0, fmt.Errorf("the database container %s is not compatible with the %s backup", name, compatibility)
+		}
+	}
+
+	if len(databases) == 0 {
+		return 0, fmt.Errorf("unable to find the database container %s, there are no database containers", name)
+	}
+
+	return 0, fmt.Errorf("unable to find the database container %s, the database containers are %s", name, strings.Join(containerfind.Names(databases), ", "))
+}
+
+// compression takes the path to a backup and determines if the backup is compressed
+// and the compression type used by the api.
+func compression(path string, output terminal.Outputer) (bool, string, error) {
+	detail, err := filetype.DetermineDetailed(path)
+	if err != nil {
+		return false, "", err
+	}
+
+	switch detail.Kind {
+	case "zip", "tgz":
+		return true, detail.Kind, nil
+	case "gzip":
+		// the api refers to single gzip files as tar
+		return true, "tar", nil
+	case "tar":
+		return false, "", fmt.Errorf("uncompressed tar archives are not supported, compress the archive with gzip or zip")
+	case "pgdump":
+		// custom format archives are compressed by pg_dump and restored with pg_restore
+		return false, "", nil
+	case "text":
+		if !detail.Confident {
+			output.Info("The backup does not appear to be valid UTF-8, the import may fail")
+		}
+	}
+
+	return false, "", nil
+}
+
+// uploadProgress returns a progress bar when the writer is a terminal, otherwise a line is
+// written every ten percent so the upload of a large backup does not appear to hang.
+func uploadProgress(w io.Writer) terminal.Progress {
+	p := terminal.NewProgress(w, "uploading")
+	if _, ok := p.(terminal.NoProgress); ok {
+		return terminal.NewProgressLines(w, "uploading")
+	}
+
+	return p
+}
+
+// pendingProgress shows the upload progress and writes the pending message once the
+// upload is finished, so the progress is not written over the message.
+type pendingProgress struct {
+	terminal.Progress
+	output terminal.Outputer
+	msg    string
+	shown  bool
+}
+
+func newPendingProgress(output terminal.Outputer, w io.Writer, msg string) *pendingProgress {
+	return &pendingProgress{Progress: uploadProgress(w), output: output, msg: msg}
+}
+
+func (p *pendingProgress) Finish() {
+	p.Progress.Finish()
+
+	p.pending()
+}
+
+// Warning shows the pending message, if the upload did not start, and the warning.
+func (p *pendingProgress) Warning() {
+	p.pending()
+
+	p.output.Warning()
+}
+
+// Done shows the pending message, if the upload did not start, and that it is done.
+func (p *pendingProgress) Done() {
+	p.pending()
+
+	p.output.Done()
+}
+
+func (p *pendingProgress) pending() {
+	if p.shown {
+		return
+	}
+
+	p.shown = true
+
+	p.output.Pending(p.msg)
+}
+
+// resendBackup opens a new import stream and sends the database details and backup
+// again. It is used when the api did not receive the whole backup.
+func resendBackup(ctx context.Context, nitrod protob.NitroClient, details *protob.DatabaseInfo, path string, progress terminal.Progress) (*protob.ImportDatabaseResponse, error) {
+	stream, err := nitrod.ImportDatabase(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the import stream, %w", err)
+	}
+
+	if err := stream.Send(&protob.ImportDatabaseRequest{Payload: &protob.ImportDatabaseRequest_Database{Database: details}}); err != nil {
+		return nil, streamError(stream, "send the database details", err)
+	}
+
+	return sendBackup(stream, path, progress)
+}
+
+// incomplete returns true if the error is from the api receiving less of the backup
+// than the size of the file.
+func incomplete(err error) bool {
+	var s interface{ GRPCStatus() *status.Status }
+
+	return errors.As(err, &s) && s.GRPCStatus().Code() == codes.DataLoss
+}
+
+// sendBackup streams the backup file to the api in chunks, after the database details
+// have been sent, and returns the reply from the api. The progress is finished once the
+// backup is uploaded, before waiting on the import.
+func sendBackup(stream protob.Nitro_ImportDatabaseClient, path string, progress terminal.Progress) (*protob.ImportDatabaseResponse, error) {
+	// open the file
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the backup, %w", err)
+	}
+	defer file.Close()
+
+	total := int64(-1)
+	if stat, err := file.Stat(); err == nil {
+		total = stat.Size()
+	}
+
+	progress.Start(total)
+
+	// create a buffer to handle large files more gracefully
+	buffer := make([]byte, 1024*20)
+	reader := bufio.NewReader(file)
+
+	for {
+		n, err := reader.Read(buffer)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			progress.Finish()
+
+			return nil, fmt.Errorf("unable to read the backup, %w", err)
+		}
+
+		// send the chunked file data in pieces
+		if err := stream.Send(&protob.ImportDatabaseRequest{
+			Payload: &protob.ImportDatabaseRequest_Data{
+				Data: buffer[:n],
+			},
+		}); err != nil {
+			progress.Finish()
+
+			return nil, streamError(stream, "send the backup", err)
+		}
+
+		progress.Add(int64(n))
+	}
+
+	progress.Finish()
+
+	// handle the response
+	reply, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, fmt.Errorf("unable to complete the import, %w", err)
+	}
+
+	return reply, nil
+}
+
+// streamError takes the phase of the import that failed and returns an error describing
+// it. When the api ends the stream early, sending returns io.EOF and the actual error
+// is only available by receiving the response.
+func streamError(stream protob.Nitro_ImportDatabaseClient, phase string, err error) error {
+	if errors.Is(err, io.EOF) {
+		if _, recvErr := stream.CloseAndRecv(); recvErr != nil {
+			err = recvErr
+		}
+	}
+
+	return fmt.Errorf("unable to %s, %w", phase, err)
+}
+
+// defaultEngine returns the database engine to import into without prompting. The
+// flag takes priority over the NITRO_DEFAULT_DB_ENGINE environment variable, which
+// takes priority over the database_engine in the config.
+func defaultEngine(home string) (string, error) {
+	engine := engineFlag
+	if engine == "" {
+		engine = os.Getenv("NITRO_DEFAULT_DB_ENGINE")
+	}
+
+	if engine == "" {
+		cfg, err := config.Load(home)
+		if err != nil && !errors.Is(err, config.ErrNoConfigFile) {
+			return "", err
+		}
+
+		if cfg != nil {
+			engine = cfg.DatabaseEngine
+		}
+	}
+
+	if engine == "" {
+		return "", nil
+	}
+
+	v := validate.DatabaseEngine{}
+	if err := v.Validate(engine); err != nil {
+		return "", err
+	}
+
+	return engine, nil
+}
+
+// tmpDir returns the directory used to stage backups, the flag takes priority over
+// the NITRO_TMP_DIR environment variable.
+func tmpDir(home string) string {
+	dir := tmpDirFlag
+	if dir == "" {
+		dir = os.Getenv("NITRO_TMP_DIR")
+	}
+
+	// replace the relative path with the full directory
+	if strings.HasPrefix(dir, "~") {
+		dir = strings.Replace(dir, "~", home, 1)
+	}
+
+	return dir
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+func TestCompression(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-import")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name           string
+		content        []byte
+		wantCompressed bool
+		wantErr        bool
+	}{
+		{
+			name:    "plain sql backups are not compressed",
+			content: []byte("CREATE TABLE users (id int);\n"),
+		},
+		{
+			name:    "postgres custom format archives are imported with pg_restore",
+			content: append([]byte("PGDMP"), 0x01, 0x0e, 0x00, 0x04, 0x08, 0x01, 0x01),
+		},
+		{
+			name:    "unknown binary files return an error",
+			content: []byte{0x00, 0x01, 0x02, 0x03, 0xff},
+			wantErr: true,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, fmt.Sprintf("backup-%d", i))
+			if err := ioutil.WriteFile(path, tt.content, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			compressed, _, err := compression(path, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("compression() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if compressed != tt.wantCompressed {
+				t.Errorf("compression() compressed = %v, want %v", compressed, tt.wantCompressed)
+			}
+		})
+	}
+}
+
+// databaseClient returns the database containers from ContainerList.
+type databaseClient struct {
+	client.ContainerAPIClient
+	containers []types.Container
+}
+
+func (c *databaseClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	return c.containers, nil
+}
+
+func TestNamedContainer(t *testing.T) {
+	mysql := types.Container{ID: "mysql", Names: []string{"/mysql-8.0-3306.database.nitro"}}
+	postgres := types.Container{ID: "postgres", Names: []string{"/postgres-13-5432.database.nitro"}}
+
+	docker := &databaseClient{containers: []types.Container{mysql, postgres}}
+
+	tests := []struct {
+		name       string
+		containers []types.Container
+		container  string
+		want       int
+		wantErr    string
+	}{
+		{
+			name:       "returns the compatible container",
+			containers: []types.Container{mysql, postgres},
+			container:  "postgres-13-5432.database.nitro",
+			want:       1,
+		},
+		{
+			name:       "containers for another engine are not compatible",
+			containers: []types.Container{mysql},
+			container:  "postgres-13-5432.database.nitro",
+			wantErr:    "is not compatible with the mysql backup",
+		},
+		{
+			name:       "containers that are not databases are not found",
+			containers: []types.Container{mysql},
+			container:  "tutorial.nitro",
+			wantErr:    "the database containers are mysql-8.0-3306.database.nitro, postgres-13-5432.database.nitro",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := namedContainer(context.TODO(), docker, tt.containers, tt.container, "mysql")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected the error to contain %q, got %v", tt.wantErr, err)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.want {
+				t.Errorf("namedContainer() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
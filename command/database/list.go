@@ -0,0 +1,110 @@
+package database
+
+import (
+	"sort"
+
+	"github.com/docker/docker/client"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/backup"
+	"github.com/craftcms/nitro/pkg/containerfind"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// systemDatabases are the databases the engines create, they are not shown in the list.
+var systemDatabases = map[string]bool{
+	"information_schema": true,
+	"performance_schema": true,
+	"mysql":              true,
+	"sys":                true,
+	"template0":          true,
+	"template1":          true,
+	"postgres":           true,
+}
+
+func listCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "Lists the databases in each engine.",
+		Aliases: []string{"ls"},
+		Example: `  # show the databases in each database engine
+  nitro db list`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			containers, err := containerfind.Databases(cmd.Context(), docker)
+			if err != nil {
+				return err
+			}
+
+			if len(containers) == 0 {
+				output.Info("There are no database engines, run `nitro db new` to add one")
+
+				return nil
+			}
+
+			// sort the engines by the name
+			sort.SliceStable(containers, func(i, j int) bool {
+				return containerfind.Name(containers[i]) < containerfind.Name(containers[j])
+			})
+
+			tbl := table.New("Engine", "Database").WithWriter(cmd.OutOrStdout()).WithPadding(2)
+
+			for _, c := range containers {
+				name := containerfind.Name(c)
+
+				// the databases can only be listed in running containers
+				if c.State != "running" {
+					output.Info("Skipping", name, "because it is not running, run `nitro start` to start it")
+
+					continue
+				}
+
+				all, err := backup.Databases(cmd.Context(), docker, c.ID, c.Labels[containerlabels.DatabaseCompatibility])
+				if err != nil {
+					output.Info("Unable to list the databases in", name+",", err.Error())
+
+					continue
+				}
+
+				databases := userDatabases(all)
+				if len(databases) == 0 {
+					tbl.AddRow(name, "(no databases)")
+
+					continue
+				}
+
+				// only show the engine on the first row to group the databases
+				for i, db := range databases {
+					if i > 0 {
+						name = ""
+					}
+
+					tbl.AddRow(name, db)
+				}
+			}
+
+			tbl.Print()
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// userDatabases returns the sorted databases without the system databases.
+func userDatabases(databases []string) []string {
+	var user []string
+	for _, db := range databases {
+		if db == "" || systemDatabases[db] {
+			continue
+		}
+
+		user = append(user, db)
+	}
+
+	sort.Strings(user)
+
+	return user
+}
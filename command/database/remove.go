@@ -91,11 +91,8 @@ func removeCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, out
 			db := databases[selected]
 
 			// wait for the api to be ready
-			for {
-				_, err := nitrod.Ping(cmd.Context(), &protob.PingRequest{})
-				if err == nil {
-					break
-				}
+			if err := waitForAPI(cmd.Context(), nitrod, apiTimeout); err != nil {
+				return err
 			}
 
 			output.Pending("removing", db)
@@ -0,0 +1,295 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/backup"
+	"github.com/craftcms/nitro/pkg/containerfind"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/database"
+	"github.com/craftcms/nitro/pkg/pathexists"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
+)
+
+var resetExampleText = `  # remove all of the data in a database
+  nitro db reset
+
+  # reset a database and import a fresh backup
+  nitro db reset --file ~/Desktop/backup.sql`
+
+var resetFileFlag string
+
+func resetCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "reset",
+		Short:   "Resets a database.",
+		Example: resetExampleText,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if resetFileFlag == "" {
+				return nil
+			}
+
+			// replace the relative path with the full directory
+			if strings.HasPrefix(resetFileFlag, "~") {
+				resetFileFlag = strings.Replace(resetFileFlag, "~", home, 1)
+			}
+
+			// make sure the file exists
+			if !pathexists.IsFile(resetFileFlag) {
+				return fmt.Errorf("unable to find file %s", resetFileFlag)
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			// get a list of all the databases
+			containers, err := containerfind.Databases(ctx, docker)
+			if err != nil {
+				return err
+			}
+
+			// generate a list of engines for the prompt
+			var containerList []string
+			for _, c := range containers {
+				containerList = append(containerList, containerfind.Name(c))
+			}
+
+			// prompt the user for which database engine
+			selectedEngine, err := output.Select(cmd.InOrStdin(), "Which database engine? ", containerList)
+			if err != nil {
+				return err
+			}
+
+			// get the containers info
+			info, err := docker.ContainerInspect(ctx, containers[selectedEngine].ID)
+			if err != nil {
+				return err
+			}
+
+			// get the containers details
+			engine := info.Config.Labels[containerlabels.DatabaseCompatibility]
+			hostname := strings.TrimLeft(info.Name, "/")
+			version := info.Config.Labels[containerlabels.DatabaseVersion]
+			var port string
+			// get the port from the container info
+			for p, bind := range info.HostConfig.PortBindings {
+				for _, v := range bind {
+					if v.HostPort != "" {
+						port = p.Port()
+					}
+				}
+			}
+
+			// get all of the databases
+			databases, err := backup.Databases(ctx, docker, info.ID, engine)
+			if err != nil {
+				return err
+			}
+
+			// ask the user which database
+			selected, err := output.Select(cmd.InOrStdin(), "Which database should we reset? ", databases)
+			if err != nil {
+				return err
+			}
+
+			db := databases[selected]
+
+			msg := fmt.Sprintf("This will remove all of the data in %q, continue?", db)
+			if resetFileFlag != "" {
+				msg = fmt.Sprintf("This will replace all of the data in %q with %s, continue?", db, resetFileFlag)
+			}
+
+			confirm, err := output.Confirm(msg, false, "")
+			if err != nil {
+				return err
+			}
+
+			if !confirm {
+				output.Info("Skipping the reset")
+
+				return nil
+			}
+
+			dbInfo := &protob.DatabaseInfo{
+				Engine:   engine,
+				Hostname: hostname,
+				Version:  version,
+				Port:     port,
+				Database: db,
+			}
+
+			// wait for the api to be ready
+			if err := waitForAPI(ctx, nitrod, apiTimeout); err != nil {
+				return err
+			}
+
+			output.Pending("removing", db)
+
+			if _, err := nitrod.RemoveDatabase(ctx, &protob.RemoveDatabaseRequest{Database: dbInfo}); err != nil {
+				output.Warning()
+
+				return fmt.Errorf("unable to remove the database, %w", err)
+			}
+
+			output.Done()
+
+			output.Pending("creating", db)
+
+			if _, err := nitrod.AddDatabase(ctx, &protob.AddDatabaseRequest{Database: dbInfo}); err != nil {
+				output.Warning()
+
+				return fmt.Errorf("unable to create the database, %w", err)
+			}
+
+			output.Done()
+
+			// import the backup
+			if resetFileFlag != "" {
+				if err := resetImport(ctx, nitrod, resetFileFlag, dbInfo, output); err != nil {
+					return err
+				}
+			}
+
+			// show the final state of the database
+			tables, size, err := databaseStats(ctx, docker, info.ID, engine, db)
+			if err != nil {
+				output.Info("Unable to get the database details,", err.Error())
+
+				return nil
+			}
+
+			output.Info(fmt.Sprintf("Database %q has %s tables (%s) 💪", db, tables, size))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&resetFileFlag, "file", "", "The backup to import after resetting the database")
+
+	return cmd
+}
+
+// resetImport takes the path to a backup and streams it to the api to import into the
+// database.
+func resetImport(ctx context.Context, nitrod protob.NitroClient, path string, info *protob.DatabaseInfo, output terminal.Outputer) error {
+	// convert utf-16 and byte order marked files to plain utf-8
+	converted, encoding, err := database.ConvertToUTF8(path, "")
+	if err != nil {
+		return err
+	}
+
+	if encoding != "" {
+		output.Info("Converted backup from", encoding, "to utf-8")
+
+		defer os.Remove(converted)
+
+		path = converted
+	}
+
+	info.Compressed, info.CompressionType, err = compression(path, output)
+	if err != nil {
+		return err
+	}
+
+	// cancel the stream on failure so the api discards the partial import
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	start := time.Now()
+
+	progress := newPendingProgress(output, os.Stderr, fmt.Sprintf("importing database %q into %q", info.Database, info.Hostname))
+
+	stream, err := nitrod.ImportDatabase(ctx)
+	if err != nil {
+		progress.Warning()
+
+		return fmt.Errorf("unable to open the import stream, %w", err)
+	}
+
+	if err := stream.Send(&protob.ImportDatabaseRequest{Payload: &protob.ImportDatabaseRequest_Database{Database: info}}); err != nil {
+		progress.Warning()
+
+		return streamError(stream, "send the database details", err)
+	}
+
+	reply, err := sendBackup(stream, path, progress)
+	if err != nil {
+		progress.Warning()
+
+		return err
+	}
+
+	progress.Done()
+
+	output.Info(fmt.Sprintf("%s in %.2f seconds", reply.Message, time.Since(start).Seconds()))
+
+	return nil
+}
+
+// databaseStats returns the number of tables and the size of a database.
+func databaseStats(ctx context.Context, docker client.CommonAPIClient, containerID, engine, db string) (string, string, error) {
+	var cmds []string
+	switch engine {
+	case "mysql":
+		cmds = []string{"mysql", "-uroot", "-pnitro", "--skip-column-names", "--silent", fmt.Sprintf(`-e SELECT COUNT(*), CONCAT(COALESCE(ROUND(SUM(data_length + index_length) / 1024 / 1024, 2), 0), ' MB') FROM information_schema.tables WHERE table_schema = %s;`, database.QuoteString(engine, db))}
+	default:
+		cmds = []string{"psql", "--username=nitro", "--host=127.0.0.1", "--tuples-only", "--no-align", "--field-separator=\t", "--dbname=" + db, fmt.Sprintf(`-c SELECT COUNT(*), pg_size_pretty(pg_database_size(%s)) FROM information_schema.tables WHERE table_schema = 'public';`, database.QuoteString(engine, db))}
+	}
+
+	// create the exec
+	e, err := docker.ContainerExecCreate(ctx, containerID, types.ExecConfig{
+		AttachStdout: true,
+		AttachStderr: true,
+		Tty:          false,
+		Cmd:          cmds,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	// attach to the container
+	resp, err := docker.ContainerExecAttach(ctx, e.ID, types.ExecStartCheck{
+		Tty: false,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Close()
+
+	// start the exec
+	if err := docker.ContainerExecStart(ctx, e.ID, types.ExecStartCheck{}); err != nil {
+		return "", "", fmt.Errorf("unable to start the container, %w", err)
+	}
+
+	var stdout bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, ioutil.Discard, resp.Reader); err != nil {
+		return "", "", err
+	}
+
+	return parseStats(stdout.String())
+}
+
+// parseStats takes the output of the stats query, the number of tables and the size
+// separated by a tab, and returns the number of tables and the size.
+func parseStats(out string) (string, string, error) {
+	sp := strings.SplitN(strings.TrimSpace(out), "\t", 2)
+	if len(sp) != 2 {
+		return "", "", fmt.Errorf("unexpected output %q", out)
+	}
+
+	return sp[0], sp[1], nil
+}
@@ -0,0 +1,165 @@
+package database
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"google.golang.org/grpc"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
+)
+
+// mockDockerClient returns a database container and the output for the container execs.
+type mockDockerClient struct {
+	client.CommonAPIClient
+
+	containers []types.Container
+	output     string
+}
+
+func (m *mockDockerClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	return m.containers, nil
+}
+
+func (m *mockDockerClient) ContainerInspect(ctx context.Context, id string) (types.ContainerJSON, error) {
+	for _, c := range m.containers {
+		if c.ID == id {
+			return types.ContainerJSON{
+				ContainerJSONBase: &types.ContainerJSONBase{ID: c.ID, Name: c.Names[0], HostConfig: &container.HostConfig{}},
+				Config:            &container.Config{Labels: c.Labels},
+			}, nil
+		}
+	}
+
+	return types.ContainerJSON{}, errors.New("no such container")
+}
+
+func (m *mockDockerClient) ContainerExecCreate(ctx context.Context, container string, config types.ExecConfig) (types.IDResponse, error) {
+	return types.IDResponse{ID: "exec-" + container}, nil
+}
+
+func (m *mockDockerClient) ContainerExecAttach(ctx context.Context, execID string, config types.ExecStartCheck) (types.HijackedResponse, error) {
+	conn, _ := net.Pipe()
+
+	return types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(strings.NewReader(m.output))}, nil
+}
+
+func (m *mockDockerClient) ContainerExecStart(ctx context.Context, execID string, config types.ExecStartCheck) error {
+	return nil
+}
+
+// resetNitro records the databases that are removed and added.
+type resetNitro struct {
+	fakeNitro
+
+	removed []string
+	added   []string
+}
+
+func (n *resetNitro) RemoveDatabase(ctx context.Context, in *protob.RemoveDatabaseRequest, opts ...grpc.CallOption) (*protob.RemoveDatabaseResponse, error) {
+	n.removed = append(n.removed, in.GetDatabase().GetDatabase())
+
+	return &protob.RemoveDatabaseResponse{}, nil
+}
+
+func (n *resetNitro) AddDatabase(ctx context.Context, in *protob.AddDatabaseRequest, opts ...grpc.CallOption) (*protob.AddDatabaseResponse, error) {
+	n.added = append(n.added, in.GetDatabase().GetDatabase())
+
+	return &protob.AddDatabaseResponse{}, nil
+}
+
+// confirmOutput selects the first option and answers the confirm prompt.
+type confirmOutput struct {
+	terminal.Outputer
+
+	confirm bool
+	info    []string
+}
+
+func (o *confirmOutput) Select(r io.Reader, msg string, opts []string) (int, error) {
+	return 0, nil
+}
+
+func (o *confirmOutput) Confirm(message string, fallback bool, sep string) (bool, error) {
+	return o.confirm, nil
+}
+
+func (o *confirmOutput) Info(s ...string) {
+	o.info = append(o.info, strings.Join(s, " "))
+}
+
+func TestResetCommandDeclined(t *testing.T) {
+	docker := &mockDockerClient{
+		containers: []types.Container{{
+			ID:     "mysql",
+			Names:  []string{"/mysql-8.0-3306.database.nitro"},
+			Labels: map[string]string{containerlabels.DatabaseCompatibility: "mysql", containerlabels.DatabaseVersion: "8.0"},
+		}},
+		output: "Database\ncraft\n",
+	}
+	nitrod := &resetNitro{fakeNitro: fakeNitro{ready: 1}}
+	output := &confirmOutput{}
+
+	cmd := resetCommand("", docker, nitrod, output)
+	cmd.SetArgs([]string{})
+	if err := cmd.ExecuteContext(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(nitrod.removed) != 0 || len(nitrod.added) != 0 {
+		t.Errorf("expected the database to not be reset, removed %v and added %v", nitrod.removed, nitrod.added)
+	}
+
+	if len(output.info) == 0 || output.info[len(output.info)-1] != "Skipping the reset" {
+		t.Errorf("expected the reset to be skipped, got %v", output.info)
+	}
+}
+
+func TestParseStats(t *testing.T) {
+	tests := []struct {
+		name       string
+		out        string
+		wantTables string
+		wantSize   string
+		wantErr    bool
+	}{
+		{
+			name:       "mysql output is parsed",
+			out:        "42\t12.50 MB\n",
+			wantTables: "42",
+			wantSize:   "12.50 MB",
+		},
+		{
+			name:       "postgres output is parsed",
+			out:        "7\t8093 kB",
+			wantTables: "7",
+			wantSize:   "8093 kB",
+		},
+		{
+			name:    "unexpected output returns an error",
+			out:     "ERROR 1049 (42000): Unknown database 'craft'\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables, size, err := parseStats(tt.out)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseStats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tables != tt.wantTables || size != tt.wantSize {
+				t.Errorf("parseStats() = %q, %q, want %q, %q", tables, size, tt.wantTables, tt.wantSize)
+			}
+		})
+	}
+}
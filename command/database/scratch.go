@@ -0,0 +1,243 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/nat"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/database"
+	"github.com/craftcms/nitro/pkg/portavail"
+)
+
+// scratchType is the type label for throwaway database containers, it keeps them out of
+// the database prompts.
+const scratchType = "scratch-database"
+
+// scratchTimeout is how long to wait for a scratch database to accept connections.
+var scratchTimeout = 2 * time.Minute
+
+// scratchContainer is a throwaway database container used to test an import.
+type scratchContainer struct {
+	ID       string
+	Hostname string
+	Port     string
+	HostPort string
+}
+
+// scratchConfig takes the info for a database container and returns the config to create
+// a scratch container using the same image, version, and settings. The scratch container
+// does not mount the named volume, so the data is removed with the container.
+func scratchConfig(info types.ContainerJSON, hostPort string) (*container.Config, *container.HostConfig, string, error) {
+	var port nat.Port
+	for p := range info.Config.ExposedPorts {
+		port = p
+	}
+
+	if port == "" {
+		return nil, nil, "", fmt.Errorf("unable to find the port for %s", strings.TrimLeft(info.Name, "/"))
+	}
+
+	labels := make(map[string]string)
+	for k, v := range info.Config.Labels {
+		labels[k] = v
+	}
+
+	labels[containerlabels.Type] = scratchType
+	labels[containerlabels.DatabasePort] = hostPort
+
+	containerConfig := &container.Config{
+		Image:        info.Config.Image,
+		Labels:       labels,
+		ExposedPorts: nat.PortSet{port: struct{}{}},
+		Env:          info.Config.Env,
+		Cmd:          info.Config.Cmd,
+	}
+
+	hostConfig := &container.HostConfig{
+		AutoRemove: true,
+		CapAdd:     info.HostConfig.CapAdd,
+		PortBindings: map[nat.Port][]nat.PortBinding{
+			port: {
+				{
+					HostIP:   "127.0.0.1",
+					HostPort: hostPort,
+				},
+			},
+		},
+	}
+
+	return containerConfig, hostConfig, port.Port(), nil
+}
+
+// createScratch takes the info for a database container and starts a scratch container,
+// with the same engine and version, on the next available port. It waits for the
+// database to accept connections before returning.
+func createScratch(ctx context.Context, docker client.CommonAPIClient, info types.ContainerJSON) (*scratchContainer, error) {
+	hostname := fmt.Sprintf("%s-scratch-%d", strings.TrimLeft(info.Name, "/"), time.Now().Unix())
+
+	var networkID string
+	if info.NetworkSettings != nil {
+		if n, ok := info.NetworkSettings.Networks["nitro-network"]; ok {
+			networkID = n.NetworkID
+		}
+	}
+
+	if networkID == "" {
+		return nil, fmt.Errorf("unable to find the nitro-network for %s", strings.TrimLeft(info.Name, "/"))
+	}
+
+	hostPort, err := availablePort(info.Config.Labels[containerlabels.DatabasePort])
+	if err != nil {
+		return nil, err
+	}
+
+	containerConfig, hostConfig, port, err := scratchConfig(info, hostPort)
+	if err != nil {
+		return nil, err
+	}
+
+	networkConfig := &network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{
+			"nitro-network": {
+				NetworkID: networkID,
+			},
+		},
+	}
+
+	resp, err := docker.ContainerCreate(ctx, containerConfig, hostConfig, networkConfig, nil, hostname)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the scratch container, %w", err)
+	}
+
+	scratch := &scratchContainer{ID: resp.ID, Hostname: hostname, Port: port, HostPort: hostPort}
+
+	if err := docker.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+		removeScratch(context.Background(), docker, scratch)
+
+		return nil, fmt.Errorf("unable to start the scratch container, %w", err)
+	}
+
+	if err := waitForScratch(ctx, docker, scratch, info.Config.Labels[containerlabels.DatabaseCompatibility]); err != nil {
+		removeScratch(context.Background(), docker, scratch)
+
+		return nil, err
+	}
+
+	// let the nitro user create databases, like the engine it is copied from
+	if info.Config.Labels[containerlabels.DatabaseCompatibility] == "mysql" {
+		for _, cmd := range database.MySQLGrants(info.Config.Labels[containerlabels.DatabaseVersion]) {
+			if err := scratchExec(ctx, docker, scratch.ID, cmd); err != nil {
+				removeScratch(context.Background(), docker, scratch)
+
+				return nil, fmt.Errorf("unable to grant the privileges in the scratch container, %w", err)
+			}
+		}
+	}
+
+	return scratch, nil
+}
+
+// waitForScratch checks the scratch database until it accepts connections over tcp, the
+// images start a temporary server on the socket while initializing.
+func waitForScratch(ctx context.Context, docker client.CommonAPIClient, scratch *scratchContainer, compatibility string) error {
+	cmd := []string{"pg_isready", "--host=127.0.0.1", "--username=nitro"}
+	if compatibility == "mysql" {
+		cmd = []string{"mysql", "-h127.0.0.1", "-uroot", "-pnitro", "-e SELECT 1;"}
+	}
+
+	deadline := time.Now().Add(scratchTimeout)
+	for time.Now().Before(deadline) {
+		if err := scratchExec(ctx, docker, scratch.ID, cmd); err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+
+	return fmt.Errorf("the scratch database %s was not ready after %s", scratch.Hostname, scratchTimeout)
+}
+
+// scratchExec runs the command in the container and returns an error if the command
+// does not exit successfully.
+func scratchExec(ctx context.Context, docker client.CommonAPIClient, containerID string, cmd []string) error {
+	e, err := docker.ContainerExecCreate(ctx, containerID, types.ExecConfig{
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          cmd,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := docker.ContainerExecAttach(ctx, e.ID, types.ExecStartCheck{})
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	var stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(ioutil.Discard, &stderr, resp.Reader); err != nil {
+		return err
+	}
+
+	inspect, err := docker.ContainerExecInspect(ctx, e.ID)
+	if err != nil {
+		return err
+	}
+
+	if inspect.ExitCode != 0 {
+		return fmt.Errorf("%s exited with %d: %s", cmd[0], inspect.ExitCode, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// removeScratch removes the scratch container and its anonymous volumes.
+func removeScratch(ctx context.Context, docker client.CommonAPIClient, scratch *scratchContainer) error {
+	return docker.ContainerRemove(ctx, scratch.ID, types.ContainerRemoveOptions{Force: true, RemoveVolumes: true})
+}
+
+// scratchConnection returns the details to connect to the scratch database from the host.
+func scratchConnection(scratch *scratchContainer, compatibility, db string) string {
+	user := "nitro"
+	if compatibility == "mysql" {
+		// the nitro user is only granted access to the nitro database in a new container
+		user = "root"
+	}
+
+	return fmt.Sprintf("host 127.0.0.1, port %s, user %s, password nitro, database %s", scratch.HostPort, user, db)
+}
+
+// availablePort returns the first port after the port that is available on the host.
+func availablePort(port string) (string, error) {
+	start, err := strconv.Atoi(port)
+	if err != nil {
+		start = 3306
+	}
+
+	for p := start + 1; p <= 65535; p++ {
+		if err := portavail.Check("", strconv.Itoa(p)); err != nil {
+			continue
+		}
+
+		return strconv.Itoa(p), nil
+	}
+
+	return "", fmt.Errorf("unable to find an available port after %d", start)
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+)
+
+func TestScratchConfig(t *testing.T) {
+	info := types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			Name:       "/mysql-8.0-3306.database.nitro",
+			HostConfig: &container.HostConfig{CapAdd: []string{"SYS_NICE"}},
+		},
+		Config: &container.Config{
+			Image:        "mysql:8.0",
+			Env:          []string{"MYSQL_ROOT_PASSWORD=nitro"},
+			Cmd:          []string{"--character-set-server=utf8mb4"},
+			ExposedPorts: nat.PortSet{"3306/tcp": struct{}{}},
+			Labels: map[string]string{
+				containerlabels.Nitro:           "true",
+				containerlabels.Type:            "database",
+				containerlabels.DatabaseEngine:  "mysql",
+				containerlabels.DatabaseVersion: "8.0",
+				containerlabels.DatabasePort:    "3306",
+			},
+		},
+	}
+
+	containerConfig, hostConfig, port, err := scratchConfig(info, "3307")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if port != "3306" {
+		t.Errorf("expected the container port to be 3306, got %q", port)
+	}
+
+	if containerConfig.Image != "mysql:8.0" {
+		t.Errorf("expected the image to match the database, got %q", containerConfig.Image)
+	}
+
+	if !reflect.DeepEqual(containerConfig.Env, info.Config.Env) || !reflect.DeepEqual(containerConfig.Cmd, info.Config.Cmd) {
+		t.Errorf("expected the env and cmd to match the database")
+	}
+
+	if containerConfig.Labels[containerlabels.Type] != scratchType {
+		t.Errorf("expected the type label to be %q, got %q", scratchType, containerConfig.Labels[containerlabels.Type])
+	}
+
+	if containerConfig.Labels[containerlabels.DatabaseVersion] != "8.0" {
+		t.Errorf("expected the version label to be kept, got %q", containerConfig.Labels[containerlabels.DatabaseVersion])
+	}
+
+	if info.Config.Labels[containerlabels.Type] != "database" {
+		t.Errorf("expected the database labels to not be modified")
+	}
+
+	if got := hostConfig.PortBindings["3306/tcp"][0].HostPort; got != "3307" {
+		t.Errorf("expected the host port to be 3307, got %q", got)
+	}
+
+	if len(hostConfig.Mounts) != 0 {
+		t.Errorf("expected the scratch container to not mount the database volume")
+	}
+}
+
+func TestScratchConfig_MissingPort(t *testing.T) {
+	info := types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{Name: "/postgres-13-5432.database.nitro", HostConfig: &container.HostConfig{}},
+		Config:            &container.Config{Image: "postgres:13"},
+	}
+
+	if _, _, _, err := scratchConfig(info, "5433"); err == nil {
+		t.Error("expected an error when the database does not expose a port")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime"
 	"strings"
 
 	"github.com/docker/docker/api/types"
@@ -13,15 +14,22 @@ import (
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/dockerhost"
 	"github.com/craftcms/nitro/pkg/proxycontainer"
 	"github.com/craftcms/nitro/pkg/setup"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
 const exampleText = `  # setup nitro
-  nitro init`
+  nitro init
 
-var skipApply, skipTrust bool
+  # setup nitro with mysql and postgres
+  nitro init --db mysql:8.0,postgres:13`
+
+var (
+	skipApply, skipTrust bool
+	flagDatabases        string
+)
 
 // NewCommand takes a docker client and returns the init command for creating a new environment
 func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
@@ -32,8 +40,15 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		SilenceErrors: false,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			// is the docker api alive?
-			if _, err := docker.Ping(cmd.Context()); err != nil {
-				return fmt.Errorf("Couldn’t connect to Docker; please make sure Docker is running.")
+			if err := dockerhost.CheckRunning(cmd.Context(), docker, runtime.GOOS); err != nil {
+				return err
+			}
+
+			// validate the engine:version pairs before making changes
+			if flagDatabases != "" {
+				if _, err := setup.ParseDatabases(flagDatabases); err != nil {
+					return err
+				}
 			}
 
 			return nil
@@ -44,13 +59,39 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				ctx = context.Background()
 			}
 
+			var databases []config.Database
+			if flagDatabases != "" {
+				var err error
+				databases, err = setup.ParseDatabases(flagDatabases)
+				if err != nil {
+					return err
+				}
+			}
+
 			// check if there is a config file
-			_, err := config.Load(home)
-			if errors.Is(err, config.ErrNoConfigFile) {
+			cfg, err := config.Load(home)
+			switch {
+			case errors.Is(err, config.ErrNoConfigFile):
 				// walk the user through the first time setup
-				if err := setup.FirstTime(home, cmd.InOrStdin(), output); err != nil {
+				if err := setup.FirstTime(home, cmd.InOrStdin(), output, databases); err != nil {
+					return err
+				}
+			case err == nil && len(databases) > 0:
+				// add the requested databases to the existing config
+				added, err := setup.AddDatabases(cfg, databases)
+				if err != nil {
 					return err
 				}
+
+				for _, db := range added {
+					output.Info(fmt.Sprintf("Adding %s %s on port %s", db.Engine, db.Version, db.Port))
+				}
+
+				if len(added) > 0 {
+					if err := cfg.Save(); err != nil {
+						return err
+					}
+				}
 			}
 
 			output.Info("Checking Nitro…")
@@ -101,8 +142,18 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				output.Done()
 			}
 
+			// get the restart policy, protocols, and additional ports for the proxy
+			var restartPolicy string
+			var http3 bool
+			var proxyPorts []string
+			if cfg, err := config.Load(home); err == nil {
+				restartPolicy = cfg.RestartPolicy
+				http3 = cfg.HTTP3
+				proxyPorts = cfg.ProxyPorts
+			}
+
 			// create the proxy container
-			if err := proxycontainer.Create(cmd.Context(), docker, output, networkID); err != nil {
+			if err := proxycontainer.Create(cmd.Context(), docker, output, networkID, restartPolicy, http3, proxyPorts); err != nil {
 				return err
 			}
 
@@ -136,6 +187,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 	// set flags for the command
 	cmd.Flags().BoolVar(&skipApply, "skip-apply", false, "skip applying changes")
 	cmd.Flags().BoolVar(&skipTrust, "skip-trust", false, "skip trusting the root certificate")
+	cmd.Flags().StringVar(&flagDatabases, "db", "", "database engines to provision, e.g. mysql:8.0,postgres:13")
 
 	return cmd
 }
@@ -198,3 +198,37 @@ func TestInitFromFreshCreatesNewResources(t *testing.T) {
 		)
 	}
 }
+
+func TestInitSetsTheProxyRestartPolicy(t *testing.T) {
+	// remove environment var
+	os.Setenv("NITRO_DEVELOPMENT", "false")
+	defer os.Unsetenv("NITRO_DEVELOPMENT")
+
+	// Arrange
+	mock := newMockDockerClient(nil, nil, nil)
+	mock.networkCreateResponse = types.NetworkCreateResponse{
+		ID: "testing-init",
+	}
+	mock.containerCreateResponse = container.ContainerCreateCreatedBody{
+		ID: "testingid",
+	}
+	home, _ := os.Getwd()
+	home = filepath.Join(home, "testdata", "restart")
+
+	// Act
+	cmd := NewCommand(home, mock, spyOutputer{})
+	err := cmd.RunE(cmd, os.Args)
+
+	// Assert
+	if err != nil {
+		t.Errorf("expected the error to be nil, got %v", err)
+	}
+
+	if len(mock.containerCreateRequests) == 0 {
+		t.Fatal("expected the proxy container to be created")
+	}
+
+	if policy := mock.containerCreateRequests[0].HostConfig.RestartPolicy.Name; policy != "unless-stopped" {
+		t.Errorf("expected the restart policy to be %q, got %q", "unless-stopped", policy)
+	}
+}
@@ -0,0 +1,268 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/pkg/stdcopy"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+)
+
+// colors are the ANSI colors used to prefix the lines from each site, they are
+// assigned in order and reused when there are more sites than colors.
+var colors = []string{
+	"\033[36m", // cyan
+	"\033[33m", // yellow
+	"\033[32m", // green
+	"\033[35m", // magenta
+	"\033[34m", // blue
+	"\033[91m", // bright red
+	"\033[96m", // bright cyan
+	"\033[93m", // bright yellow
+}
+
+const colorReset = "\033[0m"
+
+// logClient is the subset of the docker API used to aggregate the logs.
+type logClient interface {
+	ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error)
+	ContainerLogs(ctx context.Context, container string, options types.ContainerLogsOptions) (io.ReadCloser, error)
+	Events(ctx context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error)
+}
+
+// line is a single line of output from a site container.
+type line struct {
+	stderr bool
+	text   []byte
+}
+
+// aggregator follows the logs from every site container and multiplexes the
+// lines into a single stream, each line is prefixed with the site hostname.
+type aggregator struct {
+	docker logClient
+	opts   types.ContainerLogsOptions
+	filter *filter
+	lines  chan line
+
+	// width is used to align the prefixes
+	width int
+	// color is true when the prefixes are colored
+	color bool
+
+	mu        sync.Mutex
+	following map[string]bool
+	colors    map[string]string
+	wg        sync.WaitGroup
+}
+
+// aggregate writes the logs from all of the site containers, that match the filter, to
+// stdout and stderr until the logs end or, when following, the context is canceled.
+// Containers that start while following are added to the stream. The prefixes are only
+// colored when color is true.
+func aggregate(ctx context.Context, docker logClient, opts types.ContainerLogsOptions, f *filter, color bool, stdout, stderr io.Writer) error {
+	labels := filters.NewArgs()
+	labels.Add("label", containerlabels.Nitro)
+	labels.Add("label", containerlabels.Host)
+
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{Filters: labels})
+	if err != nil {
+		return err
+	}
+
+	if len(containers) == 0 && !opts.Follow {
+		return fmt.Errorf("there are no running site containers")
+	}
+
+	a := &aggregator{
+		docker:    docker,
+		opts:      opts,
+		filter:    f,
+		lines:     make(chan line),
+		color:     color,
+		following: make(map[string]bool),
+		colors:    make(map[string]string),
+	}
+
+	for _, c := range containers {
+		if l := len(c.Labels[containerlabels.Host]); l > a.width {
+			a.width = l
+		}
+	}
+
+	for _, c := range containers {
+		a.follow(ctx, c.ID, c.Labels[containerlabels.Host], opts)
+	}
+
+	// watch for site containers starting while following the logs
+	if opts.Follow {
+		a.wg.Add(1)
+		go a.watch(ctx, labels)
+	}
+
+	// close the lines once all of the containers stop sending logs
+	go func() {
+		a.wg.Wait()
+		close(a.lines)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case l, ok := <-a.lines:
+			if !ok {
+				return nil
+			}
+
+			w := stdout
+			if l.stderr {
+				w = stderr
+			}
+
+			if _, err := w.Write(l.text); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// follow starts a goroutine that sends the logs from the container to the lines, it
+// is a no-op if the container is already being followed.
+func (a *aggregator) follow(ctx context.Context, id, hostname string, opts types.ContainerLogsOptions) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.following[id] {
+		return
+	}
+
+	a.following[id] = true
+
+	prefix := fmt.Sprintf("%-*s | ", a.width, hostname)
+	if a.color {
+		color, ok := a.colors[hostname]
+		if !ok {
+			color = colors[len(a.colors)%len(colors)]
+			a.colors[hostname] = color
+		}
+
+		prefix = fmt.Sprintf("%s%-*s |%s ", color, a.width, hostname, colorReset)
+	}
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+
+		// allow the container to be followed again if it is restarted
+		defer func() {
+			a.mu.Lock()
+			delete(a.following, id)
+			a.mu.Unlock()
+		}()
+
+		out, err := a.docker.ContainerLogs(ctx, id, opts)
+		if err != nil {
+			a.send(ctx, line{stderr: true, text: []byte(prefix + "unable to get the logs, " + err.Error() + "\n")})
+
+			return
+		}
+		defer out.Close()
+
+		stdout := &prefixWriter{prefix: prefix, filter: a.filter, send: func(b []byte) { a.send(ctx, line{text: b}) }}
+		stderr := &prefixWriter{prefix: prefix, filter: a.filter, send: func(b []byte) { a.send(ctx, line{stderr: true, text: b}) }}
+
+		stdcopy.StdCopy(stdout, stderr, out)
+
+		stdout.flush()
+		stderr.flush()
+	}()
+}
+
+// watch listens for site containers starting and follows their logs from the time they
+// started.
+func (a *aggregator) watch(ctx context.Context, labels filters.Args) {
+	defer a.wg.Done()
+
+	f := labels.Clone()
+	f.Add("type", "container")
+	f.Add("event", "start")
+
+	msgs, errs := a.docker.Events(ctx, types.EventsOptions{Filters: f})
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-errs:
+			return
+		case msg := <-msgs:
+			hostname := msg.Actor.Attributes[containerlabels.Host]
+			if hostname == "" {
+				continue
+			}
+
+			// only show the logs since the container started
+			opts := a.opts
+			opts.Since = time.Unix(msg.Time, 0).Format(time.RFC3339)
+
+			a.follow(ctx, msg.Actor.ID, hostname, opts)
+		}
+	}
+}
+
+// send writes the line to the aggregated output unless the context is done.
+func (a *aggregator) send(ctx context.Context, l line) {
+	select {
+	case <-ctx.Done():
+	case a.lines <- l:
+	}
+}
+
+// prefixWriter is an io.Writer that buffers the content until a full line is available
+// and then sends the line with the prefix if it matches the filter.
+type prefixWriter struct {
+	prefix string
+	filter *filter
+	send   func([]byte)
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		if w.filter == nil || w.filter.matches(w.buf[:i+1]) {
+			w.send(append([]byte(w.prefix), w.buf[:i+1]...))
+		}
+
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// flush sends any remaining partial line.
+func (w *prefixWriter) flush() {
+	if len(strings.TrimSpace(string(w.buf))) == 0 || (w.filter != nil && !w.filter.matches(w.buf)) {
+		w.buf = nil
+
+		return
+	}
+
+	w.send(append([]byte(w.prefix), append(w.buf, '\n')...))
+
+	w.buf = nil
+}
@@ -0,0 +1,105 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/pkg/stdcopy"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+)
+
+type mockLogClient struct {
+	containers []types.Container
+	// logs are the stdout lines for each container id
+	logs map[string]string
+}
+
+func (c *mockLogClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	return c.containers, nil
+}
+
+func (c *mockLogClient) ContainerLogs(ctx context.Context, container string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+	var buf bytes.Buffer
+	w := stdcopy.NewStdWriter(&buf, stdcopy.Stdout)
+	if _, err := w.Write([]byte(c.logs[container])); err != nil {
+		return nil, err
+	}
+
+	return ioutil.NopCloser(&buf), nil
+}
+
+func (c *mockLogClient) Events(ctx context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error) {
+	return make(chan events.Message), make(chan error)
+}
+
+func TestAggregate(t *testing.T) {
+	docker := &mockLogClient{
+		containers: []types.Container{
+			{ID: "one", Labels: map[string]string{containerlabels.Host: "one.nitro"}},
+			{ID: "two", Labels: map[string]string{containerlabels.Host: "longer.nitro"}},
+		},
+		logs: map[string]string{
+			"one": "GET / 200\nGET /admin 500\n",
+			"two": "POST /admin 200\npartial line",
+		},
+	}
+
+	f, err := newFilter(nil, "admin", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		color bool
+		want  []string
+	}{
+		{
+			name:  "prefixes are colored for terminals",
+			color: true,
+			want: []string{
+				colors[0] + "one.nitro    |" + colorReset + " GET /admin 500",
+				colors[1] + "longer.nitro |" + colorReset + " POST /admin 200",
+			},
+		},
+		{
+			name: "prefixes are not colored otherwise",
+			want: []string{
+				"one.nitro    | GET /admin 500",
+				"longer.nitro | POST /admin 200",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			if err := aggregate(context.TODO(), docker, types.ContainerLogsOptions{}, f, tt.color, &stdout, ioutil.Discard); err != nil {
+				t.Fatal(err)
+			}
+
+			lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+			sort.Strings(lines)
+			sort.Strings(tt.want)
+
+			if strings.Join(lines, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("expected the output to be\n%q\ngot\n%q", tt.want, lines)
+			}
+		})
+	}
+}
+
+func TestAggregateWithoutContainers(t *testing.T) {
+	docker := &mockLogClient{}
+
+	if err := aggregate(context.TODO(), docker, types.ContainerLogsOptions{}, nil, false, ioutil.Discard, ioutil.Discard); err == nil {
+		t.Error("expected an error when there are no site containers")
+	}
+}
@@ -0,0 +1,124 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// levels maps the known log levels to their severity, it is used to
+// filter structured (JSON) logs such as the ones Caddy writes.
+var levels = map[string]int{
+	"debug": 0,
+	"info":  1,
+	"warn":  2,
+	"error": 3,
+}
+
+// timestamp matches the timestamp docker adds to the start of each line with --timestamps
+// (e.g. 2021-01-02T15:04:05.123456789Z).
+var timestamp = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\S+ `)
+
+// filter is an io.Writer that will only write lines to the underlying
+// writer that match the pattern and log level.
+type filter struct {
+	w       io.Writer
+	pattern *regexp.Regexp
+	level   int
+	buf     []byte
+}
+
+// newFilter takes a writer, a pattern to match lines against, and the minimum level
+// to show. If the pattern or level are empty, lines are not filtered by them.
+func newFilter(w io.Writer, pattern, level string) (*filter, error) {
+	f := &filter{w: w, level: -1}
+
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the grep pattern, %w", err)
+		}
+
+		f.pattern = re
+	}
+
+	if level != "" {
+		l, ok := levels[strings.ToLower(level)]
+		if !ok {
+			return nil, fmt.Errorf("unknown log level %q, must be one of error, warn, info, or debug", level)
+		}
+
+		f.level = l
+	}
+
+	return f, nil
+}
+
+// Write buffers the content until a full line is available
+// and then writes the line if it passes the filters.
+func (f *filter) Write(p []byte) (int, error) {
+	f.buf = append(f.buf, p...)
+
+	for {
+		i := bytes.IndexByte(f.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		line := f.buf[:i+1]
+		if f.matches(line) {
+			if _, err := f.w.Write(line); err != nil {
+				return 0, err
+			}
+		}
+
+		f.buf = f.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush writes any remaining partial line.
+func (f *filter) Flush() error {
+	if len(f.buf) == 0 {
+		return nil
+	}
+
+	defer func() { f.buf = nil }()
+
+	if !f.matches(f.buf) {
+		return nil
+	}
+
+	_, err := f.w.Write(f.buf)
+
+	return err
+}
+
+func (f *filter) matches(line []byte) bool {
+	if f.pattern != nil && !f.pattern.Match(line) {
+		return false
+	}
+
+	if f.level < 0 {
+		return true
+	}
+
+	// only structured logs have a level, plain lines are always shown
+	entry := struct {
+		Level string `json:"level"`
+	}{}
+	if err := json.Unmarshal(bytes.TrimSpace(timestamp.ReplaceAll(line, nil)), &entry); err != nil || entry.Level == "" {
+		return true
+	}
+
+	l, ok := levels[strings.ToLower(entry.Level)]
+	if !ok {
+		return true
+	}
+
+	return l >= f.level
+}
@@ -0,0 +1,112 @@
+package logs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	type args struct {
+		pattern string
+		level   string
+		input   []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "no filters returns all lines",
+			args: args{
+				input: []string{"first line\n", "second line\n"},
+			},
+			want: "first line\nsecond line\n",
+		},
+		{
+			name: "grep only returns matching lines",
+			args: args{
+				pattern: "GET /admin",
+				input:   []string{"GET / 200\nGET /admin 500\n", "POST /admin 200\n"},
+			},
+			want: "GET /admin 500\n",
+		},
+		{
+			name: "lines split across writes are matched",
+			args: args{
+				pattern: "error",
+				input:   []string{"an err", "or occurred\nall good\n"},
+			},
+			want: "an error occurred\n",
+		},
+		{
+			name: "level filters structured logs and keeps plain lines",
+			args: args{
+				level: "warn",
+				input: []string{`{"level":"info","msg":"handled request"}` + "\n", `{"level":"error","msg":"dial tcp"}` + "\n", "plain line\n"},
+			},
+			want: `{"level":"error","msg":"dial tcp"}` + "\nplain line\n",
+		},
+		{
+			name: "level filters structured logs with timestamps",
+			args: args{
+				level: "error",
+				input: []string{
+					`2021-01-02T15:04:05.123456789Z {"level":"warn","msg":"slow request"}` + "\n",
+					`2021-01-02T15:04:06.123456789Z {"level":"error","msg":"dial tcp"}` + "\n",
+				},
+			},
+			want: `2021-01-02T15:04:06.123456789Z {"level":"error","msg":"dial tcp"}` + "\n",
+		},
+		{
+			name: "debug shows every level",
+			args: args{
+				level: "debug",
+				input: []string{`{"level":"debug","msg":"matched route"}` + "\n", `{"level":"info","msg":"handled request"}` + "\n"},
+			},
+			want: `{"level":"debug","msg":"matched route"}` + "\n" + `{"level":"info","msg":"handled request"}` + "\n",
+		},
+		{
+			name: "invalid patterns return an error",
+			args: args{
+				pattern: "(",
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown levels return an error",
+			args: args{
+				level: "fatalish",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			f, err := newFilter(buf, tt.args.pattern, tt.args.level)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newFilter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			for _, in := range tt.args.input {
+				if _, err := f.Write([]byte(in)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := f.Flush(); err != nil {
+				t.Fatal(err)
+			}
+
+			if buf.String() != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, buf.String())
+			}
+		})
+	}
+}
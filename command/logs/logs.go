@@ -1,17 +1,26 @@
 package logs
 
 import (
+	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/spf13/cobra"
+	sshterminal "golang.org/x/crypto/ssh/terminal"
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/logfile"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
@@ -22,7 +31,19 @@ const exampleText = `  # show logs from a site
   nitro logs --since 5m
 
   # show logs but don't follow
-  nitro logs --follow=false`
+  nitro logs --follow=false
+
+  # show only lines matching a pattern
+  nitro logs --grep "POST /admin"
+
+  # show only warnings and errors from structured logs
+  nitro logs --level warn
+
+  # follow the logs from every site
+  nitro logs --all
+
+  # also write the logs to a file, rotated every 10 MB keeping the last 5 files
+  nitro logs --out ~/nitro-debug.log --max-size 10 --max-files 5`
 
 // NewCommand returns the command to show a containers logs. It will check if the current working
 // directory is a known site and default to that container or provide the user with a list of sites
@@ -34,6 +55,28 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		Short:   "Displays container logs.",
 		Example: exampleText,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// write the logs to a file as well as the output
+			stdoutWriter, stderrWriter := cmd.OutOrStdout(), cmd.ErrOrStderr()
+			if out := cmd.Flag("out").Value.String(); out != "" {
+				f, err := openOut(cmd, home, out)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+
+				output.Info("Writing the logs to", f.Name())
+
+				// a failed write to the file is reported without stopping the logs
+				file := &outFile{w: &plainWriter{w: f}, name: f.Name(), errs: cmd.ErrOrStderr()}
+				stdoutWriter = &teeWriter{w: stdoutWriter, file: file}
+				stderrWriter = &teeWriter{w: stderrWriter, file: file}
+			}
+
+			// show the logs from every site, the prefixes are only colored in a terminal
+			if all, _ := strconv.ParseBool(cmd.Flag("all").Value.String()); all {
+				return allLogs(cmd, docker, stdoutWriter, stderrWriter, isTerminal(cmd.OutOrStdout()))
+			}
+
 			// get the current working directory
 			wd, err := os.Getwd()
 			if err != nil {
@@ -87,26 +130,17 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			}
 
 			// set the options for logging based on the command flags
-			opts := types.ContainerLogsOptions{
-				ShowStdout: true,
-				ShowStderr: true,
-			}
+			opts := logOptions(cmd)
 
-			// parse the flags
-			timestamps, err := strconv.ParseBool(cmd.Flag("timestamps").Value.String())
+			// create the filters for the output
+			stdout, err := newFilter(stdoutWriter, cmd.Flag("grep").Value.String(), cmd.Flag("level").Value.String())
 			if err != nil {
-				timestamps = false
+				return err
 			}
-			opts.Timestamps = timestamps
 
-			follow, err := strconv.ParseBool(cmd.Flag("follow").Value.String())
+			stderr, err := newFilter(stderrWriter, cmd.Flag("grep").Value.String(), cmd.Flag("level").Value.String())
 			if err != nil {
-				follow = true
-			}
-			opts.Follow = follow
-
-			if cmd.Flag("since").Value.String() != "" {
-				opts.Since = cmd.Flag("since").Value.String()
+				return err
 			}
 
 			// get the containers logs
@@ -116,9 +150,14 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			}
 
 			// show the output
-			stdcopy.StdCopy(cmd.OutOrStdout(), cmd.ErrOrStderr(), out)
+			stdcopy.StdCopy(stdout, stderr, out)
 
-			return nil
+			// write any remaining output
+			if err := stdout.Flush(); err != nil {
+				return err
+			}
+
+			return stderr.Flush()
 		},
 	}
 
@@ -126,6 +165,143 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 	cmd.Flags().Bool("follow", true, "follow log output")
 	cmd.Flags().Bool("timestamps", false, "show timestamps")
 	cmd.Flags().String("since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37Z) or relative (e.g. 42m for 42 minutes)")
+	cmd.Flags().String("grep", "", "only show lines matching a regular expression")
+	cmd.Flags().String("level", "", "only show structured logs at or above a level (error, warn, info, or debug), plain lines are always shown")
+	cmd.Flags().Bool("all", false, "show the logs from every site with the site name prefixed to each line")
+	cmd.Flags().String("out", "", "also write the logs to a file, without colors")
+	cmd.Flags().Int("max-size", 0, "rotate the --out file once it reaches the size in megabytes, 0 does not rotate")
+	cmd.Flags().Int("max-files", 3, "the number of rotated --out files to keep")
 
 	return cmd
 }
+
+// logOptions returns the options for logging based on the command flags.
+func logOptions(cmd *cobra.Command) types.ContainerLogsOptions {
+	opts := types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+	}
+
+	// parse the flags
+	timestamps, err := strconv.ParseBool(cmd.Flag("timestamps").Value.String())
+	if err != nil {
+		timestamps = false
+	}
+	opts.Timestamps = timestamps
+
+	follow, err := strconv.ParseBool(cmd.Flag("follow").Value.String())
+	if err != nil {
+		follow = true
+	}
+	opts.Follow = follow
+
+	if cmd.Flag("since").Value.String() != "" {
+		opts.Since = cmd.Flag("since").Value.String()
+	}
+
+	return opts
+}
+
+// allLogs follows the logs from every site container until interrupted.
+func allLogs(cmd *cobra.Command, docker client.CommonAPIClient, stdout, stderr io.Writer, color bool) error {
+	f, err := newFilter(nil, cmd.Flag("grep").Value.String(), cmd.Flag("level").Value.String())
+	if err != nil {
+		return err
+	}
+
+	// stop following the logs on interrupt
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	return aggregate(ctx, docker, logOptions(cmd), f, color, stdout, stderr)
+}
+
+// isTerminal returns true when the writer is a terminal, so the logs are not colored when
+// they are piped or redirected to a file.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+
+	return ok && sshterminal.IsTerminal(int(f.Fd()))
+}
+
+// openOut opens the file to write the logs to using the rotation flags, a path starting
+// with ~ is relative to the home directory.
+func openOut(cmd *cobra.Command, home, out string) (*logfile.File, error) {
+	maxSize, err := strconv.Atoi(cmd.Flag("max-size").Value.String())
+	if err != nil {
+		return nil, err
+	}
+
+	keep, err := strconv.Atoi(cmd.Flag("max-files").Value.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(out, "~") {
+		out = strings.Replace(out, "~", home, 1)
+	}
+
+	path, err := filepath.Abs(out)
+	if err != nil {
+		return nil, err
+	}
+
+	return logfile.Open(path, int64(maxSize)*1024*1024, keep)
+}
+
+// colorCodes matches the ANSI escape sequences used to color the site prefixes.
+var colorCodes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// plainWriter removes the colors from the output before writing to the log file.
+type plainWriter struct {
+	w io.Writer
+}
+
+func (p *plainWriter) Write(b []byte) (int, error) {
+	if _, err := p.w.Write(colorCodes.ReplaceAll(b, nil)); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// outFile writes the logs to the log file and reports a failed write on errs, once until
+// a write succeeds again.
+type outFile struct {
+	w    io.Writer
+	name string
+	errs io.Writer
+
+	mu      sync.Mutex
+	failing bool
+}
+
+func (o *outFile) write(b []byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	_, err := o.w.Write(b)
+	switch {
+	case err == nil:
+		o.failing = false
+	case !o.failing:
+		o.failing = true
+
+		fmt.Fprintf(o.errs, "unable to write the logs to %s, %v\n", o.name, err)
+	}
+}
+
+// teeWriter writes to the output and copies the writes to the log file, the errors from
+// the log file are not returned so the output continues when the file can't be written.
+type teeWriter struct {
+	w    io.Writer
+	file *outFile
+}
+
+func (t *teeWriter) Write(b []byte) (int, error) {
+	n, err := t.w.Write(b)
+
+	t.file.write(b[:n])
+
+	return n, err
+}
@@ -0,0 +1,70 @@
+package logs
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPlainWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := &plainWriter{w: buf}
+
+	input := "\x1b[36mapp.nitro\x1b[0m | GET / 200\n"
+	n, err := w.Write([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != len(input) {
+		t.Errorf("expected to write %d bytes, got %d", len(input), n)
+	}
+
+	if got, want := buf.String(), "app.nitro | GET / 200\n"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// failingWriter fails every write until it is fixed.
+type failingWriter struct {
+	buf   bytes.Buffer
+	fixed bool
+}
+
+func (f *failingWriter) Write(b []byte) (int, error) {
+	if !f.fixed {
+		return 0, errors.New("no space left on device")
+	}
+
+	return f.buf.Write(b)
+}
+
+func TestTeeWriter(t *testing.T) {
+	out, errs := &bytes.Buffer{}, &bytes.Buffer{}
+	file := &failingWriter{}
+	w := &teeWriter{w: out, file: &outFile{w: file, name: "nitro.log", errs: errs}}
+
+	for _, l := range []string{"one\n", "two\n"} {
+		if _, err := w.Write([]byte(l)); err != nil {
+			t.Fatalf("expected the file error to not be returned, got %v", err)
+		}
+	}
+
+	file.fixed = true
+	if _, err := w.Write([]byte("three\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := out.String(), "one\ntwo\nthree\n"; got != want {
+		t.Errorf("expected the output to be %q, got %q", want, got)
+	}
+
+	if got, want := file.buf.String(), "three\n"; got != want {
+		t.Errorf("expected the file to be %q, got %q", want, got)
+	}
+
+	if got := strings.Count(errs.String(), "unable to write the logs to nitro.log"); got != 1 {
+		t.Errorf("expected the failure to be reported once, got %q", errs.String())
+	}
+}
@@ -1,6 +1,7 @@
 package ls
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -22,12 +23,28 @@ const exampleText = `  # view information about your nitro environment
   nitro ls --databases
 
   # show only sites
-  nitro ls --sites`
+  nitro ls --sites
+
+  # output the containers as json for tooling
+  nitro ls --json`
 
 var (
-	flagCustom, flagDatabases, flagProxy, flagServices, flagSites bool
+	flagCustom, flagDatabases, flagJSON, flagProxy, flagServices, flagSites bool
 )
 
+// Container is the machine readable representation of a container
+// that is output when using the json flag.
+type Container struct {
+	Name          string   `json:"name"`
+	Type          string   `json:"type"`
+	Hostname      string   `json:"hostname,omitempty"`
+	Engine        string   `json:"engine,omitempty"`
+	Version       string   `json:"version,omitempty"`
+	InternalPorts []string `json:"internal_ports"`
+	ExternalPorts []string `json:"external_ports"`
+	Status        string   `json:"status"`
+}
+
 func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "ls",
@@ -53,6 +70,9 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			// define the table headers
 			tbl := table.New("Hostname", "Type", "Internal Ports", "External Ports", "Status").WithWriter(cmd.OutOrStdout()).WithPadding(2)
 
+			// always output an array for json, even when empty
+			list := []Container{}
+
 			for _, c := range containers {
 				status := "running"
 				if c.State == "exited" {
@@ -124,12 +144,34 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 					return extPorts[i] < extPorts[j]
 				})
 
+				if flagJSON {
+					list = append(list, Container{
+						Name:          strings.TrimLeft(c.Names[0], "/"),
+						Type:          containerlabels.Identify(c),
+						Hostname:      c.Labels[containerlabels.Host],
+						Engine:        c.Labels[containerlabels.DatabaseEngine],
+						Version:       version(c),
+						InternalPorts: nonNil(intPorts),
+						ExternalPorts: nonNil(extPorts),
+						Status:        status,
+					})
+
+					continue
+				}
+
 				internalPorts := strings.Join(intPorts, ",")
 				externalPorts := strings.Join(extPorts, ",")
 
 				tbl.AddRow(strings.TrimLeft(c.Names[0], "/"), containerlabels.Identify(c), internalPorts, externalPorts, status)
 			}
 
+			if flagJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+
+				return enc.Encode(list)
+			}
+
 			tbl.Print()
 
 			return nil
@@ -141,6 +183,33 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 	cmd.Flags().BoolVarP(&flagServices, "services", "v", false, "show only services")
 	cmd.Flags().BoolVarP(&flagCustom, "custom", "c", false, "show only custom containers")
 	cmd.Flags().BoolVarP(&flagProxy, "proxy", "p", false, "show only proxy container")
+	cmd.Flags().BoolVar(&flagJSON, "json", false, "output the containers as json")
 
 	return cmd
 }
+
+// version returns the version of the container using the labels for
+// databases and the proxy, or the image tag for everything else.
+func version(c types.Container) string {
+	if v := c.Labels[containerlabels.DatabaseVersion]; v != "" {
+		return v
+	}
+
+	if v := c.Labels[containerlabels.ProxyVersion]; v != "" {
+		return v
+	}
+
+	if i := strings.LastIndex(c.Image, ":"); i != -1 && !strings.Contains(c.Image[i:], "/") {
+		return c.Image[i+1:]
+	}
+
+	return ""
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+
+	return s
+}
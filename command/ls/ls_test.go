@@ -0,0 +1,91 @@
+package ls
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+)
+
+type mockDockerClient struct {
+	client.CommonAPIClient
+	containers []types.Container
+}
+
+func (c mockDockerClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	return c.containers, nil
+}
+
+func TestLsJSONOutput(t *testing.T) {
+	docker := mockDockerClient{
+		containers: []types.Container{
+			{
+				Names: []string{"/craftdev.nitro"},
+				Image: "craftcms/nitro:7.4",
+				State: "running",
+				Labels: map[string]string{
+					containerlabels.Nitro: "true",
+					containerlabels.Host:  "craftdev.nitro",
+				},
+			},
+			{
+				Names: []string{"/mysql-8.0-3306.database.nitro"},
+				Image: "mysql:8.0",
+				State: "exited",
+				Ports: []types.Port{{PrivatePort: 3306, PublicPort: 3306}},
+				Labels: map[string]string{
+					containerlabels.Nitro:           "true",
+					containerlabels.Type:            "database",
+					containerlabels.DatabaseEngine:  "mysql",
+					containerlabels.DatabaseVersion: "8.0",
+				},
+			},
+		},
+	}
+
+	want := []Container{
+		{
+			Name:          "craftdev.nitro",
+			Type:          "site",
+			Hostname:      "craftdev.nitro",
+			Version:       "7.4",
+			InternalPorts: []string{"3000", "3001", "8080"},
+			ExternalPorts: []string{"(uses proxy ports)"},
+			Status:        "running",
+		},
+		{
+			Name:          "mysql-8.0-3306.database.nitro",
+			Type:          "database",
+			Engine:        "mysql",
+			Version:       "8.0",
+			InternalPorts: []string{"3306"},
+			ExternalPorts: []string{"3306"},
+			Status:        "stopped",
+		},
+	}
+
+	cmd := NewCommand("", docker, nil)
+	cmd.SetArgs([]string{"--json"})
+
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+
+	if err := cmd.ExecuteContext(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []Container
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unable to decode the output %q: %v", buf.String(), err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
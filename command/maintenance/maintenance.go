@@ -0,0 +1,138 @@
+package maintenance
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/prompt"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+const exampleText = `  # put the current site in maintenance mode
+  nitro maintenance on
+
+  # take a specific site out of maintenance mode
+  nitro maintenance off tutorial.nitro
+
+  # use a custom maintenance page by setting the maintenance_page in the config
+  maintenance_page: ~/dev/maintenance.html`
+
+// NewCommand returns the command to turn maintenance mode on or off for a site. Sites in maintenance
+// mode respond to all requests with a 503 and the maintenance page. The changes are applied once the
+// config is saved.
+func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "maintenance",
+		Short:   "Toggles maintenance mode for a site.",
+		Example: exampleText,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 || len(args) > 2 {
+				return fmt.Errorf("expected on or off and an optional site")
+			}
+
+			if args[0] != "on" && args[0] != "off" {
+				return fmt.Errorf("unknown option %q, use on or off", args[0])
+			}
+
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return []string{"on", "off"}, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			cfg, err := config.Load(home)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+
+			var options []string
+			for _, s := range cfg.Sites {
+				options = append(options, s.Hostname)
+			}
+
+			return options, cobra.ShellCompDirectiveNoFileComp
+		},
+		PostRunE: func(cmd *cobra.Command, args []string) error {
+			return prompt.RunApply(cmd, nil, true, output)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// load the config
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			// get the current working directory
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			// get a context aware list of sites
+			sites := cfg.ListOfSitesByDirectory(home, wd)
+
+			// create the options for the sites
+			var options []string
+			for _, s := range sites {
+				options = append(options, s.Hostname)
+			}
+
+			var siteArg string
+			if len(args) > 1 {
+				siteArg = strings.TrimSpace(args[1])
+			}
+
+			var site *config.Site
+			switch siteArg == "" {
+			case true:
+				switch len(sites) {
+				case 1:
+					site = &sites[0]
+				default:
+					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					if err != nil {
+						return err
+					}
+
+					site = &sites[selected]
+				}
+			default:
+				site, err = cfg.FindSiteByHostName(siteArg)
+				if err != nil {
+					return err
+				}
+			}
+
+			enabled := args[0] == "on"
+
+			if err := cfg.SetMaintenance(site.Hostname, enabled); err != nil {
+				return err
+			}
+
+			// make sure the maintenance page can be read before saving
+			if _, err := cfg.MaintenanceBody(home); err != nil {
+				return err
+			}
+
+			if err := cfg.Save(); err != nil {
+				return err
+			}
+
+			if enabled {
+				output.Info("Maintenance mode is on for", site.Hostname)
+			} else {
+				output.Info("Maintenance mode is off for", site.Hostname)
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,154 @@
+package mount
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/pathexists"
+	"github.com/craftcms/nitro/pkg/prompt"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/pkg/validate"
+)
+
+const exampleText = `  # add an extra directory to a sites container
+  nitro mount
+
+  # add a mount to a specific site
+  nitro mount tutorial.nitro`
+
+// NewCommand allows users to mount extra directories, such as shared libraries outside of
+// the project, into a sites container.
+func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "mount",
+		Short:   "Adds extra mounts to a site.",
+		Example: exampleText,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			cfg, err := config.Load(home)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+
+			var options []string
+			for _, s := range cfg.Sites {
+				options = append(options, s.Hostname)
+			}
+
+			return options, cobra.ShellCompDirectiveDefault
+		},
+		PostRunE: func(cmd *cobra.Command, args []string) error {
+			return prompt.RunApply(cmd, args, false, output)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// get the current working directory
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			// load the configuration
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			// check for a site arg
+			var siteArg string
+			if len(args) > 0 {
+				siteArg = strings.TrimSpace(args[0])
+			}
+
+			// get a context aware list of sites
+			sites := cfg.ListOfSitesByDirectory(home, wd)
+
+			var options []string
+			for _, s := range sites {
+				options = append(options, s.Hostname)
+			}
+
+			// did they ask for a specific site?
+			var site *config.Site
+			switch siteArg == "" {
+			case true:
+				switch len(options) {
+				case 0:
+					return fmt.Errorf("unable to find any sites")
+				case 1:
+					output.Info("adding a mount to", options[0])
+
+					site, _ = cfg.FindSiteByHostName(options[0])
+				default:
+					// prompt for the site to add the mount
+					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					if err != nil {
+						return err
+					}
+
+					site, _ = cfg.FindSiteByHostName(options[selected])
+				}
+
+				// show mounts if they exist
+				if len(site.Mounts) > 0 {
+					output.Info("The following mounts are set for", site.Hostname)
+					for _, m := range site.Mounts {
+						output.Info("  ", m.Source, "->", m.Target)
+					}
+				}
+			default:
+				site, err = cfg.FindSiteByHostName(siteArg)
+				if err != nil {
+					return err
+				}
+			}
+
+			// prompt for the directory on the host
+			source, err := output.Ask("Enter the path to the directory to mount", "", ":", nil)
+			if err != nil {
+				return err
+			}
+
+			m := config.Mount{Source: source}
+
+			// make sure the source exists
+			abs, err := m.GetAbsSource(home)
+			if err != nil {
+				return err
+			}
+
+			if !pathexists.IsDirectory(abs) && !pathexists.IsFile(abs) {
+				return fmt.Errorf("unable to find the path %s", abs)
+			}
+
+			// prompt for the path in the container
+			m.Target, err = output.Ask("Enter the path in the container", "", ":", &validate.MountTarget{})
+			if err != nil {
+				return err
+			}
+
+			m.ReadOnly, err = output.Confirm("Should the mount be read only?", false, "")
+			if err != nil {
+				return err
+			}
+
+			if err := cfg.AddSiteMount(site.Hostname, m); err != nil {
+				return err
+			}
+
+			output.Info("Mounting", abs, "to", m.Target)
+
+			// save the config file
+			if err := cfg.Save(); err != nil {
+				return fmt.Errorf("unable to save config, %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
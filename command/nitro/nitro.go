@@ -1,8 +1,11 @@
 package nitro
 
 import (
+	"fmt"
 	"log"
+	"net"
 	"os"
+	"runtime"
 
 	nitroclient "github.com/craftcms/nitro/client"
 	"github.com/craftcms/nitro/command/add"
@@ -13,8 +16,10 @@ import (
 	"github.com/craftcms/nitro/command/clean"
 	"github.com/craftcms/nitro/command/completion"
 	"github.com/craftcms/nitro/command/composer"
+	"github.com/craftcms/nitro/command/configuration"
 	"github.com/craftcms/nitro/command/container"
 	"github.com/craftcms/nitro/command/context"
+	"github.com/craftcms/nitro/command/cp"
 	"github.com/craftcms/nitro/command/craft"
 	"github.com/craftcms/nitro/command/create"
 	"github.com/craftcms/nitro/command/database"
@@ -28,10 +33,15 @@ import (
 	"github.com/craftcms/nitro/command/initialize"
 	"github.com/craftcms/nitro/command/logs"
 	"github.com/craftcms/nitro/command/ls"
+	"github.com/craftcms/nitro/command/maintenance"
+	"github.com/craftcms/nitro/command/mount"
 	"github.com/craftcms/nitro/command/npm"
 	"github.com/craftcms/nitro/command/php"
+	"github.com/craftcms/nitro/command/phpext"
 	"github.com/craftcms/nitro/command/portcheck"
+	"github.com/craftcms/nitro/command/proxy"
 	"github.com/craftcms/nitro/command/queue"
+	"github.com/craftcms/nitro/command/reload"
 	"github.com/craftcms/nitro/command/remove"
 	"github.com/craftcms/nitro/command/restart"
 	"github.com/craftcms/nitro/command/selfupdate"
@@ -45,7 +55,12 @@ import (
 	"github.com/craftcms/nitro/command/version"
 	"github.com/craftcms/nitro/command/xoff"
 	"github.com/craftcms/nitro/command/xon"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/dockerhost"
+	"github.com/craftcms/nitro/pkg/dockerlog"
 	"github.com/craftcms/nitro/pkg/downloader"
+	"github.com/craftcms/nitro/pkg/imagepull"
+	"github.com/craftcms/nitro/pkg/proxycontainer"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/docker/docker/client"
 	"github.com/mitchellh/go-homedir"
@@ -60,13 +75,39 @@ var rootCommand = &cobra.Command{
 Version: ` + version.Version,
 	RunE:         rootMain,
 	SilenceUsage: true,
-	Version:      version.Version,
+	// errors are written by main with the error code
+	SilenceErrors: true,
+	Version:       version.Version,
 }
 
 func rootMain(command *cobra.Command, _ []string) error {
 	return command.Help()
 }
 
+// withoutDocker are the commands that do not use the docker daemon, so they work
+// before docker is started.
+var withoutDocker = map[string]bool{
+	"nitro":            true,
+	"help":             true,
+	"completion":       true,
+	"config":           true,
+	"hosts":            true,
+	"portcheck":        true,
+	"self-update":      true,
+	"version":          true,
+	"__complete":       true,
+	"__completeNoDesc": true,
+}
+
+// topLevel returns the name of the command under the root command, e.g. db for db import.
+func topLevel(cmd *cobra.Command) string {
+	for cmd.HasParent() && cmd.Parent().HasParent() {
+		cmd = cmd.Parent()
+	}
+
+	return cmd.Name()
+}
+
 func NewCommand() *cobra.Command {
 	// get the users home directory
 	home, err := homedir.Dir()
@@ -74,27 +115,43 @@ func NewCommand() *cobra.Command {
 		log.Fatal(err)
 	}
 
-	// create the docker client
-	docker, err := client.NewClientWithOpts(client.FromEnv)
+	// use the docker daemon from the --docker-host or --context flags
+	host, dockerContext := dockerhost.FromArgs(os.Args[1:])
+	// the error is returned before running the commands that use docker
+	endpoint, resolveErr := dockerhost.Resolve(dockerhost.ConfigDir(home), host, dockerContext)
+
+	// create the "terminal" for capturing output
+	term := terminal.New()
+
+	// create the docker client, the calls are written as debug messages with --verbose
+	dockerClient, err := client.NewClientWithOpts(dockerhost.Opts(endpoint)...)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	docker := dockerlog.New(dockerClient, term)
+
+	// the proxy ports are only published on another address when it is set, e.g. to reach
+	// the sites on a remote docker daemon from other machines
+	remote := dockerhost.Remote(endpoint)
+	hostIP := os.Getenv("NITRO_PROXY_HOST_IP")
+	if hostIP != "" {
+		proxycontainer.HostIP = hostIP
+	}
+
 	// get the port for the nitrod API
 	apiPort := "5000"
 	if os.Getenv("NITRO_API_PORT") != "" {
 		apiPort = os.Getenv("NITRO_API_PORT")
 	}
 
-	// create the nitrod gRPC API
+	// the API is only published on the loopback address, a remote docker daemon is reached
+	// through a tunnel to the API port
 	nitrod, err := nitroclient.NewClient("127.0.0.1", apiPort)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	// create the "terminal" for capturing output
-	term := terminal.New()
-
 	// create the downloaded for creating projects
 	downloader := downloader.NewDownloader()
 
@@ -107,9 +164,11 @@ func NewCommand() *cobra.Command {
 		bridge.NewCommand(home, docker, term),
 		clean.NewCommand(home, docker, term),
 		completion.NewCommand(),
-		composer.NewCommand(docker, term),
+		composer.NewCommand(home, docker, term),
+		configuration.NewCommand(home, term),
 		container.NewCommand(home, docker, term),
 		context.NewCommand(home, docker, term),
+		cp.NewCommand(home, docker, term),
 		craft.NewCommand(home, docker, term),
 		create.NewCommand(home, docker, downloader, term),
 		database.NewCommand(home, docker, nitrod, term),
@@ -123,10 +182,15 @@ func NewCommand() *cobra.Command {
 		initialize.NewCommand(home, docker, term),
 		logs.NewCommand(home, docker, term),
 		ls.NewCommand(home, docker, term),
-		npm.NewCommand(docker, term),
+		maintenance.NewCommand(home, docker, term),
+		mount.NewCommand(home, docker, term),
+		npm.NewCommand(home, docker, term),
 		php.NewCommand(home, docker, term),
+		phpext.NewCommand(home, docker, term),
 		portcheck.NewCommand(term),
+		proxy.NewCommand(home, docker, nitrod, term),
 		queue.NewCommand(home, docker, term),
+		reload.NewCommand(home, docker, term),
 		remove.NewCommand(home, docker, term),
 		restart.NewCommand(home, docker, term),
 		selfupdate.NewCommand(term),
@@ -145,5 +209,68 @@ func NewCommand() *cobra.Command {
 	// add the commands
 	rootCommand.AddCommand(commands...)
 
+	// check docker is running before the commands that need it
+	rootCommand.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		format, err := terminal.ResolveErrorFormat(terminal.ErrorFormat)
+		if err != nil {
+			return err
+		}
+
+		terminal.ErrorFormat = format
+
+		if withoutDocker[topLevel(cmd)] {
+			return nil
+		}
+
+		if resolveErr != nil {
+			return resolveErr
+		}
+
+		// make sure the selected daemon is reachable before running the command
+		if endpoint != nil {
+			if err := dockerhost.Ping(cmd.Context(), dockerClient, endpoint); err != nil {
+				return err
+			}
+		} else if err := dockerhost.CheckRunning(cmd.Context(), docker, runtime.GOOS); err != nil {
+			return err
+		}
+
+		// the site directories are bind mounted from the filesystem of the docker daemon, the
+		// notes are written to stderr so they do not mix with json output
+		if remote != "" {
+			cmd.PrintErrln(fmt.Sprintf("Using the docker daemon on %s, the site paths must exist on that machine.", remote))
+			cmd.PrintErrln(fmt.Sprintf("The API is only published on the loopback address of %s, tunnel it with `ssh -N -L %s:127.0.0.1:%s %s`.", remote, apiPort, apiPort, remote))
+		}
+
+		if hostIP != "" {
+			ip := net.ParseIP(hostIP)
+			if ip == nil {
+				return fmt.Errorf("NITRO_PROXY_HOST_IP must be an IP address, got %q", hostIP)
+			}
+
+			if !ip.IsLoopback() {
+				cmd.PrintErrln(fmt.Sprintf("Warning: the HTTP, HTTPS, and node ports are published on %s by NITRO_PROXY_HOST_IP, anyone who can reach that address can visit the sites.", hostIP))
+			}
+		}
+
+		return nil
+	}
+
+	// allow using images that are already available locally
+	rootCommand.PersistentFlags().BoolVar(&imagepull.NoPull, "no-pull", false, "Use local images instead of pulling them (also set with NITRO_NO_PULL=true)")
+
+	// allow using a docker daemon in a non-default location
+	rootCommand.PersistentFlags().StringVar(&dockerhost.Host, "docker-host", "", "The docker daemon to connect to, e.g. unix:///path/to/docker.sock or tcp://host:2376 (overrides DOCKER_HOST)")
+	rootCommand.PersistentFlags().StringVar(&dockerhost.Context, "context", "", "The docker context to use, e.g. colima (also set with DOCKER_CONTEXT)")
+
+	// allow configs to reference environment variables that are not set on every machine
+	rootCommand.PersistentFlags().BoolVar(&config.AllowUnsetVariables, "allow-unset-vars", false, "Keep ${VAR} references to unset environment variables in the config instead of returning an error")
+
+	// show the calls to the docker API and other debug messages
+	rootCommand.PersistentFlags().BoolVar(&terminal.Verbose, "verbose", false, "Write the calls to the docker API and other debug messages")
+
+	// allow wrapping tools to parse the errors, see errcode.Code for the error and exit codes
+	rootCommand.PersistentFlags().StringVar(&terminal.ErrorFormat, "error-format", "text", "Write errors as text or json with an error_code (also set with NITRO_ERROR_FORMAT)")
+
 	return rootCommand
 }
@@ -1,7 +1,6 @@
 package npm
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"os"
@@ -19,7 +18,9 @@ import (
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/spf13/cobra"
 
+	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/imagepull"
 	"github.com/craftcms/nitro/pkg/pathexists"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/craftcms/nitro/pkg/volumename"
@@ -40,7 +41,7 @@ const exampleText = `  # run npm install in a current directory
   nitro npm run dev`
 
 // NewCommand is the command used to run npm commands in a container.
-func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "npm",
 		Short:   "Runs an npm command.",
@@ -117,19 +118,9 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 
 			// if we don't have the image, pull it
 			if len(images) == 0 {
-				output.Pending("pulling", image)
-
-				rdr, err := docker.ImagePull(ctx, image, types.ImagePullOptions{All: false})
-				if err != nil {
-					return fmt.Errorf("unable to pull docker image, %w", err)
-				}
-
-				buf := &bytes.Buffer{}
-				if _, err := buf.ReadFrom(rdr); err != nil {
-					return fmt.Errorf("unable to read the output from pulling the image, %w", err)
+				if err := imagepull.PullWithProgress(ctx, docker, output, image); err != nil {
+					return err
 				}
-
-				output.Done()
 			}
 
 			// add filters for the volume
@@ -180,12 +171,23 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 				}
 			}
 
+			// run as the host user when the option is enabled, the config is optional, and
+			// move the npm cache since the user cannot write to the root home directory
+			var user string
+			var env []string
+			if cfg, err := config.Load(home); err == nil && cfg.ContainerUser() != "" {
+				user = cfg.ContainerUser()
+				env = []string{"npm_config_cache=/tmp/.npm", "HOME=/tmp"}
+			}
+
 			// create the container
 			resp, err := docker.ContainerCreate(ctx,
 				&container.Config{
 					Image: image,
 					Cmd:   commands,
 					Tty:   false,
+					User:  user,
+					Env:   env,
 					Labels: map[string]string{
 						containerlabels.Nitro: "true",
 						containerlabels.Type:  "npm",
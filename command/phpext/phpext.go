@@ -0,0 +1,136 @@
+package phpext
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/phpext"
+	"github.com/craftcms/nitro/pkg/prompt"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+const exampleText = `  # enable the imagick extension for the current site
+  nitro php-ext enable imagick
+
+  # disable the redis extension for a specific site
+  nitro php-ext disable redis tutorial.nitro`
+
+// NewCommand returns the command to enable or disable PHP extensions for a site. The extensions
+// are installed in the site container when the config is applied.
+func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "php-ext",
+		Short:   "Enables or disables a PHP extension for a site.",
+		Example: exampleText,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 || len(args) > 3 {
+				return fmt.Errorf("expected enable or disable, the extension, and an optional site")
+			}
+
+			if args[0] != "enable" && args[0] != "disable" {
+				return fmt.Errorf("unknown option %q, use enable or disable", args[0])
+			}
+
+			return phpext.Validate(args[1])
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				return []string{"enable", "disable"}, cobra.ShellCompDirectiveNoFileComp
+			case 1:
+				return phpext.Available(), cobra.ShellCompDirectiveNoFileComp
+			}
+
+			cfg, err := config.Load(home)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+
+			var options []string
+			for _, s := range cfg.Sites {
+				options = append(options, s.Hostname)
+			}
+
+			return options, cobra.ShellCompDirectiveNoFileComp
+		},
+		PostRunE: func(cmd *cobra.Command, args []string) error {
+			return prompt.RunApply(cmd, nil, false, output)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// load the config
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			// get the current working directory
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			// get a context aware list of sites
+			sites := cfg.ListOfSitesByDirectory(home, wd)
+
+			// create the options for the sites
+			var options []string
+			for _, s := range sites {
+				options = append(options, s.Hostname)
+			}
+
+			var siteArg string
+			if len(args) > 2 {
+				siteArg = strings.TrimSpace(args[2])
+			}
+
+			var site *config.Site
+			switch siteArg == "" {
+			case true:
+				switch len(sites) {
+				case 1:
+					site = &sites[0]
+				default:
+					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					if err != nil {
+						return err
+					}
+
+					site = &sites[selected]
+				}
+			default:
+				site, err = cfg.FindSiteByHostName(siteArg)
+				if err != nil {
+					return err
+				}
+			}
+
+			extension := args[1]
+
+			switch args[0] {
+			case "enable":
+				if err := cfg.SetPHPExtension(site.Hostname, extension); err != nil {
+					return err
+				}
+			default:
+				if err := cfg.RemovePHPExtension(site.Hostname, extension); err != nil {
+					return err
+				}
+			}
+
+			if err := cfg.Save(); err != nil {
+				return fmt.Errorf("unable to save config, %w", err)
+			}
+
+			output.Info(fmt.Sprintf("The %s extension is %sd for %s", extension, args[0], site.Hostname))
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,100 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
+)
+
+var apiExampleText = `  # view the live caddy config
+  nitro proxy api GET /config/
+
+  # view only the http servers
+  nitro proxy api GET /config/apps/http/servers
+
+  # mutating requests require the write flag
+  nitro proxy api --write DELETE /config/apps/http/servers/node`
+
+func apiCommand(nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "api",
+		Short:   "Sends a request to the Caddy admin API.",
+		Example: apiExampleText,
+		Args:    cobra.RangeArgs(2, 3),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			method := strings.ToUpper(args[0])
+			path := args[1]
+
+			write, err := cmd.Flags().GetBool("write")
+			if err != nil {
+				return err
+			}
+
+			// only allow read methods unless the write flag is set
+			switch method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+			case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+				if !write {
+					return fmt.Errorf("the %s method changes the proxy config, use --write to allow it", method)
+				}
+			default:
+				return fmt.Errorf("unknown method %q", method)
+			}
+
+			var body []byte
+			if len(args) == 3 {
+				body = []byte(args[2])
+			}
+
+			resp, err := nitrod.ProxyAPI(cmd.Context(), &protob.ProxyAPIRequest{
+				Method: method,
+				Path:   path,
+				Body:   body,
+				Write:  write,
+			})
+			if code := status.Code(err); code == codes.Unimplemented {
+				output.Info("The API does not appear to be updated; run `nitro update` before using this command.")
+
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			// pretty print json responses
+			content := resp.GetBody()
+			buf := &bytes.Buffer{}
+			if err := json.Indent(buf, content, "", "  "); err == nil {
+				content = buf.Bytes()
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), string(content))
+
+			if resp.GetStatusCode() >= http.StatusBadRequest {
+				return fmt.Errorf("received %d response from the Caddy API", resp.GetStatusCode())
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("write", false, "allow requests that change the proxy config")
+
+	return cmd
+}
@@ -0,0 +1,33 @@
+package proxy
+
+import (
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
+)
+
+const exampleText = `  # view the live caddy config
+  nitro proxy api GET /config/
+
+  # update a value in the caddy config
+  nitro proxy api --write PATCH /config/apps/http/servers/http/listen '[":80"]'`
+
+// NewCommand returns the proxy commands for inspecting and debugging the proxy container.
+func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "proxy",
+		Short:   "Manages the proxy.",
+		Example: exampleText,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		apiCommand(nitrod, output),
+	)
+
+	return cmd
+}
@@ -0,0 +1,194 @@
+package reload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerfind"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+const exampleText = `  # reload php-fpm for the site in the current directory
+  nitro reload
+
+  # reload php-fpm for a specific site, e.g. to clear the opcache
+  nitro reload tutorial.nitro`
+
+// signalCommand sends the php-fpm master process the USR2 signal. The master gracefully
+// reloads the workers and the opcache is cleared without restarting the container. The
+// slim images do not include procps, so the master is found in /proc instead of using
+// pkill, and the command exits with 1 when php-fpm is not running. The bracket keeps the
+// pattern from matching the shell running the command.
+var signalCommand = []string{"sh", "-c", `for p in /proc/[0-9]*; do if grep -q "php-fpm: maste[r] process" "$p/cmdline" 2>/dev/null; then kill -USR2 "${p#/proc/}"; exit $?; fi; done; exit 1`}
+
+// NewCommand returns the command to reload php-fpm for a site so the next request uses the
+// changed code and config. The signal is sent with a container exec from the CLI rather
+// than through the nitrod API, because only the site container is involved and the proxy
+// does not need to know about the reload.
+func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "reload",
+		Short:   "Reloads PHP-FPM and clears the opcache for a site.",
+		Example: exampleText,
+		Args:    cobra.MaximumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			cfg, err := config.Load(home)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+
+			var options []string
+			for _, s := range cfg.Sites {
+				options = append(options, s.Hostname)
+			}
+
+			return options, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(home)
+			if err != nil {
+				return err
+			}
+
+			var hostname string
+			switch len(args) {
+			case 0:
+				wd, err := os.Getwd()
+				if err != nil {
+					return err
+				}
+
+				// get a context aware list of sites
+				sites := cfg.ListOfSitesByDirectory(home, wd)
+				if len(sites) == 0 {
+					sites = cfg.Sites
+				}
+
+				switch len(sites) {
+				case 0:
+					return fmt.Errorf("there are no sites in the config")
+				case 1:
+					hostname = sites[0].Hostname
+				default:
+					var options []string
+					for _, s := range sites {
+						options = append(options, s.Hostname)
+					}
+
+					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					if err != nil {
+						return err
+					}
+
+					hostname = sites[selected].Hostname
+				}
+			default:
+				site, err := cfg.FindSiteByHostName(strings.TrimSpace(args[0]))
+				if err != nil {
+					return err
+				}
+
+				hostname = site.Hostname
+			}
+
+			return reloadSite(cmd.Context(), docker, output, hostname)
+		},
+	}
+
+	return cmd
+}
+
+// reloadSite signals php-fpm in the container for the site to reload and reports the result.
+func reloadSite(ctx context.Context, docker client.CommonAPIClient, output terminal.Outputer, hostname string) error {
+	c, err := containerfind.Site(ctx, docker, hostname)
+	if errors.Is(err, containerfind.ErrNotFound) {
+		return fmt.Errorf("unable to find the container for %s, run `nitro apply` to create it", hostname)
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.State != "running" {
+		return fmt.Errorf("the container for %s is not running, run `nitro start` to start it", hostname)
+	}
+
+	output.Pending("reloading php-fpm for", hostname)
+
+	// the master process runs as root
+	e, err := docker.ContainerExecCreate(ctx, c.ID, types.ExecConfig{
+		User:         "root",
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          signalCommand,
+	})
+	if err != nil {
+		output.Warning()
+
+		return err
+	}
+
+	resp, err := docker.ContainerExecAttach(ctx, e.ID, types.ExecStartCheck{})
+	if err != nil {
+		output.Warning()
+
+		return err
+	}
+	defer resp.Close()
+
+	if err := docker.ContainerExecStart(ctx, e.ID, types.ExecStartCheck{}); err != nil {
+		output.Warning()
+
+		return fmt.Errorf("unable to start the container exec, %w", err)
+	}
+
+	buf := &bytes.Buffer{}
+	if _, err := stdcopy.StdCopy(buf, buf, resp.Reader); err != nil {
+		output.Warning()
+
+		return fmt.Errorf("unable to read the output of the container exec, %w", err)
+	}
+
+	// wait for the container exec to complete
+	waiting := true
+	exitCode := 0
+	for waiting {
+		resp, err := docker.ContainerExecInspect(ctx, e.ID)
+		if err != nil {
+			output.Warning()
+
+			return err
+		}
+
+		waiting = resp.Running
+		exitCode = resp.ExitCode
+	}
+
+	// the command exits with 1 when no master process was found
+	switch exitCode {
+	case 0:
+	case 1:
+		output.Warning()
+
+		return fmt.Errorf("php-fpm is not running in the container for %s", hostname)
+	default:
+		output.Warning()
+
+		return fmt.Errorf("unable to reload php-fpm for %s, %s", hostname, strings.TrimSpace(buf.String()))
+	}
+
+	output.Done()
+
+	output.Info(fmt.Sprintf("Reloaded PHP-FPM and cleared the opcache for %s 🧹", hostname))
+
+	return nil
+}
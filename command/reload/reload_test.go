@@ -0,0 +1,129 @@
+package reload
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+type mockDockerClient struct {
+	client.CommonAPIClient
+
+	containers []types.Container
+	exitCode   int
+
+	// execs are the commands run in each container
+	execs map[string][]string
+	users []string
+}
+
+func (m *mockDockerClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	var containers []types.Container
+	for _, c := range m.containers {
+		if options.Filters.MatchKVList("label", c.Labels) {
+			containers = append(containers, c)
+		}
+	}
+
+	return containers, nil
+}
+
+func (m *mockDockerClient) ContainerExecCreate(ctx context.Context, container string, config types.ExecConfig) (types.IDResponse, error) {
+	if m.execs == nil {
+		m.execs = make(map[string][]string)
+	}
+
+	m.execs[container] = config.Cmd
+	m.users = append(m.users, config.User)
+
+	return types.IDResponse{ID: "exec-" + container}, nil
+}
+
+func (m *mockDockerClient) ContainerExecAttach(ctx context.Context, execID string, config types.ExecStartCheck) (types.HijackedResponse, error) {
+	conn, _ := net.Pipe()
+
+	return types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(strings.NewReader(""))}, nil
+}
+
+func (m *mockDockerClient) ContainerExecStart(ctx context.Context, execID string, config types.ExecStartCheck) error {
+	return nil
+}
+
+func (m *mockDockerClient) ContainerExecInspect(ctx context.Context, execID string) (types.ContainerExecInspect, error) {
+	return types.ContainerExecInspect{ExecID: execID, ExitCode: m.exitCode}, nil
+}
+
+func TestReloadSite(t *testing.T) {
+	site := types.Container{
+		ID:     "tutorial",
+		Names:  []string{"/tutorial.nitro"},
+		State:  "running",
+		Labels: map[string]string{containerlabels.Nitro: "true", containerlabels.Host: "tutorial.nitro"},
+	}
+
+	stopped := site
+	stopped.State = "exited"
+
+	tests := []struct {
+		name       string
+		containers []types.Container
+		exitCode   int
+		want       map[string][]string
+		wantErr    string
+	}{
+		{
+			name:       "php-fpm is signaled in the sites container",
+			containers: []types.Container{site},
+			want:       map[string][]string{"tutorial": signalCommand},
+		},
+		{
+			name:    "sites without a container return an error",
+			wantErr: "run `nitro apply`",
+		},
+		{
+			name:       "stopped containers return an error",
+			containers: []types.Container{stopped},
+			wantErr:    "is not running",
+		},
+		{
+			name:       "containers without php-fpm running return an error",
+			containers: []types.Container{site},
+			exitCode:   1,
+			want:       map[string][]string{"tutorial": signalCommand},
+			wantErr:    "php-fpm is not running",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockDockerClient{containers: tt.containers, exitCode: tt.exitCode}
+
+			err := reloadSite(context.Background(), mock, terminal.NewWithWriter(ioutil.Discard), "tutorial.nitro")
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("reloadSite() error = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("expected the error to contain %q, got %v", tt.wantErr, err)
+			}
+
+			if !reflect.DeepEqual(mock.execs, tt.want) {
+				t.Errorf("expected the execs to be %v, got %v", tt.want, mock.execs)
+			}
+
+			for _, u := range mock.users {
+				if u != "root" {
+					t.Errorf("expected the exec to run as root, got %q", u)
+				}
+			}
+		})
+	}
+}
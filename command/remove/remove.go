@@ -1,19 +1,39 @@
 package remove
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/spf13/cobra"
 
 	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerfind"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/database"
+	"github.com/craftcms/nitro/pkg/envedit"
 	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
 const exampleText = `  # remove a site from the config
-  nitro remove`
+  nitro remove
+
+  # remove a site and its container
+  nitro remove --with-containers
+
+  # remove a site, its container, and drop its database
+  nitro remove --with-containers --with-database`
+
+var withContainers, withDatabase bool
 
 func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
@@ -92,10 +112,13 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				}
 			}
 
-			output.Info("Removing", site.Hostname)
+			// copy the site, removing it from the config shifts the sites it points into
+			removed := *site
+
+			output.Info("Removing", removed.Hostname)
 
 			// remove the site
-			if err := cfg.RemoveSite(site); err != nil {
+			if err := cfg.RemoveSite(&removed); err != nil {
 				return err
 			}
 
@@ -104,9 +127,229 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				return err
 			}
 
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			// remove the sites container
+			if withContainers {
+				if err := removeContainers(ctx, docker, removed.Hostname, output); err != nil {
+					return err
+				}
+			}
+
+			// drop the sites database
+			if withDatabase {
+				if err := dropDatabase(ctx, home, docker, output, removed); err != nil {
+					return err
+				}
+			}
+
 			return nil
 		},
 	}
 
+	cmd.Flags().BoolVar(&withContainers, "with-containers", false, "stop and remove the site container")
+	cmd.Flags().BoolVar(&withDatabase, "with-database", false, "drop the database for the site, the DB_DATABASE in the sites .env")
+
 	return cmd
 }
+
+// removeContainers stops and removes the container for the site, sites without a
+// container are skipped.
+func removeContainers(ctx context.Context, docker client.CommonAPIClient, hostname string, output terminal.Outputer) error {
+	// find the container for the site by its hostname
+	c, err := containerfind.Site(ctx, docker, hostname)
+	if errors.Is(err, containerfind.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to get a list of the containers, %w", err)
+	}
+
+	name := containerfind.Name(*c)
+
+	output.Pending("removing", name)
+
+	// stop the container
+	if err := docker.ContainerStop(ctx, c.ID, nil); err != nil {
+		output.Warning()
+		return fmt.Errorf("unable to stop container %s: %w", name, err)
+	}
+
+	// remove the container
+	if err := docker.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{}); err != nil {
+		output.Warning()
+		return fmt.Errorf("unable to remove container %s: %w", name, err)
+	}
+
+	output.Done()
+
+	return nil
+}
+
+// dropDatabase drops the database of the site, which is the DB_DATABASE on the DB_SERVER
+// engine in the sites .env file. Sites without a database in the .env are skipped.
+func dropDatabase(ctx context.Context, home string, docker client.CommonAPIClient, output terminal.Outputer, site config.Site) error {
+	path, err := site.GetAbsPath(home)
+	if err != nil {
+		return err
+	}
+
+	env := filepath.Join(path, ".env")
+	server, err := envedit.Get(env, "DB_SERVER")
+	if err != nil && !errors.Is(err, envedit.ErrNoEnvFile) {
+		return err
+	}
+
+	db, err := envedit.Get(env, "DB_DATABASE")
+	if err != nil && !errors.Is(err, envedit.ErrNoEnvFile) {
+		return err
+	}
+
+	if server == "" || db == "" {
+		output.Info(fmt.Sprintf("Unable to find the database for %s in %s, skipping dropping the database", site.Hostname, env))
+		return nil
+	}
+
+	// find the running engine for the site
+	containers, err := containerfind.Databases(ctx, docker)
+	if err != nil {
+		return err
+	}
+
+	var id, compatibility string
+	for _, c := range containers {
+		if containerfind.Name(c) == server && c.State == "running" {
+			id = c.ID
+			compatibility = c.Labels[containerlabels.DatabaseCompatibility]
+		}
+	}
+
+	if id == "" {
+		output.Info(fmt.Sprintf("The database engine %s is not running, skipping dropping the database", server))
+		return nil
+	}
+
+	// dropping a database can't be undone, so always confirm
+	confirm, err := output.Confirm(fmt.Sprintf("Drop the database %q from %s? This cannot be undone", db, server), false, "")
+	if err != nil {
+		return err
+	}
+
+	if !confirm {
+		output.Info("Skipping dropping the database")
+		return nil
+	}
+
+	// use the credentials the container was created with
+	info, err := docker.ContainerInspect(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	commands, execEnv := dropCommand(compatibility, db, info.Config.Env)
+
+	output.Pending("dropping", db)
+
+	// create the exec
+	exec, err := docker.ContainerExecCreate(ctx, id, types.ExecConfig{
+		AttachStdout: true,
+		AttachStderr: true,
+		Tty:          false,
+		Env:          execEnv,
+		Cmd:          commands,
+	})
+	if err != nil {
+		output.Warning()
+		return err
+	}
+
+	// attach to the container
+	resp, err := docker.ContainerExecAttach(ctx, exec.ID, types.ExecStartCheck{Tty: false})
+	if err != nil {
+		output.Warning()
+		return err
+	}
+	defer resp.Close()
+
+	// start the exec
+	if err := docker.ContainerExecStart(ctx, exec.ID, types.ExecStartCheck{}); err != nil {
+		output.Warning()
+		return fmt.Errorf("unable to start the container exec, %w", err)
+	}
+
+	// keep the output to report errors
+	buf := &bytes.Buffer{}
+	if _, err := stdcopy.StdCopy(buf, buf, resp.Reader); err != nil {
+		output.Warning()
+		return fmt.Errorf("unable to read the output of the container exec, %w", err)
+	}
+
+	// wait for the container exec to complete
+	exitCode, err := waitForExec(ctx, docker, exec.ID)
+	if err != nil {
+		output.Warning()
+		return err
+	}
+
+	if exitCode != 0 {
+		output.Warning()
+		return fmt.Errorf("unable to drop the database %q, %s", db, strings.TrimSpace(buf.String()))
+	}
+
+	output.Done()
+
+	return nil
+}
+
+// dropCommand returns the command to drop the database and the environment for the exec,
+// using the credentials from the environment of the database container. The password is
+// set in the environment so it is not in the arguments of the process.
+func dropCommand(compatibility, db string, containerEnv []string) ([]string, []string) {
+	user, password := database.Credentials(compatibility, containerEnv)
+
+	switch compatibility {
+	case "mysql":
+		// root is needed to drop the databases of other users
+		if user == "" {
+			user, password = "root", "nitro"
+		}
+
+		return []string{"mysql", "--user=" + user, fmt.Sprintf(`-e DROP DATABASE IF EXISTS %s;`, database.QuoteIdentifier(compatibility, db))}, []string{"MYSQL_PWD=" + password}
+	default:
+		if user == "" {
+			user, password = database.DefaultUser, database.DefaultPassword
+		}
+
+		return []string{"psql", "--username=" + user, "--host=127.0.0.1", fmt.Sprintf(`-c DROP DATABASE IF EXISTS %s;`, database.QuoteIdentifier(compatibility, db))}, []string{"PGPASSWORD=" + password}
+	}
+}
+
+// execTimeout is how long to wait for the exec to drop the database.
+var execTimeout = time.Minute
+
+// waitForExec waits for the container exec to complete, checking the exec every 100
+// milliseconds, and returns the exit code.
+func waitForExec(ctx context.Context, docker client.CommonAPIClient, id string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, execTimeout)
+	defer cancel()
+
+	for {
+		resp, err := docker.ContainerExecInspect(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+
+		if !resp.Running {
+			return resp.ExitCode, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, fmt.Errorf("the container exec did not complete within %s", execTimeout)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package remove
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDropCommand(t *testing.T) {
+	tests := []struct {
+		name          string
+		compatibility string
+		env           []string
+		wantCommands  []string
+		wantEnv       []string
+	}{
+		{
+			name:          "mysql uses the root password from the container",
+			compatibility: "mysql",
+			env:           []string{"MYSQL_ROOT_PASSWORD=secret"},
+			wantCommands:  []string{"mysql", "--user=root", "-e DROP DATABASE IF EXISTS `craft`;"},
+			wantEnv:       []string{"MYSQL_PWD=secret"},
+		},
+		{
+			name:          "mysql defaults to the root user",
+			compatibility: "mysql",
+			wantCommands:  []string{"mysql", "--user=root", "-e DROP DATABASE IF EXISTS `craft`;"},
+			wantEnv:       []string{"MYSQL_PWD=nitro"},
+		},
+		{
+			name:          "postgres uses the user from the container",
+			compatibility: "postgres",
+			env:           []string{"POSTGRES_USER=admin", "POSTGRES_PASSWORD=secret"},
+			wantCommands:  []string{"psql", "--username=admin", "--host=127.0.0.1", `-c DROP DATABASE IF EXISTS "craft";`},
+			wantEnv:       []string{"PGPASSWORD=secret"},
+		},
+		{
+			name:          "postgres defaults to the nitro user",
+			compatibility: "postgres",
+			wantCommands:  []string{"psql", "--username=nitro", "--host=127.0.0.1", `-c DROP DATABASE IF EXISTS "craft";`},
+			wantEnv:       []string{"PGPASSWORD=nitro"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commands, env := dropCommand(tt.compatibility, "craft", tt.env)
+			if !reflect.DeepEqual(commands, tt.wantCommands) {
+				t.Errorf("dropCommand() commands = %q, want %q", commands, tt.wantCommands)
+			}
+			if !reflect.DeepEqual(env, tt.wantEnv) {
+				t.Errorf("dropCommand() env = %q, want %q", env, tt.wantEnv)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package restart
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -18,13 +19,21 @@ import (
 var (
 	// ErrNoContainers is returned when no containers are running for an environment
 	ErrNoContainers = fmt.Errorf("there are no running containers")
+
+	// ErrNoSiteContainer is returned when the container for a site does not exist
+	ErrNoSiteContainer = fmt.Errorf("unable to find the container for the site, run `nitro apply` to create it")
+
+	siteFlag string
 )
 
 const exampleText = `  # restart all containers
   nitro restart
 
   # restart specific site
-  nitro restart tutorial.nitro`
+  nitro restart tutorial.nitro
+
+  # restart only the container for a site, e.g. after changing a PHP setting
+  nitro restart --site tutorial.nitro`
 
 // New returns the command to restart all of an environments containers
 func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
@@ -48,6 +57,11 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 
+			// restart the container for a single site
+			if siteFlag != "" {
+				return restartSite(ctx, home, docker, output, strings.TrimSpace(siteFlag))
+			}
+
 			var site string
 			if len(args) > 0 {
 				site = strings.TrimSpace(args[0])
@@ -99,5 +113,60 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		},
 	}
 
+	cmd.Flags().StringVar(&siteFlag, "site", "", "The hostname of a site to restart only the sites container")
+	_ = cmd.RegisterFlagCompletionFunc("site", cmd.ValidArgsFunction)
+
 	return cmd
 }
+
+// restartSite restarts the container for the site with the hostname and reports the status
+// of the container after the restart.
+func restartSite(ctx context.Context, home string, docker client.CommonAPIClient, output terminal.Outputer, hostname string) error {
+	cfg, err := config.Load(home)
+	if err != nil {
+		return err
+	}
+
+	// make sure the site is in the config
+	if _, err := cfg.FindSiteByHostName(hostname); err != nil {
+		return err
+	}
+
+	// find the sites container, including stopped containers
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro)
+	filter.Add("label", containerlabels.Host+"="+hostname)
+
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return fmt.Errorf("unable to get a list of the containers, %w", err)
+	}
+
+	if len(containers) == 0 {
+		return fmt.Errorf("%s: %w", hostname, ErrNoSiteContainer)
+	}
+
+	c := containers[0]
+	name := strings.TrimLeft(c.Names[0], "/")
+
+	output.Pending("restarting", name)
+
+	timeout := time.Duration(5000) * time.Millisecond
+	if err := docker.ContainerRestart(ctx, c.ID, &timeout); err != nil {
+		output.Warning()
+
+		return fmt.Errorf("unable to restart container %s: %w", name, err)
+	}
+
+	output.Done()
+
+	// report the status after the restart
+	info, err := docker.ContainerInspect(ctx, c.ID)
+	if err != nil {
+		return fmt.Errorf("unable to inspect container %s: %w", name, err)
+	}
+
+	output.Info(fmt.Sprintf("%s is %s", name, info.State.Status))
+
+	return nil
+}
@@ -27,6 +27,10 @@ func (spy spyOutputer) Ask(message, fallback, sep string, validator terminal.Val
 	return fallback, nil
 }
 
+func (spy spyOutputer) AskSecret(message, sep string, validator terminal.Validator) (string, error) {
+	return "", nil
+}
+
 func (spy spyOutputer) Confirm(message string, fallback bool, sep string) (bool, error) {
 	return fallback, nil
 }
@@ -155,6 +159,10 @@ func (c *mockDockerClient) ContainerRestart(ctx context.Context, container strin
 	return c.mockError
 }
 
+func (c *mockDockerClient) ContainerInspect(ctx context.Context, container string) (types.ContainerJSON, error) {
+	return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{ID: container, State: &types.ContainerState{Status: "running"}}}, c.mockError
+}
+
 func (c *mockDockerClient) ContainerStop(ctx context.Context, containerID string, timeout *time.Duration) error {
 	c.containerID = containerID
 
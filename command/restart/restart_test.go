@@ -1,6 +1,7 @@
 package restart
 
 import (
+	"context"
 	"os"
 	"reflect"
 	"testing"
@@ -66,3 +67,50 @@ func TestRestartWithNoContainersDoesNoWork(t *testing.T) {
 		t.Errorf("expected the number of restart requests to be zero, got %d instead", len(mock.containerRestartRequests))
 	}
 }
+
+func TestRestartSite(t *testing.T) {
+	tests := []struct {
+		name       string
+		site       string
+		containers []types.Container
+		want       []string
+		wantErr    bool
+	}{
+		{
+			name: "the sites container is restarted",
+			site: "tutorial.nitro",
+			containers: []types.Container{
+				{
+					ID:     "tutorial",
+					Names:  []string{"/tutorial.nitro"},
+					Labels: map[string]string{containerlabels.Nitro: "true", containerlabels.Host: "tutorial.nitro"},
+				},
+			},
+			want: []string{"tutorial"},
+		},
+		{
+			name:    "sites that are not in the config return an error",
+			site:    "missing.nitro",
+			wantErr: true,
+		},
+		{
+			name:    "sites without a container return an error",
+			site:    "tutorial.nitro",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newMockDockerClient(nil, tt.containers, nil)
+
+			err := restartSite(context.Background(), "testdata", mock, spyOutputer{}, tt.site)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("restartSite() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(mock.containerRestartRequests, tt.want) {
+				t.Errorf("expected container restart requests %v, got %v", tt.want, mock.containerRestartRequests)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"runtime"
 	"strings"
 
 	"github.com/docker/docker/api/types"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/dockerhost"
 	"github.com/craftcms/nitro/pkg/proxycontainer"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
@@ -41,8 +43,8 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		},
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			// is the docker api alive?
-			if _, err := docker.Ping(cmd.Context()); err != nil {
-				return fmt.Errorf("Couldn’t connect to Docker; please make sure Docker is running.")
+			if err := dockerhost.CheckRunning(cmd.Context(), docker, runtime.GOOS); err != nil {
+				return err
 			}
 
 			return nil
@@ -2,6 +2,7 @@ package start
 
 import (
 	"fmt"
+	"runtime"
 	"strings"
 
 	"github.com/docker/docker/api/types"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/dockerhost"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
@@ -43,8 +45,8 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		},
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			// is the docker api alive?
-			if _, err := docker.Ping(cmd.Context()); err != nil {
-				return fmt.Errorf("Couldn’t connect to Docker; please make sure Docker is running.")
+			if err := dockerhost.CheckRunning(cmd.Context(), docker, runtime.GOOS); err != nil {
+				return err
 			}
 
 			return nil
@@ -27,6 +27,10 @@ func (spy spyOutputer) Ask(message, fallback, sep string, validator terminal.Val
 	return fallback, nil
 }
 
+func (spy spyOutputer) AskSecret(message, sep string, validator terminal.Validator) (string, error) {
+	return "", nil
+}
+
 func (spy spyOutputer) Confirm(message string, fallback bool, sep string) (bool, error) {
 	return fallback, nil
 }
@@ -2,15 +2,12 @@ package stop
 
 import (
 	"fmt"
-	"strings"
 
-	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 
 	"github.com/craftcms/nitro/pkg/config"
-	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/containerfind"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
@@ -48,13 +45,8 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				site = args[0]
 			}
 
-			// get all the containers using a filter, we only want to stop containers which
-			// have the environment label
-			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
-
-			// get all of the container
-			containers, err := docker.ContainerList(ctx, types.ContainerListOptions{Filters: filter})
+			// get all of the running containers with the nitro label
+			containers, err := containerfind.All(ctx, docker, false)
 			if err != nil {
 				return fmt.Errorf("unable to get a list of the containers, %w", err)
 			}
@@ -69,7 +61,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			// stop each environment container
 			for _, c := range containers {
-				hostname := strings.TrimLeft(c.Names[0], "/")
+				hostname := containerfind.Name(c)
 
 				// if the user wants a single site only, skip all of the other sites
 				if site != "" && hostname != site {
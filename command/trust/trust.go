@@ -3,6 +3,7 @@ package trust
 import (
 	"archive/tar"
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -10,14 +11,12 @@ import (
 	"path/filepath"
 	"runtime"
 
-	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 
 	"github.com/craftcms/nitro/pkg/certinstall"
 	"github.com/craftcms/nitro/pkg/config"
-	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/containerfind"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
@@ -49,23 +48,16 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				ctx = cmd.Parent().Context()
 			}
 
-			// find the nitro proxy for the environment
-			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro)
-			filter.Add("label", containerlabels.Proxy+"=true")
-
-			// find the container, should only be one
-			containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+			// find the proxy container
+			proxy, err := containerfind.Proxy(ctx, docker)
+			if errors.Is(err, containerfind.ErrNotFound) {
+				return ErrNoContainers
+			}
 			if err != nil {
 				return fmt.Errorf("unable to get the list of containers, %w", err)
 			}
 
-			// make sure there is at least one container
-			if len(containers) == 0 {
-				return ErrNoContainers
-			}
-
-			containerID := containers[0].ID
+			containerID := proxy.ID
 
 			// get the contents of the certificate from the container
 			output.Pending("getting Nitro’s root site certificate")
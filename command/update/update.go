@@ -1,7 +1,6 @@
 package update
 
 import (
-	"bytes"
 	"fmt"
 	"strconv"
 	"strings"
@@ -15,6 +14,7 @@ import (
 	"github.com/craftcms/nitro/command/version"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/imagepull"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
@@ -56,6 +56,11 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// updating requires pulling the latest images
+			if imagepull.Disabled() {
+				return fmt.Errorf("the update command pulls the latest images and cannot be used with --no-pull")
+			}
+
 			ctx := cmd.Context()
 			debug, err := strconv.ParseBool(cmd.Flag("debug").Value.String())
 			if err != nil {
@@ -89,21 +94,13 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				output.Pending("downloading", name)
 
 				// pull the image
-				rdr, err := docker.ImagePull(ctx, image, types.ImagePullOptions{All: false})
-				if err != nil {
+				if err := imagepull.Pull(ctx, docker, image); err != nil {
 					output.Warning()
 					output.Info("  \u2717 unable to pull image", name)
 
 					continue
 				}
 
-				buf := &bytes.Buffer{}
-				if _, err := buf.ReadFrom(rdr); err != nil {
-					output.Warning()
-
-					return fmt.Errorf("unable to read the output while pulling image, %w", err)
-				}
-
 				output.Done()
 			}
 
@@ -55,10 +55,11 @@ func NewCommand(home string, client client.CommonAPIClient, nitrod protob.NitroC
 
 			output.Info(fmt.Sprintf("View the changelog at https://github.com/craftcms/nitro/blob/%s/CHANGELOG.md\n", Version))
 
-			output.Info("Nitro CLI: \t", Version)
-			output.Info("Nitro gRPC: \t", vers)
-			output.Info("Docker API: \t", ver.APIVersion, "("+ver.MinAPIVersion+" min)")
-			output.Info("Docker CLI: \t", client.ClientVersion())
+			// the versions are the result, so write them to stdout
+			fmt.Fprintln(cmd.OutOrStdout(), "Nitro CLI: \t", Version)
+			fmt.Fprintln(cmd.OutOrStdout(), "Nitro gRPC: \t", vers)
+			fmt.Fprintln(cmd.OutOrStdout(), "Docker API: \t", ver.APIVersion, "("+ver.MinAPIVersion+" min)")
+			fmt.Fprintln(cmd.OutOrStdout(), "Docker CLI: \t", client.ClientVersion())
 
 			// check if the cli and API do not match
 			if Version != vers {
@@ -11,10 +11,16 @@ import (
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/pkg/validate"
 )
 
 const exampleText = `  # example command
-  nitro xon`
+  nitro xon
+
+  # enable xdebug for profiling and step debugging
+  nitro xon --mode profile,debug`
+
+var flagMode string
 
 // NewCommand returns the command that is used to enable xdebug for a specific site. It will first check
 // if the current working directory or prompt the user for a site.
@@ -101,6 +107,23 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				return fmt.Errorf("Xdebug with PHP 7.0 is not supported")
 			}
 
+			// set the xdebug mode
+			if flagMode != "" {
+				v := validate.XdebugMode{}
+				if err := v.Validate(flagMode); err != nil {
+					return err
+				}
+
+				// php 7.1 uses xdebug 2 which does not support modes
+				if site.Version == "7.1" {
+					output.Info("Xdebug modes are not supported with PHP 7.1, using the Xdebug 2 settings")
+				}
+
+				if err := cfg.SetXdebugMode(site.Hostname, flagMode); err != nil {
+					return err
+				}
+			}
+
 			// if blackfire is set, we need to disable it to profile the site
 			if site.Blackfire {
 				// disable blackfire for the sites hostname
@@ -123,5 +146,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		},
 	}
 
+	cmd.Flags().StringVar(&flagMode, "mode", "", "The Xdebug modes to enable, e.g. debug,profile (default develop,debug)")
+
 	return cmd
 }
@@ -1,24 +1,40 @@
 package api
 
 import (
+	"archive/tar"
 	"archive/zip"
+	"bufio"
 	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"html"
 	"io"
 	"io/ioutil"
+	"log"
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
+	"unicode/utf8"
 
 	"github.com/craftcms/nitro/pkg/caddy"
 	"github.com/craftcms/nitro/pkg/database"
 	"github.com/craftcms/nitro/pkg/portavail"
+	"github.com/craftcms/nitro/pkg/tempdir"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/pkg/validate"
 	"github.com/craftcms/nitro/protob"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -30,17 +46,60 @@ var Version string
 // implements the gRPC API used in the proxy container. The gRPC API is used to
 // handle making changes to the Caddy Server via its local API. If no addr is
 // provided, it will set the default addr to http://127.0.0.1:2019
-func NewService(addr string) protob.NitroServer {
+//
+// When the Caddy admin API is secured, NITRO_CADDY_ADMIN_URL overrides the addr (e.g.
+// https://127.0.0.1:2020), NITRO_CADDY_ADMIN_TOKEN is sent as a bearer token, and
+// NITRO_CADDY_ADMIN_CA is the path to a PEM certificate used to verify the endpoint.
+func NewService(addr string) *Service {
 	// set the nitro version on start
 	if env, ok := os.LookupEnv("NITRO_VERSION"); ok {
 		Version = env
 	}
 
+	if env, ok := os.LookupEnv("NITRO_CADDY_ADMIN_URL"); ok && env != "" {
+		addr = strings.TrimSuffix(env, "/")
+	}
+
+	httpClient := http.DefaultClient
+	if ca := os.Getenv("NITRO_CADDY_ADMIN_CA"); ca != "" {
+		c, err := adminClient(ca)
+		if err != nil {
+			log.Println("unable to load the Caddy admin certificate,", err)
+		} else {
+			httpClient = c
+		}
+	}
+
 	return &Service{
 		Addr:     addr,
-		HTTP:     http.DefaultClient,
+		HTTP:     httpClient,
+		Token:    os.Getenv("NITRO_CADDY_ADMIN_TOKEN"),
 		Importer: database.NewImporter(),
+		Exporter: database.NewExporter(),
+	}
+}
+
+// adminClient returns an HTTP client that trusts the PEM certificate(s) in the file
+// in addition to the system certificates.
+func adminClient(file string) (*http.Client, error) {
+	pem, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", file)
 	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+
+	return &http.Client{Transport: transport}, nil
 }
 
 // Service implements the protob.NitroServer interface
@@ -48,9 +107,45 @@ type Service struct {
 	Addr     string
 	HTTP     *http.Client
 	Importer database.Importer
+	// Exporter runs the export tool for ExportDatabase, the default is database.NewExporter
+	Exporter database.Exporter
+	// Token is sent as a bearer token to the Caddy API when the admin endpoint is secured
+	Token string
+	// Timeout is the longest time to wait for the Caddy API when the request has no deadline
+	Timeout time.Duration
+	// AskAddr is the address Caddy uses to ask if a certificate can be issued for a host
+	AskAddr string
+	// TempDir is the directory used to stage database imports, the default temp directory
+	// is used when empty
+	TempDir string
+	// Runner runs the database tools (e.g. psql), the default runs the tool on the proxy
+	Runner Runner
+
+	// hosts are the hostnames from the last successful apply
+	hosts map[string]bool
+	// routes are the sites, and their settings, from the last successful apply
+	routes map[string]string
+	mu     sync.RWMutex
 }
 
-// AddDatabase handle creating a new database for a hostname
+// the route status of each site returned by Apply
+const (
+	SiteCreated   = "created"
+	SiteUpdated   = "updated"
+	SiteUnchanged = "unchanged"
+	SiteError     = "error"
+)
+
+// ExportChunkSize is the most data, in bytes, sent in each message of an export stream.
+const ExportChunkSize = 1024 * 20
+
+// MaxImportOutput is the most output from the import tool, in bytes, that is sent with the
+// import response.
+const MaxImportOutput = 1024 * 1024
+
+// AddDatabase handle creating a new database for a hostname. When the request has a user
+// other than nitro, the user is created with the password and granted all privileges on
+// the database. Existing users keep their password.
 func (svc *Service) AddDatabase(ctx context.Context, req *protob.AddDatabaseRequest) (*protob.AddDatabaseResponse, error) {
 	// get the database info from the request
 	hostname := req.GetDatabase().GetHostname()
@@ -58,9 +153,26 @@ func (svc *Service) AddDatabase(ctx context.Context, req *protob.AddDatabaseRequ
 	engine := req.GetDatabase().GetEngine()
 	version := req.GetDatabase().GetVersion()
 	db := req.GetDatabase().GetDatabase()
+	user := req.GetDatabase().GetUser()
+	password := req.GetDatabase().GetPassword()
 
 	// TODO(jasonmccallister) validate the request
 
+	// the nitro user is created with the container
+	if user == "nitro" {
+		user = ""
+	}
+
+	if user != "" {
+		if err := (&validate.DatabaseUser{}).Validate(user); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		if password == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "a password is required to create the user %q", user)
+		}
+	}
+
 	// verify we can connect to the database hostname - no error means its reachable
 	if err := portavail.Check(hostname, port); err == nil {
 		return nil, status.Errorf(codes.Internal, "it does not appear the database is available on host %s using port %s: %v", hostname, port, err)
@@ -72,29 +184,117 @@ func (svc *Service) AddDatabase(ctx context.Context, req *protob.AddDatabaseRequ
 		return nil, status.Error(codes.Internal, "error finding the database tool")
 	}
 
+	// check if the database already exists
+	exists, err := svc.databaseExists(tool, engine, hostname, port, db)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("error checking for the database: %s", err.Error()))
+	}
+
+	// there is nothing to do for an existing database without a user
+	if exists && user == "" {
+		return &protob.AddDatabaseResponse{Message: fmt.Sprintf("Database %q already exists on %q", db, hostname)}, nil
+	}
+
 	// run the commands to add the database
-	var addCommand, privilegesCommand []string
+	quoted := database.QuoteIdentifier(engine, db)
+	var addCommand, userCommand, privilegesCommand []string
 	switch engine {
 	case "mysql":
-		addCommand = []string{"--user=nitro", fmt.Sprintf("--host=%s", hostname), "-pnitro", fmt.Sprintf(`-e CREATE DATABASE IF NOT EXISTS %s;`, db)}
-		privilegesCommand = []string{"--user=nitro", fmt.Sprintf("--host=%s", hostname), "-pnitro", fmt.Sprintf(`-e CREATE DATABASE IF NOT EXISTS %s;`, db)}
+		grantee := "nitro"
+		if user != "" {
+			grantee = user
+			userCommand = []string{"--user=nitro", fmt.Sprintf("--host=%s", hostname), "-pnitro", fmt.Sprintf(`-e CREATE USER %s@'%%' IDENTIFIED BY %s;`, database.QuoteString(engine, user), database.QuoteString(engine, password))}
+		}
+
+		addCommand = []string{"--user=nitro", fmt.Sprintf("--host=%s", hostname), "-pnitro", fmt.Sprintf(`-e CREATE DATABASE IF NOT EXISTS %s;`, quoted)}
+		privilegesCommand = []string{"--user=nitro", fmt.Sprintf("--host=%s", hostname), "-pnitro", fmt.Sprintf(`-e GRANT ALL PRIVILEGES ON %s.* TO %s@'%%';`, quoted, database.QuoteString(engine, grantee))}
 	default:
-		addCommand = []string{fmt.Sprintf("--host=%s", hostname), "--port=" + port, "--username=nitro", fmt.Sprintf(`-c CREATE DATABASE %s;`, db)}
+		addCommand = []string{fmt.Sprintf("--host=%s", hostname), "--port=" + port, "--username=nitro", fmt.Sprintf(`-c CREATE DATABASE %s;`, quoted)}
+
+		// the nitro user is the superuser and does not need privileges
+		if user != "" {
+			role := database.QuoteIdentifier(engine, user)
+			userCommand = []string{fmt.Sprintf("--host=%s", hostname), "--port=" + port, "--username=nitro", fmt.Sprintf(`-c CREATE ROLE %s WITH LOGIN PASSWORD %s;`, role, database.QuoteString(engine, password))}
+			privilegesCommand = []string{fmt.Sprintf("--host=%s", hostname), "--port=" + port, "--username=nitro", fmt.Sprintf(`-c GRANT ALL PRIVILEGES ON DATABASE %s TO %s;`, quoted, role)}
+		}
 	}
 
 	// add the database
-	if err := svc.exec(tool, addCommand); err != nil {
-		return nil, status.Error(codes.Internal, fmt.Sprintf("error creating database: %s", err.Error()))
+	if !exists {
+		if out, err := svc.runner().Run(tool, addCommand); err != nil {
+			// postgres does not support if not exists, so the database may have been created since the check
+			if !strings.Contains(out, "already exists") {
+				return nil, status.Error(codes.Internal, fmt.Sprintf("error creating database: %s", err.Error()))
+			}
+
+			if user == "" {
+				return &protob.AddDatabaseResponse{Message: fmt.Sprintf("Database %q already exists on %q", db, hostname)}, nil
+			}
+
+			exists = true
+		}
+	}
+
+	// create the user if required, an existing user keeps its password
+	userExists := false
+	if userCommand != nil {
+		if out, err := svc.runner().Run(tool, userCommand); err != nil {
+			// mysql reports existing users with error 1396 and postgres with already exists
+			if !strings.Contains(out, "ERROR 1396") && !strings.Contains(out, "already exists") {
+				return nil, status.Error(codes.Internal, fmt.Sprintf("error creating user: %s", err.Error()))
+			}
+
+			userExists = true
+		}
 	}
 
 	// set privileges if required
 	if privilegesCommand != nil {
-		if err := svc.exec(tool, addCommand); err != nil {
+		if err := svc.exec(tool, privilegesCommand); err != nil {
 			return nil, status.Error(codes.Internal, fmt.Sprintf("error setting privileges on database: %s", err.Error()))
 		}
 	}
 
-	return &protob.AddDatabaseResponse{Message: fmt.Sprintf("Database %q added to %q successfully", db, hostname)}, nil
+	var msg string
+	switch {
+	case exists:
+		msg = fmt.Sprintf("Database %q already exists on %q, granted privileges to user %q", db, hostname, user)
+	case user != "":
+		msg = fmt.Sprintf("Database %q added to %q for user %q successfully", db, hostname, user)
+	default:
+		msg = fmt.Sprintf("Database %q added to %q successfully", db, hostname)
+	}
+
+	if userExists {
+		msg = fmt.Sprintf("%s, the user %q already exists and its password was not changed", msg, user)
+	}
+
+	return &protob.AddDatabaseResponse{Message: msg}, nil
+}
+
+// databaseExists uses the engines tool to check if the database has already been created.
+func (svc *Service) databaseExists(tool, engine, hostname, port, db string) (bool, error) {
+	var cmd []string
+	switch engine {
+	case "mysql":
+		cmd = []string{"--user=nitro", fmt.Sprintf("--host=%s", hostname), "-pnitro", "--skip-column-names", "--silent", fmt.Sprintf(`-e SELECT SCHEMA_NAME FROM INFORMATION_SCHEMA.SCHEMATA WHERE SCHEMA_NAME = %s;`, database.QuoteString(engine, db))}
+	default:
+		cmd = []string{fmt.Sprintf("--host=%s", hostname), "--port=" + port, "--username=nitro", "--tuples-only", "--no-align", fmt.Sprintf(`-c SELECT 1 FROM pg_database WHERE datname = %s;`, database.QuoteString(engine, db))}
+	}
+
+	out, err := svc.runner().Run(tool, cmd)
+	if err != nil {
+		return false, err
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == db || line == "1" {
+			return true, nil
+		}
+	}
+
+	return false, nil
 }
 
 // Apply is used to take all of the sites from a Nitro config and apply those changes. The Sites
@@ -112,17 +312,111 @@ func (svc *Service) Apply(ctx context.Context, request *protob.ApplyRequest) (*p
 		svc.Addr = "http://127.0.0.1:2019"
 	}
 
+	// use the deadline of the request, which is set by apply --timeout, and only fall back
+	// to the timeout so the caddy api is not waited on forever
+	if _, ok := ctx.Deadline(); !ok {
+		if svc.Timeout == 0 {
+			svc.Timeout = 10 * time.Second
+		}
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, svc.Timeout)
+		defer cancel()
+	}
+
+	// set the ask addr if not provided
+	if svc.AskAddr == "" {
+		svc.AskAddr = "http://127.0.0.1:5001/ask"
+	}
+
+	// make sure two sites are not using the same host
+	if err := duplicateHosts(request.GetSites()); err != nil {
+		return &protob.ApplyResponse{
+			Message: err.Error(),
+			Error:   true,
+		}, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// the additional proxy ports can't use the ports of the other servers
+	for _, port := range request.GetProxyPorts() {
+		if err := validProxyPort(port); err != nil {
+			return &protob.ApplyResponse{
+				Message: err.Error(),
+				Error:   true,
+			}, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
 	// convert each of the sites into a route
-	var siteRoutes, nodeRoutes, nodeAltRoutes []caddy.ServerRoute
-	for k, site := range request.GetSites() {
+	var siteRoutes, httpSiteRoutes, nodeRoutes, nodeAltRoutes []caddy.ServerRoute
+	portRoutes := make(map[int32][]caddy.ServerRoute)
+	var pathRoutes, httpPathRoutes []caddy.ServerRoute
+	var errorRoutes []caddy.ServerRoute
+	var tlsPolicies []caddy.TLSConnectionPolicy
+	loggerNames := make(map[string]string)
+	applied := make(map[string]bool)
+	routes := make(map[string]string)
+	var statuses []*protob.SiteStatus
+	// sort the sites so the routes are always in the same order
+	var keys []string
+	for k := range request.GetSites() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	svc.mu.RLock()
+	previous := svc.routes
+	svc.mu.RUnlock()
+
+	for _, k := range keys {
+		site := request.GetSites()[k]
+
+		// skip sites that can't be routed so the other sites are still applied
+		if err := validSite(site); err != nil {
+			statuses = append(statuses, &protob.SiteStatus{Hostname: k, Status: SiteError, Message: err.Error()})
+			continue
+		}
+
+		// compare the site to the last apply
+		b, err := json.Marshal(site)
+		if err != nil {
+			return nil, err
+		}
+
+		routes[k] = string(b)
+		switch prev, ok := previous[k]; {
+		case !ok:
+			statuses = append(statuses, &protob.SiteStatus{Hostname: k, Status: SiteCreated})
+		case prev != routes[k]:
+			statuses = append(statuses, &protob.SiteStatus{Hostname: k, Status: SiteUpdated})
+		default:
+			statuses = append(statuses, &protob.SiteStatus{Hostname: k, Status: SiteUnchanged})
+		}
+
 		// get all of the host names for the site
 		hosts := []string{site.GetHostname()}
 		if site.GetAliases() != "" {
 			hosts = append(hosts, strings.Split(site.GetAliases(), ",")...)
 		}
 
+		for _, h := range hosts {
+			applied[h] = true
+		}
+
+		// match any subdomain of the site when issuing certificates on demand
+		if request.GetOnDemandTLS() {
+			for _, h := range hosts {
+				hosts = append(hosts, "*."+h)
+			}
+		}
+
+		// log the requests for all of the hosts to the sites logger
+		for _, h := range hosts {
+			loggerNames[h] = loggerName(site.GetHostname())
+		}
+
 		// create the route for each of the sites
-		siteRoutes = append(siteRoutes, caddy.ServerRoute{
+		route := caddy.ServerRoute{
 			Handle: []caddy.RouteHandle{
 				{
 					Handler: "reverse_proxy",
@@ -131,6 +425,7 @@ func (svc *Service) Apply(ctx context.Context, request *protob.ApplyRequest) (*p
 							Dial: fmt.Sprintf("%s:%d", k, site.GetPort()),
 						},
 					},
+					HandleResponse: errorPageResponses(k, site),
 				},
 			},
 			Match: []caddy.Match{
@@ -139,7 +434,114 @@ func (svc *Service) Apply(ctx context.Context, request *protob.ApplyRequest) (*p
 				},
 			},
 			Terminal: true,
-		})
+		}
+
+		// sites in maintenance mode respond with the maintenance page instead of the upstream
+		if site.GetMaintenance() {
+			route.Handle = []caddy.RouteHandle{maintenanceHandle(site.GetMaintenanceBody())}
+		}
+
+		// send the early hints while the upstream handles the request
+		if hints, ok := earlyHintsHandle(site); ok && !site.GetMaintenance() {
+			route.Handle = append([]caddy.RouteHandle{hints}, route.Handle...)
+		}
+
+		// set and remove the response headers before the upstream handles the request
+		headers, hasHeaders := headersHandle(site)
+		if hasHeaders {
+			route.Handle = append([]caddy.RouteHandle{headers}, route.Handle...)
+		}
+
+		// limit the size of the request body before the upstream reads it
+		requestBody, hasRequestBody := requestBodyHandle(site)
+		if hasRequestBody {
+			route.Handle = append([]caddy.RouteHandle{requestBody}, route.Handle...)
+		}
+
+		siteRoutes = append(siteRoutes, route)
+
+		// route the path prefixes to their upstream
+		var sitePathRoutes []caddy.ServerRoute
+		for _, p := range site.GetPaths() {
+			// the whole site is unavailable in maintenance mode
+			if site.GetMaintenance() {
+				break
+			}
+
+			prefix := strings.TrimSuffix(p.GetPrefix(), "/")
+
+			handle := []caddy.RouteHandle{
+				{
+					Handler: "reverse_proxy",
+					Upstreams: []caddy.Upstream{
+						{
+							Dial: p.GetUpstream(),
+						},
+					},
+				},
+			}
+
+			if hasHeaders {
+				handle = append([]caddy.RouteHandle{headers}, handle...)
+			}
+
+			if hasRequestBody {
+				handle = append([]caddy.RouteHandle{requestBody}, handle...)
+			}
+
+			sitePathRoutes = append(sitePathRoutes, caddy.ServerRoute{
+				Handle: handle,
+				Match: []caddy.Match{
+					{
+						Host: hosts,
+						Path: []string{prefix, prefix + "/*"},
+					},
+				},
+				Terminal: true,
+			})
+		}
+
+		pathRoutes = append(pathRoutes, sitePathRoutes...)
+
+		// require and verify client certificates for the site
+		if site.GetClientCa() != "" {
+			policy, err := clientAuthPolicy(site.GetClientCa(), hosts)
+			if err != nil {
+				return nil, err
+			}
+
+			tlsPolicies = append(tlsPolicies, policy)
+		}
+
+		// handle the errors for the site by status code
+		if !site.GetMaintenance() {
+			errorRoutes = append(errorRoutes, errorPageRoutes(k, site, hosts)...)
+		}
+
+		// redirect plain HTTP requests for the site to HTTPS
+		switch site.GetHttpsRedirect() {
+		case true:
+			httpSiteRoutes = append(httpSiteRoutes, caddy.ServerRoute{
+				Handle: []caddy.RouteHandle{
+					{
+						Handler:    "static_response",
+						StatusCode: http.StatusPermanentRedirect,
+						Headers: map[string][]string{
+							"Location": {"https://{http.request.host}{http.request.uri}"},
+						},
+					},
+				},
+				Match: []caddy.Match{
+					{
+						Host: hosts,
+					},
+				},
+				Terminal: true,
+			})
+		default:
+			httpPathRoutes = append(httpPathRoutes, sitePathRoutes...)
+			httpSiteRoutes = append(httpSiteRoutes, route)
+		}
 
 		// add the node routes
 		nodeRoutes = append(nodeRoutes, caddy.ServerRoute{
@@ -179,22 +581,45 @@ func (svc *Service) Apply(ctx context.Context, request *protob.ApplyRequest) (*p
 			},
 			Terminal: true,
 		})
+
+		// the additional proxy ports are sent to the same port on the site
+		for _, port := range request.GetProxyPorts() {
+			portRoutes[port] = append(portRoutes[port], caddy.ServerRoute{
+				Handle: []caddy.RouteHandle{
+					{
+						Handler: "reverse_proxy",
+						Upstreams: []caddy.Upstream{
+							{
+								Dial: fmt.Sprintf("%s:%d", k, port),
+							},
+						},
+					},
+				},
+				Match: []caddy.Match{
+					{
+						Host: hosts,
+					},
+				},
+				Terminal: true,
+			})
+		}
 	}
 
 	update := caddy.UpdateRequest{}
 
-	httpRoutes := append(siteRoutes, caddy.ServerRoute{
-		Handle: []caddy.RouteHandle{
-			{
-				Handler: "vars",
-				Root:    "/var/www/html",
-			},
-			{
-				Handler: "file_server",
-				Root:    "/var/www/html",
-				Hide:    []string{"/etc/caddy/Caddyfile"},
-			},
-		},
+	// path routes must be matched before the catch-all site routes
+	siteRoutes = append(pathRoutes, siteRoutes...)
+	httpSiteRoutes = append(httpPathRoutes, httpSiteRoutes...)
+
+	// exact hosts must be matched before the wildcard hosts of other sites
+	siteRoutes = prioritizeHosts(siteRoutes)
+	httpSiteRoutes = prioritizeHosts(httpSiteRoutes)
+	nodeRoutes = prioritizeHosts(nodeRoutes)
+	nodeAltRoutes = prioritizeHosts(nodeAltRoutes)
+
+	// the not found page is only used for hosts that do not match a site
+	httpRoutes := append(httpSiteRoutes, caddy.ServerRoute{
+		Handle:   []caddy.RouteHandle{notFoundHandle(request.GetNotFoundBody(), request.GetSites())},
 		Terminal: true,
 	})
 
@@ -216,7 +641,23 @@ func (svc *Service) Apply(ctx context.Context, request *protob.ApplyRequest) (*p
 		},
 	}
 
-	// set the default welcome server
+	// add a server for each of the additional proxy ports
+	for _, port := range request.GetProxyPorts() {
+		if update.Ports == nil {
+			update.Ports = make(map[string]caddy.Server)
+		}
+
+		update.Ports[fmt.Sprintf("port_%d", port)] = caddy.Server{
+			Listen: []string{fmt.Sprintf(":%d", port)},
+			Routes: prioritizeHosts(portRoutes[port]),
+			AutomaticHTTPS: caddy.AutomaticHTTPS{
+				Disable:          true,
+				DisableRedirects: true,
+			},
+		}
+	}
+
+	// set the default not found server
 	update.HTTP = caddy.Server{
 		Listen: []string{":80"},
 		Routes: httpRoutes,
@@ -231,116 +672,831 @@ func (svc *Service) Apply(ctx context.Context, request *protob.ApplyRequest) (*p
 		Routes: siteRoutes,
 	}
 
+	// caddy enables http/1.1 and http/2 by default, http/3 has to be listed with them
+	if request.GetHttp3() {
+		update.HTTPS.Protocols = []string{"h1", "h2", "h3"}
+	}
+
+	// the error pages are used by both servers, sites redirected to HTTPS never error on HTTP
+	if len(errorRoutes) > 0 {
+		errorRoutes = prioritizeHosts(errorRoutes)
+
+		update.HTTP.Errors = &caddy.ServerErrors{Routes: errorRoutes}
+		update.HTTPS.Errors = &caddy.ServerErrors{Routes: errorRoutes}
+	}
+
+	// the connection policies are matched in order, so the other sites use the default policy
+	if len(tlsPolicies) > 0 {
+		update.HTTPS.TLSConnectionPolicies = append(tlsPolicies, caddy.TLSConnectionPolicy{})
+	}
+
+	// the configs are posted in order, the servers last since they use the loggers
+	var payloads []caddyPayload
+
+	// configure caddy to ask nitrod before issuing a certificate
+	if request.GetOnDemandTLS() {
+		automation, err := json.Marshal(svc.onDemandAutomation())
+		if err != nil {
+			return nil, err
+		}
+
+		payloads = append(payloads, caddyPayload{name: "TLS automation", path: "/config/apps/tls/automation", content: automation})
+	}
+
+	// configure the access logs for each of the sites
+	if request.GetAccessLogs() {
+		logging, err := json.Marshal(accessLogging(request.GetSites()))
+		if err != nil {
+			return nil, err
+		}
+
+		payloads = append(payloads, caddyPayload{name: "logging", path: "/config/logging", content: logging})
+
+		update.HTTP.Logs = &caddy.ServerLogs{LoggerNames: loggerNames}
+		update.HTTPS.Logs = &caddy.ServerLogs{LoggerNames: loggerNames}
+	}
+
 	content, err := json.Marshal(&update)
 	if err != nil {
 		return nil, err
 	}
 
+	// return the configs, even when caddy rejects them, for inspecting and reporting bugs
+	var config string
+	if request.GetReturnConfig() {
+		config, err = combinePayloads(append(payloads, caddyPayload{path: "/config/apps/http/servers", content: content}))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the errors are returned in the response so the config is still returned
+	for _, p := range payloads {
+		if err := svc.postConfig(ctx, p.path, p.content); err != nil {
+			return &protob.ApplyResponse{
+				Message: fmt.Sprintf("Error updating Caddy %s, err: %s", p.name, err.Error()),
+				Error:   true,
+				Config:  config,
+			}, nil
+		}
+	}
+
 	// send the update
-	res, err := svc.HTTP.Post(svc.Addr+"/config/apps/http/servers", "application/json", bytes.NewReader(content))
+	res, err := svc.post(ctx, "/config/apps/http/servers", content)
 	if err != nil {
+		msg := fmt.Sprintf("Error updating Caddy API, err: %s", err.Error())
+
 		return &protob.ApplyResponse{
-			Message: fmt.Sprintf("Error updating Caddy API, err: %s", err.Error()),
+			Message: msg,
 			Error:   true,
-		}, err
+			Sites:   failed(statuses, msg),
+			Config:  config,
+		}, nil
 	}
 
 	// check the status code
 	if res.StatusCode != http.StatusOK {
+		msg := fmt.Sprintf("Received %d response from Caddy API", res.StatusCode)
+
 		return &protob.ApplyResponse{
-			Message: fmt.Sprintf("Received %d response from Caddy API", res.StatusCode),
+			Message: msg,
 			Error:   true,
+			Sites:   failed(statuses, msg),
+			Config:  config,
 		}, nil
 	}
 
+	// keep the hosts to answer on demand certificate requests
+	svc.mu.Lock()
+	svc.hosts = applied
+	svc.routes = routes
+	svc.mu.Unlock()
+
+	msg := fmt.Sprintf("Successfully applied changes, sites: %d", len(request.GetSites()))
+	if errs := len(request.GetSites()) - len(routes); errs > 0 {
+		msg = fmt.Sprintf("Applied changes with errors, sites: %d, errors: %d", len(request.GetSites()), errs)
+	}
+
 	// set the message and error to false
 	return &protob.ApplyResponse{
-		Message: fmt.Sprintf("Successfully applied changes, sites: %d", len(request.GetSites())),
+		Message: msg,
 		Error:   false,
+		Sites:   statuses,
+		Config:  config,
 	}, nil
 }
 
-// ImportDatabase is used to handle streaming requests from the client and import a
-// database from a backup into the remote database container.
-func (svc *Service) ImportDatabase(stream protob.Nitro_ImportDatabaseServer) error {
-	// verify the importer is declared
-	if svc.Importer == nil {
-		svc.Importer = database.NewImporter()
+// DefaultMaintenancePage is the HTML shown for sites in maintenance mode when a custom
+// maintenance page is not provided.
+const DefaultMaintenancePage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="utf-8">
+    <title>Down for maintenance</title>
+</head>
+<body>
+    <h1>Down for maintenance</h1>
+    <p>This site is in maintenance mode, run <code>nitro maintenance off</code> to bring it back.</p>
+</body>
+</html>
+`
+
+// maintenanceHandle returns a static response handler that responds with a 503 and
+// the maintenance page.
+func maintenanceHandle(body string) caddy.RouteHandle {
+	if body == "" {
+		body = DefaultMaintenancePage
 	}
 
-	// create the options for the import
-	opts := database.ImportOptions{}
-
-	// create a temp file used to import the database content
-	tempFile, err := ioutil.TempFile(os.TempDir(), "nitro-db-import")
-	if err != nil {
-		return status.Errorf(codes.Internal, "Unable creating a temp file for the upload")
+	return caddy.RouteHandle{
+		Handler:    "static_response",
+		StatusCode: http.StatusServiceUnavailable,
+		Headers: map[string][]string{
+			"Content-Type": {"text/html; charset=utf-8"},
+			"Retry-After":  {"3600"},
+		},
+		Body: body,
 	}
+}
 
-	// defer the file close and deletion
-	defer tempFile.Close()
-	defer os.Remove(tempFile.Name())
+// DefaultNotFoundPage is the HTML shown for hosts that do not match a site when a custom
+// not found page is not provided, the sites are added to the list.
+const DefaultNotFoundPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="utf-8">
+    <title>Site not found</title>
+</head>
+<body>
+    <h1>Site not found</h1>
+    <p>There is no Nitro site for this hostname, the sites are:</p>
+    <ul>
+%s    </ul>
+</body>
+</html>
+`
+
+// notFoundHandle returns a static response handler that responds with a 404 and the
+// not found page. When the body is empty, the default page lists the sites.
+func notFoundHandle(body string, sites map[string]*protob.Site) caddy.RouteHandle {
+	if body == "" {
+		var hosts []string
+		for _, s := range sites {
+			hosts = append(hosts, s.GetHostname())
+		}
 
-	// set the temporary file
-	opts.File = tempFile.Name()
+		sort.Strings(hosts)
 
-	req, err := stream.Recv()
-	if err != nil {
-		return status.Errorf(codes.Internal, "unable to receive from stream: %s", err.Error())
-	}
+		var list strings.Builder
+		for _, h := range hosts {
+			fmt.Fprintf(&list, "        <li><a href=\"http://%s\">%s</a></li>\n", html.EscapeString(h), html.EscapeString(validate.Unicode(h)))
+		}
 
-	// get the database engine
-	if opts.Engine == "" {
-		opts.Engine = req.GetDatabase().GetEngine()
+		if len(hosts) == 0 {
+			list.WriteString("        <li>No sites have been added, run <code>nitro create</code> to add one</li>\n")
+		}
+
+		body = fmt.Sprintf(DefaultNotFoundPage, list.String())
 	}
 
-	// get the database version
-	if opts.Version == "" {
-		opts.Version = req.GetDatabase().GetVersion()
+	return caddy.RouteHandle{
+		Handler:    "static_response",
+		StatusCode: http.StatusNotFound,
+		Headers: map[string][]string{
+			"Content-Type": {"text/html; charset=utf-8"},
+		},
+		Body: body,
 	}
+}
 
-	// get the database port
-	if opts.Port == "" {
-		opts.Port = req.GetDatabase().GetPort()
+// validProxyPort returns an error if the additional proxy port is not a valid port or
+// is used by the other servers or the caddy and nitrod APIs.
+func validProxyPort(port int32) error {
+	if port <= 0 || port > 65535 {
+		return fmt.Errorf("the proxy port %d is not valid", port)
 	}
 
-	// get the database hostname
-	if opts.Hostname == "" {
-		opts.Hostname = req.GetDatabase().GetHostname()
+	switch port {
+	case 80, 443, 2019, 3000, 3001, 5000, 5001:
+		return fmt.Errorf("the proxy port %d is already used by the proxy", port)
 	}
 
-	// get the database name
-	if opts.DatabaseName == "" {
-		opts.DatabaseName = req.GetDatabase().GetDatabase()
+	return nil
+}
+
+// validSite returns an error if the site is missing the details needed to
+// create the routes.
+func validSite(site *protob.Site) error {
+	if site.GetHostname() == "" {
+		return fmt.Errorf("the site is missing a hostname")
 	}
 
-	// check if the file is compressed
-	if (!opts.Compressed) && (req.GetDatabase().GetCompressed()) {
-		opts.Compressed = req.GetDatabase().GetCompressed()
+	if site.GetPort() <= 0 || site.GetPort() > 65535 {
+		return fmt.Errorf("the port %d is not valid", site.GetPort())
 	}
 
-	// set the compression type
-	if opts.CompressionType == "" && req.GetDatabase().GetCompressionType() != "" {
-		opts.CompressionType = req.GetDatabase().GetCompressionType()
+	if site.GetAliases() != "" {
+		for _, a := range strings.Split(site.GetAliases(), ",") {
+			if strings.TrimSpace(a) == "" {
+				return fmt.Errorf("the aliases %q contain an empty alias", site.GetAliases())
+			}
+		}
 	}
 
-	// handle the streaming request
-	for {
-		req, err := stream.Recv()
-		if err == io.EOF {
-			break
+	for _, p := range site.GetPaths() {
+		if p.GetUpstream() == "" {
+			return fmt.Errorf("the path %s is missing an upstream", p.GetPrefix())
 		}
-		if err != nil {
-			return status.Errorf(codes.Internal, "unable to create the stream: %s", err.Error())
+	}
+
+	for _, e := range site.GetErrorPages() {
+		if e.GetStatus() < 400 || e.GetStatus() > 599 {
+			return fmt.Errorf("the error page status %d is not a 4xx or 5xx status", e.GetStatus())
 		}
 
-		// write the streamed content into the temp file
-		_, err = tempFile.Write(req.GetData())
-		if err != nil && !errors.Is(err, io.EOF) {
-			return status.Errorf(codes.Internal, "unable to write content to the temp file")
+		if e.GetBody() == "" && !strings.HasPrefix(e.GetUri(), "/") {
+			return fmt.Errorf("the error page for %d must have a body or a uri starting with a /", e.GetStatus())
 		}
 	}
 
+	if site.GetClientCa() != "" {
+		if _, err := caCerts(site.GetClientCa()); err != nil {
+			return err
+		}
+	}
+
+	v := &validate.HeaderName{}
+	for name, value := range site.GetHeaders() {
+		if err := v.Validate(name); err != nil {
+			return err
+		}
+
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("the header %s must not include a new line", name)
+		}
+	}
+
+	for _, name := range site.GetRemoveHeaders() {
+		if err := v.Validate(name); err != nil {
+			return err
+		}
+	}
+
+	if site.GetMaxRequestBody() < 0 {
+		return fmt.Errorf("the max request body %d must not be negative", site.GetMaxRequestBody())
+	}
+
+	for _, link := range site.GetEarlyHints() {
+		if !strings.HasPrefix(link, "<") || strings.ContainsAny(link, "\r\n") {
+			return fmt.Errorf("the early hint %q must be a link starting with a < and not include a new line", link)
+		}
+	}
+
+	return nil
+}
+
+// earlyHintsHandle returns the handler that sends a 103 Early Hints response with the
+// links before the upstream responds, and false if the site does not have early hints.
+// Caddy sends the informational response and continues to the next handler.
+func earlyHintsHandle(site *protob.Site) (caddy.RouteHandle, bool) {
+	if len(site.GetEarlyHints()) == 0 {
+		return caddy.RouteHandle{}, false
+	}
+
+	return caddy.RouteHandle{
+		Handler:    "static_response",
+		StatusCode: http.StatusEarlyHints,
+		Headers: map[string][]string{
+			"Link": site.GetEarlyHints(),
+		},
+	}, true
+}
+
+// requestBodyHandle returns the handler that limits the size of the request body for the
+// site and false if the site does not limit the request body. Caddy responds with a 413
+// when the body is larger than the limit.
+func requestBodyHandle(site *protob.Site) (caddy.RouteHandle, bool) {
+	if site.GetMaxRequestBody() <= 0 {
+		return caddy.RouteHandle{}, false
+	}
+
+	return caddy.RouteHandle{
+		Handler: "request_body",
+		MaxSize: site.GetMaxRequestBody(),
+	}, true
+}
+
+// headersHandle returns the handler that sets and removes the response headers for the
+// site and false if the site does not change any headers. The operations are deferred so
+// they apply to the headers from the upstream.
+func headersHandle(site *protob.Site) (caddy.RouteHandle, bool) {
+	if len(site.GetHeaders()) == 0 && len(site.GetRemoveHeaders()) == 0 {
+		return caddy.RouteHandle{}, false
+	}
+
+	ops := &caddy.HeaderOps{
+		Delete:   site.GetRemoveHeaders(),
+		Deferred: true,
+	}
+
+	if len(site.GetHeaders()) > 0 {
+		ops.Set = make(map[string][]string)
+		for name, value := range site.GetHeaders() {
+			ops.Set[name] = []string{value}
+		}
+	}
+
+	return caddy.RouteHandle{
+		Handler:  "headers",
+		Response: ops,
+	}, true
+}
+
+// clientAuthPolicy returns the TLS connection policy for the hosts that requires client
+// certificates signed by the CA.
+func clientAuthPolicy(ca string, hosts []string) (caddy.TLSConnectionPolicy, error) {
+	certs, err := caCerts(ca)
+	if err != nil {
+		return caddy.TLSConnectionPolicy{}, err
+	}
+
+	return caddy.TLSConnectionPolicy{
+		Match: &caddy.TLSConnectionMatch{
+			SNI: hosts,
+		},
+		ClientAuthentication: &caddy.TLSClientAuthentication{
+			TrustedCACerts: certs,
+			Mode:           "require_and_verify",
+		},
+	}, nil
+}
+
+// caCerts takes PEM encoded certificates and returns the base64 encoded DER of each
+// certificate, which is the format Caddy uses for trusted CAs.
+func caCerts(ca string) ([]string, error) {
+	var certs []string
+	rest := []byte(ca)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return nil, fmt.Errorf("the client CA is not a valid certificate, %w", err)
+		}
+
+		certs = append(certs, base64.StdEncoding.EncodeToString(block.Bytes))
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("the client CA does not contain a PEM encoded certificate")
+	}
+
+	return certs, nil
+}
+
+// errorPageRoutes returns the routes that handle the errors Caddy returns for the site,
+// such as a 502 when the site is not running. The errors the site responds with are
+// handled by the reverse proxy, see errorPageResponses.
+func errorPageRoutes(name string, site *protob.Site, hosts []string) []caddy.ServerRoute {
+	var routes []caddy.ServerRoute
+	for _, e := range site.GetErrorPages() {
+		routes = append(routes, caddy.ServerRoute{
+			Handle: errorPageHandle(name, site.GetPort(), e),
+			Match: []caddy.Match{
+				{
+					Host:       hosts,
+					Expression: fmt.Sprintf("{http.error.status_code} == %d", e.GetStatus()),
+				},
+			},
+			Terminal: true,
+		})
+	}
+
+	return routes
+}
+
+// errorPageResponses returns the response handlers for the reverse proxy to the site that
+// replace the responses with the status of an error page, such as a 404 from the app.
+func errorPageResponses(name string, site *protob.Site) []caddy.ResponseHandler {
+	var handlers []caddy.ResponseHandler
+	for _, e := range site.GetErrorPages() {
+		handlers = append(handlers, caddy.ResponseHandler{
+			Match: &caddy.ResponseMatch{StatusCode: []int{int(e.GetStatus())}},
+			Routes: []caddy.ServerRoute{
+				{
+					Handle:   errorPageHandle(name, site.GetPort(), e),
+					Terminal: true,
+				},
+			},
+		})
+	}
+
+	return handlers
+}
+
+// errorPageHandle returns the handlers for an error page, which either respond with the
+// body and status, or rewrite the request to a GET for the uri and send it to the site.
+func errorPageHandle(name string, port int32, e *protob.SiteErrorPage) []caddy.RouteHandle {
+	if e.GetBody() != "" {
+		return []caddy.RouteHandle{
+			{
+				Handler:    "static_response",
+				StatusCode: int(e.GetStatus()),
+				Headers: map[string][]string{
+					"Content-Type": {"text/html; charset=utf-8"},
+				},
+				Body: e.GetBody(),
+			},
+		}
+	}
+
+	return []caddy.RouteHandle{
+		{
+			Handler: "rewrite",
+			Method:  http.MethodGet,
+			URI:     e.GetUri(),
+		},
+		{
+			Handler: "reverse_proxy",
+			Upstreams: []caddy.Upstream{
+				{
+					Dial: fmt.Sprintf("%s:%d", name, port),
+				},
+			},
+		},
+	}
+}
+
+// failed marks the statuses of the sites as errors when the routes
+// could not be applied.
+func failed(statuses []*protob.SiteStatus, msg string) []*protob.SiteStatus {
+	for _, s := range statuses {
+		if s.Status != SiteError {
+			s.Status = SiteError
+			s.Message = msg
+		}
+	}
+
+	return statuses
+}
+
+// caddyPayload is a config posted to a path of the Caddy admin API.
+type caddyPayload struct {
+	name    string
+	path    string
+	content []byte
+}
+
+// combinePayloads returns the configs as a JSON object keyed by the admin API path, so
+// every config sent to Caddy is returned together.
+func combinePayloads(payloads []caddyPayload) (string, error) {
+	combined := make(map[string]json.RawMessage)
+	for _, p := range payloads {
+		combined[p.path] = p.content
+	}
+
+	content, err := json.Marshal(combined)
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+// postConfig posts the config to the path of the Caddy admin API and returns an error
+// when Caddy does not accept it.
+func (svc *Service) postConfig(ctx context.Context, path string, content []byte) error {
+	res, err := svc.post(ctx, path, content)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("received %d response from Caddy API", res.StatusCode)
+	}
+
+	return nil
+}
+
+// accessLogging returns the logging config with a logger for each site that writes the
+// access logs to a file, using the hostname, on the proxy data volume.
+func accessLogging(sites map[string]*protob.Site) caddy.Logging {
+	logging := caddy.Logging{
+		Logs: map[string]caddy.Log{
+			// keep the access logs out of the default logger
+			"default": {
+				Exclude: []string{"http.log.access"},
+			},
+		},
+	}
+
+	for _, site := range sites {
+		name := loggerName(site.GetHostname())
+
+		logging.Logs[name] = caddy.Log{
+			Writer: &caddy.LogWriter{
+				Output:   "file",
+				Filename: fmt.Sprintf("/data/logs/%s.log", site.GetHostname()),
+			},
+			Include: []string{"http.log.access." + name},
+		}
+	}
+
+	return logging
+}
+
+// onDemandAutomation returns the TLS automation config for Caddy to issue certificates
+// from its internal CA when a host is first requested, if the ask endpoint allows the host.
+func (svc *Service) onDemandAutomation() caddy.TLSAutomation {
+	return caddy.TLSAutomation{
+		Policies: []caddy.TLSPolicy{
+			{
+				Issuers: []caddy.TLSIssuer{
+					{
+						Module: "internal",
+					},
+				},
+				OnDemand: true,
+			},
+		},
+		OnDemand: &caddy.TLSOnDemand{
+			Ask: svc.AskAddr,
+		},
+	}
+}
+
+// Ask is the HTTP handler Caddy uses to check if a certificate can be issued on demand. It
+// allows the hosts from the last apply, and any subdomain of those hosts, and rejects the rest.
+func (svc *Service) Ask(w http.ResponseWriter, r *http.Request) {
+	domain := strings.ToLower(strings.TrimSuffix(r.URL.Query().Get("domain"), "."))
+	if domain == "" {
+		http.Error(w, "missing domain", http.StatusBadRequest)
+		return
+	}
+
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+
+	for h := range svc.hosts {
+		if domain == h || strings.HasSuffix(domain, "."+h) {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+	}
+
+	http.Error(w, "unknown domain", http.StatusForbidden)
+}
+
+// post sends the JSON content to the Caddy API path using the context.
+func (svc *Service) post(ctx context.Context, path string, content []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, svc.Addr+path, bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	svc.authorize(req)
+
+	res, err := svc.HTTP.Do(req)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("the Caddy API did not respond before the deadline")
+	}
+
+	return res, err
+}
+
+// authorize adds the token, if there is one, to the request to the Caddy API.
+func (svc *Service) authorize(req *http.Request) {
+	if svc.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+svc.Token)
+	}
+}
+
+// prioritizeHosts takes a list of routes and splits the routes that match wildcard hosts
+// (e.g. *.example.nitro) so all of the routes for exact hosts are matched first. Without
+// this, the wildcard of one site could shadow another site using a subdomain. The order
+// of the routes is kept within the exact and wildcard routes.
+func prioritizeHosts(routes []caddy.ServerRoute) []caddy.ServerRoute {
+	var exact, wildcard []caddy.ServerRoute
+	for _, r := range routes {
+		var exactMatches, wildcardMatches []caddy.Match
+		for _, m := range r.Match {
+			var exactHosts, wildcardHosts []string
+			for _, h := range m.Host {
+				if strings.HasPrefix(h, "*.") {
+					wildcardHosts = append(wildcardHosts, h)
+					continue
+				}
+
+				exactHosts = append(exactHosts, h)
+			}
+
+			if len(exactHosts) > 0 {
+				exactMatches = append(exactMatches, caddy.Match{Host: exactHosts, Path: m.Path, Expression: m.Expression})
+			}
+
+			if len(wildcardHosts) > 0 {
+				wildcardMatches = append(wildcardMatches, caddy.Match{Host: wildcardHosts, Path: m.Path, Expression: m.Expression})
+			}
+		}
+
+		// routes without a host matcher are kept as is
+		if len(r.Match) == 0 || len(wildcardMatches) == 0 {
+			exact = append(exact, r)
+			continue
+		}
+
+		if len(exactMatches) > 0 {
+			exact = append(exact, caddy.ServerRoute{Handle: r.Handle, Match: exactMatches, Terminal: r.Terminal})
+		}
+
+		wildcard = append(wildcard, caddy.ServerRoute{Handle: r.Handle, Match: wildcardMatches, Terminal: r.Terminal})
+	}
+
+	return append(exact, wildcard...)
+}
+
+// duplicateHosts checks the hostnames and aliases of all the sites and returns
+// an error naming the sites if more than one site uses the same host.
+func duplicateHosts(sites map[string]*protob.Site) error {
+	// sort the sites so the error is consistent
+	var names []string
+	for k := range sites {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	used := make(map[string]string)
+	for _, k := range names {
+		site := sites[k]
+
+		hosts := []string{site.GetHostname()}
+		if site.GetAliases() != "" {
+			hosts = append(hosts, strings.Split(site.GetAliases(), ",")...)
+		}
+
+		for _, h := range hosts {
+			h = strings.ToLower(strings.TrimSpace(h))
+			if h == "" {
+				continue
+			}
+
+			if existing, ok := used[h]; ok && existing != site.GetHostname() {
+				return fmt.Errorf("the host %q is used by the sites %q and %q", h, existing, site.GetHostname())
+			}
+
+			used[h] = site.GetHostname()
+		}
+	}
+
+	return nil
+}
+
+// loggerName converts a hostname into a Caddy logger name.
+func loggerName(hostname string) string {
+	return strings.ReplaceAll(hostname, ".", "_")
+}
+
+// ImportDatabase is used to handle streaming requests from the client and import a
+// database from a backup into the remote database container.
+func (svc *Service) ImportDatabase(stream protob.Nitro_ImportDatabaseServer) error {
+	// verify the importer is declared
+	if svc.Importer == nil {
+		svc.Importer = database.NewImporter()
+	}
+
+	// create the options for the import
+	opts := database.ImportOptions{}
+
+	req, err := stream.Recv()
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to receive from stream: %s", err.Error())
+	}
+
+	// get the database engine
+	if opts.Engine == "" {
+		opts.Engine = req.GetDatabase().GetEngine()
+	}
+
+	// get the database version
+	if opts.Version == "" {
+		opts.Version = req.GetDatabase().GetVersion()
+	}
+
+	// get the database port
+	if opts.Port == "" {
+		opts.Port = req.GetDatabase().GetPort()
+	}
+
+	// get the database hostname
+	if opts.Hostname == "" {
+		opts.Hostname = req.GetDatabase().GetHostname()
+	}
+
+	// get the database name
+	if opts.DatabaseName == "" {
+		opts.DatabaseName = req.GetDatabase().GetDatabase()
+	}
+
+	// check if the file is compressed
+	if (!opts.Compressed) && (req.GetDatabase().GetCompressed()) {
+		opts.Compressed = req.GetDatabase().GetCompressed()
+	}
+
+	// set the compression type
+	if opts.CompressionType == "" && req.GetDatabase().GetCompressionType() != "" {
+		opts.CompressionType = req.GetDatabase().GetCompressionType()
+	}
+
+	// check if we are only validating the backup
+	opts.Validate = req.GetDatabase().GetValidate()
+
+	// check if we should analyze the tables after importing
+	opts.Analyze = req.GetDatabase().GetAnalyze()
+
+	// check if the upload should be kept after the import
+	keep := req.GetDatabase().GetKeepUpload()
+
+	// set the parallel jobs for postgres archives
+	opts.Jobs = int(req.GetDatabase().GetJobs())
+
+	// check if the import should continue on errors
+	opts.OnError = req.GetDatabase().GetOnError()
+
+	// check if the table prefix should be rewritten
+	opts.FromPrefix = req.GetDatabase().GetFromPrefix()
+	opts.ToPrefix = req.GetDatabase().GetToPrefix()
+
+	// check if the database in the backup should be renamed
+	opts.RenameFrom = req.GetDatabase().GetRenameFrom()
+	opts.RenameTo = req.GetDatabase().GetRenameTo()
+
+	// check if tables should be removed from the backup
+	opts.ExcludeTables = req.GetDatabase().GetExcludeTables()
+
+	// get the credentials for the database, the nitro user is used when they are empty
+	opts.User = req.GetDatabase().GetUser()
+	opts.Password = req.GetDatabase().GetPassword()
+
+	// read the backup from the stream and log the progress, the client only shows a bar in a terminal
+	upload := &uploadReader{stream: stream, size: req.GetDatabase().GetSize(), progress: terminal.NewProgressLines(log.Writer(), fmt.Sprintf("receiving the backup for %q", opts.DatabaseName))}
+	upload.progress.Start(req.GetDatabase().GetSize())
+	data := bufio.NewReader(upload)
+
+	// plain backups are piped into the import tool as they are received
+	if streamable(opts, keep, data) {
+		return svc.streamImport(stream, &opts, upload, data)
+	}
+
+	// the request can use a different directory to stage the upload
+	dir := svc.TempDir
+	if req.GetDatabase().GetTmpDir() != "" {
+		dir = req.GetDatabase().GetTmpDir()
+	}
+
+	// fail early if the upload will not fit in the temp directory
+	if err := tempdir.Check(dir, req.GetDatabase().GetSize()); err != nil {
+		if errors.Is(err, tempdir.ErrNoSpace) {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	// create a temp file used to import the database content
+	tempFile, err := ioutil.TempFile(tempdir.Dir(dir), "nitro-db-import")
+	if err != nil {
+		return status.Errorf(codes.Internal, "Unable creating a temp file for the upload")
+	}
+
+	// defer the file close and deletion, unless the upload should be kept
+	defer tempFile.Close()
+	defer func() {
+		if !keep {
+			os.Remove(tempFile.Name())
+		}
+	}()
+
+	// set the temporary file
+	opts.File = tempFile.Name()
+
+	// write the streamed content into the temp file
+	if _, err := io.Copy(tempFile, data); err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+
+		return status.Errorf(codes.Internal, "unable to write content to the temp file")
+	}
+
 	// verify we can connect to the database hostname - no error means its reachable
 	if err := portavail.Check(opts.Hostname, opts.Port); err == nil {
 		return status.Errorf(codes.Internal, "it does not appear the database is available on host %s using port %s: %v", opts.Hostname, opts.Port, err)
@@ -348,7 +1504,7 @@ func (svc *Service) ImportDatabase(stream protob.Nitro_ImportDatabaseServer) err
 
 	if opts.Compressed {
 		// create the temp file to store the data
-		temp, err := ioutil.TempFile(os.TempDir(), "nitro-db-compressed")
+		temp, err := ioutil.TempFile(tempdir.Dir(dir), "nitro-db-compressed")
 		if err != nil {
 			return status.Errorf(codes.Internal, "unable to create a temp file: %s", err)
 		}
@@ -402,29 +1558,398 @@ func (svc *Service) ImportDatabase(stream protob.Nitro_ImportDatabaseServer) err
 			}
 
 			opts.File = temp.Name()
+		case "tgz":
+			// open the compressed file
+			f, err := os.Open(opts.File)
+			if err != nil {
+				return status.Error(codes.Unknown, fmt.Sprintf("unable to open file for gzip reader %s: %s", opts.File, err))
+			}
+			defer f.Close()
+
+			// read the file
+			r, err := gzip.NewReader(f)
+			if err != nil {
+				return status.Error(codes.Unknown, fmt.Sprintf("unable to open gzip reader %s: %s", opts.File, err))
+			}
+			defer r.Close()
+
+			// directory format postgres archives are extracted into a temp directory
+			archive, err := ioutil.TempDir(tempdir.Dir(dir), "nitro-db-archive")
+			if err != nil {
+				return status.Errorf(codes.Internal, "unable to create a temp directory: %s", err)
+			}
+			defer os.RemoveAll(archive)
+
+			// find the first sql file or the postgres archive files
+			var foundSQL bool
+			tr := tar.NewReader(r)
+			for {
+				h, err := tr.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return status.Error(codes.Unknown, fmt.Sprintf("unable to read the tar archive %s: %s", opts.File, err))
+				}
+
+				if h.Typeflag != tar.TypeReg || strings.Contains(h.Name, "MACOSX") {
+					continue
+				}
+
+				name := filepath.Base(h.Name)
+				switch {
+				case strings.HasSuffix(name, ".sql") && !foundSQL:
+					// copy the content into the new temp file
+					if _, err := io.Copy(temp, tr); err != nil {
+						return status.Error(codes.Unknown, fmt.Sprintf("unable to copy tar reader into temp file %s: %s", temp.Name(), err))
+					}
+
+					foundSQL = true
+				case name == "toc.dat" || strings.Contains(name, ".dat"):
+					if err := extractFile(tr, filepath.Join(archive, name)); err != nil {
+						return status.Error(codes.Unknown, fmt.Sprintf("unable to extract %s from the archive: %s", h.Name, err))
+					}
+				}
+			}
+
+			switch {
+			case database.ArchiveFormat(archive) == "directory":
+				opts.File = archive
+			case foundSQL:
+				opts.File = temp.Name()
+			default:
+				return status.Error(codes.InvalidArgument, fmt.Sprintf("unable to find a sql file in the archive %s", opts.File))
+			}
 		default:
 			return status.Error(codes.InvalidArgument, fmt.Sprintf("unsupported compressed file type %q provided", opts.CompressionType))
 		}
 	}
 
 	// import the database
-	if err := database.NewImporter().Import(&opts, database.DefaultImportToolFinder); err != nil {
-		return status.Errorf(codes.Internal, "error importing the database %v", err)
+	msg, err := importBackup(&opts)
+	if err != nil {
+		return err
+	}
+
+	if keep {
+		msg = fmt.Sprintf("%s, the upload was kept at %s", msg, tempFile.Name())
+	}
+
+	return sendImportResponse(stream, msg, opts.Output)
+}
+
+// streamImport imports a plain backup that is piped into the import tool as it is received,
+// so the backup is never written to disk. When the stream is interrupted or ends before the
+// whole backup is received, the import tool is killed instead of reaching the end of its
+// input and the upload is reported as data loss.
+func (svc *Service) streamImport(stream protob.Nitro_ImportDatabaseServer, opts *database.ImportOptions, upload *uploadReader, data io.Reader) error {
+	opts.Stdin = data
+
+	var msg string
+	var err error
+
+	// verify we can connect to the database hostname - no error means its reachable
+	if perr := portavail.Check(opts.Hostname, opts.Port); perr == nil {
+		err = status.Errorf(codes.Internal, "it does not appear the database is available on host %s using port %s: %v", opts.Hostname, opts.Port, perr)
+	} else {
+		msg, err = importBackup(opts)
+	}
+
+	// read the rest of the upload, the import tool stops reading on errors
+	if _, rerr := io.Copy(ioutil.Discard, data); rerr != nil && err == nil {
+		err = rerr
+	}
+
+	// the error reading the upload is the reason the import failed
+	if upload.err != nil {
+		return upload.err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return sendImportResponse(stream, msg, opts.Output)
+}
+
+// streamable returns true when the backup can be piped into the import tool. Compressed
+// backups, Postgres custom format archives, and uploads that are kept are written to a
+// temp file instead.
+func streamable(opts database.ImportOptions, keep bool, data *bufio.Reader) bool {
+	if opts.Compressed || keep {
+		return false
+	}
+
+	// custom format archives start with the magic string PGDMP
+	magic, _ := data.Peek(5)
+
+	return string(magic) != "PGDMP"
+}
+
+// importBackup imports the backup with the options and returns the message for the client.
+func importBackup(opts *database.ImportOptions) (string, error) {
+	importer := database.NewImporter()
+	if err := importer.Import(opts, database.DefaultImportToolFinder); err != nil {
+		if opts.Validate {
+			return "", status.Errorf(codes.InvalidArgument, "the backup is not valid: %v", err)
+		}
+
+		return "", status.Errorf(codes.Internal, "error importing the database %v", err)
 	}
 
+	msg := database.Summary(opts)
+
+	// update the table statistics
+	if opts.Analyze && !opts.Validate {
+		start := time.Now()
+		if err := importer.Analyze(opts, database.DefaultImportToolFinder); err != nil {
+			return "", status.Errorf(codes.Internal, "imported the database but unable to analyze the tables %v", err)
+		}
+
+		msg = fmt.Sprintf("%s and analyzed the tables in %.2f seconds", msg, time.Since(start).Seconds())
+	}
+
+	// jobs are only supported by pg_restore
+	if opts.Jobs > 0 && opts.Format == "" {
+		msg = fmt.Sprintf("%s, the jobs were ignored because the backup is not a custom or directory format archive", msg)
+	}
+
+	return msg, nil
+}
+
+// sendImportResponse sends the message and the output of the import tool and closes the stream.
+func sendImportResponse(stream protob.Nitro_ImportDatabaseServer, msg, output string) error {
 	// send and close the stream
 	return stream.SendAndClose(
 		&protob.ImportDatabaseResponse{
-			Message: fmt.Sprintf("Imported database %q", opts.DatabaseName),
+			Message: msg,
+			Output:  truncateOutput(output, MaxImportOutput),
 		},
 	)
 }
 
+// truncateOutput keeps the response under the gRPC message size limit, without cutting
+// a multi-byte character in half.
+func truncateOutput(output string, max int) string {
+	if len(output) <= max {
+		return output
+	}
+
+	cut := max
+	for cut > 0 && !utf8.RuneStart(output[cut]) {
+		cut--
+	}
+
+	return output[:cut] + "\n[the output was truncated]\n"
+}
+
+// uploadReader reads the backup from the data of an import stream and counts the bytes
+// received. When the size is known, a stream that ends before the size is received returns
+// a data loss error instead of io.EOF, so the backup is not treated as complete.
+type uploadReader struct {
+	stream   protob.Nitro_ImportDatabaseServer
+	buf      []byte
+	size     int64
+	received int64
+	progress terminal.Progress
+
+	// err is the first error reading the stream
+	err error
+}
+
+func (r *uploadReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	for len(r.buf) == 0 {
+		req, err := r.stream.Recv()
+		if err == io.EOF {
+			if r.size > 0 && r.received != r.size {
+				r.err = status.Errorf(codes.DataLoss, "the upload is incomplete, received %d of %d bytes", r.received, r.size)
+
+				return 0, r.err
+			}
+
+			r.progress.Finish()
+
+			return 0, io.EOF
+		}
+		if err != nil {
+			r.err = status.Errorf(codes.Internal, "unable to create the stream: %s", err.Error())
+
+			return 0, r.err
+		}
+
+		r.buf = req.GetData()
+		r.received += int64(len(r.buf))
+		r.progress.Add(int64(len(r.buf)))
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
+}
+
+// ExportDatabase runs the export tool on the proxy and streams the backup to the client. The
+// first message describes the backup and the rest of the messages contain the data.
+func (svc *Service) ExportDatabase(req *protob.ExportDatabaseRequest, stream protob.Nitro_ExportDatabaseServer) error {
+	// the exporter is set by NewAPI, the service is not changed here because streams run concurrently
+	exporter := svc.Exporter
+	if exporter == nil {
+		exporter = database.NewExporter()
+	}
+
+	opts := &database.ExportOptions{
+		Engine:       req.GetDatabase().GetEngine(),
+		Version:      req.GetDatabase().GetVersion(),
+		Hostname:     req.GetDatabase().GetHostname(),
+		Port:         req.GetDatabase().GetPort(),
+		DatabaseName: req.GetDatabase().GetDatabase(),
+		Compressed:   req.GetDatabase().GetCompressed(),
+		Format:       req.GetFormat(),
+		User:         req.GetDatabase().GetUser(),
+		Password:     req.GetDatabase().GetPassword(),
+	}
+
+	// the data is sent in chunks as the export tool writes the backup
+	w := bufio.NewWriterSize(&downloadWriter{stream: stream}, ExportChunkSize)
+	opts.Stdout = w
+
+	if err := database.ValidateExport(opts); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// verify we can connect to the database hostname - no error means its reachable
+	if err := portavail.Check(opts.Hostname, opts.Port); err == nil {
+		return status.Errorf(codes.Internal, "it does not appear the database is available on host %s using port %s: %v", opts.Hostname, opts.Port, err)
+	}
+
+	// send the details of the backup before the data
+	info := &protob.DatabaseInfo{
+		Engine:     opts.Engine,
+		Version:    opts.Version,
+		Hostname:   opts.Hostname,
+		Port:       opts.Port,
+		Database:   opts.DatabaseName,
+		Compressed: opts.Compressed,
+	}
+	if opts.Compressed {
+		info.CompressionType = "gz"
+	}
+
+	if err := stream.Send(&protob.ExportDatabaseResponse{Payload: &protob.ExportDatabaseResponse_Database{Database: info}}); err != nil {
+		return status.Errorf(codes.Internal, "unable to send to the stream: %s", err.Error())
+	}
+
+	if err := exporter.Export(opts, database.DefaultExportToolFinder); err != nil {
+		return status.Errorf(codes.Internal, "error exporting the database %v", err)
+	}
+
+	// send the last of the data
+	if err := w.Flush(); err != nil {
+		return status.Errorf(codes.Internal, "unable to send to the stream: %s", err.Error())
+	}
+
+	return nil
+}
+
+// downloadWriter sends the data written to it as the data of an export stream.
+type downloadWriter struct {
+	stream protob.Nitro_ExportDatabaseServer
+}
+
+func (w *downloadWriter) Write(p []byte) (int, error) {
+	// the buffer is reused by the caller, so the data is copied for the message
+	data := make([]byte, len(p))
+	copy(data, p)
+
+	if err := w.stream.Send(&protob.ExportDatabaseResponse{Payload: &protob.ExportDatabaseResponse_Data{Data: data}}); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// extractFile copies the content of the reader into a new file at the path.
+func extractFile(r io.Reader, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+
+	return err
+}
+
 // Ping returns a simple response "pong" from the gRPC API to verify connectivity.
 func (svc *Service) Ping(ctx context.Context, request *protob.PingRequest) (*protob.PingResponse, error) {
 	return &protob.PingResponse{Pong: "pong"}, nil
 }
 
+// ProxyAPI is used to pass a request through to the Caddy admin API. It is used for debugging the
+// live Caddy configuration that Apply produced and returns the status code and body from Caddy.
+// Requests that change the config are denied unless the request allows writes.
+func (svc *Service) ProxyAPI(ctx context.Context, request *protob.ProxyAPIRequest) (*protob.ProxyAPIResponse, error) {
+	// if there is no client, use the default
+	if svc.HTTP == nil {
+		svc.HTTP = http.DefaultClient
+	}
+
+	// set the addr if not provided
+	if svc.Addr == "" {
+		svc.Addr = "http://127.0.0.1:2019"
+	}
+
+	// default to a get request
+	method := strings.ToUpper(request.GetMethod())
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	// only allow the methods that read the config unless writing is allowed
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		if !request.GetWrite() {
+			return nil, status.Errorf(codes.PermissionDenied, "the %s method changes the proxy config and must allow writes", method)
+		}
+	}
+
+	// make sure the path is relative to the admin api
+	path := request.GetPath()
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, svc.Addr+path, bytes.NewReader(request.GetBody()))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to create the request: %s", err.Error())
+	}
+
+	if len(request.GetBody()) > 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	svc.authorize(req)
+
+	res, err := svc.HTTP.Do(req)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "unable to reach the Caddy API: %s", err.Error())
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to read the Caddy API response: %s", err.Error())
+	}
+
+	return &protob.ProxyAPIResponse{StatusCode: int32(res.StatusCode), Body: body}, nil
+}
+
 // RemoveDatabase handles removing a specific database from a database container
 func (svc *Service) RemoveDatabase(ctx context.Context, req *protob.RemoveDatabaseRequest) (*protob.RemoveDatabaseResponse, error) {
 	// get the database info from the request
@@ -472,25 +1997,49 @@ func (svc *Service) Version(ctx context.Context, request *protob.VersionRequest)
 }
 
 func (svc *Service) exec(tool string, commands []string) error {
+	_, err := svc.runner().Run(tool, commands)
+
+	return err
+}
+
+func (svc *Service) runner() Runner {
+	if svc.Runner == nil {
+		return execRunner{}
+	}
+
+	return svc.Runner
+}
+
+// Runner is used to run the database tools, such as mysql or psql, and return the output.
+type Runner interface {
+	Run(tool string, commands []string) (string, error)
+}
+
+type execRunner struct{}
+
+// Run executes the tool and returns the combined output, the output is also written to
+// stderr for the proxy logs.
+func (execRunner) Run(tool string, commands []string) (string, error) {
 	c := exec.Command(tool, commands...)
 
-	c.Stderr = os.Stderr
-	c.Stdout = ioutil.Discard
+	var out bytes.Buffer
+	c.Stderr = io.MultiWriter(os.Stderr, &out)
+	c.Stdout = &out
 
 	if err := c.Start(); err != nil {
-		return fmt.Errorf("unable to start the command: %w", err)
+		return "", fmt.Errorf("unable to start the command: %w", err)
 	}
 
 	if err := c.Wait(); err != nil {
 		if exiterr, ok := err.(*exec.ExitError); ok {
 			// The program has exited with an exit code != 0
 			if status, ok := exiterr.Sys().(syscall.WaitStatus); ok {
-				return fmt.Errorf("Exit Status: %d\nCommands: %s", status.ExitStatus(), strings.Join(commands, " "))
+				return out.String(), fmt.Errorf("Exit Status: %d\nCommands: %s", status.ExitStatus(), strings.Join(commands, " "))
 			}
 		} else {
-			return err
+			return out.String(), err
 		}
 	}
 
-	return nil
+	return out.String(), nil
 }
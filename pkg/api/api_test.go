@@ -2,13 +2,1533 @@ package api
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unicode/utf8"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/craftcms/nitro/pkg/caddy"
+	"github.com/craftcms/nitro/pkg/database"
 	"github.com/craftcms/nitro/protob"
 )
 
+func TestService_ApplyHTTPRedirects(t *testing.T) {
+	tests := []struct {
+		name        string
+		sites       map[string]*protob.Site
+		wantHandler map[string]string
+	}{
+		{
+			name: "sites with redirects use a static response on the http server",
+			sites: map[string]*protob.Site{
+				"craftdev.nitro":        {Hostname: "craftdev.nitro", Aliases: "alias.nitro", Port: 8080, HttpsRedirect: true},
+				"mailhog.service.nitro": {Hostname: "mailhog.service.nitro", Port: 8025},
+			},
+			wantHandler: map[string]string{
+				"craftdev.nitro":        "static_response",
+				"alias.nitro":           "static_response",
+				"mailhog.service.nitro": "reverse_proxy",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeCaddy(t, http.StatusOK)
+			svc := fake.service()
+
+			if _, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: tt.sites}); err != nil {
+				t.Fatal(err)
+			}
+
+			update := fake.update(t)
+
+			// the welcome server must be the last route
+			if len(update.HTTP.Routes) != len(tt.sites)+1 {
+				t.Fatalf("expected %d http routes, got %d", len(tt.sites)+1, len(update.HTTP.Routes))
+			}
+			if update.HTTP.Routes[len(update.HTTP.Routes)-1].Match != nil {
+				t.Errorf("expected the last http route to be the welcome route")
+			}
+
+			for _, route := range update.HTTP.Routes[:len(tt.sites)] {
+				for _, host := range route.Match[0].Host {
+					want, ok := tt.wantHandler[host]
+					if !ok {
+						t.Errorf("unexpected host %q in the http routes", host)
+						continue
+					}
+
+					handle := route.Handle[0]
+					if handle.Handler != want {
+						t.Errorf("expected the handler for %q to be %q, got %q", host, want, handle.Handler)
+					}
+
+					if want == "static_response" {
+						if handle.StatusCode != http.StatusPermanentRedirect {
+							t.Errorf("expected the status code to be %d, got %d", http.StatusPermanentRedirect, handle.StatusCode)
+						}
+						if handle.Headers["Location"][0] != "https://{http.request.host}{http.request.uri}" {
+							t.Errorf("unexpected location header %v", handle.Headers["Location"])
+						}
+					}
+				}
+			}
+
+			// the https server always proxies to the site
+			for _, route := range update.HTTPS.Routes {
+				if route.Handle[0].Handler != "reverse_proxy" {
+					t.Errorf("expected the https routes to use reverse_proxy, got %q", route.Handle[0].Handler)
+				}
+			}
+		})
+	}
+}
+
+func TestService_ApplyRouteOrder(t *testing.T) {
+	fake := newFakeCaddy(t, http.StatusOK)
+	svc := fake.service()
+
+	sites := map[string]*protob.Site{
+		"craftdev.nitro":       {Hostname: "craftdev.nitro", Port: 8080},
+		"api.craftdev.nitro":   {Hostname: "api.craftdev.nitro", Port: 8080},
+		"redirect.nitro":       {Hostname: "redirect.nitro", Port: 8080, HttpsRedirect: true},
+		"paths.craftdev.nitro": {Hostname: "paths.craftdev.nitro", Port: 8080, Paths: []*protob.SitePath{{Prefix: "/api", Upstream: "api.containers.nitro:3000"}}},
+	}
+
+	if _, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: sites, OnDemandTLS: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	update := fake.update(t)
+
+	tests := []struct {
+		name       string
+		routes     []caddy.ServerRoute
+		host       string
+		path       string
+		wantHandle string
+		wantDial   string
+	}{
+		{
+			name:       "known hosts do not use the not found route",
+			routes:     update.HTTP.Routes,
+			host:       "craftdev.nitro",
+			path:       "/",
+			wantHandle: "reverse_proxy",
+			wantDial:   "craftdev.nitro:8080",
+		},
+		{
+			name:       "redirected hosts do not use the not found route",
+			routes:     update.HTTP.Routes,
+			host:       "redirect.nitro",
+			path:       "/",
+			wantHandle: "static_response",
+		},
+		{
+			name:       "unknown hosts use the not found route",
+			routes:     update.HTTP.Routes,
+			host:       "unknown.nitro",
+			path:       "/",
+			wantHandle: "static_response",
+		},
+		{
+			name:       "sites are not shadowed by the wildcard of another site",
+			routes:     update.HTTPS.Routes,
+			host:       "api.craftdev.nitro",
+			path:       "/",
+			wantHandle: "reverse_proxy",
+			wantDial:   "api.craftdev.nitro:8080",
+		},
+		{
+			name:       "sites sorted after the wildcard of another site are not shadowed",
+			routes:     update.HTTPS.Routes,
+			host:       "paths.craftdev.nitro",
+			path:       "/",
+			wantHandle: "reverse_proxy",
+			wantDial:   "paths.craftdev.nitro:8080",
+		},
+		{
+			name:       "path routes are not shadowed by the wildcard of another site",
+			routes:     update.HTTPS.Routes,
+			host:       "paths.craftdev.nitro",
+			path:       "/api/users",
+			wantHandle: "reverse_proxy",
+			wantDial:   "api.containers.nitro:3000",
+		},
+		{
+			name:       "subdomains use the wildcard of the site",
+			routes:     update.HTTPS.Routes,
+			host:       "www.craftdev.nitro",
+			path:       "/",
+			wantHandle: "reverse_proxy",
+			wantDial:   "craftdev.nitro:8080",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := firstRoute(tt.routes, tt.host, tt.path)
+			if route == nil {
+				t.Fatalf("expected a route to match %s%s", tt.host, tt.path)
+			}
+
+			if route.Handle[0].Handler != tt.wantHandle {
+				t.Errorf("expected the handler to be %q, got %q", tt.wantHandle, route.Handle[0].Handler)
+			}
+
+			if tt.wantDial != "" && route.Handle[0].Upstreams[0].Dial != tt.wantDial {
+				t.Errorf("expected the upstream to be %q, got %q", tt.wantDial, route.Handle[0].Upstreams[0].Dial)
+			}
+		})
+	}
+}
+
+// firstRoute returns the first route that matches the host and path, which is
+// how Caddy chooses the route for a request.
+func firstRoute(routes []caddy.ServerRoute, host, path string) *caddy.ServerRoute {
+	for i, r := range routes {
+		if len(r.Match) == 0 {
+			return &routes[i]
+		}
+
+		for _, m := range r.Match {
+			var hostMatch bool
+			for _, h := range m.Host {
+				if h == host || (strings.HasPrefix(h, "*.") && strings.Count(host, ".") == strings.Count(h, ".") && strings.HasSuffix(host, h[1:])) {
+					hostMatch = true
+				}
+			}
+
+			pathMatch := len(m.Path) == 0
+			for _, p := range m.Path {
+				if p == path || (strings.HasSuffix(p, "/*") && strings.HasPrefix(path, strings.TrimSuffix(p, "*"))) {
+					pathMatch = true
+				}
+			}
+
+			if hostMatch && pathMatch {
+				return &routes[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+// fakeCaddy is a fake Caddy admin API that keeps the body of the last request to each
+// path and responds with the status.
+type fakeCaddy struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	status   int
+	requests map[string][]byte
+}
+
+// newFakeCaddy starts a fake Caddy admin API that responds with the status, the server
+// is closed when the test finishes.
+func newFakeCaddy(t *testing.T, status int) *fakeCaddy {
+	t.Helper()
+
+	return startFakeCaddy(t, status, httptest.NewServer)
+}
+
+// newFakeCaddyTLS is newFakeCaddy with a self signed certificate.
+func newFakeCaddyTLS(t *testing.T, status int) *fakeCaddy {
+	t.Helper()
+
+	return startFakeCaddy(t, status, httptest.NewTLSServer)
+}
+
+func startFakeCaddy(t *testing.T, status int, start func(http.Handler) *httptest.Server) *fakeCaddy {
+	t.Helper()
+
+	f := &fakeCaddy{status: status, requests: make(map[string][]byte)}
+	f.Server = start(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("unable to read the request to %s, %v", r.URL.Path, err)
+		}
+
+		f.mu.Lock()
+		f.requests[r.URL.Path] = body
+		f.mu.Unlock()
+
+		w.WriteHeader(f.status)
+	}))
+	t.Cleanup(f.Close)
+
+	return f
+}
+
+// service returns a service that uses the fake Caddy admin API.
+func (f *fakeCaddy) service() *Service {
+	return &Service{Addr: f.URL, HTTP: f.Client()}
+}
+
+// update returns the servers sent to the fake Caddy admin API.
+func (f *fakeCaddy) update(t *testing.T) caddy.UpdateRequest {
+	t.Helper()
+
+	var update caddy.UpdateRequest
+	f.decode(t, "/config/apps/http/servers", &update)
+
+	return update
+}
+
+// body returns the last request sent to the path of the fake Caddy admin API.
+func (f *fakeCaddy) body(t *testing.T, path string) []byte {
+	t.Helper()
+
+	f.mu.Lock()
+	body, ok := f.requests[path]
+	f.mu.Unlock()
+
+	if !ok {
+		t.Fatalf("expected a request to %s", path)
+	}
+
+	return body
+}
+
+// decode decodes the last request sent to the path of the fake Caddy admin API into v.
+func (f *fakeCaddy) decode(t *testing.T, path string, v interface{}) {
+	t.Helper()
+
+	if err := json.Unmarshal(f.body(t, path), v); err != nil {
+		t.Fatalf("unable to decode the request to %s, %v", path, err)
+	}
+}
+
+func TestService_ApplyConfig(t *testing.T) {
+	sites := map[string]*protob.Site{
+		"craftdev.nitro": {Hostname: "craftdev.nitro", Aliases: "alias.nitro", Port: 8080},
+		"laravel.nitro":  {Hostname: "laravel.nitro", Port: 3000, HttpsRedirect: true},
+	}
+
+	fake := newFakeCaddy(t, http.StatusOK)
+
+	resp, err := fake.service().Apply(context.TODO(), &protob.ApplyRequest{Sites: sites})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.GetError() || resp.GetMessage() != "Successfully applied changes, sites: 2" {
+		t.Errorf("expected the changes to be applied, got %q", resp.GetMessage())
+	}
+
+	proxy := func(dial string, hosts ...string) caddy.ServerRoute {
+		return caddy.ServerRoute{
+			Handle:   []caddy.RouteHandle{{Handler: "reverse_proxy", Upstreams: []caddy.Upstream{{Dial: dial}}}},
+			Match:    []caddy.Match{{Host: hosts}},
+			Terminal: true,
+		}
+	}
+
+	nodeServer := func(listen string, routes ...caddy.ServerRoute) caddy.Server {
+		return caddy.Server{
+			Listen:         []string{listen},
+			Routes:         routes,
+			AutomaticHTTPS: caddy.AutomaticHTTPS{Disable: true, DisableRedirects: true},
+		}
+	}
+
+	want := caddy.UpdateRequest{
+		HTTPS: caddy.Server{
+			Listen: []string{":443"},
+			Routes: []caddy.ServerRoute{
+				proxy("craftdev.nitro:8080", "craftdev.nitro", "alias.nitro"),
+				proxy("laravel.nitro:3000", "laravel.nitro"),
+			},
+		},
+		HTTP: caddy.Server{
+			Listen: []string{":80"},
+			Routes: []caddy.ServerRoute{
+				proxy("craftdev.nitro:8080", "craftdev.nitro", "alias.nitro"),
+				{
+					Handle: []caddy.RouteHandle{
+						{
+							Handler:    "static_response",
+							StatusCode: http.StatusPermanentRedirect,
+							Headers:    map[string][]string{"Location": {"https://{http.request.host}{http.request.uri}"}},
+						},
+					},
+					Match:    []caddy.Match{{Host: []string{"laravel.nitro"}}},
+					Terminal: true,
+				},
+				{
+					Handle:   []caddy.RouteHandle{notFoundHandle("", sites)},
+					Terminal: true,
+				},
+			},
+			AutomaticHTTPS: caddy.AutomaticHTTPS{DisableRedirects: true},
+		},
+		Node: nodeServer(":3000",
+			proxy("craftdev.nitro:3000", "craftdev.nitro", "alias.nitro"),
+			proxy("laravel.nitro:3000", "laravel.nitro"),
+		),
+		NodeAlt: nodeServer(":3001",
+			proxy("craftdev.nitro:3001", "craftdev.nitro", "alias.nitro"),
+			proxy("laravel.nitro:3001", "laravel.nitro"),
+		),
+	}
+
+	got := fake.update(t)
+	for _, s := range []struct {
+		name      string
+		got, want caddy.Server
+	}{
+		{name: "https", got: got.HTTPS, want: want.HTTPS},
+		{name: "http", got: got.HTTP, want: want.HTTP},
+		{name: "node", got: got.Node, want: want.Node},
+		{name: "node_alt", got: got.NodeAlt, want: want.NodeAlt},
+	} {
+		if !reflect.DeepEqual(s.got, s.want) {
+			gotJSON, _ := json.MarshalIndent(s.got, "", "  ")
+			wantJSON, _ := json.MarshalIndent(s.want, "", "  ")
+
+			t.Errorf("expected the %s server to be\n%s\ngot\n%s", s.name, wantJSON, gotJSON)
+		}
+	}
+}
+
+func TestService_ApplyCaddyErrors(t *testing.T) {
+	sites := map[string]*protob.Site{
+		"craftdev.nitro": {Hostname: "craftdev.nitro", Port: 8080},
+	}
+
+	tests := []struct {
+		name        string
+		status      int
+		closed      bool
+		wantMessage string
+	}{
+		{
+			name:        "non-200 responses return an error response",
+			status:      http.StatusBadRequest,
+			wantMessage: "Received 400 response from Caddy API",
+		},
+		{
+			name:        "network errors return an error response",
+			closed:      true,
+			wantMessage: "Error updating Caddy API",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeCaddy(t, tt.status)
+			svc := fake.service()
+
+			if tt.closed {
+				fake.Close()
+			}
+
+			// the errors are in the response so grpc does not drop it
+			resp, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: sites})
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+
+			if !resp.GetError() {
+				t.Error("expected the response to be an error")
+			}
+
+			if !strings.HasPrefix(resp.GetMessage(), tt.wantMessage) {
+				t.Errorf("expected the message to start with %q, got %q", tt.wantMessage, resp.GetMessage())
+			}
+
+			// the sites are reported as failed
+			for _, s := range resp.GetSites() {
+				if s.GetStatus() != SiteError {
+					t.Errorf("expected %s to have the error status, got %q", s.GetHostname(), s.GetStatus())
+				}
+			}
+
+			// the hosts are only kept after a successful apply
+			if len(svc.hosts) != 0 {
+				t.Errorf("expected no hosts to be kept, got %v", svc.hosts)
+			}
+		})
+	}
+}
+
+func TestService_ApplyPathRoutes(t *testing.T) {
+	fake := newFakeCaddy(t, http.StatusOK)
+	svc := fake.service()
+
+	sites := map[string]*protob.Site{
+		"craftdev.nitro": {
+			Hostname:      "craftdev.nitro",
+			Port:          8080,
+			HttpsRedirect: true,
+			Paths:         []*protob.SitePath{{Prefix: "/api/", Upstream: "api.containers.nitro:3000"}},
+		},
+		"another.nitro": {Hostname: "another.nitro", Port: 8080},
+	}
+
+	if _, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: sites}); err != nil {
+		t.Fatal(err)
+	}
+
+	update := fake.update(t)
+
+	if len(update.HTTPS.Routes) != 3 {
+		t.Fatalf("expected 3 https routes, got %d", len(update.HTTPS.Routes))
+	}
+
+	// the path route must come before the catch-all site routes
+	first := update.HTTPS.Routes[0]
+	if !reflect.DeepEqual(first.Match[0].Path, []string{"/api", "/api/*"}) {
+		t.Errorf("expected the first route to match the path prefix, got %v", first.Match[0].Path)
+	}
+
+	if first.Handle[0].Upstreams[0].Dial != "api.containers.nitro:3000" {
+		t.Errorf("expected the path route to use the upstream, got %q", first.Handle[0].Upstreams[0].Dial)
+	}
+
+	for _, route := range update.HTTPS.Routes[1:] {
+		if route.Match[0].Path != nil {
+			t.Errorf("expected the site routes to not match a path, got %v", route.Match[0].Path)
+		}
+	}
+
+	// the redirected site does not need the path route on http
+	for _, route := range update.HTTP.Routes {
+		if len(route.Match) > 0 && route.Match[0].Path != nil {
+			t.Errorf("expected no path routes on the http server, got %v", route.Match[0].Path)
+		}
+	}
+}
+
+func TestService_Ask(t *testing.T) {
+	fake := newFakeCaddy(t, http.StatusOK)
+	svc := fake.service()
+
+	sites := map[string]*protob.Site{
+		"craftdev.nitro": {Hostname: "craftdev.nitro", Aliases: "alias.nitro", Port: 8080},
+	}
+
+	if _, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: sites, OnDemandTLS: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	var automation caddy.TLSAutomation
+	fake.decode(t, "/config/apps/tls/automation", &automation)
+
+	if automation.OnDemand == nil || automation.OnDemand.Ask != "http://127.0.0.1:5001/ask" {
+		t.Errorf("expected the ask endpoint to be configured, got %+v", automation.OnDemand)
+	}
+
+	tests := []struct {
+		domain string
+		want   int
+	}{
+		{domain: "craftdev.nitro", want: http.StatusOK},
+		{domain: "alias.nitro", want: http.StatusOK},
+		{domain: "de.craftdev.nitro", want: http.StatusOK},
+		{domain: "unknown.nitro", want: http.StatusForbidden},
+		{domain: "notcraftdev.nitro", want: http.StatusForbidden},
+		{domain: "", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			svc.Ask(w, httptest.NewRequest(http.MethodGet, "/ask?domain="+tt.domain, nil))
+
+			if w.Code != tt.want {
+				t.Errorf("expected the status code for %q to be %d, got %d", tt.domain, tt.want, w.Code)
+			}
+		})
+	}
+}
+
+func TestService_ApplyTimeout(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// simulate a hung caddy api
+		<-done
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	svc := &Service{Addr: srv.URL, HTTP: srv.Client(), Timeout: 50 * time.Millisecond}
+
+	sites := map[string]*protob.Site{
+		"craftdev.nitro": {Hostname: "craftdev.nitro", Port: 8080},
+	}
+
+	resp, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: sites})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !resp.GetError() || !strings.Contains(resp.GetMessage(), "deadline") {
+		t.Errorf("expected the response to be a deadline error, got %q", resp.GetMessage())
+	}
+}
+
+func TestService_ApplyUsesTheRequestDeadline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// respond slower than the default timeout
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	svc := &Service{Addr: srv.URL, HTTP: srv.Client(), Timeout: 10 * time.Millisecond}
+
+	sites := map[string]*protob.Site{
+		"craftdev.nitro": {Hostname: "craftdev.nitro", Port: 8080},
+	}
+
+	// the deadline of the request, e.g. from apply --timeout, replaces the default
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := svc.Apply(ctx, &protob.ApplyRequest{Sites: sites}); err != nil {
+		t.Fatalf("expected the request deadline to be used, got %v", err)
+	}
+}
+
+func TestService_ApplyDuplicateHosts(t *testing.T) {
+	tests := []struct {
+		name    string
+		sites   map[string]*protob.Site
+		wantErr bool
+	}{
+		{
+			name: "unique hosts do not return an error",
+			sites: map[string]*protob.Site{
+				"craftdev.nitro": {Hostname: "craftdev.nitro", Aliases: "alias.nitro", Port: 8080},
+				"another.nitro":  {Hostname: "another.nitro", Port: 8080},
+			},
+		},
+		{
+			name: "duplicate hostnames return an error",
+			sites: map[string]*protob.Site{
+				"craftdev.nitro":       {Hostname: "craftdev.nitro", Port: 8080},
+				"craftdev-clone.nitro": {Hostname: "craftdev-clone.nitro", Aliases: "craftdev.nitro", Port: 8080},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate aliases return an error",
+			sites: map[string]*protob.Site{
+				"craftdev.nitro": {Hostname: "craftdev.nitro", Aliases: "shared.nitro", Port: 8080},
+				"another.nitro":  {Hostname: "another.nitro", Aliases: "Shared.nitro", Port: 8080},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newFakeCaddy(t, http.StatusOK).service()
+
+			resp, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: tt.sites})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if resp.GetError() != tt.wantErr {
+				t.Errorf("expected the response error to be %v, got %v", tt.wantErr, resp.GetError())
+			}
+		})
+	}
+}
+
+func TestService_ApplySiteStatus(t *testing.T) {
+	svc := newFakeCaddy(t, http.StatusOK).service()
+
+	statuses := func(resp *protob.ApplyResponse) map[string]string {
+		got := make(map[string]string)
+		for _, s := range resp.GetSites() {
+			got[s.GetHostname()] = s.GetStatus()
+		}
+
+		return got
+	}
+
+	// the first apply creates the routes, and reports the invalid site
+	resp, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: map[string]*protob.Site{
+		"craftdev.nitro": {Hostname: "craftdev.nitro", Port: 8080},
+		"another.nitro":  {Hostname: "another.nitro", Port: 8080},
+		"broken.nitro":   {Hostname: "broken.nitro"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"craftdev.nitro": SiteCreated, "another.nitro": SiteCreated, "broken.nitro": SiteError}
+	if got := statuses(resp); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the statuses to match\ngot:\n%v\nwant:\n%v", got, want)
+	}
+
+	if resp.GetError() {
+		t.Errorf("expected the response to not be an error when only one site fails, got %q", resp.GetMessage())
+	}
+
+	// the second apply only updates the changed site
+	resp, err = svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: map[string]*protob.Site{
+		"craftdev.nitro": {Hostname: "craftdev.nitro", Port: 8080},
+		"another.nitro":  {Hostname: "another.nitro", Aliases: "alias.nitro", Port: 8080},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want = map[string]string{"craftdev.nitro": SiteUnchanged, "another.nitro": SiteUpdated}
+	if got := statuses(resp); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the statuses to match\ngot:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestService_ApplyAccessLogs(t *testing.T) {
+	fake := newFakeCaddy(t, http.StatusOK)
+	svc := fake.service()
+
+	sites := map[string]*protob.Site{
+		"craftdev.nitro": {Hostname: "craftdev.nitro", Aliases: "alias.nitro", Port: 8080},
+	}
+
+	if _, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: sites, AccessLogs: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	update := fake.update(t)
+
+	var logging caddy.Logging
+	fake.decode(t, "/config/logging", &logging)
+
+	wantNames := map[string]string{"craftdev.nitro": "craftdev_nitro", "alias.nitro": "craftdev_nitro"}
+	for _, server := range []caddy.Server{update.HTTP, update.HTTPS} {
+		if server.Logs == nil {
+			t.Fatal("expected the server logs to be set")
+		}
+
+		if !reflect.DeepEqual(server.Logs.LoggerNames, wantNames) {
+			t.Errorf("expected the logger names to be %v, got %v", wantNames, server.Logs.LoggerNames)
+		}
+	}
+
+	log, ok := logging.Logs["craftdev_nitro"]
+	if !ok {
+		t.Fatal("expected a logger for the site")
+	}
+
+	if log.Writer.Filename != "/data/logs/craftdev.nitro.log" {
+		t.Errorf("expected the log filename to be %q, got %q", "/data/logs/craftdev.nitro.log", log.Writer.Filename)
+	}
+
+	if !reflect.DeepEqual(log.Include, []string{"http.log.access.craftdev_nitro"}) {
+		t.Errorf("unexpected log include %v", log.Include)
+	}
+}
+
+func TestService_ApplyHTTP3(t *testing.T) {
+	tests := []struct {
+		name  string
+		http3 bool
+		want  []string
+	}{
+		{name: "http3 is disabled by default", http3: false},
+		{name: "http3 adds h3 to the protocols", http3: true, want: []string{"h1", "h2", "h3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeCaddy(t, http.StatusOK)
+			svc := fake.service()
+
+			sites := map[string]*protob.Site{
+				"craftdev.nitro": {Hostname: "craftdev.nitro", Port: 8080},
+			}
+
+			if _, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: sites, Http3: tt.http3}); err != nil {
+				t.Fatal(err)
+			}
+
+			update := fake.update(t)
+
+			if !reflect.DeepEqual(update.HTTPS.Protocols, tt.want) {
+				t.Errorf("expected the https protocols to be %v, got %v", tt.want, update.HTTPS.Protocols)
+			}
+
+			if update.HTTP.Protocols != nil {
+				t.Error("expected the http server to not enable http3")
+			}
+		})
+	}
+}
+
+func TestService_ApplyMaintenance(t *testing.T) {
+	tests := []struct {
+		name     string
+		site     *protob.Site
+		wantBody string
+	}{
+		{
+			name:     "sites in maintenance mode use the default page",
+			site:     &protob.Site{Hostname: "craftdev.nitro", Port: 8080, Maintenance: true, Paths: []*protob.SitePath{{Prefix: "/api", Upstream: "api.containers.nitro:3000"}}},
+			wantBody: DefaultMaintenancePage,
+		},
+		{
+			name:     "sites in maintenance mode can use a custom page",
+			site:     &protob.Site{Hostname: "craftdev.nitro", Port: 8080, Maintenance: true, MaintenanceBody: "<h1>Back soon</h1>"},
+			wantBody: "<h1>Back soon</h1>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeCaddy(t, http.StatusOK)
+			svc := fake.service()
+
+			if _, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: map[string]*protob.Site{"craftdev.nitro": tt.site}}); err != nil {
+				t.Fatal(err)
+			}
+
+			update := fake.update(t)
+
+			// the path routes are not added while the site is in maintenance
+			if len(update.HTTPS.Routes) != 1 {
+				t.Fatalf("expected 1 https route, got %d", len(update.HTTPS.Routes))
+			}
+
+			handle := update.HTTPS.Routes[0].Handle[0]
+			if handle.Handler != "static_response" || handle.StatusCode != http.StatusServiceUnavailable {
+				t.Errorf("expected a 503 static response, got %s %d", handle.Handler, handle.StatusCode)
+			}
+
+			if handle.Body != tt.wantBody {
+				t.Errorf("expected the body to be %q, got %q", tt.wantBody, handle.Body)
+			}
+		})
+	}
+}
+
+func TestNewService_AdminCredentials(t *testing.T) {
+	var authorization string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "nitro-caddy-admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca := filepath.Join(dir, "ca.pem")
+	if err := ioutil.WriteFile(ca, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	env := map[string]string{
+		"NITRO_CADDY_ADMIN_URL":   srv.URL + "/",
+		"NITRO_CADDY_ADMIN_TOKEN": "secret",
+		"NITRO_CADDY_ADMIN_CA":    ca,
+	}
+	for k, v := range env {
+		os.Setenv(k, v)
+		defer os.Unsetenv(k)
+	}
+
+	svc := NewService("http://127.0.0.1:2019")
+
+	if svc.Addr != srv.URL {
+		t.Errorf("expected the addr to be %q, got %q", srv.URL, svc.Addr)
+	}
+
+	sites := map[string]*protob.Site{
+		"craftdev.nitro": {Hostname: "craftdev.nitro", Port: 8080},
+	}
+
+	if _, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: sites}); err != nil {
+		t.Fatal(err)
+	}
+
+	if authorization != "Bearer secret" {
+		t.Errorf("expected the authorization header to be %q, got %q", "Bearer secret", authorization)
+	}
+}
+
+func TestNewService_DefaultsToPlaintext(t *testing.T) {
+	for _, k := range []string{"NITRO_CADDY_ADMIN_URL", "NITRO_CADDY_ADMIN_TOKEN", "NITRO_CADDY_ADMIN_CA"} {
+		os.Unsetenv(k)
+	}
+
+	svc := NewService("http://127.0.0.1:2019")
+
+	if svc.Addr != "http://127.0.0.1:2019" {
+		t.Errorf("expected the default addr, got %q", svc.Addr)
+	}
+
+	if svc.Token != "" {
+		t.Errorf("expected no token, got %q", svc.Token)
+	}
+
+	if svc.HTTP != http.DefaultClient {
+		t.Error("expected the default http client")
+	}
+}
+
+func TestService_ApplyNotFound(t *testing.T) {
+	tests := []struct {
+		name         string
+		notFoundBody string
+		wantContains []string
+	}{
+		{
+			name:         "the default page lists the sites",
+			wantContains: []string{`<a href="http://craftdev.nitro">craftdev.nitro</a>`, `<a href="http://xn--bcher-kva.nitro">bücher.nitro</a>`},
+		},
+		{
+			name:         "a custom page is used when provided",
+			notFoundBody: "<h1>Nothing here</h1>",
+			wantContains: []string{"<h1>Nothing here</h1>"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeCaddy(t, http.StatusOK)
+			svc := fake.service()
+
+			sites := map[string]*protob.Site{
+				"craftdev.nitro": {Hostname: "craftdev.nitro", Port: 8080},
+				"bücher.nitro":   {Hostname: "xn--bcher-kva.nitro", Port: 8080},
+			}
+
+			if _, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: sites, NotFoundBody: tt.notFoundBody}); err != nil {
+				t.Fatal(err)
+			}
+
+			update := fake.update(t)
+
+			route := firstRoute(update.HTTP.Routes, "unknown.nitro", "/")
+			if route == nil {
+				t.Fatal("expected a route to match unknown hosts")
+			}
+
+			handle := route.Handle[0]
+			if handle.Handler != "static_response" || handle.StatusCode != http.StatusNotFound {
+				t.Fatalf("expected a static 404 response, got %q with %d", handle.Handler, handle.StatusCode)
+			}
+
+			for _, want := range tt.wantContains {
+				if !strings.Contains(handle.Body, want) {
+					t.Errorf("expected the body to contain %q, got %q", want, handle.Body)
+				}
+			}
+		})
+	}
+}
+
+// fakeImportStream sends the requests to ImportDatabase and returns io.EOF once all of
+// the requests are received.
+type fakeImportStream struct {
+	grpc.ServerStream
+	requests []*protob.ImportDatabaseRequest
+}
+
+func (s *fakeImportStream) Recv() (*protob.ImportDatabaseRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+
+	return req, nil
+}
+
+func (s *fakeImportStream) SendAndClose(*protob.ImportDatabaseResponse) error {
+	return nil
+}
+
+func TestService_ImportDatabaseVerifiesTheSize(t *testing.T) {
+	tests := []struct {
+		name         string
+		size         int64
+		data         []byte
+		wantDataLoss bool
+	}{
+		{
+			name:         "incomplete uploads return a data loss error",
+			size:         10,
+			data:         []byte("SELECT"),
+			wantDataLoss: true,
+		},
+		{
+			name: "complete uploads are not rejected",
+			size: 6,
+			data: []byte("SELECT"),
+		},
+		{
+			name: "uploads without a size are not verified",
+			data: []byte("SELECT"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "nitro-import")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			svc := &Service{TempDir: dir}
+
+			stream := &fakeImportStream{requests: []*protob.ImportDatabaseRequest{
+				{Payload: &protob.ImportDatabaseRequest_Database{Database: &protob.DatabaseInfo{Engine: "mysql", Hostname: "127.0.0.1", Port: "1", Database: "project", Size: tt.size}}},
+				{Payload: &protob.ImportDatabaseRequest_Data{Data: tt.data}},
+			}}
+
+			err = svc.ImportDatabase(stream)
+			if got := status.Code(err) == codes.DataLoss; got != tt.wantDataLoss {
+				t.Errorf("expected data loss to be %v, got error %v", tt.wantDataLoss, err)
+			}
+
+			// the upload is always removed
+			files, err := ioutil.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(files) != 0 {
+				t.Errorf("expected the upload to be removed, found %d files", len(files))
+			}
+		})
+	}
+}
+
+// fakeExportStream keeps the responses sent by ExportDatabase.
+type fakeExportStream struct {
+	grpc.ServerStream
+	responses []*protob.ExportDatabaseResponse
+}
+
+func (s *fakeExportStream) Send(resp *protob.ExportDatabaseResponse) error {
+	s.responses = append(s.responses, resp)
+
+	return nil
+}
+
+// fakeExporter writes the backup to stdout in place of the export tool.
+type fakeExporter struct {
+	backup string
+	opts   *database.ExportOptions
+}
+
+func (e *fakeExporter) Export(opts *database.ExportOptions, find func(engine, version string) (string, error)) error {
+	e.opts = opts
+
+	_, err := io.WriteString(opts.Stdout, e.backup)
+
+	return err
+}
+
+func TestService_ExportDatabase(t *testing.T) {
+	// the database must be reachable
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+
+	port := strconv.Itoa(lis.Addr().(*net.TCPAddr).Port)
+
+	tests := []struct {
+		name     string
+		request  *protob.ExportDatabaseRequest
+		wantInfo *protob.DatabaseInfo
+		wantCode codes.Code
+	}{
+		{
+			name:     "plain backups are streamed after the database info",
+			request:  &protob.ExportDatabaseRequest{Database: &protob.DatabaseInfo{Engine: "mysql", Version: "8.0", Hostname: "127.0.0.1", Port: port, Database: "project"}},
+			wantInfo: &protob.DatabaseInfo{Engine: "mysql", Version: "8.0", Hostname: "127.0.0.1", Port: port, Database: "project"},
+		},
+		{
+			name:     "compressed backups set the compression type",
+			request:  &protob.ExportDatabaseRequest{Database: &protob.DatabaseInfo{Engine: "postgres", Version: "13", Hostname: "127.0.0.1", Port: port, Database: "project", Compressed: true}},
+			wantInfo: &protob.DatabaseInfo{Engine: "postgres", Version: "13", Hostname: "127.0.0.1", Port: port, Database: "project", Compressed: true, CompressionType: "gz"},
+		},
+		{
+			name:     "directory archives cannot be streamed",
+			request:  &protob.ExportDatabaseRequest{Database: &protob.DatabaseInfo{Engine: "postgres", Version: "13", Hostname: "127.0.0.1", Port: port, Database: "project"}, Format: "directory"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "the database must be reachable",
+			request:  &protob.ExportDatabaseRequest{Database: &protob.DatabaseInfo{Engine: "mysql", Version: "8.0", Hostname: "127.0.0.1", Port: "1", Database: "project"}},
+			wantCode: codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := &fakeExporter{backup: strings.Repeat("SELECT 1;\n", ExportChunkSize/5)}
+			svc := &Service{Exporter: exporter}
+			stream := &fakeExportStream{}
+
+			err := svc.ExportDatabase(tt.request, stream)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("ExportDatabase() error = %v, want code %v", err, tt.wantCode)
+			}
+
+			if tt.wantCode != codes.OK {
+				if len(stream.responses) != 0 {
+					t.Errorf("expected no responses on error, got %d", len(stream.responses))
+				}
+
+				return
+			}
+
+			if len(stream.responses) < 2 {
+				t.Fatalf("expected the database info and the data, got %d responses", len(stream.responses))
+			}
+
+			if got := stream.responses[0].GetDatabase(); !reflect.DeepEqual(got, tt.wantInfo) {
+				t.Errorf("expected the first response to be %v, got %v", tt.wantInfo, got)
+			}
+
+			var data []byte
+			for _, resp := range stream.responses[1:] {
+				if len(resp.GetData()) > ExportChunkSize {
+					t.Errorf("expected the data to be sent in chunks of %d bytes, got %d", ExportChunkSize, len(resp.GetData()))
+				}
+
+				data = append(data, resp.GetData()...)
+			}
+
+			if string(data) != exporter.backup {
+				t.Errorf("expected the backup to be streamed, got %d of %d bytes", len(data), len(exporter.backup))
+			}
+
+			if exporter.opts.Compressed != tt.request.GetDatabase().GetCompressed() {
+				t.Errorf("expected compressed to be %v", tt.request.GetDatabase().GetCompressed())
+			}
+		})
+	}
+}
+
+func TestService_ApplyErrorPages(t *testing.T) {
+	fake := newFakeCaddy(t, http.StatusOK)
+	svc := fake.service()
+
+	sites := map[string]*protob.Site{
+		"craftdev.nitro": {Hostname: "craftdev.nitro", Port: 8080, ErrorPages: []*protob.SiteErrorPage{
+			{Status: 404, Uri: "/errors/404"},
+			{Status: 502, Body: "<h1>The site is down</h1>"},
+		}},
+		"plain.nitro": {Hostname: "plain.nitro", Port: 8080},
+	}
+
+	if _, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: sites}); err != nil {
+		t.Fatal(err)
+	}
+
+	update := fake.update(t)
+
+	if update.HTTPS.Errors == nil || len(update.HTTPS.Errors.Routes) != 2 {
+		t.Fatalf("expected two error routes, got %v", update.HTTPS.Errors)
+	}
+
+	proxy := update.HTTPS.Errors.Routes[0]
+	if proxy.Match[0].Expression != "{http.error.status_code} == 404" {
+		t.Errorf("unexpected expression %q", proxy.Match[0].Expression)
+	}
+
+	if proxy.Handle[0].Handler != "rewrite" || proxy.Handle[0].URI != "/errors/404" {
+		t.Errorf("expected the request to be rewritten to /errors/404, got %q %q", proxy.Handle[0].Handler, proxy.Handle[0].URI)
+	}
+
+	if proxy.Handle[1].Upstreams[0].Dial != "craftdev.nitro:8080" {
+		t.Errorf("expected the error to be sent to the site, got %q", proxy.Handle[1].Upstreams[0].Dial)
+	}
+
+	static := update.HTTPS.Errors.Routes[1]
+	if static.Handle[0].Handler != "static_response" || static.Handle[0].StatusCode != 502 || static.Handle[0].Body != "<h1>The site is down</h1>" {
+		t.Errorf("expected a static 502 response, got %v", static.Handle[0])
+	}
+
+	if !reflect.DeepEqual(update.HTTP.Errors, update.HTTPS.Errors) {
+		t.Error("expected the http server to use the same error routes")
+	}
+
+	// the errors from the site are handled by the reverse proxy
+	var responses []caddy.ResponseHandler
+	for _, r := range update.HTTPS.Routes {
+		if r.Match[0].Host[0] == "craftdev.nitro" {
+			responses = r.Handle[len(r.Handle)-1].HandleResponse
+		}
+	}
+
+	if len(responses) != 2 {
+		t.Fatalf("expected two response handlers for the site, got %v", responses)
+	}
+
+	if !reflect.DeepEqual(responses[0].Match.StatusCode, []int{404}) {
+		t.Errorf("expected the first response handler to match 404, got %v", responses[0].Match.StatusCode)
+	}
+
+	rewrite := responses[0].Routes[0].Handle[0]
+	if rewrite.Handler != "rewrite" || rewrite.URI != "/errors/404" || rewrite.Method != http.MethodGet {
+		t.Errorf("expected the response to be rewritten to GET /errors/404, got %v", rewrite)
+	}
+
+	if !reflect.DeepEqual(responses[1].Routes[0].Handle, static.Handle) {
+		t.Errorf("expected the 502 response to use the static response, got %v", responses[1].Routes[0].Handle)
+	}
+}
+
+func TestService_ApplyWithoutErrorPages(t *testing.T) {
+	fake := newFakeCaddy(t, http.StatusOK)
+	svc := fake.service()
+
+	sites := map[string]*protob.Site{
+		"craftdev.nitro": {Hostname: "craftdev.nitro", Port: 8080},
+	}
+
+	if _, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: sites}); err != nil {
+		t.Fatal(err)
+	}
+
+	update := fake.update(t)
+
+	if update.HTTPS.Errors != nil || update.HTTP.Errors != nil {
+		t.Error("expected no error handling by default")
+	}
+
+	if update.HTTPS.Routes[0].Handle[0].HandleResponse != nil {
+		t.Error("expected no response handlers by default")
+	}
+}
+
+func TestService_ApplyClientCA(t *testing.T) {
+	fake := newFakeCaddyTLS(t, http.StatusOK)
+	svc := fake.service()
+
+	// the test server certificate is self signed, so use it as the CA
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: fake.Certificate().Raw})
+
+	sites := map[string]*protob.Site{
+		"mtls.nitro":   {Hostname: "mtls.nitro", Aliases: "api.mtls.nitro", Port: 8080, ClientCa: string(ca)},
+		"plain.nitro":  {Hostname: "plain.nitro", Port: 8080},
+		"broken.nitro": {Hostname: "broken.nitro", Port: 8080, ClientCa: "not a certificate"},
+	}
+
+	resp, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: sites})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update := fake.update(t)
+
+	for _, s := range resp.GetSites() {
+		if s.GetHostname() == "broken.nitro" && s.GetStatus() != SiteError {
+			t.Errorf("expected the site with an invalid CA to fail, got %q", s.GetStatus())
+		}
+	}
+
+	policies := update.HTTPS.TLSConnectionPolicies
+	if len(policies) != 2 {
+		t.Fatalf("expected a policy for the site and the default policy, got %d", len(policies))
+	}
+
+	want := caddy.TLSConnectionPolicy{
+		Match: &caddy.TLSConnectionMatch{SNI: []string{"mtls.nitro", "api.mtls.nitro"}},
+		ClientAuthentication: &caddy.TLSClientAuthentication{
+			TrustedCACerts: []string{base64.StdEncoding.EncodeToString(fake.Certificate().Raw)},
+			Mode:           "require_and_verify",
+		},
+	}
+	if !reflect.DeepEqual(policies[0], want) {
+		t.Errorf("expected the policy %v, got %v", want, policies[0])
+	}
+
+	if !reflect.DeepEqual(policies[1], caddy.TLSConnectionPolicy{}) {
+		t.Errorf("expected the last policy to be the default, got %v", policies[1])
+	}
+
+	if update.HTTP.TLSConnectionPolicies != nil {
+		t.Error("expected the http server to not have connection policies")
+	}
+}
+
+func TestService_ApplyWithoutClientCA(t *testing.T) {
+	fake := newFakeCaddy(t, http.StatusOK)
+	svc := fake.service()
+
+	sites := map[string]*protob.Site{
+		"craftdev.nitro": {Hostname: "craftdev.nitro", Port: 8080},
+	}
+
+	if _, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: sites}); err != nil {
+		t.Fatal(err)
+	}
+
+	update := fake.update(t)
+
+	if update.HTTPS.TLSConnectionPolicies != nil {
+		t.Error("expected client certificates to not be required by default")
+	}
+}
+
+func TestService_ApplyHeaders(t *testing.T) {
+	fake := newFakeCaddy(t, http.StatusOK)
+	svc := fake.service()
+
+	sites := map[string]*protob.Site{
+		"cors.nitro": {
+			Hostname:      "cors.nitro",
+			Port:          8080,
+			Headers:       map[string]string{"Access-Control-Allow-Origin": "*"},
+			RemoveHeaders: []string{"X-Powered-By"},
+			Paths:         []*protob.SitePath{{Prefix: "/api", Upstream: "api.containers.nitro:3000"}},
+		},
+		"plain.nitro":  {Hostname: "plain.nitro", Port: 8080},
+		"broken.nitro": {Hostname: "broken.nitro", Port: 8080, Headers: map[string]string{"Bad Header": "value"}},
+	}
+
+	resp, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: sites})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update := fake.update(t)
+
+	for _, s := range resp.GetSites() {
+		if s.GetHostname() == "broken.nitro" && s.GetStatus() != SiteError {
+			t.Errorf("expected the site with an invalid header to fail, got %q", s.GetStatus())
+		}
+	}
+
+	want := caddy.RouteHandle{
+		Handler: "headers",
+		Response: &caddy.HeaderOps{
+			Set:      map[string][]string{"Access-Control-Allow-Origin": {"*"}},
+			Delete:   []string{"X-Powered-By"},
+			Deferred: true,
+		},
+	}
+
+	var found int
+	for _, r := range update.HTTPS.Routes {
+		if len(r.Match) == 0 {
+			continue
+		}
+
+		switch r.Match[0].Host[0] {
+		case "cors.nitro":
+			found++
+
+			if !reflect.DeepEqual(r.Handle[0], want) {
+				t.Errorf("expected the headers handler first, got %v", r.Handle[0])
+			}
+
+			if r.Handle[1].Handler != "reverse_proxy" {
+				t.Errorf("expected the request to be proxied after the headers, got %q", r.Handle[1].Handler)
+			}
+		case "plain.nitro":
+			if r.Handle[0].Handler != "reverse_proxy" {
+				t.Errorf("expected sites without headers to not have a headers handler, got %q", r.Handle[0].Handler)
+			}
+		}
+	}
+
+	if found != 2 {
+		t.Errorf("expected the site and path routes to set the headers, got %d routes", found)
+	}
+}
+
+func TestService_ApplyEarlyHints(t *testing.T) {
+	fake := newFakeCaddy(t, http.StatusOK)
+	svc := fake.service()
+
+	sites := map[string]*protob.Site{
+		"hints.nitro":       {Hostname: "hints.nitro", Port: 8080, EarlyHints: []string{"</css/site.css>; rel=preload; as=style"}},
+		"maintenance.nitro": {Hostname: "maintenance.nitro", Port: 8080, Maintenance: true, EarlyHints: []string{"</css/site.css>; rel=preload; as=style"}},
+		"broken.nitro":      {Hostname: "broken.nitro", Port: 8080, EarlyHints: []string{"/css/site.css"}},
+	}
+
+	resp, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: sites})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update := fake.update(t)
+
+	for _, s := range resp.GetSites() {
+		if s.GetHostname() == "broken.nitro" && s.GetStatus() != SiteError {
+			t.Errorf("expected the site with an invalid early hint to fail, got %q", s.GetStatus())
+		}
+	}
+
+	want := caddy.RouteHandle{
+		Handler:    "static_response",
+		StatusCode: http.StatusEarlyHints,
+		Headers:    map[string][]string{"Link": {"</css/site.css>; rel=preload; as=style"}},
+	}
+
+	var found bool
+	for _, r := range update.HTTPS.Routes {
+		if len(r.Match) == 0 {
+			continue
+		}
+
+		switch r.Match[0].Host[0] {
+		case "hints.nitro":
+			found = true
+
+			if !reflect.DeepEqual(r.Handle[0], want) {
+				t.Errorf("expected the early hints handler first, got %v", r.Handle[0])
+			}
+
+			if r.Handle[1].Handler != "reverse_proxy" {
+				t.Errorf("expected the request to be proxied after the early hints, got %q", r.Handle[1].Handler)
+			}
+		case "maintenance.nitro":
+			if len(r.Handle) != 1 || r.Handle[0].StatusCode != http.StatusServiceUnavailable {
+				t.Errorf("expected sites in maintenance mode to not send early hints, got %v", r.Handle)
+			}
+		}
+	}
+
+	if !found {
+		t.Error("expected a route for the site with early hints")
+	}
+}
+
+func TestService_ApplyMaxRequestBody(t *testing.T) {
+	fake := newFakeCaddy(t, http.StatusOK)
+	svc := fake.service()
+
+	sites := map[string]*protob.Site{
+		"uploads.nitro": {Hostname: "uploads.nitro", Port: 8080, MaxRequestBody: 100 << 20, Paths: []*protob.SitePath{{Prefix: "/api", Upstream: "api:3000"}}},
+		"default.nitro": {Hostname: "default.nitro", Port: 8080},
+		"broken.nitro":  {Hostname: "broken.nitro", Port: 8080, MaxRequestBody: -1},
+	}
+
+	resp, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: sites})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update := fake.update(t)
+
+	for _, s := range resp.GetSites() {
+		if s.GetHostname() == "broken.nitro" && s.GetStatus() != SiteError {
+			t.Errorf("expected the site with an invalid max request body to fail, got %q", s.GetStatus())
+		}
+	}
+
+	want := caddy.RouteHandle{Handler: "request_body", MaxSize: 100 << 20}
+
+	var limited int
+	for _, r := range update.HTTPS.Routes {
+		if len(r.Match) == 0 {
+			continue
+		}
+
+		switch r.Match[0].Host[0] {
+		case "uploads.nitro":
+			limited++
+
+			if !reflect.DeepEqual(r.Handle[0], want) {
+				t.Errorf("expected the request body handler first, got %v", r.Handle[0])
+			}
+		case "default.nitro":
+			for _, h := range r.Handle {
+				if h.Handler == "request_body" {
+					t.Errorf("expected sites without a max request body to not be limited, got %v", r.Handle)
+				}
+			}
+		}
+	}
+
+	// the site and the path routes are limited
+	if limited != 2 {
+		t.Errorf("expected the site and path routes to limit the request body, got %d routes", limited)
+	}
+}
+
+func TestService_ApplyReturnConfig(t *testing.T) {
+	fake := newFakeCaddy(t, http.StatusOK)
+	svc := fake.service()
+
+	sites := map[string]*protob.Site{
+		"example.nitro": {Hostname: "example.nitro", Port: 8080},
+	}
+
+	resp, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: sites})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.GetConfig() != "" {
+		t.Errorf("expected the config to only be returned when requested")
+	}
+
+	resp, err = svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: sites, ReturnConfig: true, AccessLogs: true, OnDemandTLS: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(resp.GetConfig()), &config); err != nil {
+		t.Fatal(err)
+	}
+
+	paths := []string{"/config/apps/http/servers", "/config/logging", "/config/apps/tls/automation"}
+	if len(config) != len(paths) {
+		t.Errorf("expected the config for %d paths, got %d", len(paths), len(config))
+	}
+
+	for _, path := range paths {
+		if sent := fake.body(t, path); string(config[path]) != string(sent) {
+			t.Errorf("expected the returned config for %s to match the config sent to caddy\ngot:\n%s\nwant:\n%s", path, config[path], sent)
+		}
+	}
+}
+
+func TestService_ApplyReturnConfigWhenCaddyIsUnavailable(t *testing.T) {
+	fake := newFakeCaddy(t, http.StatusOK)
+	svc := fake.service()
+	fake.Close()
+
+	sites := map[string]*protob.Site{
+		"example.nitro": {Hostname: "example.nitro", Port: 8080},
+	}
+
+	resp, err := svc.Apply(context.TODO(), &protob.ApplyRequest{Sites: sites, ReturnConfig: true, AccessLogs: true})
+	if err != nil {
+		t.Fatalf("expected the error in the response, got %v", err)
+	}
+
+	if !resp.GetError() || !strings.HasPrefix(resp.GetMessage(), "Error updating Caddy logging") {
+		t.Errorf("expected the logging error in the response, got %q", resp.GetMessage())
+	}
+
+	if !strings.Contains(resp.GetConfig(), `"/config/apps/http/servers"`) {
+		t.Errorf("expected the config to be returned, got %q", resp.GetConfig())
+	}
+}
+
 func TestService_Ping(t *testing.T) {
 	type fields struct {
 		HTTP *http.Client
@@ -92,3 +1612,471 @@ func TestService_Version(t *testing.T) {
 		})
 	}
 }
+
+func TestService_ProxyAPI(t *testing.T) {
+	type args struct {
+		ctx     context.Context
+		request *protob.ProxyAPIRequest
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantMethod string
+		wantPath   string
+		wantBody   string
+		want       *protob.ProxyAPIResponse
+		wantErr    bool
+		wantCode   codes.Code
+	}{
+		{
+			name: "get requests are passed to the caddy api",
+			args: args{
+				ctx:     context.TODO(),
+				request: &protob.ProxyAPIRequest{Method: "get", Path: "config/"},
+			},
+			wantMethod: http.MethodGet,
+			wantPath:   "/config/",
+			want:       &protob.ProxyAPIResponse{StatusCode: http.StatusOK, Body: []byte(`{"ok":true}`)},
+		},
+		{
+			name: "request bodies are passed to the caddy api",
+			args: args{
+				ctx:     context.TODO(),
+				request: &protob.ProxyAPIRequest{Method: http.MethodPatch, Path: "/config/apps/http/servers/http/listen", Body: []byte(`[":80"]`), Write: true},
+			},
+			wantMethod: http.MethodPatch,
+			wantPath:   "/config/apps/http/servers/http/listen",
+			wantBody:   `[":80"]`,
+			want:       &protob.ProxyAPIResponse{StatusCode: http.StatusOK, Body: []byte(`{"ok":true}`)},
+		},
+		{
+			name: "requests that change the config are denied without write",
+			args: args{
+				ctx:     context.TODO(),
+				request: &protob.ProxyAPIRequest{Method: http.MethodDelete, Path: "/config/apps/http/servers/node"},
+			},
+			wantErr:  true,
+			wantCode: codes.PermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var method, path, body string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := ioutil.ReadAll(r.Body)
+				method, path, body = r.Method, r.URL.Path, string(b)
+
+				w.Write([]byte(`{"ok":true}`))
+			}))
+			defer srv.Close()
+
+			svc := &Service{Addr: srv.URL, HTTP: srv.Client()}
+
+			got, err := svc.ProxyAPI(tt.args.ctx, tt.args.request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Service.ProxyAPI() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if code := status.Code(err); code != tt.wantCode {
+					t.Errorf("expected the code to be %s, got %s", tt.wantCode, code)
+				}
+				if method != "" {
+					t.Errorf("expected the request to not be sent, got %s", method)
+				}
+				return
+			}
+			if method != tt.wantMethod {
+				t.Errorf("expected the method to be %q, got %q", tt.wantMethod, method)
+			}
+			if path != tt.wantPath {
+				t.Errorf("expected the path to be %q, got %q", tt.wantPath, path)
+			}
+			if body != tt.wantBody {
+				t.Errorf("expected the body to be %q, got %q", tt.wantBody, body)
+			}
+			if got.GetStatusCode() != tt.want.GetStatusCode() || string(got.GetBody()) != string(tt.want.GetBody()) {
+				t.Errorf("Service.ProxyAPI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeRunner returns the output and error for each command that contains the key.
+type fakeRunner struct {
+	outputs map[string]string
+	errors  map[string]error
+	ran     []string
+}
+
+func (r *fakeRunner) Run(tool string, commands []string) (string, error) {
+	cmd := strings.Join(commands, " ")
+	r.ran = append(r.ran, cmd)
+
+	for k, err := range r.errors {
+		if strings.Contains(cmd, k) {
+			return r.outputs[k], err
+		}
+	}
+
+	for k, out := range r.outputs {
+		if strings.Contains(cmd, k) {
+			return out, nil
+		}
+	}
+
+	return "", nil
+}
+
+// fakeDatabase puts fake database tools on the path and listens on a port so the
+// database is reachable, it returns the port.
+func fakeDatabase(t *testing.T) string {
+	t.Helper()
+
+	// the database tools are looked up on the path
+	dir, err := ioutil.TempDir("", "nitro-tools")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	for _, tool := range []string{"psql", "mysql"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, tool), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", dir)
+	t.Cleanup(func() { os.Setenv("PATH", path) })
+
+	// the database must be reachable
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lis.Close() })
+
+	return strconv.Itoa(lis.Addr().(*net.TCPAddr).Port)
+}
+
+func TestService_AddDatabase(t *testing.T) {
+	port := fakeDatabase(t)
+
+	tests := []struct {
+		name        string
+		engine      string
+		user        string
+		password    string
+		runner      *fakeRunner
+		wantMessage string
+		wantCreate  bool
+		wantRan     []string
+		wantErr     bool
+	}{
+		{
+			name:        "postgres databases are created",
+			engine:      "postgres",
+			runner:      &fakeRunner{},
+			wantMessage: `Database "project" added to "127.0.0.1" successfully`,
+			wantCreate:  true,
+		},
+		{
+			name:        "existing postgres databases are not created again",
+			engine:      "postgres",
+			runner:      &fakeRunner{outputs: map[string]string{"pg_database": "1\n"}},
+			wantMessage: `Database "project" already exists on "127.0.0.1"`,
+		},
+		{
+			name:   "postgres already exists errors are treated as existing",
+			engine: "postgres",
+			runner: &fakeRunner{
+				outputs: map[string]string{"CREATE DATABASE": `ERROR:  database "project" already exists`},
+				errors:  map[string]error{"CREATE DATABASE": errors.New("Exit Status: 1")},
+			},
+			wantMessage: `Database "project" already exists on "127.0.0.1"`,
+			wantCreate:  true,
+		},
+		{
+			name:   "other postgres errors are returned",
+			engine: "postgres",
+			runner: &fakeRunner{
+				outputs: map[string]string{"CREATE DATABASE": `ERROR:  permission denied to create database`},
+				errors:  map[string]error{"CREATE DATABASE": errors.New("Exit Status: 1")},
+			},
+			wantCreate: true,
+			wantErr:    true,
+		},
+		{
+			name:        "existing mysql databases are not created again",
+			engine:      "mysql",
+			runner:      &fakeRunner{outputs: map[string]string{"INFORMATION_SCHEMA.SCHEMATA": "project\n"}},
+			wantMessage: `Database "project" already exists on "127.0.0.1"`,
+		},
+		{
+			name:        "mysql databases are created and granted to nitro",
+			engine:      "mysql",
+			runner:      &fakeRunner{},
+			wantMessage: `Database "project" added to "127.0.0.1" successfully`,
+			wantCreate:  true,
+			wantRan:     []string{"-e GRANT ALL PRIVILEGES ON `project`.* TO 'nitro'@'%';"},
+		},
+		{
+			name:        "mysql users are created and granted privileges",
+			engine:      "mysql",
+			user:        "craft",
+			password:    `it's\secret`,
+			runner:      &fakeRunner{},
+			wantMessage: `Database "project" added to "127.0.0.1" for user "craft" successfully`,
+			wantCreate:  true,
+			wantRan: []string{
+				`-e CREATE USER 'craft'@'%' IDENTIFIED BY 'it''s\\secret';`,
+				"-e GRANT ALL PRIVILEGES ON `project`.* TO 'craft'@'%';",
+			},
+		},
+		{
+			name:        "postgres roles are created and granted privileges",
+			engine:      "postgres",
+			user:        "craft",
+			password:    "secret",
+			runner:      &fakeRunner{},
+			wantMessage: `Database "project" added to "127.0.0.1" for user "craft" successfully`,
+			wantCreate:  true,
+			wantRan: []string{
+				`-c CREATE ROLE "craft" WITH LOGIN PASSWORD 'secret';`,
+				`-c GRANT ALL PRIVILEGES ON DATABASE "project" TO "craft";`,
+			},
+		},
+		{
+			name:     "existing postgres roles are granted privileges",
+			engine:   "postgres",
+			user:     "craft",
+			password: "secret",
+			runner: &fakeRunner{
+				outputs: map[string]string{"CREATE ROLE": `ERROR:  role "craft" already exists`},
+				errors:  map[string]error{"CREATE ROLE": errors.New("Exit Status: 1")},
+			},
+			wantMessage: `Database "project" added to "127.0.0.1" for user "craft" successfully, the user "craft" already exists and its password was not changed`,
+			wantCreate:  true,
+			wantRan:     []string{`-c GRANT ALL PRIVILEGES ON DATABASE "project" TO "craft";`},
+		},
+		{
+			name:     "existing mysql users are granted privileges",
+			engine:   "mysql",
+			user:     "craft",
+			password: "secret",
+			runner: &fakeRunner{
+				outputs: map[string]string{"CREATE USER": `ERROR 1396 (HY000) at line 1: Operation CREATE USER failed for 'craft'@'%'`},
+				errors:  map[string]error{"CREATE USER": errors.New("Exit Status: 1")},
+			},
+			wantMessage: `Database "project" added to "127.0.0.1" for user "craft" successfully, the user "craft" already exists and its password was not changed`,
+			wantCreate:  true,
+			wantRan:     []string{"-e GRANT ALL PRIVILEGES ON `project`.* TO 'craft'@'%';"},
+		},
+		{
+			name:        "users are created and granted privileges on existing databases",
+			engine:      "mysql",
+			user:        "craft",
+			password:    "secret",
+			runner:      &fakeRunner{outputs: map[string]string{"INFORMATION_SCHEMA.SCHEMATA": "project\n"}},
+			wantMessage: `Database "project" already exists on "127.0.0.1", granted privileges to user "craft"`,
+			wantRan: []string{
+				`-e CREATE USER 'craft'@'%' IDENTIFIED BY 'secret';`,
+				"-e GRANT ALL PRIVILEGES ON `project`.* TO 'craft'@'%';",
+			},
+		},
+		{
+			name:     "other user errors are returned",
+			engine:   "mysql",
+			user:     "craft",
+			password: "secret",
+			runner: &fakeRunner{
+				outputs: map[string]string{"CREATE USER": `ERROR 1045 (28000): Access denied for user 'nitro'@'%'`},
+				errors:  map[string]error{"CREATE USER": errors.New("Exit Status: 1")},
+			},
+			wantCreate: true,
+			wantErr:    true,
+		},
+		{
+			name:     "invalid users return an error",
+			engine:   "mysql",
+			user:     "craft'@'%",
+			password: "secret",
+			runner:   &fakeRunner{},
+			wantErr:  true,
+		},
+		{
+			name:    "users require a password",
+			engine:  "postgres",
+			user:    "craft",
+			runner:  &fakeRunner{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &Service{Runner: tt.runner}
+
+			resp, err := svc.AddDatabase(context.TODO(), &protob.AddDatabaseRequest{Database: &protob.DatabaseInfo{
+				Engine:   tt.engine,
+				Hostname: "127.0.0.1",
+				Port:     port,
+				Database: "project",
+				User:     tt.user,
+				Password: tt.password,
+			}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AddDatabase() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && resp.GetMessage() != tt.wantMessage {
+				t.Errorf("AddDatabase() message = %q, want %q", resp.GetMessage(), tt.wantMessage)
+			}
+
+			var created bool
+			for _, cmd := range tt.runner.ran {
+				if strings.Contains(cmd, "CREATE DATABASE") {
+					created = true
+				}
+			}
+
+			if created != tt.wantCreate {
+				t.Errorf("expected create to be run %v, got %v (%v)", tt.wantCreate, created, tt.runner.ran)
+			}
+
+			for _, want := range tt.wantRan {
+				found := false
+				for _, cmd := range tt.runner.ran {
+					if strings.HasSuffix(cmd, want) {
+						found = true
+					}
+				}
+
+				if !found {
+					t.Errorf("expected %q to be run, got %v", want, tt.runner.ran)
+				}
+			}
+		})
+	}
+}
+
+func TestService_AddDatabase_GrantsPrivilegesOnce(t *testing.T) {
+	port := fakeDatabase(t)
+
+	runner := &fakeRunner{}
+	svc := &Service{Runner: runner}
+
+	if _, err := svc.AddDatabase(context.TODO(), &protob.AddDatabaseRequest{Database: &protob.DatabaseInfo{
+		Engine:   "mysql",
+		Hostname: "127.0.0.1",
+		Port:     port,
+		Database: "project",
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var creates, grants int
+	for _, cmd := range runner.ran {
+		if strings.Contains(cmd, "CREATE DATABASE") {
+			creates++
+		}
+
+		if strings.HasSuffix(cmd, "-e GRANT ALL PRIVILEGES ON `project`.* TO 'nitro'@'%';") {
+			grants++
+		}
+	}
+
+	if creates != 1 || grants != 1 {
+		t.Errorf("expected the database to be created and granted once, got %d creates and %d grants (%v)", creates, grants, runner.ran)
+	}
+}
+
+func TestTruncateOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		max    int
+		want   string
+	}{
+		{
+			name:   "output under the limit is not changed",
+			output: "Warning (Code 1366)",
+			max:    100,
+			want:   "Warning (Code 1366)",
+		},
+		{
+			name:   "output over the limit is truncated",
+			output: "abcdef",
+			max:    3,
+			want:   "abc\n[the output was truncated]\n",
+		},
+		{
+			name:   "multi-byte characters are not cut in half",
+			output: "ab€cd",
+			max:    3,
+			want:   "ab\n[the output was truncated]\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateOutput(tt.output, tt.max)
+			if got != tt.want {
+				t.Errorf("truncateOutput() = %q, want %q", got, tt.want)
+			}
+
+			if !utf8.ValidString(got) {
+				t.Errorf("expected valid utf-8, got %q", got)
+			}
+		})
+	}
+}
+
+func TestService_ApplyProxyPorts(t *testing.T) {
+	sites := map[string]*protob.Site{
+		"craftdev.nitro": {Hostname: "craftdev.nitro", Aliases: "alias.nitro", Port: 8080},
+	}
+
+	fake := newFakeCaddy(t, http.StatusOK)
+
+	if _, err := fake.service().Apply(context.TODO(), &protob.ApplyRequest{Sites: sites, ProxyPorts: []int32{6001}}); err != nil {
+		t.Fatal(err)
+	}
+
+	fake.mu.Lock()
+	body := fake.requests["/config/apps/http/servers"]
+	fake.mu.Unlock()
+
+	var servers map[string]caddy.Server
+	if err := json.Unmarshal(body, &servers); err != nil {
+		t.Fatal(err)
+	}
+
+	want := caddy.Server{
+		Listen: []string{":6001"},
+		Routes: []caddy.ServerRoute{
+			{
+				Handle:   []caddy.RouteHandle{{Handler: "reverse_proxy", Upstreams: []caddy.Upstream{{Dial: "craftdev.nitro:6001"}}}},
+				Match:    []caddy.Match{{Host: []string{"craftdev.nitro", "alias.nitro"}}},
+				Terminal: true,
+			},
+		},
+		AutomaticHTTPS: caddy.AutomaticHTTPS{Disable: true, DisableRedirects: true},
+	}
+
+	if !reflect.DeepEqual(servers["port_6001"], want) {
+		t.Errorf("expected the server for the proxy port to be %v, got %v", want, servers["port_6001"])
+	}
+
+	if _, ok := servers["https"]; !ok {
+		t.Error("expected the other servers to be sent with the proxy ports")
+	}
+}
+
+func TestService_ApplyRejectsProxyPortsUsedByTheProxy(t *testing.T) {
+	fake := newFakeCaddy(t, http.StatusOK)
+
+	_, err := fake.service().Apply(context.TODO(), &protob.ApplyRequest{ProxyPorts: []int32{3000}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected an invalid argument error, got %v", err)
+	}
+}
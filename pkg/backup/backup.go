@@ -30,6 +30,7 @@ type Options struct {
 	Database      string
 	BackupName    string
 	Commands      []string
+	Env           []string
 }
 
 func (o *Options) Validate() error {
@@ -183,6 +184,7 @@ func Perform(ctx context.Context, docker client.ContainerAPIClient, opts *Option
 		AttachStdout: true,
 		AttachStderr: true,
 		Tty:          false,
+		Env:          opts.Env,
 		Cmd:          opts.Commands,
 	})
 	if err != nil {
@@ -0,0 +1,133 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+
+	"github.com/craftcms/nitro/pkg/database"
+)
+
+// TimestampColumns are the common names of the columns that store when a row was last
+// updated, they are checked in order when the column for an incremental backup is not
+// provided. Craft uses dateUpdated for all of its content tables.
+var TimestampColumns = []string{"dateUpdated", "updated_at", "date_updated", "modified"}
+
+var validColumn = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// IncrementalTables returns the timestamp column and the tables in the database that have
+// the column. If the column is empty, the first of the TimestampColumns used by a table in
+// the database is returned. The credentials are taken from the environment of the database
+// container. Incremental backups are only supported for mysql.
+func IncrementalTables(ctx context.Context, docker client.ContainerAPIClient, containerID, db, column string, containerEnv []string) (string, []string, error) {
+	columns := TimestampColumns
+	if column != "" {
+		columns = []string{column}
+	}
+
+	for _, c := range columns {
+		if !validColumn.MatchString(c) {
+			return "", nil, fmt.Errorf("the column %q must only include letters, numbers, and underscores", c)
+		}
+	}
+
+	query := fmt.Sprintf("SELECT table_name, column_name FROM information_schema.columns WHERE table_schema = %s AND column_name IN ('%s');", database.QuoteString("mysql", db), strings.Join(columns, "','"))
+
+	user, env := IncrementalCredentials(containerEnv)
+
+	exec, err := docker.ContainerExecCreate(ctx, containerID, types.ExecConfig{
+		AttachStdout: true,
+		AttachStderr: true,
+		Tty:          false,
+		Env:          env,
+		Cmd:          []string{"mysql", "--user=" + user, "--skip-column-names", "--silent", "-e", query},
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	resp, err := docker.ContainerExecAttach(ctx, exec.ID, types.ExecStartCheck{Tty: false})
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Close()
+
+	if err := docker.ContainerExecStart(ctx, exec.ID, types.ExecStartCheck{}); err != nil {
+		return "", nil, fmt.Errorf("unable to start the container exec, %w", err)
+	}
+
+	var stdout bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, ioutil.Discard, resp.Reader); err != nil {
+		return "", nil, err
+	}
+
+	column, tables := timestampTables(stdout.String(), columns)
+	if len(tables) == 0 {
+		return "", nil, fmt.Errorf("no tables in %s have a %s column", db, strings.Join(columns, " or "))
+	}
+
+	return column, tables, nil
+}
+
+// IncrementalCredentials takes the environment of the database container and returns the
+// user and the environment for the exec. The password is set in the environment so it is
+// not in the arguments of the process.
+func IncrementalCredentials(containerEnv []string) (string, []string) {
+	user, password := database.Credentials("mysql", containerEnv)
+	if user == "" {
+		user, password = database.DefaultUser, database.DefaultPassword
+	}
+
+	return user, []string{"MYSQL_PWD=" + password}
+}
+
+// IncrementalCommands returns the mysqldump command to backup the rows in the tables that
+// were updated since the time, using the user from IncrementalCredentials. The backup
+// replaces existing rows when it is imported and does not include the table definitions.
+func IncrementalCommands(db, backupName, column, user string, since time.Time, tables []string) []string {
+	commands := []string{
+		"mysqldump",
+		"--user=" + user,
+		"--no-create-info",
+		"--replace",
+		"--skip-triggers",
+		fmt.Sprintf("--where=`%s` >= '%s'", column, since.UTC().Format("2006-01-02 15:04:05")),
+		"--result-file=/tmp/" + backupName,
+		db,
+	}
+
+	return append(commands, tables...)
+}
+
+// timestampTables takes the table and column output from mysql and returns the first
+// column, in order of the columns, used by a table and the sorted tables with the column.
+func timestampTables(output string, columns []string) (string, []string) {
+	found := make(map[string][]string)
+	for _, line := range strings.Split(output, "\n") {
+		sp := strings.Fields(line)
+		if len(sp) != 2 {
+			continue
+		}
+
+		found[sp[1]] = append(found[sp[1]], sp[0])
+	}
+
+	for _, c := range columns {
+		if tables, ok := found[c]; ok {
+			sort.Strings(tables)
+
+			return c, tables
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,111 @@
+package backup
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_timestampTables(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     string
+		columns    []string
+		wantColumn string
+		wantTables []string
+	}{
+		{
+			name:       "returns the tables with the first column",
+			output:     "entries\tdateUpdated\ncontent\tdateUpdated\nlegacy\tupdated_at\n",
+			columns:    TimestampColumns,
+			wantColumn: "dateUpdated",
+			wantTables: []string{"content", "entries"},
+		},
+		{
+			name:       "uses later columns when the first is not found",
+			output:     "legacy\tupdated_at\n",
+			columns:    TimestampColumns,
+			wantColumn: "updated_at",
+			wantTables: []string{"legacy"},
+		},
+		{
+			name:    "ignores warnings and empty output",
+			output:  "mysql: [Warning] Using a password on the command line interface can be insecure.\n\n",
+			columns: TimestampColumns,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			column, tables := timestampTables(tt.output, tt.columns)
+			if column != tt.wantColumn {
+				t.Errorf("expected the column %q, got %q", tt.wantColumn, column)
+			}
+
+			if !reflect.DeepEqual(tables, tt.wantTables) {
+				t.Errorf("expected the tables %v, got %v", tt.wantTables, tables)
+			}
+		})
+	}
+}
+
+func TestIncrementalCommands(t *testing.T) {
+	since := time.Date(2021, 3, 1, 8, 30, 0, 0, time.FixedZone("EST", -5*60*60))
+
+	got := IncrementalCommands("project", "project.sql", "dateUpdated", "nitro", since, []string{"content", "entries"})
+
+	want := []string{
+		"mysqldump",
+		"--user=nitro",
+		"--no-create-info",
+		"--replace",
+		"--skip-triggers",
+		"--where=`dateUpdated` >= '2021-03-01 13:30:00'",
+		"--result-file=/tmp/project.sql",
+		"project",
+		"content",
+		"entries",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("IncrementalCommands() = %v, want %v", got, want)
+	}
+}
+
+func TestIncrementalCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      []string
+		wantUser string
+		wantEnv  []string
+	}{
+		{
+			name:     "uses the root password from the container",
+			env:      []string{"MYSQL_ROOT_PASSWORD=secret", "MYSQL_USER=nitro"},
+			wantUser: "root",
+			wantEnv:  []string{"MYSQL_PWD=secret"},
+		},
+		{
+			name:     "uses the user from the container",
+			env:      []string{"MYSQL_USER=craft", "MYSQL_PASSWORD=secret"},
+			wantUser: "craft",
+			wantEnv:  []string{"MYSQL_PWD=secret"},
+		},
+		{
+			name:     "defaults to the nitro user",
+			wantUser: "nitro",
+			wantEnv:  []string{"MYSQL_PWD=nitro"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, env := IncrementalCredentials(tt.env)
+			if user != tt.wantUser {
+				t.Errorf("expected the user %q, got %q", tt.wantUser, user)
+			}
+
+			if !reflect.DeepEqual(env, tt.wantEnv) {
+				t.Errorf("expected the env %v, got %v", tt.wantEnv, env)
+			}
+		})
+	}
+}
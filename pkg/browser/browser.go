@@ -0,0 +1,30 @@
+package browser
+
+import (
+	"os/exec"
+	"runtime"
+
+	"github.com/craftcms/nitro/pkg/wsl"
+)
+
+// Open takes a url and opens it in the users default browser.
+func Open(url string) error {
+	name, args := command(runtime.GOOS, wsl.IsWSL(), url)
+
+	return exec.Command(name, args...).Start()
+}
+
+// command returns the executable and arguments used to open a url for the operating system.
+func command(goos string, isWSL bool, url string) (string, []string) {
+	switch {
+	case goos == "darwin":
+		return "open", []string{url}
+	case goos == "windows":
+		return "rundll32", []string{"url.dll,FileProtocolHandler", url}
+	case isWSL:
+		// use the windows browser from wsl
+		return "cmd.exe", []string{"/c", "start", url}
+	}
+
+	return "xdg-open", []string{url}
+}
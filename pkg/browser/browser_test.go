@@ -0,0 +1,56 @@
+package browser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_command(t *testing.T) {
+	type args struct {
+		goos  string
+		isWSL bool
+		url   string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantName string
+		wantArgs []string
+	}{
+		{
+			name:     "macOS uses open",
+			args:     args{goos: "darwin", url: "https://tutorial.nitro"},
+			wantName: "open",
+			wantArgs: []string{"https://tutorial.nitro"},
+		},
+		{
+			name:     "windows uses the url protocol handler",
+			args:     args{goos: "windows", url: "https://tutorial.nitro"},
+			wantName: "rundll32",
+			wantArgs: []string{"url.dll,FileProtocolHandler", "https://tutorial.nitro"},
+		},
+		{
+			name:     "wsl uses the windows browser",
+			args:     args{goos: "linux", isWSL: true, url: "https://tutorial.nitro"},
+			wantName: "cmd.exe",
+			wantArgs: []string{"/c", "start", "https://tutorial.nitro"},
+		},
+		{
+			name:     "linux uses xdg-open",
+			args:     args{goos: "linux", url: "https://tutorial.nitro"},
+			wantName: "xdg-open",
+			wantArgs: []string{"https://tutorial.nitro"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotName, gotArgs := command(tt.args.goos, tt.args.isWSL, tt.args.url)
+			if gotName != tt.wantName {
+				t.Errorf("command() name = %v, want %v", gotName, tt.wantName)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("command() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -1,16 +1,73 @@
 package caddy
 
+import "encoding/json"
+
 type UpdateRequest struct {
 	HTTPS   Server `json:"https,omitempty"`
 	HTTP    Server `json:"http,omitempty"`
 	Node    Server `json:"node,omitempty"`
 	NodeAlt Server `json:"node_alt,omitempty"`
+	// Ports are the servers for the additional proxy ports, by server name
+	Ports map[string]Server `json:"-"`
+}
+
+// MarshalJSON adds the servers for the additional proxy ports to the other servers.
+func (u UpdateRequest) MarshalJSON() ([]byte, error) {
+	type servers UpdateRequest
+
+	data, err := json.Marshal(servers(u))
+	if err != nil || len(u.Ports) == 0 {
+		return data, err
+	}
+
+	all := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	for name, s := range u.Ports {
+		server, err := json.Marshal(s)
+		if err != nil {
+			return nil, err
+		}
+
+		all[name] = server
+	}
+
+	return json.Marshal(all)
 }
 
 type Server struct {
-	Listen         []string       `json:"listen"`
-	Routes         []ServerRoute  `json:"routes"`
-	AutomaticHTTPS AutomaticHTTPS `json:"automatic_https"`
+	Listen                []string              `json:"listen"`
+	Routes                []ServerRoute         `json:"routes"`
+	AutomaticHTTPS        AutomaticHTTPS        `json:"automatic_https"`
+	Logs                  *ServerLogs           `json:"logs,omitempty"`
+	Protocols             []string              `json:"protocols,omitempty"`
+	Errors                *ServerErrors         `json:"errors,omitempty"`
+	TLSConnectionPolicies []TLSConnectionPolicy `json:"tls_connection_policies,omitempty"`
+}
+
+type ServerErrors struct {
+	Routes []ServerRoute `json:"routes"`
+}
+
+type ServerLogs struct {
+	LoggerNames map[string]string `json:"logger_names,omitempty"`
+}
+
+type Logging struct {
+	Logs map[string]Log `json:"logs"`
+}
+
+type Log struct {
+	Writer  *LogWriter `json:"writer,omitempty"`
+	Include []string   `json:"include,omitempty"`
+	Exclude []string   `json:"exclude,omitempty"`
+}
+
+type LogWriter struct {
+	Output   string `json:"output"`
+	Filename string `json:"filename,omitempty"`
 }
 
 type AutomaticHTTPS struct {
@@ -25,16 +82,74 @@ type ServerRoute struct {
 }
 
 type RouteHandle struct {
-	Handler   string     `json:"handler"`
-	Root      string     `json:"root,omitempty"`
-	Upstreams []Upstream `json:"upstreams,omitempty"`
-	Hide      []string   `json:"hide,omitempty"`
+	Handler    string              `json:"handler"`
+	Root       string              `json:"root,omitempty"`
+	Upstreams  []Upstream          `json:"upstreams,omitempty"`
+	Hide       []string            `json:"hide,omitempty"`
+	StatusCode int                 `json:"status_code,omitempty"`
+	Headers    map[string][]string `json:"headers,omitempty"`
+	Body       string              `json:"body,omitempty"`
+	URI        string              `json:"uri,omitempty"`
+	Method     string              `json:"method,omitempty"`
+	Response   *HeaderOps          `json:"response,omitempty"`
+	MaxSize    int64               `json:"max_size,omitempty"`
+
+	HandleResponse []ResponseHandler `json:"handle_response,omitempty"`
+}
+
+type ResponseHandler struct {
+	Match  *ResponseMatch `json:"match,omitempty"`
+	Routes []ServerRoute  `json:"routes,omitempty"`
+}
+
+type ResponseMatch struct {
+	StatusCode []int `json:"status_code,omitempty"`
+}
+
+type HeaderOps struct {
+	Set      map[string][]string `json:"set,omitempty"`
+	Delete   []string            `json:"delete,omitempty"`
+	Deferred bool                `json:"deferred,omitempty"`
 }
 
 type Match struct {
-	Host []string `json:"host"`
+	Host       []string `json:"host"`
+	Path       []string `json:"path,omitempty"`
+	Expression string   `json:"expression,omitempty"`
 }
 
 type Upstream struct {
 	Dial string `json:"dial,omitempty"`
 }
+
+type TLSAutomation struct {
+	Policies []TLSPolicy  `json:"policies,omitempty"`
+	OnDemand *TLSOnDemand `json:"on_demand,omitempty"`
+}
+
+type TLSPolicy struct {
+	Issuers  []TLSIssuer `json:"issuers,omitempty"`
+	OnDemand bool        `json:"on_demand,omitempty"`
+}
+
+type TLSIssuer struct {
+	Module string `json:"module"`
+}
+
+type TLSOnDemand struct {
+	Ask string `json:"ask,omitempty"`
+}
+
+type TLSConnectionPolicy struct {
+	Match                *TLSConnectionMatch      `json:"match,omitempty"`
+	ClientAuthentication *TLSClientAuthentication `json:"client_authentication,omitempty"`
+}
+
+type TLSConnectionMatch struct {
+	SNI []string `json:"sni,omitempty"`
+}
+
+type TLSClientAuthentication struct {
+	TrustedCACerts []string `json:"trusted_ca_certs,omitempty"`
+	Mode           string   `json:"mode,omitempty"`
+}
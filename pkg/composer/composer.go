@@ -20,6 +20,7 @@ type Options struct {
 	Labels        map[string]string
 	Volume        *types.Volume
 	Path          string
+	User          string
 	NetworkConfig *network.NetworkingConfig
 }
 
@@ -30,7 +31,10 @@ func CreateContainer(ctx context.Context, docker client.CommonAPIClient, opts *O
 	}
 
 	containerUser := "www-data"
-	if runtime.GOOS == "linux" {
+	switch {
+	case opts.User != "":
+		containerUser = opts.User
+	case runtime.GOOS == "linux":
 		user, err := user.Current()
 		if err != nil {
 			return container.ContainerCreateCreatedBody{}, err
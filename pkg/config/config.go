@@ -1,13 +1,21 @@
 package config
 
 import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/craftcms/nitro/pkg/errcode"
 	"github.com/craftcms/nitro/pkg/helpers"
 
 	"gopkg.in/yaml.v3"
@@ -18,7 +26,7 @@ var (
 	DirectoryName = ".nitro"
 
 	// ErrNoConfigFile is returned when a configuration file cannot be found
-	ErrNoConfigFile = fmt.Errorf("there is no config file for the environment")
+	ErrNoConfigFile = errcode.WithCode(errcode.ErrNoConfig, fmt.Errorf("there is no config file for the environment"))
 
 	// ErrEmptyfile is returned when a config file is empty
 	ErrEmptyfile = fmt.Errorf("the config file appears to be empty")
@@ -26,6 +34,37 @@ var (
 	// FileName is the default name for the yaml file
 	FileName = "nitro.yaml"
 
+	// DefaultXdebugMode is the Xdebug 3 mode used when a site enables Xdebug without a mode
+	DefaultXdebugMode = "develop,debug"
+
+	// DefaultIndex is the PHP front controller used when a site does not set an index
+	DefaultIndex = "index.php"
+
+	// BackupDirectoryName is the directory, in the config directory, used to keep the
+	// previous versions of the config file
+	BackupDirectoryName = "backups"
+
+	// BackupLimit is the number of previous versions of the config file to keep
+	BackupLimit = 5
+
+	// AllowUnsetVariables keeps ${VAR} references to unset environment variables as
+	// literal text instead of returning an error when loading the config
+	AllowUnsetVariables = false
+
+	// InterpolatedFields are the keys whose values expand ${VAR} and ${VAR:-default}
+	// references to environment variables when the config is loaded, e.g. a site
+	// path of ${PROJECTS_DIR}/example
+	InterpolatedFields = map[string]bool{
+		"path":             true,
+		"webroot":          true,
+		"client_ca":        true,
+		"source":           true,
+		"target":           true,
+		"port":             true,
+		"maintenance_page": true,
+		"not_found_page":   true,
+	}
+
 	// DefaultEnvs is used to map a config to a known environment variable that is used
 	// on the container instances to their default values
 	DefaultEnvs = map[string]string{
@@ -48,16 +87,57 @@ var (
 
 // Config represents the nitro-dev.yaml users add for local development.
 type Config struct {
-	Containers []Container `json:"containers,omitempty" yaml:"containers,omitempty"`
-	Blackfire  Blackfire   `json:"blackfire,omitempty" yaml:"blackfire,omitempty"`
-	Databases  []Database  `json:"databases,omitempty" yaml:"databases,omitempty"`
-	Services   Services    `json:"services" yaml:"services"`
-	Sites      []Site      `json:"sites,omitempty" yaml:"sites,omitempty"`
-	File       string      `json:"-" yaml:"-"`
+	AccessLogs      bool        `json:"access_logs,omitempty" yaml:"access_logs,omitempty"`
+	OnDemandTLS     bool        `json:"on_demand_tls,omitempty" yaml:"on_demand_tls,omitempty"`
+	HTTP3           bool        `json:"http3,omitempty" yaml:"http3,omitempty"`
+	MaintenancePage string      `json:"maintenance_page,omitempty" yaml:"maintenance_page,omitempty"`
+	NotFoundPage    string      `json:"not_found_page,omitempty" yaml:"not_found_page,omitempty"`
+	RestartPolicy   string      `json:"restart_policy,omitempty" yaml:"restart_policy,omitempty"`
+	ProxyPorts      []string    `json:"proxy_ports,omitempty" yaml:"proxy_ports,omitempty"`
+	HostUser        bool        `json:"host_user,omitempty" yaml:"host_user,omitempty"`
+	Containers      []Container `json:"containers,omitempty" yaml:"containers,omitempty"`
+	Blackfire       Blackfire   `json:"blackfire,omitempty" yaml:"blackfire,omitempty"`
+	Databases       []Database  `json:"databases,omitempty" yaml:"databases,omitempty"`
+	DatabaseEngine  string      `json:"database_engine,omitempty" yaml:"database_engine,omitempty"`
+	Services        Services    `json:"services" yaml:"services"`
+	Sites           []Site      `json:"sites,omitempty" yaml:"sites,omitempty"`
+	Projects        []string    `json:"projects,omitempty" yaml:"projects,omitempty"`
+	File            string      `json:"-" yaml:"-"`
+	// Project is the path to the project config found from the working directory, if any
+	Project string `json:"-" yaml:"-"`
+
+	// projectSites are the sites from the project configs, marshalled to check for changes
+	projectSites map[string]projectSite
+	// overriddenSites are the sites in the config file replaced by project sites
+	overriddenSites map[string]Site
+	// projectAdded is true when the project config for the working directory was not recorded
+	projectAdded bool
 
 	// rw sync.RWMutex
 }
 
+// ContainerUser returns the uid and gid (e.g. 1000:1000) of the host user when the
+// host_user option is enabled, so files PHP, Composer, and npm create in the mounted
+// directories are owned by the host user instead of root. It returns an empty string
+// when the option is disabled or on Windows, where docker does not use the host ids.
+func (c *Config) ContainerUser() string {
+	if !c.HostUser {
+		return ""
+	}
+
+	return hostUser(runtime.GOOS, os.Getuid(), os.Getgid())
+}
+
+// hostUser returns the uid and gid as a docker user, or an empty string on windows
+// or when the ids are not available.
+func hostUser(goos string, uid, gid int) string {
+	if goos == "windows" || uid < 0 || gid < 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%d:%d", uid, gid)
+}
+
 // AllSitesWithHostnames takes the address, which is the nitro-proxy
 // ip address, and the current site and returns a list of all the
 func (c *Config) AllSitesWithHostnames(site Site, addr string) map[string][]string {
@@ -241,15 +321,60 @@ type Services struct {
 // are alternate domains), the local path to the site, additional mounts
 // to add to the container, and the directory the index.php is located.
 type Site struct {
-	Hostname   string   `json:"hostname" yaml:"hostname"`
-	Aliases    []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
-	Path       string   `json:"path" yaml:"path"`
-	Version    string   `json:"version" yaml:"version"`
-	PHP        PHP      `json:"php,omitempty" yaml:"php,omitempty"`
-	Extensions []string `json:"extensions,omitempty" yaml:"extensions,omitempty"`
-	Webroot    string   `json:"webroot" yaml:"webroot"`
-	Xdebug     bool     `json:"xdebug" yaml:"xdebug"`
-	Blackfire  bool     `json:"blackfire" yaml:"blackfire"`
+	Hostname       string            `json:"hostname" yaml:"hostname"`
+	Aliases        []string          `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Path           string            `json:"path" yaml:"path"`
+	Version        string            `json:"version" yaml:"version"`
+	PHP            PHP               `json:"php,omitempty" yaml:"php,omitempty"`
+	FPM            FPM               `json:"fpm,omitempty" yaml:"fpm,omitempty"`
+	Extensions     []string          `json:"extensions,omitempty" yaml:"extensions,omitempty"`
+	Webroot        string            `json:"webroot" yaml:"webroot"`
+	Index          string            `json:"index,omitempty" yaml:"index,omitempty"`
+	Xdebug         bool              `json:"xdebug" yaml:"xdebug"`
+	XdebugMode     string            `json:"xdebug_mode,omitempty" yaml:"xdebug_mode,omitempty"`
+	Blackfire      bool              `json:"blackfire" yaml:"blackfire"`
+	Maintenance    bool              `json:"maintenance,omitempty" yaml:"maintenance,omitempty"`
+	Paths          []SitePath        `json:"paths,omitempty" yaml:"paths,omitempty"`
+	ErrorPages     []ErrorPage       `json:"error_pages,omitempty" yaml:"error_pages,omitempty"`
+	ClientCA       string            `json:"client_ca,omitempty" yaml:"client_ca,omitempty"`
+	Headers        map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	RemoveHeaders  []string          `json:"remove_headers,omitempty" yaml:"remove_headers,omitempty"`
+	EarlyHints     []string          `json:"early_hints,omitempty" yaml:"early_hints,omitempty"`
+	MaxRequestBody string            `json:"max_request_body,omitempty" yaml:"max_request_body,omitempty"`
+	Mounts         []Mount           `json:"mounts,omitempty" yaml:"mounts,omitempty"`
+	// Project is the path to the project config that defines the site, if any
+	Project string `json:"-" yaml:"-"`
+}
+
+// SitePath maps a path prefix for a site (e.g. /api) to an alternate
+// upstream, such as a custom container, that will handle requests
+// for the prefix instead of the sites container.
+type SitePath struct {
+	Prefix   string `json:"prefix" yaml:"prefix"`
+	Upstream string `json:"upstream" yaml:"upstream"`
+}
+
+// ErrorPage handles the errors with a status code for a site, either by
+// proxying the request to a path on the site (e.g. an error route in the
+// app) or by responding with a file from the sites webroot.
+type ErrorPage struct {
+	Status int    `json:"status" yaml:"status"`
+	Path   string `json:"path,omitempty" yaml:"path,omitempty"`
+	File   string `json:"file,omitempty" yaml:"file,omitempty"`
+}
+
+// Mount is an additional directory on the host, such as a shared library
+// outside of the project, that is bind mounted into the sites container.
+type Mount struct {
+	Source   string `json:"source" yaml:"source"`
+	Target   string `json:"target" yaml:"target"`
+	ReadOnly bool   `json:"read_only,omitempty" yaml:"read_only,omitempty"`
+}
+
+// GetAbsSource gets the full path for the source of the mount
+// and expands the home directory.
+func (m *Mount) GetAbsSource(home string) (string, error) {
+	return cleanPath(home, m.Source)
 }
 
 // GetAbsPath gets the directory for a site.Path,
@@ -259,6 +384,12 @@ func (s *Site) GetAbsPath(home string) (string, error) {
 	return cleanPath(home, s.Path)
 }
 
+// GetAbsWebroot gets the directory on the host for the
+// site’s webroot and expands the home directory.
+func (s *Site) GetAbsWebroot(home string) (string, error) {
+	return cleanPath(home, filepath.Join(s.Path, s.Webroot))
+}
+
 // GetAbsContainerPath gets the directory for a site’s
 // container path.
 func (s *Site) GetAbsContainerPath(home string) (string, error) {
@@ -286,6 +417,241 @@ func (s *Site) GetContainerPath() string {
 	return ""
 }
 
+// ValidateErrorPages returns an error if an error page is not for a 4xx or 5xx
+// status, the status is used more than once, or the page does not have exactly
+// one of a path or a file in the webroot.
+func (s *Site) ValidateErrorPages() error {
+	seen := make(map[int]bool)
+	for _, e := range s.ErrorPages {
+		if e.Status < 400 || e.Status > 599 {
+			return fmt.Errorf("error page status %d must be a 4xx or 5xx status", e.Status)
+		}
+
+		if seen[e.Status] {
+			return fmt.Errorf("error page status %d is used more than once", e.Status)
+		}
+		seen[e.Status] = true
+
+		switch {
+		case e.Path != "" && e.File != "":
+			return fmt.Errorf("error page %d must use a path or a file, not both", e.Status)
+		case e.Path != "":
+			if !strings.HasPrefix(e.Path, "/") || strings.ContainsAny(e.Path, " \t") {
+				return fmt.Errorf("error page %d path %q must start with a / and not include spaces", e.Status, e.Path)
+			}
+		case e.File != "":
+			if filepath.IsAbs(e.File) || strings.HasPrefix(filepath.Clean(e.File), "..") {
+				return fmt.Errorf("error page %d file %q must be relative to the webroot", e.Status, e.File)
+			}
+		default:
+			return fmt.Errorf("error page %d must have a path or a file", e.Status)
+		}
+	}
+
+	return nil
+}
+
+// earlyHintTypes are the file extensions that can be sent as early hints and the type
+// (the as attribute of the link) the browser preloads them as.
+var earlyHintTypes = map[string]string{
+	".css":   "style",
+	".js":    "script",
+	".mjs":   "script",
+	".woff":  "font",
+	".woff2": "font",
+	".ttf":   "font",
+	".otf":   "font",
+	".avif":  "image",
+	".gif":   "image",
+	".jpeg":  "image",
+	".jpg":   "image",
+	".png":   "image",
+	".svg":   "image",
+	".webp":  "image",
+}
+
+// EarlyHintLinks validates the early hints, which are paths on the site (e.g.
+// /css/site.css), and returns the Link header values to preload them. It returns
+// an error if a hint is not a path to a css, js, font, or image file or the hint
+// is used more than once.
+func (s *Site) EarlyHintLinks() ([]string, error) {
+	var links []string
+	seen := make(map[string]bool)
+	for _, h := range s.EarlyHints {
+		if !strings.HasPrefix(h, "/") || strings.HasPrefix(h, "//") || strings.ContainsAny(h, " \t\r\n<>,;\"") {
+			return nil, fmt.Errorf("early hint %q must be a path starting with a / and not include spaces, quotes, commas, semicolons, or angle brackets", h)
+		}
+
+		if seen[h] {
+			return nil, fmt.Errorf("early hint %q is used more than once", h)
+		}
+		seen[h] = true
+
+		// ignore the query string (e.g. ?v=2) when checking the file type
+		file := strings.SplitN(h, "?", 2)[0]
+		as, ok := earlyHintTypes[strings.ToLower(filepath.Ext(file))]
+		if !ok {
+			return nil, fmt.Errorf("early hint %q must be a css, js, font, or image file", h)
+		}
+
+		link := fmt.Sprintf("<%s>; rel=preload; as=%s", h, as)
+
+		// fonts are always requested with cors
+		if as == "font" {
+			link += "; crossorigin"
+		}
+
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+// requestBodySize matches the max request body size of a site, a number of bytes
+// with an optional k, m, or g unit (e.g. 100m or 2G).
+var requestBodySize = regexp.MustCompile(`^(\d+)([kKmMgG])?[bB]?$`)
+
+// MaxRequestBodySize returns the largest request body, in bytes, the proxy will
+// accept for the site. It returns 0 when the size is not set, which does not
+// limit the request body, and an error if the size is not valid.
+func (s *Site) MaxRequestBodySize() (int64, error) {
+	if s.MaxRequestBody == "" {
+		return 0, nil
+	}
+
+	m := requestBodySize.FindStringSubmatch(s.MaxRequestBody)
+	if m == nil {
+		return 0, fmt.Errorf("max request body %q must be a size such as 512k, 100m, or 1g", s.MaxRequestBody)
+	}
+
+	size, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil || size == 0 {
+		return 0, fmt.Errorf("max request body %q must be greater than 0", s.MaxRequestBody)
+	}
+
+	var shift uint
+	switch strings.ToLower(m[2]) {
+	case "k":
+		shift = 10
+	case "m":
+		shift = 20
+	case "g":
+		shift = 30
+	}
+
+	if size > (1<<62)>>shift {
+		return 0, fmt.Errorf("max request body %q is too large", s.MaxRequestBody)
+	}
+
+	return size << shift, nil
+}
+
+// ErrorPageBody returns the contents of the error page file from the sites
+// webroot, or an empty string if the error page uses a path on the site.
+func (s *Site) ErrorPageBody(home string, page ErrorPage) (string, error) {
+	if page.File == "" {
+		return "", nil
+	}
+
+	dir, err := s.GetAbsPath(home)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, s.Webroot, page.File))
+	if err != nil {
+		return "", fmt.Errorf("unable to read the error page for %d, %w", page.Status, err)
+	}
+
+	return string(b), nil
+}
+
+// GetIndex returns the PHP front controller, in the webroot, that handles the
+// requests that do not match a file.
+func (s *Site) GetIndex() string {
+	if s.Index == "" {
+		return DefaultIndex
+	}
+
+	return s.Index
+}
+
+// ValidateIndex returns an error if the front controller for the site is not a PHP
+// file in the webroot. Sites using the default index are not checked.
+func (s *Site) ValidateIndex(home string) error {
+	if s.Index == "" {
+		return nil
+	}
+
+	if filepath.IsAbs(s.Index) || strings.HasPrefix(filepath.Clean(s.Index), "..") || strings.ContainsAny(s.Index, " \t?") {
+		return fmt.Errorf("the index %q must be a file relative to the webroot", s.Index)
+	}
+
+	if filepath.Ext(s.Index) != ".php" {
+		return fmt.Errorf("the index %q must be a .php file", s.Index)
+	}
+
+	dir, err := s.GetAbsWebroot(home)
+	if err != nil {
+		return err
+	}
+
+	if stat, err := os.Stat(filepath.Join(dir, s.Index)); err != nil || stat.IsDir() {
+		return fmt.Errorf("the index %q does not exist in the webroot %s", s.Index, dir)
+	}
+
+	return nil
+}
+
+// ClientCACert returns the PEM encoded CA used to verify client certificates (mTLS)
+// for the site, or an empty string if client certificates are not required.
+func (s *Site) ClientCACert(home string) (string, error) {
+	if s.ClientCA == "" {
+		return "", nil
+	}
+
+	file, err := cleanPath(home, s.ClientCA)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the client CA, %w", err)
+	}
+
+	// make sure there is at least one CA certificate
+	found := false
+	for rest := b; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return "", fmt.Errorf("the client CA %s is not a valid certificate, %w", s.ClientCA, err)
+		}
+
+		if !cert.IsCA {
+			return "", fmt.Errorf("the client CA %s contains a certificate that is not a CA", s.ClientCA)
+		}
+
+		found = true
+	}
+
+	if !found {
+		return "", fmt.Errorf("the client CA %s does not contain a PEM encoded certificate", s.ClientCA)
+	}
+
+	return string(b), nil
+}
+
 // AsEnvs takes a gateway addr and turns specific options
 // such as PHP settings into env vars that can be set on the
 // containers environment
@@ -295,7 +661,17 @@ func (s *Site) AsEnvs(addr string) []string {
 	// set the php vars
 	envs = append(envs, phpVars(s.PHP, s.Version)...)
 
-	return append(envs, xdebugVars(s.PHP, s.Xdebug, s.Version, s.Hostname, addr)...)
+	return append(envs, xdebugVars(s.PHP, s.Xdebug, s.GetXdebugMode(), s.Version, s.Hostname, addr)...)
+}
+
+// GetXdebugMode returns the Xdebug 3 modes (e.g. develop,debug) used when Xdebug
+// is enabled for the site. Sites without a mode use the default modes.
+func (s *Site) GetXdebugMode() string {
+	if s.XdebugMode == "" {
+		return DefaultXdebugMode
+	}
+
+	return s.XdebugMode
 }
 
 // SetPHPBoolSetting is used to set php settings that are bool. It will look
@@ -363,6 +739,31 @@ func (c *Config) SetSiteAlias(hostname, alias string) error {
 	return fmt.Errorf("unable to find the site: %s", hostname)
 }
 
+// AddSiteMount is used to add a mount to a site. If the site
+// cannot be found or the target is already mounted it will
+// return an error.
+func (c *Config) AddSiteMount(hostname string, mount Mount) error {
+	for i, s := range c.Sites {
+		// if its not the right hostname
+		if s.Hostname != hostname {
+			continue
+		}
+
+		// make sure the target is not already mounted
+		for _, m := range s.Mounts {
+			if m.Target == mount.Target {
+				return fmt.Errorf("%s is already mounted for %s", mount.Target, hostname)
+			}
+		}
+
+		c.Sites[i].Mounts = append(c.Sites[i].Mounts, mount)
+
+		return nil
+	}
+
+	return fmt.Errorf("unable to find the site: %s", hostname)
+}
+
 // SetPHPExtension is used to set php settings that are bool. It will look
 // for the site by its hostname and change the setting. If it cannot find the
 // site or setting it will return an error.
@@ -389,6 +790,27 @@ func (c *Config) SetPHPExtension(hostname, extension string) error {
 	return fmt.Errorf("unable to find the site: %s", hostname)
 }
 
+// RemovePHPExtension removes the extension from the site. It will look for
+// the site by its hostname and return an error if the site cannot be found
+// or the extension is not set for the site.
+func (c *Config) RemovePHPExtension(hostname, extension string) error {
+	for i, s := range c.Sites {
+		if s.Hostname == hostname {
+			for j, e := range c.Sites[i].Extensions {
+				if e == extension {
+					c.Sites[i].Extensions = append(c.Sites[i].Extensions[:j], c.Sites[i].Extensions[j+1:]...)
+
+					return nil
+				}
+			}
+
+			return fmt.Errorf("extension %s is not set for %s", extension, hostname)
+		}
+	}
+
+	return fmt.Errorf("unable to find the site: %s", hostname)
+}
+
 // SetPHPIntSetting is used to set php settings that are ints. It will look
 // for the site by its hostname and change the setting. If it cannot find the
 // site or setting it will return an error.
@@ -469,6 +891,119 @@ type PHP struct {
 	UploadMaxFileSize         string `json:"upload_max_file_size,omitempty" yaml:"upload_max_file_size,omitempty"`
 }
 
+// FPM is the PHP-FPM process manager settings for a site. The settings are
+// written to the sites pool config, unset settings use the image defaults.
+type FPM struct {
+	PM                 string `json:"pm,omitempty" yaml:"pm,omitempty"`
+	MaxChildren        int    `json:"pm.max_children,omitempty" yaml:"pm.max_children,omitempty"`
+	StartServers       int    `json:"pm.start_servers,omitempty" yaml:"pm.start_servers,omitempty"`
+	MinSpareServers    int    `json:"pm.min_spare_servers,omitempty" yaml:"pm.min_spare_servers,omitempty"`
+	MaxSpareServers    int    `json:"pm.max_spare_servers,omitempty" yaml:"pm.max_spare_servers,omitempty"`
+	ProcessIdleTimeout int    `json:"pm.process_idle_timeout,omitempty" yaml:"pm.process_idle_timeout,omitempty"`
+	MaxRequests        int    `json:"pm.max_requests,omitempty" yaml:"pm.max_requests,omitempty"`
+}
+
+// Validate returns an error if the process manager is not static, dynamic, or
+// ondemand, a setting is negative, or the dynamic settings do not fit within
+// the max children.
+func (f FPM) Validate() error {
+	switch f.PM {
+	case "", "static", "dynamic", "ondemand":
+	default:
+		return fmt.Errorf("pm %q must be static, dynamic, or ondemand", f.PM)
+	}
+
+	for _, s := range f.numbers() {
+		if s.value < 0 {
+			return fmt.Errorf("%s must be a positive number, got %d", s.name, s.value)
+		}
+	}
+
+	if f.MinSpareServers > 0 && f.MaxSpareServers > 0 && f.MinSpareServers > f.MaxSpareServers {
+		return fmt.Errorf("pm.min_spare_servers (%d) must not be more than pm.max_spare_servers (%d)", f.MinSpareServers, f.MaxSpareServers)
+	}
+
+	if f.StartServers > 0 && f.MinSpareServers > 0 && f.StartServers < f.MinSpareServers {
+		return fmt.Errorf("pm.start_servers (%d) must not be less than pm.min_spare_servers (%d)", f.StartServers, f.MinSpareServers)
+	}
+
+	if f.StartServers > 0 && f.MaxSpareServers > 0 && f.StartServers > f.MaxSpareServers {
+		return fmt.Errorf("pm.start_servers (%d) must not be more than pm.max_spare_servers (%d)", f.StartServers, f.MaxSpareServers)
+	}
+
+	if f.MaxChildren > 0 && f.StartServers > f.MaxChildren {
+		return fmt.Errorf("pm.start_servers (%d) must not be more than pm.max_children (%d)", f.StartServers, f.MaxChildren)
+	}
+
+	if f.MaxChildren > 0 && f.MaxSpareServers > f.MaxChildren {
+		return fmt.Errorf("pm.max_spare_servers (%d) must not be more than pm.max_children (%d)", f.MaxSpareServers, f.MaxChildren)
+	}
+
+	return nil
+}
+
+// Conf returns the pool config with the settings, or an empty string if none of
+// the settings are set.
+func (f FPM) Conf() string {
+	settings := f.settings()
+	if len(settings) == 0 {
+		return ""
+	}
+
+	var conf strings.Builder
+	conf.WriteString("[www]\n")
+	for _, s := range settings {
+		fmt.Fprintf(&conf, "%s = %s\n", s[0], s[1])
+	}
+
+	return conf.String()
+}
+
+// String returns the settings as a comma separated list of name=value, it is used
+// to label the sites container.
+func (f FPM) String() string {
+	var settings []string
+	for _, s := range f.settings() {
+		settings = append(settings, s[0]+"="+s[1])
+	}
+
+	return strings.Join(settings, ",")
+}
+
+// settings returns the names and values of the settings that are set, in the
+// order they are written to the pool config.
+func (f FPM) settings() [][2]string {
+	var settings [][2]string
+	if f.PM != "" {
+		settings = append(settings, [2]string{"pm", f.PM})
+	}
+
+	for _, s := range f.numbers() {
+		if s.value != 0 {
+			settings = append(settings, [2]string{s.name, strconv.Itoa(s.value)})
+		}
+	}
+
+	return settings
+}
+
+type fpmNumber struct {
+	name  string
+	value int
+}
+
+// numbers returns the names and values of the numeric settings.
+func (f FPM) numbers() []fpmNumber {
+	return []fpmNumber{
+		{"pm.max_children", f.MaxChildren},
+		{"pm.start_servers", f.StartServers},
+		{"pm.min_spare_servers", f.MinSpareServers},
+		{"pm.max_spare_servers", f.MaxSpareServers},
+		{"pm.process_idle_timeout", f.ProcessIdleTimeout},
+		{"pm.max_requests", f.MaxRequests},
+	}
+}
+
 // Load is used to return the unmarshalled config, and
 // returns an error when trying to get the users home directory or
 // while marshalling the config.
@@ -490,14 +1025,120 @@ func Load(home string) (*Config, error) {
 	}
 
 	// unmarshal
-	if err := yaml.Unmarshal(data, &c); err != nil {
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, err
+	}
+
+	// expand the environment variables in the supported fields
+	if err := interpolate(doc, false); err != nil {
 		return nil, err
 	}
 
+	if err := doc.Decode(&c); err != nil {
+		return nil, err
+	}
+
+	// the project configs that were moved or removed are no longer recorded
+	var projects []string
+	for _, p := range c.Projects {
+		if _, err := os.Stat(p); err == nil {
+			projects = append(projects, p)
+		}
+	}
+	c.Projects = projects
+
+	// record the project config for the working directory so its sites are kept
+	// when nitro is used from other directories
+	if wd, err := getwd(); err == nil {
+		if project := FindProject(wd); project != "" {
+			c.Project = project
+			if !c.recorded(project) {
+				c.Projects = append(c.Projects, project)
+				c.projectAdded = true
+			}
+		}
+	}
+
+	// merge the sites from the project configs, the projects take precedence
+	for _, p := range c.Projects {
+		if err := c.mergeProject(p); err != nil {
+			return nil, err
+		}
+	}
+
 	// return the config
 	return c, nil
 }
 
+// variablePattern matches ${VAR} and ${VAR:-default} references to environment variables.
+var variablePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// interpolate walks the node and expands the environment variables in the values of the
+// InterpolatedFields. It returns an error for unset variables without a default, unless
+// AllowUnsetVariables is set.
+func interpolate(n *yaml.Node, field bool) error {
+	switch n.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, c := range n.Content {
+			if err := interpolate(c, field); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if err := interpolate(n.Content[i+1], InterpolatedFields[n.Content[i].Value]); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		if !field {
+			return nil
+		}
+
+		v, err := expand(n.Value)
+		if err != nil {
+			return fmt.Errorf("unable to expand line %d of the config, %w", n.Line, err)
+		}
+
+		n.Value = v
+	}
+
+	return nil
+}
+
+// expand replaces the ${VAR} and ${VAR:-default} references in the value with the
+// environment variables. The default is used when the variable is unset or empty.
+func expand(value string) (string, error) {
+	var err error
+	expanded := variablePattern.ReplaceAllStringFunc(value, func(m string) string {
+		sm := variablePattern.FindStringSubmatch(m)
+		if v := os.Getenv(sm[1]); v != "" {
+			return v
+		}
+
+		if sm[2] != "" {
+			return sm[3]
+		}
+
+		if _, ok := os.LookupEnv(sm[1]); ok {
+			return ""
+		}
+
+		if AllowUnsetVariables {
+			return m
+		}
+
+		if err == nil {
+			err = fmt.Errorf("the environment variable %s is not set, set it or use ${%s:-default}", sm[1], sm[1])
+		}
+
+		return m
+	})
+
+	return expanded, err
+}
+
 // IsEmpty is used to check if the config file is empty
 func IsEmpty(home string) (string, error) {
 	// verify the file exists
@@ -521,7 +1162,7 @@ func (c *Config) AddSite(s Site) error {
 	for _, e := range c.Sites {
 		// does the hostname match
 		if e.Hostname == s.Hostname {
-			return fmt.Errorf("hostname already exists")
+			return errcode.WithCode(errcode.ErrSiteExists, fmt.Errorf("hostname already exists"))
 		}
 	}
 
@@ -630,6 +1271,27 @@ func (c *Config) EnableBlackfire(site string) error {
 	return fmt.Errorf("unknown site, %s", site)
 }
 
+// SetXdebugMode takes a sites hostname and sets the Xdebug 3 modes (e.g.
+// debug,profile) used when Xdebug is enabled. An empty mode uses the
+// default modes. If the site cannot be found, it returns an error.
+func (c *Config) SetXdebugMode(site, mode string) error {
+	// find the site by the hostname
+	for i, s := range c.Sites {
+		if s.Hostname == site {
+			// the default mode does not need to be saved
+			if mode == DefaultXdebugMode {
+				mode = ""
+			}
+
+			c.Sites[i].XdebugMode = mode
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown site, %s", site)
+}
+
 // EnableXdebug takes a sites hostname and sets the xdebug option
 // to true. If the site cannot be found, it returns an error.
 func (c *Config) EnableXdebug(site string) error {
@@ -647,6 +1309,60 @@ func (c *Config) EnableXdebug(site string) error {
 	return fmt.Errorf("unknown site, %s", site)
 }
 
+// SetMaintenance takes a sites hostname and turns maintenance mode on or off.
+func (c *Config) SetMaintenance(site string, enabled bool) error {
+	// find the site by the hostname
+	for i, s := range c.Sites {
+		if s.Hostname == site {
+			c.Sites[i].Maintenance = enabled
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown site, %s", site)
+}
+
+// MaintenanceBody returns the contents of the maintenance page, or an empty string
+// if a maintenance page is not set.
+func (c *Config) MaintenanceBody(home string) (string, error) {
+	if c.MaintenancePage == "" {
+		return "", nil
+	}
+
+	file, err := cleanPath(home, c.MaintenancePage)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the maintenance page, %w", err)
+	}
+
+	return string(b), nil
+}
+
+// NotFoundBody returns the contents of the page shown for hosts that do not match
+// a site, or an empty string if a not found page is not set.
+func (c *Config) NotFoundBody(home string) (string, error) {
+	if c.NotFoundPage == "" {
+		return "", nil
+	}
+
+	file, err := cleanPath(home, c.NotFoundPage)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the not found page, %w", err)
+	}
+
+	return string(b), nil
+}
+
 // Save takes a file path and marshals the config into a file.
 func (c *Config) Save() error {
 	// make sure the file exists
@@ -658,14 +1374,40 @@ func (c *Config) Save() error {
 		}
 	}
 
-	// open the file
-	f, err := os.OpenFile(c.File, os.O_TRUNC|os.O_WRONLY, os.ModeAppend)
+	// the project sites are not saved in the home config
+	sites, err := c.homeSites()
 	if err != nil {
 		return err
 	}
 
-	// unmarshal
-	data, err := yaml.Marshal(&c)
+	saved := *c
+	saved.Sites = sites
+
+	// marshal the config into a yaml document
+	doc := &yaml.Node{Kind: yaml.DocumentNode}
+	content := &yaml.Node{}
+	if err := content.Encode(&saved); err != nil {
+		return err
+	}
+	doc.Content = []*yaml.Node{content}
+
+	// keep the comments and ordering of the existing file so hand edits are not lost
+	if existing := c.existing(); existing != nil {
+		preserve(existing, doc)
+	}
+
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	// keep a copy of the previous version of the file
+	if err := c.backup(data); err != nil {
+		return err
+	}
+
+	// open the file
+	f, err := os.OpenFile(c.File, os.O_TRUNC|os.O_WRONLY, os.ModeAppend)
 	if err != nil {
 		return err
 	}
@@ -678,6 +1420,213 @@ func (c *Config) Save() error {
 	return f.Close()
 }
 
+// backup copies the current config file into the backups directory, unless it is
+// empty or the same as the data being saved, and removes the oldest backups over
+// the BackupLimit.
+func (c *Config) backup(data []byte) error {
+	current, err := ioutil.ReadFile(c.File)
+	if err != nil || len(current) == 0 || bytes.Equal(current, data) {
+		return nil
+	}
+
+	dir := filepath.Join(filepath.Dir(c.File), BackupDirectoryName)
+	if err := helpers.MkdirIfNotExists(dir); err != nil {
+		return fmt.Errorf("unable to create the backups directory, %w", err)
+	}
+
+	name := fmt.Sprintf("nitro-%s.yaml", time.Now().Format("20060102-150405.000000000"))
+	if err := ioutil.WriteFile(filepath.Join(dir, name), current, 0600); err != nil {
+		return fmt.Errorf("unable to backup the config, %w", err)
+	}
+
+	backups, err := listBackups(dir)
+	if err != nil {
+		return err
+	}
+
+	// the backups are sorted newest first
+	for i := BackupLimit; i < len(backups); i++ {
+		if err := os.Remove(backups[i]); err != nil {
+			return fmt.Errorf("unable to remove the backup %s, %w", backups[i], err)
+		}
+	}
+
+	return nil
+}
+
+// Backups returns the paths to the previous versions of the config file, the most
+// recent backup is first.
+func Backups(home string) ([]string, error) {
+	return listBackups(filepath.Join(home, DirectoryName, BackupDirectoryName))
+}
+
+func listBackups(dir string) ([]string, error) {
+	backups, err := filepath.Glob(filepath.Join(dir, "nitro-*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	// the names include the time so they sort in order
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+
+	return backups, nil
+}
+
+// Restore replaces the config file with a backup. The current config file is
+// backed up first so the restore can be undone.
+func Restore(home, backup string) error {
+	data, err := ioutil.ReadFile(backup)
+	if err != nil {
+		return err
+	}
+
+	// make sure the backup is a valid config
+	if err := yaml.Unmarshal(data, &Config{}); err != nil {
+		return fmt.Errorf("unable to parse the backup %s, %w", backup, err)
+	}
+
+	c := &Config{File: filepath.Join(home, DirectoryName, FileName)}
+	if err := c.backup(data); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(c.File, data, 0644)
+}
+
+// existing returns the yaml document of the config file, or nil if the
+// file is empty or can't be parsed.
+func (c *Config) existing() *yaml.Node {
+	data, err := ioutil.ReadFile(c.File)
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil
+	}
+
+	return doc
+}
+
+// preserve copies the comments from the previous node to the next node and orders
+// mapping keys and sequence items (e.g. sites) to match the previous node. Keys
+// and items that are not in the previous node are added at the end.
+func preserve(prev, next *yaml.Node) {
+	if prev == nil || next == nil || prev.Kind != next.Kind {
+		return
+	}
+
+	next.HeadComment = prev.HeadComment
+	next.LineComment = prev.LineComment
+	next.FootComment = prev.FootComment
+
+	type entry struct {
+		nodes []*yaml.Node
+		pos   int
+	}
+
+	var entries []entry
+	switch next.Kind {
+	case yaml.DocumentNode:
+		if len(prev.Content) > 0 && len(next.Content) > 0 {
+			preserve(prev.Content[0], next.Content[0])
+		}
+
+		return
+	case yaml.MappingNode:
+		keys := make(map[string]int)
+		for i := 0; i+1 < len(prev.Content); i += 2 {
+			keys[prev.Content[i].Value] = i
+		}
+
+		for i := 0; i+1 < len(next.Content); i += 2 {
+			k, v := next.Content[i], next.Content[i+1]
+
+			pos := len(prev.Content) + i
+			if j, ok := keys[k.Value]; ok {
+				preserve(prev.Content[j], k)
+				preserve(prev.Content[j+1], v)
+				pos = j
+			}
+
+			entries = append(entries, entry{nodes: []*yaml.Node{k, v}, pos: pos})
+		}
+	case yaml.SequenceNode:
+		items := make(map[string]int)
+		for i, n := range prev.Content {
+			if id := identity(n); id != "" {
+				items[id] = i
+			}
+		}
+
+		for i, n := range next.Content {
+			pos := len(prev.Content) + i
+			id := identity(n)
+			if j, ok := items[id]; ok && id != "" {
+				preserve(prev.Content[j], n)
+				pos = j
+			}
+
+			entries = append(entries, entry{nodes: []*yaml.Node{n}, pos: pos})
+		}
+	case yaml.ScalarNode:
+		// keep the environment variables when the value has not changed since it was loaded
+		if strings.Contains(prev.Value, "${") && prev.Value != next.Value {
+			if v, err := expand(prev.Value); err == nil && v == next.Value {
+				next.Value = prev.Value
+				next.Style = prev.Style
+			}
+		}
+
+		return
+	default:
+		return
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].pos < entries[j].pos
+	})
+
+	next.Content = nil
+	for _, e := range entries {
+		next.Content = append(next.Content, e.nodes...)
+	}
+}
+
+// identity returns the value used to match sequence items between saves, such
+// as the hostname of a site or the name of a container.
+func identity(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		return n.Value
+	case yaml.MappingNode:
+		values := make(map[string]string)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			// match the loaded values when the previous file references environment variables
+			v := n.Content[i+1].Value
+			if expanded, err := expand(v); err == nil && InterpolatedFields[n.Content[i].Value] {
+				v = expanded
+			}
+
+			values[n.Content[i].Value] = v
+		}
+
+		for _, k := range []string{"hostname", "name", "target"} {
+			if values[k] != "" {
+				return k + ":" + values[k]
+			}
+		}
+
+		// databases are identified by the engine, version, and port
+		if values["engine"] != "" {
+			return fmt.Sprintf("engine:%s-%s-%s", values["engine"], values["version"], values["port"])
+		}
+	}
+
+	return ""
+}
+
 func (c *Config) createFile(dir string) error {
 	// create the .nitro directory if it does not exist
 	if err := helpers.MkdirIfNotExists(dir); err != nil {
@@ -768,7 +1717,7 @@ func phpVars(php PHP, version string) []string {
 	return envs
 }
 
-func xdebugVars(php PHP, xdebug bool, version, hostname, addr string) []string {
+func xdebugVars(php PHP, xdebug bool, mode, version, hostname, addr string) []string {
 	envs := []string{}
 
 	// always set the session
@@ -785,7 +1734,7 @@ func xdebugVars(php PHP, xdebug bool, version, hostname, addr string) []string {
 	switch version {
 	case "8.0", "7.4", "7.3", "7.2":
 		envs = append(envs, fmt.Sprintf(`XDEBUG_CONFIG=client_host=%s client_port=9003`, addr))
-		envs = append(envs, "XDEBUG_MODE=develop,debug")
+		envs = append(envs, "XDEBUG_MODE="+mode)
 	default:
 		// use legacy xdebug settings to support older versions of php
 		envs = append(envs, fmt.Sprintf(`XDEBUG_CONFIG=idekey=PHPSTORM remote_host=%s profiler_enable=1 remote_port=9000 remote_autostart=1 remote_enable=1`, addr))
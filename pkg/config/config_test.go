@@ -1,21 +1,33 @@
 package config
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestSite_AsEnvs(t *testing.T) {
 	type fields struct {
-		Hostname string
-		Aliases  []string
-		Path     string
-		Version  string
-		PHP      PHP
-		Webroot  string
-		Xdebug   bool
+		Hostname   string
+		Aliases    []string
+		Path       string
+		Version    string
+		PHP        PHP
+		Webroot    string
+		Xdebug     bool
+		XdebugMode string
 	}
 	type args struct {
 		addr string
@@ -100,6 +112,34 @@ func TestSite_AsEnvs(t *testing.T) {
 				"XDEBUG_MODE=develop,debug",
 			},
 		},
+		{
+			name: "xdebug 3 mode is set if enabled with a mode",
+			fields: fields{
+				Hostname:   "somewebsite.nitro",
+				Version:    "8.0",
+				Xdebug:     true,
+				XdebugMode: "profile,trace",
+			},
+			args: args{
+				addr: "host.docker.internal",
+			},
+			want: []string{
+				"COMPOSER_HOME=/tmp",
+				"PHP_DISPLAY_ERRORS=on",
+				"PHP_MEMORY_LIMIT=512M",
+				"PHP_MAX_EXECUTION_TIME=5000",
+				"PHP_UPLOAD_MAX_FILESIZE=512M",
+				"PHP_MAX_INPUT_VARS=5000",
+				"PHP_POST_MAX_SIZE=512M",
+				"PHP_OPCACHE_ENABLE=0",
+				"PHP_OPCACHE_REVALIDATE_FREQ=0",
+				"PHP_OPCACHE_VALIDATE_TIMESTAMPS=0",
+				"XDEBUG_SESSION=PHPSTORM",
+				"PHP_IDE_CONFIG=serverName=somewebsite.nitro",
+				"XDEBUG_CONFIG=client_host=host.docker.internal client_port=9003",
+				"XDEBUG_MODE=profile,trace",
+			},
+		},
 		{
 			name: "defaults are overridden when set on the site",
 			fields: fields{
@@ -156,13 +196,14 @@ func TestSite_AsEnvs(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := &Site{
-				Hostname: tt.fields.Hostname,
-				Aliases:  tt.fields.Aliases,
-				Path:     tt.fields.Path,
-				Version:  tt.fields.Version,
-				PHP:      tt.fields.PHP,
-				Webroot:  tt.fields.Webroot,
-				Xdebug:   tt.fields.Xdebug,
+				Hostname:   tt.fields.Hostname,
+				Aliases:    tt.fields.Aliases,
+				Path:       tt.fields.Path,
+				Version:    tt.fields.Version,
+				PHP:        tt.fields.PHP,
+				Webroot:    tt.fields.Webroot,
+				Xdebug:     tt.fields.Xdebug,
+				XdebugMode: tt.fields.XdebugMode,
 			}
 			if got := s.AsEnvs(tt.args.addr); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Site.AsEnvs() = \ngot:\n%v, \nwant:\n%v", got, tt.want)
@@ -520,6 +561,66 @@ func TestConfig_AddSite(t *testing.T) {
 	}
 }
 
+func TestConfig_AddSiteMount(t *testing.T) {
+	type args struct {
+		hostname string
+		mount    Mount
+	}
+	tests := []struct {
+		name    string
+		sites   []Site
+		args    args
+		want    []Mount
+		wantErr bool
+	}{
+		{
+			name:  "mounts are added to the site",
+			sites: []Site{{Hostname: "example"}},
+			args: args{
+				hostname: "example",
+				mount:    Mount{Source: "~/shared", Target: "/shared", ReadOnly: true},
+			},
+			want: []Mount{{Source: "~/shared", Target: "/shared", ReadOnly: true}},
+		},
+		{
+			name: "existing targets return an error",
+			sites: []Site{
+				{
+					Hostname: "example",
+					Mounts:   []Mount{{Source: "~/shared", Target: "/shared"}},
+				},
+			},
+			args: args{
+				hostname: "example",
+				mount:    Mount{Source: "~/other", Target: "/shared"},
+			},
+			want:    []Mount{{Source: "~/shared", Target: "/shared"}},
+			wantErr: true,
+		},
+		{
+			name:  "unknown sites return an error",
+			sites: []Site{{Hostname: "example"}},
+			args: args{
+				hostname: "missing",
+				mount:    Mount{Source: "~/shared", Target: "/shared"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{Sites: tt.sites}
+			if err := c.AddSiteMount(tt.args.hostname, tt.args.mount); (err != nil) != tt.wantErr {
+				t.Errorf("Config.AddSiteMount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(c.Sites[0].Mounts, tt.want) {
+				t.Errorf("Config.AddSiteMount() = %v, want %v", c.Sites[0].Mounts, tt.want)
+			}
+		})
+	}
+}
+
 func TestSite_GetAbsPath(t *testing.T) {
 	wd, err := os.Getwd()
 	if err != nil {
@@ -580,6 +681,42 @@ func TestSite_GetAbsPath(t *testing.T) {
 	}
 }
 
+func TestSite_GetAbsWebroot(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		webroot string
+		want    string
+	}{
+		{
+			name:    "the webroot is relative to the site path",
+			path:    "/dev/craft",
+			webroot: "web",
+			want:    "/dev/craft/web",
+		},
+		{
+			name:    "the home directory is expanded",
+			path:    "~/dev/craft",
+			webroot: "public/",
+			want:    "/home/nitro/dev/craft/public",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Site{Path: tt.path, Webroot: tt.webroot}
+
+			got, err := s.GetAbsWebroot("/home/nitro")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != filepath.FromSlash(tt.want) {
+				t.Errorf("Site.GetAbsWebroot() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestConfig_SetPHPStrSetting(t *testing.T) {
 	type fields struct {
 		Sites []Site
@@ -1416,3 +1553,874 @@ func TestConfig_IsEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_SavePreservesCommentsAndOrdering(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.Mkdir(filepath.Join(dir, DirectoryName), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(dir, DirectoryName, FileName)
+	content := `# team config for nitro
+sites:
+    # the main site
+    - hostname: zebra.nitro
+      path: ~/dev/zebra
+      version: "8.0"
+      webroot: web
+    - hostname: alpha.nitro # the api
+      path: ~/dev/alpha
+      version: "7.4"
+      webroot: web
+databases:
+    - engine: mysql
+      version: "8.0"
+      port: "3306"
+services:
+    dynamodb: false
+    mailhog: false
+    minio: false
+    redis: true
+`
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cfg.EnableXdebug("alpha.nitro"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := string(saved)
+	for _, want := range []string{"# team config for nitro", "# the main site", "# the api", "xdebug: true"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected the saved config to contain %q, got:\n%s", want, got)
+		}
+	}
+
+	// the sites and top level keys keep their order
+	order := []string{"sites:", "zebra.nitro", "alpha.nitro", "databases:", "services:"}
+	last := -1
+	for _, o := range order {
+		i := strings.Index(got, o)
+		if i < last {
+			t.Errorf("expected %q to keep its position, got:\n%s", o, got)
+		}
+		last = i
+	}
+}
+
+func TestExpand(t *testing.T) {
+	os.Setenv("NITRO_TEST_PROJECTS", "/Users/oli/projects")
+	os.Setenv("NITRO_TEST_EMPTY", "")
+	os.Unsetenv("NITRO_TEST_UNSET")
+	defer os.Unsetenv("NITRO_TEST_PROJECTS")
+	defer os.Unsetenv("NITRO_TEST_EMPTY")
+
+	tests := []struct {
+		name       string
+		value      string
+		allowUnset bool
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:  "variables are expanded",
+			value: "${NITRO_TEST_PROJECTS}/example",
+			want:  "/Users/oli/projects/example",
+		},
+		{
+			name:  "defaults are used for unset variables",
+			value: "${NITRO_TEST_UNSET:-~/dev}/example",
+			want:  "~/dev/example",
+		},
+		{
+			name:  "defaults are used for empty variables",
+			value: "${NITRO_TEST_EMPTY:-3306}",
+			want:  "3306",
+		},
+		{
+			name:  "empty variables without a default are removed",
+			value: "~/dev${NITRO_TEST_EMPTY}/example",
+			want:  "~/dev/example",
+		},
+		{
+			name:  "values without variables are not changed",
+			value: "~/dev/$example",
+			want:  "~/dev/$example",
+		},
+		{
+			name:    "unset variables return an error",
+			value:   "${NITRO_TEST_UNSET}/example",
+			want:    "${NITRO_TEST_UNSET}/example",
+			wantErr: true,
+		},
+		{
+			name:       "unset variables are kept when allowed",
+			value:      "${NITRO_TEST_UNSET}/example",
+			allowUnset: true,
+			want:       "${NITRO_TEST_UNSET}/example",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			AllowUnsetVariables = tt.allowUnset
+			defer func() { AllowUnsetVariables = false }()
+
+			got, err := expand(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("expand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_LoadAndSaveKeepsVariables(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.Mkdir(filepath.Join(dir, DirectoryName), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("NITRO_TEST_PROJECTS", "/Users/oli/projects")
+	defer os.Unsetenv("NITRO_TEST_PROJECTS")
+
+	file := filepath.Join(dir, DirectoryName, FileName)
+	content := `sites:
+    - hostname: ${NITRO_TEST_PROJECTS}.nitro
+      path: ${NITRO_TEST_PROJECTS}/example
+      version: "8.0"
+      webroot: web
+databases:
+    - engine: mysql
+      version: "8.0"
+      port: ${NITRO_TEST_PORT:-3306}
+`
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := cfg.Sites[0].Path; got != "/Users/oli/projects/example" {
+		t.Errorf("expected the path to be expanded, got %q", got)
+	}
+
+	if got := cfg.Sites[0].Hostname; got != "${NITRO_TEST_PROJECTS}.nitro" {
+		t.Errorf("expected the hostname to not be expanded, got %q", got)
+	}
+
+	if got := cfg.Databases[0].Port; got != "3306" {
+		t.Errorf("expected the port to use the default, got %q", got)
+	}
+
+	if err := cfg.EnableXdebug(cfg.Sites[0].Hostname); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := string(saved)
+	for _, want := range []string{"path: ${NITRO_TEST_PROJECTS}/example", "port: ${NITRO_TEST_PORT:-3306}", "xdebug: true"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected the saved config to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestConfig_SaveRotatesBackups(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.Mkdir(filepath.Join(dir, DirectoryName), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(dir, DirectoryName, FileName)
+	if err := ioutil.WriteFile(file, []byte("sites:\n    - hostname: first.nitro\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// save more versions than are kept
+	for i := 0; i < BackupLimit+2; i++ {
+		if err := cfg.AddSite(Site{Hostname: fmt.Sprintf("site%d.nitro", i)}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := cfg.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// saving without changes does not create a backup
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, err := Backups(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(backups) != BackupLimit {
+		t.Fatalf("expected %d backups, got %d", BackupLimit, len(backups))
+	}
+
+	// the most recent backup is the version before the last save
+	if err := Restore(dir, backups[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(restored.Sites) != len(cfg.Sites)-1 {
+		t.Errorf("expected the restored config to have %d sites, got %d", len(cfg.Sites)-1, len(restored.Sites))
+	}
+
+	// the restore can be undone
+	backups, err = Backups(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	undo, err := ioutil.ReadFile(backups[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(undo), fmt.Sprintf("site%d.nitro", BackupLimit+1)) {
+		t.Errorf("expected the latest backup to be the config before the restore, got\n%s", undo)
+	}
+}
+
+func TestRestoreRejectsInvalidBackups(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	backup := filepath.Join(dir, "nitro-invalid.yaml")
+	if err := ioutil.WriteFile(backup, []byte("sites: [\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Restore(dir, backup); err == nil {
+		t.Error("expected an error restoring an invalid backup")
+	}
+}
+
+func TestConfig_MaintenanceBody(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "maintenance.html"), []byte("<h1>Back soon</h1>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		page    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "no maintenance page returns an empty body",
+		},
+		{
+			name: "the maintenance page is relative to the home directory",
+			page: "~/maintenance.html",
+			want: "<h1>Back soon</h1>",
+		},
+		{
+			name:    "missing maintenance pages return an error",
+			page:    "~/missing.html",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{MaintenancePage: tt.page}
+
+			got, err := c.MaintenanceBody(dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MaintenanceBody() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("MaintenanceBody() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_NotFoundBody(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "404.html"), []byte("<h1>Nothing here</h1>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		page    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "no not found page returns an empty body",
+		},
+		{
+			name: "the not found page is relative to the home directory",
+			page: "~/404.html",
+			want: "<h1>Nothing here</h1>",
+		},
+		{
+			name:    "missing not found pages return an error",
+			page:    "~/missing.html",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{NotFoundPage: tt.page}
+
+			got, err := c.NotFoundBody(dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NotFoundBody() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("NotFoundBody() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_RemovePHPExtension(t *testing.T) {
+	tests := []struct {
+		name      string
+		hostname  string
+		extension string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "removes the extension from the site",
+			hostname:  "craftdev.nitro",
+			extension: "imagick",
+			want:      []string{"bcmath", "redis"},
+		},
+		{
+			name:      "extensions that are not set return an error",
+			hostname:  "craftdev.nitro",
+			extension: "gd",
+			want:      []string{"bcmath", "imagick", "redis"},
+			wantErr:   true,
+		},
+		{
+			name:      "unknown sites return an error",
+			hostname:  "unknown.nitro",
+			extension: "imagick",
+			want:      []string{"bcmath", "imagick", "redis"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Sites: []Site{{Hostname: "craftdev.nitro", Extensions: []string{"bcmath", "imagick", "redis"}}},
+			}
+
+			if err := cfg.RemovePHPExtension(tt.hostname, tt.extension); (err != nil) != tt.wantErr {
+				t.Errorf("RemovePHPExtension() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(cfg.Sites[0].Extensions, tt.want) {
+				t.Errorf("expected the extensions to be %v, got %v", tt.want, cfg.Sites[0].Extensions)
+			}
+		})
+	}
+}
+
+func TestSite_ValidateErrorPages(t *testing.T) {
+	tests := []struct {
+		name    string
+		pages   []ErrorPage
+		wantErr bool
+	}{
+		{
+			name: "paths and files are valid",
+			pages: []ErrorPage{
+				{Status: 404, Path: "/errors/404"},
+				{Status: 500, File: "500.html"},
+			},
+		},
+		{
+			name:    "statuses must be errors",
+			pages:   []ErrorPage{{Status: 200, Path: "/"}},
+			wantErr: true,
+		},
+		{
+			name:    "statuses can only be used once",
+			pages:   []ErrorPage{{Status: 404, Path: "/404"}, {Status: 404, File: "404.html"}},
+			wantErr: true,
+		},
+		{
+			name:    "pages cannot use a path and a file",
+			pages:   []ErrorPage{{Status: 404, Path: "/404", File: "404.html"}},
+			wantErr: true,
+		},
+		{
+			name:    "pages need a path or a file",
+			pages:   []ErrorPage{{Status: 404}},
+			wantErr: true,
+		},
+		{
+			name:    "paths must start with a slash",
+			pages:   []ErrorPage{{Status: 404, Path: "errors/404"}},
+			wantErr: true,
+		},
+		{
+			name:    "files must be in the webroot",
+			pages:   []ErrorPage{{Status: 404, File: "../404.html"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Site{Hostname: "craftdev.nitro", ErrorPages: tt.pages}
+
+			if err := s.ValidateErrorPages(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateErrorPages() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSite_ClientCACert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca := testCertificate(t, true)
+	files := map[string][]byte{
+		"ca.pem":    ca,
+		"leaf.pem":  testCertificate(t, false),
+		"empty.pem": []byte("not a certificate"),
+	}
+	for name, b := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		ca      string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "no client CA returns an empty certificate",
+		},
+		{
+			name: "the client CA is relative to the home directory",
+			ca:   "~/ca.pem",
+			want: string(ca),
+		},
+		{
+			name:    "certificates that are not a CA return an error",
+			ca:      "~/leaf.pem",
+			wantErr: true,
+		},
+		{
+			name:    "files without a certificate return an error",
+			ca:      "~/empty.pem",
+			wantErr: true,
+		},
+		{
+			name:    "missing files return an error",
+			ca:      "~/missing.pem",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Site{Hostname: "mtls.nitro", ClientCA: tt.ca}
+
+			got, err := s.ClientCACert(dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ClientCACert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ClientCACert() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// testCertificate returns a PEM encoded self signed certificate.
+func testCertificate(t *testing.T, isCA bool) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Nitro Test"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestSite_ValidateIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "site", "public"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "site", "public", "app.php"), []byte("<?php"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		index   string
+		wantErr bool
+	}{
+		{
+			name: "the default index is not checked",
+		},
+		{
+			name:  "files in the webroot are valid",
+			index: "app.php",
+		},
+		{
+			name:    "missing files return an error",
+			index:   "missing.php",
+			wantErr: true,
+		},
+		{
+			name:    "files outside of the webroot return an error",
+			index:   "../app.php",
+			wantErr: true,
+		},
+		{
+			name:    "files that are not php return an error",
+			index:   "index.html",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Site{Hostname: "legacy.nitro", Path: "~/site", Webroot: "public", Index: tt.index}
+
+			if err := s.ValidateIndex(dir); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateIndex() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFPM_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		fpm     FPM
+		wantErr bool
+	}{
+		{
+			name: "no settings use the image defaults",
+		},
+		{
+			name: "dynamic settings within the max children are valid",
+			fpm:  FPM{PM: "dynamic", MaxChildren: 10, StartServers: 3, MinSpareServers: 2, MaxSpareServers: 5},
+		},
+		{
+			name: "ondemand is valid",
+			fpm:  FPM{PM: "ondemand", MaxChildren: 5, ProcessIdleTimeout: 10},
+		},
+		{
+			name:    "unknown process managers return an error",
+			fpm:     FPM{PM: "adaptive"},
+			wantErr: true,
+		},
+		{
+			name:    "negative values return an error",
+			fpm:     FPM{PM: "static", MaxChildren: -1},
+			wantErr: true,
+		},
+		{
+			name:    "start servers more than the max children return an error",
+			fpm:     FPM{PM: "dynamic", MaxChildren: 2, StartServers: 4},
+			wantErr: true,
+		},
+		{
+			name:    "start servers outside of the spare servers return an error",
+			fpm:     FPM{PM: "dynamic", StartServers: 1, MinSpareServers: 2, MaxSpareServers: 4},
+			wantErr: true,
+		},
+		{
+			name:    "min spare servers more than max spare servers return an error",
+			fpm:     FPM{PM: "dynamic", MinSpareServers: 5, MaxSpareServers: 2},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fpm.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFPM_Conf(t *testing.T) {
+	if conf := (FPM{}).Conf(); conf != "" {
+		t.Errorf("expected no pool config without settings, got %q", conf)
+	}
+
+	fpm := FPM{PM: "dynamic", MaxChildren: 10, StartServers: 3, MaxRequests: 500}
+
+	want := "[www]\npm = dynamic\npm.max_children = 10\npm.start_servers = 3\npm.max_requests = 500\n"
+	if conf := fpm.Conf(); conf != want {
+		t.Errorf("Conf() = %q, want %q", conf, want)
+	}
+
+	if s := fpm.String(); s != "pm=dynamic,pm.max_children=10,pm.start_servers=3,pm.max_requests=500" {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestHostUser(t *testing.T) {
+	tests := []struct {
+		name string
+		goos string
+		uid  int
+		gid  int
+		want string
+	}{
+		{
+			name: "linux uses the uid and gid",
+			goos: "linux",
+			uid:  1000,
+			gid:  1000,
+			want: "1000:1000",
+		},
+		{
+			name: "macos uses the uid and gid",
+			goos: "darwin",
+			uid:  501,
+			gid:  20,
+			want: "501:20",
+		},
+		{
+			name: "windows does not use the host user",
+			goos: "windows",
+			uid:  -1,
+			gid:  -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hostUser(tt.goos, tt.uid, tt.gid); got != tt.want {
+				t.Errorf("hostUser() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSite_EarlyHintLinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		hints   []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "sites without hints do not have links",
+		},
+		{
+			name:  "hints are preloaded as the file type",
+			hints: []string{"/css/site.css", "/js/app.js?v=2", "/fonts/inter.woff2", "/images/hero.webp"},
+			want: []string{
+				"</css/site.css>; rel=preload; as=style",
+				"</js/app.js?v=2>; rel=preload; as=script",
+				"</fonts/inter.woff2>; rel=preload; as=font; crossorigin",
+				"</images/hero.webp>; rel=preload; as=image",
+			},
+		},
+		{
+			name:    "hints must be paths",
+			hints:   []string{"https://cdn.example.com/site.css"},
+			wantErr: true,
+		},
+		{
+			name:    "hints cannot include link syntax",
+			hints:   []string{"/css/site.css>; rel=preload"},
+			wantErr: true,
+		},
+		{
+			name:    "hints must be a known file type",
+			hints:   []string{"/index.php"},
+			wantErr: true,
+		},
+		{
+			name:    "hints cannot be used more than once",
+			hints:   []string{"/css/site.css", "/css/site.css"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Site{Hostname: "example.nitro", EarlyHints: tt.hints}
+
+			got, err := s.EarlyHintLinks()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EarlyHintLinks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EarlyHintLinks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSite_MaxRequestBodySize(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    string
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "sites without a size are not limited",
+		},
+		{
+			name: "sizes without a unit are bytes",
+			size: "1024",
+			want: 1024,
+		},
+		{
+			name: "kilobytes are supported",
+			size: "512k",
+			want: 512 * 1024,
+		},
+		{
+			name: "megabytes are supported",
+			size: "100m",
+			want: 100 * 1024 * 1024,
+		},
+		{
+			name: "units are case insensitive and can end with a b",
+			size: "2GB",
+			want: 2 * 1024 * 1024 * 1024,
+		},
+		{
+			name:    "unknown units are invalid",
+			size:    "100t",
+			wantErr: true,
+		},
+		{
+			name:    "sizes must be greater than 0",
+			size:    "0m",
+			wantErr: true,
+		},
+		{
+			name:    "sizes that overflow are invalid",
+			size:    "99999999999g",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Site{Hostname: "example.nitro", MaxRequestBody: tt.size}
+
+			got, err := s.MaxRequestBodySize()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MaxRequestBodySize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("MaxRequestBodySize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectFileName is the name of the optional project config. It is found by walking up
+// from the working directory and its sites are merged with the sites in the config file
+// in the home directory. The project takes precedence, so a project site replaces the
+// site with the same hostname in the home config. This lets a repository ship the site
+// definition for everyone working on the project. The path to the project config is
+// recorded in the home config, so the project sites are kept when nitro is used from
+// other directories.
+var ProjectFileName = ".nitro.yaml"
+
+// getwd returns the directory used to find the project config.
+var getwd = os.Getwd
+
+// projectSite is a site from a project config, marshalled to check for changes.
+type projectSite struct {
+	file string
+	data []byte
+}
+
+// Project represents the project config, which can only define sites.
+type Project struct {
+	Sites []Site `json:"sites,omitempty" yaml:"sites,omitempty"`
+}
+
+// FindProject walks up from the directory and returns the path to the first project
+// config, or an empty string if there is no project config.
+func FindProject(dir string) string {
+	for {
+		file := filepath.Join(dir, ProjectFileName)
+		if stat, err := os.Stat(file); err == nil && !stat.IsDir() {
+			return file
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+
+		dir = parent
+	}
+}
+
+// LoadProject reads and validates the project config. Site paths are relative to the
+// directory of the project config, and sites without a path use the directory.
+func LoadProject(file string) (*Project, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Project{}
+
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("unable to read the project config %s, %w", file, err)
+	}
+
+	// the file is empty
+	if len(doc.Content) == 0 {
+		return p, nil
+	}
+
+	// the home config is used for everything other than the sites
+	if root := doc.Content[0]; root.Kind == yaml.MappingNode {
+		for i := 0; i < len(root.Content); i += 2 {
+			if key := root.Content[i].Value; key != "sites" {
+				return nil, fmt.Errorf("the project config %s can only define sites, found %q on line %d", file, key, root.Content[i].Line)
+			}
+		}
+	}
+
+	// expand the environment variables in the supported fields
+	if err := interpolate(doc, false); err != nil {
+		return nil, fmt.Errorf("unable to read the project config %s, %w", file, err)
+	}
+
+	if err := doc.Decode(p); err != nil {
+		return nil, fmt.Errorf("unable to read the project config %s, %w", file, err)
+	}
+
+	dir := filepath.Dir(file)
+	seen := make(map[string]bool)
+	for i, s := range p.Sites {
+		if s.Hostname == "" {
+			return nil, fmt.Errorf("the project config %s has a site without a hostname", file)
+		}
+
+		if seen[s.Hostname] {
+			return nil, fmt.Errorf("the project config %s defines the site %s more than once", file, s.Hostname)
+		}
+		seen[s.Hostname] = true
+
+		switch {
+		case s.Path == "":
+			p.Sites[i].Path = dir
+		case !filepath.IsAbs(s.Path) && !strings.HasPrefix(s.Path, "~"):
+			p.Sites[i].Path = filepath.Join(dir, s.Path)
+		}
+	}
+
+	return p, nil
+}
+
+// mergeProject loads the project config and adds its sites to the config, replacing the
+// sites with the same hostname. It returns an error if a project site uses a hostname or
+// alias of a different site in the config.
+func (c *Config) mergeProject(file string) error {
+	p, err := LoadProject(file)
+	if err != nil {
+		return err
+	}
+
+	for _, ps := range p.Sites {
+		for _, s := range c.Sites {
+			if s.Hostname == ps.Hostname {
+				if s.Project != "" {
+					return fmt.Errorf("the site %s is defined in the project configs %s and %s", ps.Hostname, s.Project, file)
+				}
+
+				continue
+			}
+
+			for _, name := range siteNames(ps) {
+				for _, existing := range siteNames(s) {
+					if name == existing {
+						return fmt.Errorf("the site %s in the project config %s uses %s, which is already used by the site %s in %s", ps.Hostname, file, name, s.Hostname, c.File)
+					}
+				}
+			}
+		}
+	}
+
+	if c.projectSites == nil {
+		c.projectSites = make(map[string]projectSite)
+		c.overriddenSites = make(map[string]Site)
+	}
+
+	for _, ps := range p.Sites {
+		// keep the project site to check if it was changed before saving
+		data, err := yaml.Marshal(ps)
+		if err != nil {
+			return err
+		}
+		c.projectSites[ps.Hostname] = projectSite{file: file, data: data}
+
+		ps.Project = file
+
+		replaced := false
+		for i, s := range c.Sites {
+			if s.Hostname == ps.Hostname {
+				c.overriddenSites[s.Hostname] = s
+				c.Sites[i] = ps
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			c.Sites = append(c.Sites, ps)
+		}
+	}
+
+	return nil
+}
+
+// homeSites returns the sites to save in the config file in the home directory, which
+// are the sites without the project sites and with the sites the project replaced. It
+// returns an error if a project site was changed or removed, because the change has to
+// be made in the project config.
+func (c *Config) homeSites() ([]Site, error) {
+	if c.projectSites == nil {
+		return c.Sites, nil
+	}
+
+	var sites []Site
+	found := make(map[string]bool)
+	for _, s := range c.Sites {
+		project, ok := c.projectSites[s.Hostname]
+		if !ok {
+			sites = append(sites, s)
+			continue
+		}
+
+		found[s.Hostname] = true
+
+		data, err := yaml.Marshal(s)
+		if err != nil {
+			return nil, err
+		}
+
+		if string(data) != string(project.data) {
+			return nil, fmt.Errorf("the site %s is defined in the project config %s, make the change in the project config", s.Hostname, project.file)
+		}
+
+		if overridden, ok := c.overriddenSites[s.Hostname]; ok {
+			sites = append(sites, overridden)
+		}
+	}
+
+	for hostname, project := range c.projectSites {
+		if !found[hostname] {
+			return nil, fmt.Errorf("the site %s is defined in the project config %s, remove it from the project config", hostname, project.file)
+		}
+	}
+
+	return sites, nil
+}
+
+// ProjectAdded returns true when the project config for the working directory was
+// recorded while loading the config, and the config has to be saved to keep it.
+func (c *Config) ProjectAdded() bool {
+	return c.projectAdded
+}
+
+// recorded returns true if the project config is recorded in the config.
+func (c *Config) recorded(file string) bool {
+	for _, p := range c.Projects {
+		if p == file {
+			return true
+		}
+	}
+
+	return false
+}
+
+// siteNames returns the hostname and aliases of the site.
+func siteNames(s Site) []string {
+	return append([]string{s.Hostname}, s.Aliases...)
+}
@@ -0,0 +1,279 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// projectHome creates a home directory with the config and a project directory with the
+// project config, and changes the working directory used to find the project to a
+// directory in the project.
+func projectHome(t *testing.T, config, project string) (string, string, func()) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "nitro-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	home := filepath.Join(dir, "home")
+	if err := os.MkdirAll(filepath.Join(home, DirectoryName), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(home, DirectoryName, FileName), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	projectDir := filepath.Join(dir, "project")
+	if err := os.MkdirAll(filepath.Join(projectDir, "web", "assets"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(projectDir, ProjectFileName), []byte(project), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wd := getwd
+	getwd = func() (string, error) { return filepath.Join(projectDir, "web", "assets"), nil }
+
+	return home, projectDir, func() {
+		getwd = wd
+		os.RemoveAll(dir)
+	}
+}
+
+func TestFindProject(t *testing.T) {
+	_, projectDir, cleanup := projectHome(t, "sites: []\n", "sites: []\n")
+	defer cleanup()
+
+	want := filepath.Join(projectDir, ProjectFileName)
+	if got := FindProject(filepath.Join(projectDir, "web", "assets")); got != want {
+		t.Errorf("FindProject() = %q, want %q", got, want)
+	}
+
+	if got := FindProject(filepath.Dir(projectDir)); got != "" {
+		t.Errorf("expected no project config above the project, got %q", got)
+	}
+}
+
+func TestLoadProject(t *testing.T) {
+	tests := []struct {
+		name      string
+		project   string
+		wantPaths []string
+		wantErr   string
+	}{
+		{
+			name:      "paths are relative to the project config",
+			project:   "sites:\n  - hostname: app.nitro\n  - hostname: docs.nitro\n    path: docs\n  - hostname: api.nitro\n    path: /srv/api\n",
+			wantPaths: []string{"", "docs", "/srv/api"},
+		},
+		{
+			name:    "the project config can only define sites",
+			project: "sites: []\nhttp3: true\n",
+			wantErr: `can only define sites, found "http3"`,
+		},
+		{
+			name:    "sites require a hostname",
+			project: "sites:\n  - path: docs\n",
+			wantErr: "without a hostname",
+		},
+		{
+			name:    "sites cannot be defined more than once",
+			project: "sites:\n  - hostname: app.nitro\n  - hostname: app.nitro\n",
+			wantErr: "defines the site app.nitro more than once",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, projectDir, cleanup := projectHome(t, "sites: []\n", tt.project)
+			defer cleanup()
+
+			p, err := LoadProject(filepath.Join(projectDir, ProjectFileName))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected the error to contain %q, got %v", tt.wantErr, err)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, want := range tt.wantPaths {
+				if !filepath.IsAbs(want) {
+					want = filepath.Join(projectDir, want)
+				}
+
+				if p.Sites[i].Path != want {
+					t.Errorf("expected the path of %s to be %q, got %q", p.Sites[i].Hostname, want, p.Sites[i].Path)
+				}
+			}
+		})
+	}
+}
+
+func TestLoad_MergesTheProject(t *testing.T) {
+	config := `sites:
+    - hostname: app.nitro
+      path: ~/dev/app
+      version: "7.4"
+      webroot: web
+    - hostname: other.nitro
+      path: ~/dev/other
+      version: "8.0"
+      webroot: web
+`
+	project := `sites:
+    - hostname: app.nitro
+      version: "8.0"
+      webroot: web
+    - hostname: docs.nitro
+      path: docs
+      version: "8.0"
+      webroot: public
+`
+	home, projectDir, cleanup := projectHome(t, config, project)
+	defer cleanup()
+
+	cfg, err := Load(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Project != filepath.Join(projectDir, ProjectFileName) {
+		t.Errorf("expected the project to be %q, got %q", filepath.Join(projectDir, ProjectFileName), cfg.Project)
+	}
+
+	if len(cfg.Sites) != 3 {
+		t.Fatalf("expected 3 sites, got %d", len(cfg.Sites))
+	}
+
+	app, err := cfg.FindSiteByHostName("app.nitro")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the project takes precedence
+	if app.Version != "8.0" || app.Path != projectDir {
+		t.Errorf("expected the project site to replace the site, got version %q and path %q", app.Version, app.Path)
+	}
+
+	// changes to other sites are saved without the project sites
+	if err := cfg.SetSiteAlias("other.nitro", "alias.nitro"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(cfg.File)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved := string(data)
+	if strings.Contains(saved, "docs.nitro") || strings.Contains(saved, "path: "+projectDir) {
+		t.Errorf("expected the project sites to not be saved in the config, got:\n%s", saved)
+	}
+
+	if !strings.Contains(saved, filepath.Join(projectDir, ProjectFileName)) {
+		t.Errorf("expected the project config to be recorded in the config, got:\n%s", saved)
+	}
+
+	if !strings.Contains(saved, "~/dev/app") || !strings.Contains(saved, "alias.nitro") {
+		t.Errorf("expected the config to keep the replaced site and the change, got:\n%s", saved)
+	}
+
+	// changes to project sites are made in the project config
+	if err := cfg.EnableXdebug("docs.nitro"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cfg.Save(); err == nil || !strings.Contains(err.Error(), "make the change in the project config") {
+		t.Errorf("expected saving a changed project site to fail, got %v", err)
+	}
+}
+
+func TestLoad_KeepsTheRecordedProjects(t *testing.T) {
+	project := `sites:
+    - hostname: docs.nitro
+      version: "8.0"
+      webroot: web
+`
+	home, projectDir, cleanup := projectHome(t, "sites: []\n", project)
+	defer cleanup()
+
+	cfg, err := Load(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !cfg.ProjectAdded() {
+		t.Fatal("expected the project config to be added")
+	}
+
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// load the config outside of the project
+	getwd = func() (string, error) { return home, nil }
+
+	cfg, err = Load(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.ProjectAdded() || cfg.Project != "" {
+		t.Errorf("expected the project config to be recorded, got %q", cfg.Project)
+	}
+
+	docs, err := cfg.FindSiteByHostName("docs.nitro")
+	if err != nil {
+		t.Fatalf("expected the project site outside of the project, %v", err)
+	}
+
+	if want := filepath.Join(projectDir, ProjectFileName); docs.Project != want {
+		t.Errorf("expected the site project to be %q, got %q", want, docs.Project)
+	}
+
+	// removed project configs are no longer recorded
+	if err := os.Remove(filepath.Join(projectDir, ProjectFileName)); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err = Load(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cfg.Projects) != 0 || len(cfg.Sites) != 0 {
+		t.Errorf("expected the removed project to be dropped, got %v and %d sites", cfg.Projects, len(cfg.Sites))
+	}
+}
+
+func TestLoad_ProjectConflicts(t *testing.T) {
+	config := `sites:
+    - hostname: app.nitro
+      aliases:
+        - docs.nitro
+      path: ~/dev/app
+`
+	project := `sites:
+    - hostname: docs.nitro
+`
+	home, _, cleanup := projectHome(t, config, project)
+	defer cleanup()
+
+	_, err := Load(home)
+	if err == nil || !strings.Contains(err.Error(), "already used by the site app.nitro") {
+		t.Errorf("expected a conflict with the alias, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package containercopy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/archive"
+)
+
+// To copies the file or directory on the host at src into the container at dst and returns the
+// number of bytes transferred. The dst must be an absolute path, if it is an existing directory
+// src is copied into the directory, otherwise src is copied to dst.
+func To(ctx context.Context, docker client.ContainerAPIClient, id, src, dst string) (int64, error) {
+	if !path.IsAbs(dst) {
+		return 0, fmt.Errorf("the container path %q must be absolute", dst)
+	}
+
+	// prepare the source on the host
+	srcInfo, err := archive.CopyInfoSourcePath(src, true)
+	if err != nil {
+		return 0, fmt.Errorf("unable to find %s, %w", src, err)
+	}
+
+	srcArchive, err := archive.TarResource(srcInfo)
+	if err != nil {
+		return 0, fmt.Errorf("unable to archive %s, %w", src, err)
+	}
+	defer srcArchive.Close()
+
+	// check if the destination exists in the container
+	dstInfo := archive.CopyInfo{Path: dst}
+	stat, err := docker.ContainerStatPath(ctx, id, dst)
+	if err == nil {
+		dstInfo.Exists, dstInfo.IsDir = true, stat.Mode.IsDir()
+	}
+
+	// copying a directory onto an existing file is not possible
+	if srcInfo.IsDir && dstInfo.Exists && !dstInfo.IsDir {
+		return 0, fmt.Errorf("unable to copy the directory %s to the file %s", src, dst)
+	}
+
+	dstDir, content, err := archive.PrepareArchiveCopy(srcArchive, srcInfo, dstInfo)
+	if err != nil {
+		return 0, err
+	}
+	defer content.Close()
+
+	r := &counter{r: content}
+	if err := docker.CopyToContainer(ctx, id, dstDir, r, types.CopyToContainerOptions{AllowOverwriteDirWithFile: false}); err != nil {
+		return 0, fmt.Errorf("unable to copy to the container, %w", err)
+	}
+
+	return r.n, nil
+}
+
+// From copies the file or directory in the container at src to dst on the host and returns
+// the number of bytes transferred. The src must be an absolute path.
+func From(ctx context.Context, docker client.ContainerAPIClient, id, src, dst string) (int64, error) {
+	if !path.IsAbs(src) {
+		return 0, fmt.Errorf("the container path %q must be absolute", src)
+	}
+
+	content, stat, err := docker.CopyFromContainer(ctx, id, src)
+	if err != nil {
+		return 0, fmt.Errorf("unable to copy from the container, %w", err)
+	}
+	defer content.Close()
+
+	srcInfo := archive.CopyInfo{
+		Path:   src,
+		Exists: true,
+		IsDir:  stat.Mode.IsDir(),
+	}
+
+	r := &counter{r: content}
+	if err := archive.CopyTo(r, srcInfo, dst); err != nil {
+		return 0, fmt.Errorf("unable to copy to %s, %w", dst, err)
+	}
+
+	return r.n, nil
+}
+
+// counter is used to count the bytes read from the archive.
+type counter struct {
+	r io.Reader
+	n int64
+}
+
+func (c *counter) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+
+	return n, err
+}
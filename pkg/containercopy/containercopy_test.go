@@ -0,0 +1,183 @@
+package containercopy
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+type mockContainerClient struct {
+	client.ContainerAPIClient
+
+	// stat is returned for the destination, a nil stat means the path does not exist
+	stat *types.ContainerPathStat
+	// archive is returned when copying from the container
+	archive []byte
+
+	copiedTo   string
+	copiedTar  bytes.Buffer
+	copiedFrom string
+}
+
+func (c *mockContainerClient) ContainerStatPath(ctx context.Context, container, path string) (types.ContainerPathStat, error) {
+	if c.stat == nil {
+		return types.ContainerPathStat{}, errors.New("no such file or directory")
+	}
+
+	return *c.stat, nil
+}
+
+func (c *mockContainerClient) CopyToContainer(ctx context.Context, container, path string, content io.Reader, options types.CopyToContainerOptions) error {
+	c.copiedTo = path
+	_, err := io.Copy(&c.copiedTar, content)
+
+	return err
+}
+
+func (c *mockContainerClient) CopyFromContainer(ctx context.Context, container, path string) (io.ReadCloser, types.ContainerPathStat, error) {
+	c.copiedFrom = path
+
+	return ioutil.NopCloser(bytes.NewReader(c.archive)), types.ContainerPathStat{Name: filepath.Base(path)}, nil
+}
+
+func tarNames(t *testing.T, b []byte) []string {
+	var names []string
+	tr := tar.NewReader(bytes.NewReader(b))
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		names = append(names, h.Name)
+	}
+
+	return names
+}
+
+func TestTo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-copy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "fixtures.sql")
+	if err := ioutil.WriteFile(src, []byte("select 1;"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		dst       string
+		stat      *types.ContainerPathStat
+		wantDir   string
+		wantNames []string
+		wantErr   bool
+	}{
+		{
+			name:      "files are copied into existing directories",
+			dst:       "/app/storage",
+			stat:      &types.ContainerPathStat{Name: "storage", Mode: os.ModeDir},
+			wantDir:   "/app/storage",
+			wantNames: []string{"fixtures.sql"},
+		},
+		{
+			name:      "files are renamed when the destination does not exist",
+			dst:       "/app/storage/seed.sql",
+			wantDir:   "/app/storage",
+			wantNames: []string{"seed.sql"},
+		},
+		{
+			name:    "relative container paths return an error",
+			dst:     "app/storage",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docker := &mockContainerClient{stat: tt.stat}
+
+			n, err := To(context.TODO(), docker, "id", src, tt.dst)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("To() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if n != int64(docker.copiedTar.Len()) {
+				t.Errorf("expected %d bytes to be transferred, got %d", docker.copiedTar.Len(), n)
+			}
+
+			if docker.copiedTo != tt.wantDir {
+				t.Errorf("expected the archive to be copied to %q, got %q", tt.wantDir, docker.copiedTo)
+			}
+
+			names := tarNames(t, docker.copiedTar.Bytes())
+			if len(names) != len(tt.wantNames) || names[0] != tt.wantNames[0] {
+				t.Errorf("expected the archive to contain %v, got %v", tt.wantNames, names)
+			}
+		})
+	}
+}
+
+func TestFrom(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-copy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// create the archive the container would return
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	content := []byte("error log")
+	if err := tw.WriteHeader(&tar.Header{Name: "web.log", Mode: 0644, Size: int64(len(content))}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	docker := &mockContainerClient{archive: buf.Bytes()}
+
+	dst := filepath.Join(dir, "copied.log")
+	n, err := From(context.TODO(), docker, "id", "/app/storage/logs/web.log", dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != int64(buf.Len()) {
+		t.Errorf("expected %d bytes to be transferred, got %d", buf.Len(), n)
+	}
+
+	b, err := ioutil.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(b, content) {
+		t.Errorf("expected the file to contain %q, got %q", content, b)
+	}
+
+	if _, err := From(context.TODO(), docker, "id", "logs/web.log", dst); err == nil {
+		t.Error("expected an error for a relative container path")
+	}
+}
@@ -0,0 +1,121 @@
+package containerfind
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+)
+
+// ErrNotFound is returned when a single container is expected but none are found
+var ErrNotFound = errors.New("unable to find the container")
+
+// All returns all of the Nitro containers sorted by name. If all is false,
+// only running containers are returned.
+func All(ctx context.Context, docker client.ContainerAPIClient, all bool) ([]types.Container, error) {
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro)
+
+	return list(ctx, docker, filter, all)
+}
+
+// Databases returns all of the database containers, running or stopped, sorted by name.
+func Databases(ctx context.Context, docker client.ContainerAPIClient) ([]types.Container, error) {
+	return DatabasesByCompatibility(ctx, docker, "")
+}
+
+// DatabasesByCompatibility returns the database containers that are compatible with the
+// engine (e.g. mysql or postgres). If the compatibility is empty, all of the database
+// containers are returned.
+func DatabasesByCompatibility(ctx context.Context, docker client.ContainerAPIClient, compatibility string) ([]types.Container, error) {
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro)
+	filter.Add("label", containerlabels.Type+"=database")
+
+	if compatibility != "" {
+		filter.Add("label", containerlabels.DatabaseCompatibility+"="+compatibility)
+	}
+
+	return list(ctx, docker, filter, true)
+}
+
+// Custom returns all of the custom containers, running or stopped, sorted by name.
+func Custom(ctx context.Context, docker client.ContainerAPIClient) ([]types.Container, error) {
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro)
+	filter.Add("label", containerlabels.Type+"=custom")
+
+	return list(ctx, docker, filter, true)
+}
+
+// Site returns the container for the site hostname, running or stopped. It returns
+// ErrNotFound if there is no container for the site.
+func Site(ctx context.Context, docker client.ContainerAPIClient, hostname string) (*types.Container, error) {
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro)
+	filter.Add("label", containerlabels.Host+"="+hostname)
+
+	return first(ctx, docker, filter)
+}
+
+// Proxy returns the proxy container, running or stopped. It returns ErrNotFound
+// if there is no proxy container.
+func Proxy(ctx context.Context, docker client.ContainerAPIClient) (*types.Container, error) {
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro)
+	filter.Add("label", containerlabels.Type+"=proxy")
+
+	return first(ctx, docker, filter)
+}
+
+// Name returns the name of the container without the leading slash.
+func Name(c types.Container) string {
+	if len(c.Names) == 0 {
+		return ""
+	}
+
+	return strings.TrimLeft(c.Names[0], "/")
+}
+
+// Names returns the names of the containers without the leading slash.
+func Names(containers []types.Container) []string {
+	var names []string
+	for _, c := range containers {
+		names = append(names, Name(c))
+	}
+
+	return names
+}
+
+func first(ctx context.Context, docker client.ContainerAPIClient, filter filters.Args) (*types.Container, error) {
+	containers, err := list(ctx, docker, filter, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(containers) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return &containers[0], nil
+}
+
+func list(ctx context.Context, docker client.ContainerAPIClient, filter filters.Args, all bool) ([]types.Container, error) {
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: all, Filters: filter})
+	if err != nil {
+		return nil, err
+	}
+
+	// sort containers by the name
+	sort.SliceStable(containers, func(i, j int) bool {
+		return Name(containers[i]) < Name(containers[j])
+	})
+
+	return containers, nil
+}
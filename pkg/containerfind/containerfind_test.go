@@ -0,0 +1,110 @@
+package containerfind
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+)
+
+type mockDockerClient struct {
+	client.ContainerAPIClient
+	containers []types.Container
+	options    []types.ContainerListOptions
+}
+
+func (c *mockDockerClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	c.options = append(c.options, options)
+
+	return c.containers, nil
+}
+
+func TestDatabasesByCompatibility(t *testing.T) {
+	docker := &mockDockerClient{
+		containers: []types.Container{
+			{ID: "postgres", Names: []string{"/postgres-13-5432.database.nitro"}},
+			{ID: "mysql", Names: []string{"/mysql-8.0-3306.database.nitro"}},
+		},
+	}
+
+	got, err := DatabasesByCompatibility(context.TODO(), docker, "mysql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the containers are sorted by name
+	if names := Names(got); !reflect.DeepEqual(names, []string{"mysql-8.0-3306.database.nitro", "postgres-13-5432.database.nitro"}) {
+		t.Errorf("expected the containers to be sorted by name, got %v", names)
+	}
+
+	wantFilter := filters.NewArgs()
+	wantFilter.Add("label", containerlabels.Nitro)
+	wantFilter.Add("label", containerlabels.Type+"=database")
+	wantFilter.Add("label", containerlabels.DatabaseCompatibility+"=mysql")
+
+	if !reflect.DeepEqual(docker.options[0].Filters, wantFilter) {
+		t.Errorf("expected the filter %v, got %v", wantFilter, docker.options[0].Filters)
+	}
+
+	if !docker.options[0].All {
+		t.Errorf("expected stopped containers to be included")
+	}
+}
+
+func TestAll(t *testing.T) {
+	docker := &mockDockerClient{}
+
+	if _, err := All(context.TODO(), docker, false); err != nil {
+		t.Fatal(err)
+	}
+
+	wantFilter := filters.NewArgs()
+	wantFilter.Add("label", containerlabels.Nitro)
+
+	if !reflect.DeepEqual(docker.options[0].Filters, wantFilter) {
+		t.Errorf("expected the filter %v, got %v", wantFilter, docker.options[0].Filters)
+	}
+
+	if docker.options[0].All {
+		t.Errorf("expected only running containers")
+	}
+}
+
+func TestProxy(t *testing.T) {
+	tests := []struct {
+		name       string
+		containers []types.Container
+		want       *types.Container
+		wantErr    error
+	}{
+		{
+			name:       "returns the proxy container",
+			containers: []types.Container{{ID: "proxy", Names: []string{"/nitro-proxy"}}},
+			want:       &types.Container{ID: "proxy", Names: []string{"/nitro-proxy"}},
+		},
+		{
+			name:    "no containers returns not found",
+			wantErr: ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docker := &mockDockerClient{containers: tt.containers}
+
+			got, err := Proxy(context.TODO(), docker)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Proxy() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Proxy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
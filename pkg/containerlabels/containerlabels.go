@@ -32,6 +32,15 @@ const (
 	// Extensions is used for a list of comma seperated extensions for a site
 	Extensions = "com.craftcms.nitro.extensions"
 
+	// Index is the PHP front controller for the site, it is only set when the site does not use index.php
+	Index = "com.craftcms.nitro.index"
+
+	// FPM is the PHP-FPM process manager settings for the site, it is only set when the site changes the image defaults
+	FPM = "com.craftcms.nitro.fpm"
+
+	// User is the uid and gid of the host user PHP runs as, it is only set when the host_user option is enabled
+	User = "com.craftcms.nitro.user"
+
 	// Host is used to identify a web application by the hostname of the site (e.g demo.nitro)
 	Host = "com.craftcms.nitro.host"
 
@@ -44,6 +53,9 @@ const (
 	// Volume is used to identify a volume for an environment
 	Volume = "com.craftcms.nitro.volume"
 
+	// Project is the path to the project config that defines the site, it is only set for project sites
+	Project = "com.craftcms.nitro.project"
+
 	// Proxy is the label used to identify the proxy container
 	Proxy = "com.craftcms.nitro.proxy"
 
@@ -65,6 +77,21 @@ func ForSite(s config.Site) map[string]string {
 		Webroot: s.Webroot,
 	}
 
+	// only label sites with a custom front controller
+	if s.Index != "" {
+		labels[Index] = s.Index
+	}
+
+	// only label sites from a project config
+	if s.Project != "" {
+		labels[Project] = s.Project
+	}
+
+	// only label sites with process manager settings
+	if fpm := s.FPM.String(); fpm != "" {
+		labels[FPM] = fpm
+	}
+
 	// if there are extensions, add them as comma separated
 	if len(s.Extensions) > 0 {
 		labels[Extensions] = strings.Join(s.Extensions, ",")
@@ -20,50 +20,138 @@ import (
 // ErrUnknownDatabaseEngine is returned when we are unable to determine the engine type from a database backup file.
 var ErrUnknownDatabaseEngine = fmt.Errorf("unknown database engine detected from file")
 
-// DetermineEngine takes a file and will check if the
-// content of the file is for mysql or postgres db
-// imports. It will return the engine "mysql" or
-// "postgres" if it can determine the engine.
-// If it cannot, it will return an error.
-func DetermineEngine(file string) (string, error) {
+// Signal is a token found in a backup that is specific to a database engine.
+type Signal struct {
+	Engine string
+	Token  string
+}
+
+// signals are the tokens used to score the engine of a backup, each token is
+// only counted once regardless of how many times it appears in the backup.
+var signals = []Signal{
+	{Engine: "mysql", Token: "MySQL"},
+	{Engine: "mysql", Token: "mysqldump"},
+	{Engine: "mysql", Token: "MariaDB"},
+	{Engine: "mysql", Token: "mariadb"},
+	{Engine: "mysql", Token: "ENGINE=InnoDB"},
+	{Engine: "mysql", Token: "AUTO_INCREMENT"},
+	{Engine: "mysql", Token: "LOCK TABLES"},
+	{Engine: "mysql", Token: "/*!40"},
+	{Engine: "postgres", Token: "PostgreSQL"},
+	{Engine: "postgres", Token: "pg_dump"},
+	{Engine: "postgres", Token: "OWNER TO"},
+	{Engine: "postgres", Token: "SEQUENCE"},
+	{Engine: "postgres", Token: "pg_catalog"},
+	{Engine: "postgres", Token: "search_path"},
+	{Engine: "postgres", Token: "FROM stdin"},
+}
+
+// ambiguousConfidence is the confidence below which a detection should be
+// confirmed with the user.
+const ambiguousConfidence = 0.75
+
+// Detection is the result of scoring a backup for each database engine.
+type Detection struct {
+	// Engine is the engine with the highest score, it is empty when the
+	// scores are tied.
+	Engine string
+
+	// MySQL and Postgres are the number of signals found for each engine.
+	MySQL    int
+	Postgres int
+
+	// Signals are the engine specific tokens that were found.
+	Signals []Signal
+}
+
+// Confidence returns the share of signals, between 0 and 1, that belong to
+// the detected engine.
+func (d Detection) Confidence() float64 {
+	total := d.MySQL + d.Postgres
+	if total == 0 || d.Engine == "" {
+		return 0
+	}
+
+	if d.Engine == "postgres" {
+		return float64(d.Postgres) / float64(total)
+	}
+
+	return float64(d.MySQL) / float64(total)
+}
+
+// Ambiguous returns true when the backup has signals for more than one engine
+// and the detected engine should be confirmed.
+func (d Detection) Ambiguous() bool {
+	return d.Confidence() < ambiguousConfidence
+}
+
+// DetermineEngine takes a file and will score the
+// content of the file for mysql and postgres db
+// imports using engine specific tokens. It will
+// return the detection with the engine "mysql" or
+// "postgres" and the signals that were found. If no
+// signals are found, it will return an error.
+func DetermineEngine(file string) (Detection, error) {
+	// custom format archives are binary and only used by postgres
+	if ArchiveFormat(file) == "custom" {
+		return Detection{Engine: "postgres", Postgres: 1, Signals: []Signal{{Engine: "postgres", Token: "PGDMP"}}}, nil
+	}
+
 	f, err := os.Open(file)
 	if err != nil {
-		return "", err
+		return Detection{}, err
 	}
 	defer f.Close()
 
-	engine := ""
+	var d Detection
+	found := make(map[Signal]bool)
 	line := 1
 
 	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
 	for s.Scan() {
 		txt := s.Text()
 
-		// check if its postgres
-		if strings.Contains(txt, "PostgreSQL") || strings.Contains(txt, "pg_dump") {
-			engine = "postgres"
-			break
-		}
+		for _, sig := range signals {
+			if found[sig] || !strings.Contains(txt, sig.Token) {
+				continue
+			}
 
-		// check if its mysql
-		if strings.Contains(txt, "MySQL") || strings.Contains(txt, "mysqldump") || strings.Contains(txt, "mariadb") || strings.Contains(txt, "MariaDB") || strings.Contains(txt, "ENGINE=InnoDB") {
-			engine = "mysql"
-			break
+			found[sig] = true
+			d.Signals = append(d.Signals, sig)
+
+			switch sig.Engine {
+			case "postgres":
+				d.Postgres++
+			default:
+				d.MySQL++
+			}
 		}
 
-		if line >= 50 || engine != "" {
+		if line >= 500 {
 			break
 		}
 
 		line++
 	}
 
-	// final check for empty engine
-	if engine == "" {
-		return "", ErrUnknownDatabaseEngine
+	if err := s.Err(); err != nil {
+		return Detection{}, fmt.Errorf("unable to read the backup, %w", err)
+	}
+
+	// final check for no signals
+	if d.MySQL == 0 && d.Postgres == 0 {
+		return Detection{}, ErrUnknownDatabaseEngine
 	}
 
-	return engine, nil
+	switch {
+	case d.MySQL > d.Postgres:
+		d.Engine = "mysql"
+	case d.Postgres > d.MySQL:
+		d.Engine = "postgres"
+	}
+
+	return d, nil
 }
 
 // HasCreateStatement takes a file and will determine
@@ -227,3 +315,22 @@ func PrepareArchiveFromPath(path string) (io.Reader, string, error) {
 
 	return reader, name, err
 }
+
+// MySQLGrants returns the commands, run in a mysql container, that let the nitro user create
+// and access every database. The images only grant the user access to the nitro database.
+func MySQLGrants(version string) [][]string {
+	commands := [][]string{
+		{"mysql", "-uroot", "-pnitro", fmt.Sprintf(`-e CREATE USER IF NOT EXISTS '%s'@'%s' IDENTIFIED BY 'nitro';`, "nitro", "localhost")},
+		{"mysql", "-uroot", "-pnitro", fmt.Sprintf(`-e GRANT ALL PRIVILEGES ON *.* TO '%s'@'%s' WITH GRANT OPTION;`, "nitro", "%")},
+		{"mysql", "-uroot", "-pnitro", fmt.Sprintf(`-e GRANT ALL PRIVILEGES ON *.* TO '%s'@'%s' WITH GRANT OPTION;`, "nitro", "localhost")},
+		{"mysql", "-uroot", "-pnitro", `-e FLUSH PRIVILEGES;`},
+	}
+
+	// for mysql 8.0 images
+	// ALTER USER ‘username’@‘ip_address’ IDENTIFIED WITH mysql_native_password BY ‘password’
+	if strings.Contains(version, "8.0") {
+		commands = append(commands, []string{"mysql", "-uroot", "-pnitro", fmt.Sprintf(`-e ALTER USER '%s'@'%s' IDENTIFIED WITH mysql_native_password BY 'nitro';`, "nitro", "%")})
+	}
+
+	return commands
+}
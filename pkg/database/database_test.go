@@ -1,6 +1,10 @@
 package database
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -9,28 +13,58 @@ func TestDetermineEngine(t *testing.T) {
 		file string
 	}
 	tests := []struct {
-		name    string
-		args    args
-		want    string
-		wantErr bool
+		name          string
+		args          args
+		want          string
+		wantMySQL     int
+		wantPostgres  int
+		wantAmbiguous bool
+		wantErr       bool
 	}{
 		{
-			name:    "can detect mysql database backup files from phpmyadmin",
-			args:    args{file: "./testdata/phpmyadmin-backup.sql"},
-			want:    "mysql",
-			wantErr: false,
+			name:      "can detect mysql database backup files from phpmyadmin",
+			args:      args{file: "./testdata/phpmyadmin-backup.sql"},
+			want:      "mysql",
+			wantMySQL: 2,
+			wantErr:   false,
 		},
 		{
-			name:    "can detect mysql database backup files",
-			args:    args{file: "./testdata/mysql-backup.sql"},
-			want:    "mysql",
-			wantErr: false,
+			name:      "can detect mysql database backup files",
+			args:      args{file: "./testdata/mysql-backup.sql"},
+			want:      "mysql",
+			wantMySQL: 2,
+			wantErr:   false,
 		},
 		{
-			name:    "can detect postgres database backup files",
-			args:    args{file: "./testdata/postgres-backup.sql"},
-			want:    "postgres",
-			wantErr: false,
+			name:         "can detect postgres database backup files",
+			args:         args{file: "./testdata/postgres-backup.sql"},
+			want:         "postgres",
+			wantPostgres: 4,
+			wantErr:      false,
+		},
+		{
+			name:         "can detect postgres custom format archives",
+			args:         args{file: "./testdata/postgres-custom.dump"},
+			want:         "postgres",
+			wantPostgres: 1,
+			wantErr:      false,
+		},
+		{
+			name:         "backups with mostly mysql signals are not ambiguous",
+			args:         args{file: "./testdata/mostly-mysql-backup.sql"},
+			want:         "mysql",
+			wantMySQL:    4,
+			wantPostgres: 1,
+			wantErr:      false,
+		},
+		{
+			name:          "backups with tied signals are ambiguous",
+			args:          args{file: "./testdata/mixed-backup.sql"},
+			want:          "",
+			wantMySQL:     2,
+			wantPostgres:  2,
+			wantAmbiguous: true,
+			wantErr:       false,
 		},
 		{
 			name:    "non mysql or postgres files return an error",
@@ -46,13 +80,37 @@ func TestDetermineEngine(t *testing.T) {
 				t.Errorf("DetermineEngine() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if got != tt.want {
-				t.Errorf("DetermineEngine() got = %v, want %v", got, tt.want)
+			if got.Engine != tt.want {
+				t.Errorf("DetermineEngine() got = %v, want %v", got.Engine, tt.want)
+			}
+			if got.MySQL != tt.wantMySQL || got.Postgres != tt.wantPostgres {
+				t.Errorf("DetermineEngine() scores = mysql %d postgres %d, want mysql %d postgres %d (signals %v)", got.MySQL, got.Postgres, tt.wantMySQL, tt.wantPostgres, got.Signals)
+			}
+			if !tt.wantErr && got.Ambiguous() != tt.wantAmbiguous {
+				t.Errorf("DetermineEngine() ambiguous = %v, want %v (confidence %.2f)", got.Ambiguous(), tt.wantAmbiguous, got.Confidence())
 			}
 		})
 	}
 }
 
+func TestDetermineEngineReturnsReadErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-determine-engine-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// a line longer than the scanner buffer can't be read
+	file := filepath.Join(dir, "backup.sql")
+	if err := ioutil.WriteFile(file, []byte("-- MySQL dump\n"+strings.Repeat("a", 2*1024*1024)+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := DetermineEngine(file); err == nil || !strings.Contains(err.Error(), "unable to read the backup") {
+		t.Errorf("expected a read error, got %v", err)
+	}
+}
+
 func TestHasCreateStatement(t *testing.T) {
 	type args struct {
 		file string
@@ -95,3 +153,26 @@ func TestHasCreateStatement(t *testing.T) {
 		})
 	}
 }
+
+func TestMySQLGrants(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    int
+	}{
+		{name: "mysql 5.7 grants the nitro user every database", version: "5.7", want: 4},
+		{name: "mysql 8.0 also uses the native password", version: "8.0", want: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MySQLGrants(tt.version)
+			if len(got) != tt.want {
+				t.Fatalf("expected %d commands, got %d", tt.want, len(got))
+			}
+
+			if got[1][3] != `-e GRANT ALL PRIVILEGES ON *.* TO 'nitro'@'%' WITH GRANT OPTION;` {
+				t.Errorf("expected the nitro user to be granted every database, got %q", got[1][3])
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"bufio"
+	"compress/bzip2"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/ulikunitz/xz"
+
+	"github.com/craftcms/nitro/pkg/filetype"
+	"github.com/craftcms/nitro/pkg/tempdir"
+)
+
+// Decompress takes a file and will check if the file is compressed with bzip2
+// or xz, which the api does not support. If it is, it will decompress the file
+// into a temporary file and return the path to the new file along with the
+// compression. If the file is not compressed with bzip2 or xz, it returns the
+// original file and an empty compression. The temporary file is created in dir,
+// or the default temp directory when dir is empty.
+func Decompress(file, dir string) (string, string, error) {
+	// other types, including unknown types, are checked by the import
+	detail, err := filetype.DetermineDetailed(file)
+	if err != nil || (detail.Kind != "bzip2" && detail.Kind != "xz") {
+		return file, "", nil
+	}
+
+	src, err := os.Open(file)
+	if err != nil {
+		return "", "", err
+	}
+	defer src.Close()
+
+	var r io.Reader
+	switch detail.Kind {
+	case "bzip2":
+		r = bzip2.NewReader(bufio.NewReader(src))
+	default:
+		r, err = xz.NewReader(bufio.NewReader(src))
+		if err != nil {
+			return "", "", fmt.Errorf("unable to read the xz file, %w", err)
+		}
+	}
+
+	temp, err := ioutil.TempFile(tempdir.Dir(dir), "nitro-import-decompressed-")
+	if err != nil {
+		return "", "", err
+	}
+	defer temp.Close()
+
+	if _, err := io.Copy(temp, r); err != nil {
+		os.Remove(temp.Name())
+		return "", "", fmt.Errorf("unable to decompress the %s file, %w", detail.Kind, err)
+	}
+
+	return temp.Name(), detail.Kind, nil
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestDecompress(t *testing.T) {
+	expected, err := ioutil.ReadFile("./testdata/mysql-backup.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		file            string
+		wantCompression string
+	}{
+		{
+			name:            "bzip2 files are decompressed",
+			file:            "./testdata/mysql-backup.sql.bz2",
+			wantCompression: "bzip2",
+		},
+		{
+			name:            "xz files are decompressed",
+			file:            "./testdata/mysql-backup.sql.xz",
+			wantCompression: "xz",
+		},
+		{
+			name: "plain files are not changed",
+			file: "./testdata/mysql-backup.sql",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, compression, err := Decompress(tt.file, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if compression != tt.wantCompression {
+				t.Errorf("Decompress() compression = %q, want %q", compression, tt.wantCompression)
+			}
+
+			if tt.wantCompression == "" {
+				if got != tt.file {
+					t.Errorf("expected the original file %q, got %q", tt.file, got)
+				}
+
+				return
+			}
+			defer os.Remove(got)
+
+			content, err := ioutil.ReadFile(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(content, expected) {
+				t.Errorf("expected the decompressed file to match the backup")
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package database
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/craftcms/nitro/pkg/tempdir"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// DetectEncoding takes a file and checks for a byte order mark
+// to determine if the file is UTF-16 (little or big endian) or
+// UTF-8 with a BOM. If there is no byte order mark, it will
+// return an empty string.
+func DetectEncoding(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	head := make([]byte, 3)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	head = head[:n]
+
+	switch {
+	case bytes.HasPrefix(head, bomUTF8):
+		return "utf-8-bom", nil
+	case bytes.HasPrefix(head, bomUTF16LE):
+		return "utf-16le", nil
+	case bytes.HasPrefix(head, bomUTF16BE):
+		return "utf-16be", nil
+	}
+
+	return "", nil
+}
+
+// ConvertToUTF8 takes a file and will check the encoding of the file. If the
+// file is UTF-16 or has a byte order mark, it will transcode the file into a
+// temporary UTF-8 file, without the byte order mark, and return the path to
+// the new file along with the detected encoding. If no conversion is needed,
+// it returns the original file and an empty encoding. The temporary file is
+// created in dir, or the default temp directory when dir is empty.
+func ConvertToUTF8(file, dir string) (string, string, error) {
+	encoding, err := DetectEncoding(file)
+	if err != nil {
+		return "", "", err
+	}
+
+	if encoding == "" {
+		return file, "", nil
+	}
+
+	src, err := os.Open(file)
+	if err != nil {
+		return "", "", err
+	}
+	defer src.Close()
+
+	temp, err := ioutil.TempFile(tempdir.Dir(dir), "nitro-import-utf8-")
+	if err != nil {
+		return "", "", err
+	}
+
+	// remove the file unless it was converted, after it is closed
+	converted := false
+	defer func() {
+		if !converted {
+			os.Remove(temp.Name())
+		}
+	}()
+	defer temp.Close()
+
+	w := bufio.NewWriter(temp)
+
+	switch encoding {
+	case "utf-8-bom":
+		// skip the byte order mark and copy the rest
+		if _, err := src.Seek(int64(len(bomUTF8)), io.SeekStart); err != nil {
+			return "", "", err
+		}
+
+		if _, err := io.Copy(w, src); err != nil {
+			return "", "", err
+		}
+	default:
+		var order binary.ByteOrder = binary.LittleEndian
+		if encoding == "utf-16be" {
+			order = binary.BigEndian
+		}
+
+		// skip the byte order mark
+		if _, err := src.Seek(int64(len(bomUTF16LE)), io.SeekStart); err != nil {
+			return "", "", err
+		}
+
+		if err := transcodeUTF16(w, bufio.NewReader(src), order); err != nil {
+			return "", "", fmt.Errorf("unable to convert the file from %s, %w", encoding, err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return "", "", err
+	}
+
+	converted = true
+
+	return temp.Name(), encoding, nil
+}
+
+func transcodeUTF16(w io.Writer, r io.Reader, order binary.ByteOrder) error {
+	chunk := make([]byte, 32*1024)
+	buf := make([]byte, utf8.UTFMax)
+
+	var pending []uint16
+	var odd []byte
+	for {
+		n, err := r.Read(chunk)
+		if n > 0 {
+			data := append(odd, chunk[:n]...)
+
+			// hold on to an odd trailing byte for the next read
+			odd = nil
+			if len(data)%2 != 0 {
+				odd = []byte{data[len(data)-1]}
+				data = data[:len(data)-1]
+			}
+
+			units := pending
+			for i := 0; i < len(data); i += 2 {
+				units = append(units, order.Uint16(data[i:]))
+			}
+
+			// keep a trailing high surrogate for the next read
+			pending = nil
+			if l := len(units); l > 0 && utf16.IsSurrogate(rune(units[l-1])) && units[l-1] < 0xDC00 {
+				pending = []uint16{units[l-1]}
+				units = units[:l-1]
+			}
+
+			for _, rn := range utf16.Decode(units) {
+				size := utf8.EncodeRune(buf, rn)
+				if _, err := w.Write(buf[:size]); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(odd) > 0 {
+		return fmt.Errorf("the file has an odd number of bytes")
+	}
+
+	// write a dangling surrogate as the replacement character
+	for _, rn := range utf16.Decode(pending) {
+		size := utf8.EncodeRune(buf, rn)
+		if _, err := w.Write(buf[:size]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
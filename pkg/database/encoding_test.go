@@ -0,0 +1,116 @@
+package database
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConvertToUTF8(t *testing.T) {
+	expected, err := ioutil.ReadFile("./testdata/utf8-bom-backup.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = bytes.TrimPrefix(expected, []byte{0xEF, 0xBB, 0xBF})
+
+	type args struct {
+		file string
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantEncoding string
+		wantErr      bool
+	}{
+		{
+			name:         "utf-16 little endian files are converted",
+			args:         args{file: "./testdata/utf16le-backup.sql"},
+			wantEncoding: "utf-16le",
+			wantErr:      false,
+		},
+		{
+			name:         "utf-16 big endian files are converted",
+			args:         args{file: "./testdata/utf16be-backup.sql"},
+			wantEncoding: "utf-16be",
+			wantErr:      false,
+		},
+		{
+			name:         "utf-8 files with a byte order mark have it removed",
+			args:         args{file: "./testdata/utf8-bom-backup.sql"},
+			wantEncoding: "utf-8-bom",
+			wantErr:      false,
+		},
+		{
+			name:         "utf-8 files without a byte order mark are not converted",
+			args:         args{file: "./testdata/mysql-backup.sql"},
+			wantEncoding: "",
+			wantErr:      false,
+		},
+		{
+			name:    "missing files return an error",
+			args:    args{file: "./testdata/missing.sql"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, encoding, err := ConvertToUTF8(tt.args.file, "")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ConvertToUTF8() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if encoding != tt.wantEncoding {
+				t.Errorf("ConvertToUTF8() encoding = %v, want %v", encoding, tt.wantEncoding)
+			}
+
+			if encoding == "" {
+				if got != tt.args.file {
+					t.Errorf("ConvertToUTF8() got = %v, want %v", got, tt.args.file)
+				}
+				return
+			}
+			defer os.Remove(got)
+
+			content, err := ioutil.ReadFile(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(content, expected) {
+				t.Errorf("ConvertToUTF8() content = %q, want %q", content, expected)
+			}
+		})
+	}
+}
+
+func TestConvertToUTF8RemovesTheFileOnError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-convert-utf8-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// a utf-16 file with an odd number of bytes can't be converted
+	file := filepath.Join(dir, "backup.sql")
+	if err := ioutil.WriteFile(file, []byte{0xFF, 0xFE, 'a', 0x00, 'b'}, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := ConvertToUTF8(file, dir); err == nil {
+		t.Fatal("expected an error converting the file")
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "nitro-import-utf8-*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(matches) != 0 {
+		t.Errorf("expected the temporary file to be removed, got %v", matches)
+	}
+}
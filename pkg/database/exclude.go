@@ -0,0 +1,156 @@
+package database
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// tableStatements match the start of the statements that create, change, or add data to
+// a table, such as the statements written by mysqldump and pg_dump, and capture the table.
+var tableStatements = []*regexp.Regexp{
+	regexp.MustCompile("(?i)^\\s*(?:/\\*!\\d+\\s+)?(?:CREATE\\s+(?:UNLOGGED\\s+)?TABLE(?:\\s+IF\\s+NOT\\s+EXISTS)?|DROP\\s+TABLE(?:\\s+IF\\s+EXISTS)?|ALTER\\s+TABLE(?:\\s+ONLY)?|INSERT\\s+(?:IGNORE\\s+)?INTO|REPLACE\\s+INTO|LOCK\\s+TABLES|COPY|TRUNCATE(?:\\s+TABLE)?|CREATE\\s+(?:UNIQUE\\s+)?INDEX\\s+\\S+\\s+ON(?:\\s+ONLY)?)\\s+(?:[`\"]?[A-Za-z0-9_]+[`\"]?\\.)?[`\"]?([A-Za-z0-9_$]+)[`\"]?"),
+	// postgres sequences are owned by the column of the table
+	regexp.MustCompile(`(?i)^\s*ALTER\s+SEQUENCE\s+\S+\s+OWNED\s+BY\s+(?:"?[A-Za-z0-9_]+"?\.)?"?([A-Za-z0-9_$]+)"?\.`),
+}
+
+// ValidateExcludeTables checks the patterns of the tables to exclude from an import, the
+// patterns are globs (e.g. *_sessions) and cannot contain spaces, quotes, or dots.
+func ValidateExcludeTables(patterns []string) error {
+	for _, p := range patterns {
+		if p == "" {
+			return fmt.Errorf("the table to exclude cannot be empty")
+		}
+
+		if strings.ContainsAny(p, " \t\r\n`\"';.") {
+			return fmt.Errorf("the table to exclude %q cannot contain spaces, quotes, dots, or semicolons", p)
+		}
+
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("the table to exclude %q is not a valid pattern, %w", p, err)
+		}
+	}
+
+	return nil
+}
+
+// TableExcluder removes the statements for the tables that match a pattern from the
+// lines of a sql backup, e.g. the CREATE TABLE and INSERT statements for the sessions
+// table. It is a textual filter, not a sql parser, so it relies on statements ending
+// with a semicolon at the end of a line and removes the data of Postgres COPY
+// statements until the \. that ends it. Statements that reference a table elsewhere,
+// such as a foreign key from another table, are not removed.
+type TableExcluder struct {
+	patterns []string
+
+	// skipping is true while the lines of an excluded statement are removed
+	skipping bool
+	// copy is true when the excluded statement is a COPY, which is followed by data
+	copy bool
+	// copying is true while the data of an excluded COPY statement is removed
+	copying bool
+
+	mu      sync.Mutex
+	skipped map[string]bool
+}
+
+// NewTableExcluder returns an excluder that removes the statements for the tables that
+// match one of the patterns, the patterns are not case sensitive.
+func NewTableExcluder(patterns []string) (*TableExcluder, error) {
+	if err := ValidateExcludeTables(patterns); err != nil {
+		return nil, err
+	}
+
+	var lower []string
+	for _, p := range patterns {
+		lower = append(lower, strings.ToLower(p))
+	}
+
+	return &TableExcluder{patterns: lower, skipped: make(map[string]bool)}, nil
+}
+
+// Rewrite returns an empty string for the lines of the statements for excluded tables
+// and the line for everything else.
+func (e *TableExcluder) Rewrite(line string) string {
+	switch {
+	case e.copying:
+		if strings.TrimRight(line, "\r\n") == `\.` {
+			e.copying = false
+		}
+
+		return ""
+	case e.skipping:
+		if endsStatement(line) {
+			e.skipping = false
+			e.copying = e.copy
+		}
+
+		return ""
+	}
+
+	table := statementTable(line)
+	if table == "" || !e.excluded(table) {
+		return line
+	}
+
+	e.mu.Lock()
+	e.skipped[table] = true
+	e.mu.Unlock()
+
+	// the data of a COPY follows the statement
+	e.copy = strings.HasPrefix(strings.ToUpper(strings.TrimSpace(line)), "COPY")
+
+	if endsStatement(line) {
+		e.copying = e.copy
+	} else {
+		e.skipping = true
+	}
+
+	return ""
+}
+
+// Skipped returns the tables that were removed from the backup, sorted by name.
+func (e *TableExcluder) Skipped() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var tables []string
+	for t := range e.skipped {
+		tables = append(tables, t)
+	}
+
+	sort.Strings(tables)
+
+	return tables
+}
+
+// excluded returns true if the table matches one of the patterns.
+func (e *TableExcluder) excluded(table string) bool {
+	for _, p := range e.patterns {
+		if ok, _ := path.Match(p, strings.ToLower(table)); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// statementTable returns the table of a statement that creates, changes, or adds data
+// to a table, or an empty string for other lines.
+func statementTable(line string) string {
+	for _, p := range tableStatements {
+		if m := p.FindStringSubmatch(line); m != nil {
+			return m[1]
+		}
+	}
+
+	return ""
+}
+
+// endsStatement returns true if the line ends a statement.
+func endsStatement(line string) bool {
+	return strings.HasSuffix(strings.TrimRight(line, " \t\r\n"), ";")
+}
@@ -0,0 +1,134 @@
+package database
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTableExcluder_Rewrite(t *testing.T) {
+	tests := []struct {
+		name        string
+		patterns    []string
+		backup      string
+		want        string
+		wantSkipped []string
+	}{
+		{
+			name:     "mysql statements for the table are removed",
+			patterns: []string{"craft_sessions"},
+			backup: "DROP TABLE IF EXISTS `craft_sessions`;\n" +
+				"CREATE TABLE `craft_sessions` (\n" +
+				"  `id` int NOT NULL AUTO_INCREMENT,\n" +
+				"  PRIMARY KEY (`id`)\n" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n" +
+				"LOCK TABLES `craft_sessions` WRITE;\n" +
+				"/*!40000 ALTER TABLE `craft_sessions` DISABLE KEYS */;\n" +
+				"INSERT INTO `craft_sessions` VALUES (1),(2);\n" +
+				"/*!40000 ALTER TABLE `craft_sessions` ENABLE KEYS */;\n" +
+				"UNLOCK TABLES;\n" +
+				"CREATE TABLE `craft_users` (\n" +
+				"  `id` int NOT NULL\n" +
+				");\n" +
+				"INSERT INTO `craft_users` VALUES (1);\n",
+			want: "UNLOCK TABLES;\n" +
+				"CREATE TABLE `craft_users` (\n" +
+				"  `id` int NOT NULL\n" +
+				");\n" +
+				"INSERT INTO `craft_users` VALUES (1);\n",
+			wantSkipped: []string{"craft_sessions"},
+		},
+		{
+			name:     "postgres copy data is removed",
+			patterns: []string{"*_searchindex"},
+			backup: "CREATE TABLE public.craft_searchindex (\n" +
+				"    \"elementId\" integer NOT NULL\n" +
+				");\n" +
+				"ALTER TABLE public.craft_searchindex OWNER TO nitro;\n" +
+				"ALTER SEQUENCE public.craft_searchindex_id_seq OWNED BY public.craft_searchindex.id;\n" +
+				"COPY public.craft_searchindex (\"elementId\") FROM stdin;\n" +
+				"1\n" +
+				"2\n" +
+				"\\.\n" +
+				"COPY public.craft_users (id) FROM stdin;\n" +
+				"1\n" +
+				"\\.\n" +
+				"CREATE INDEX idx_search ON public.craft_searchindex USING btree (\"elementId\");\n",
+			want: "COPY public.craft_users (id) FROM stdin;\n" +
+				"1\n" +
+				"\\.\n",
+			wantSkipped: []string{"craft_searchindex"},
+		},
+		{
+			name:        "patterns are not case sensitive",
+			patterns:    []string{"SESSIONS"},
+			backup:      "insert into sessions values (1);\ninsert into users values (1);\n",
+			want:        "insert into users values (1);\n",
+			wantSkipped: []string{"sessions"},
+		},
+		{
+			name:     "backups without the tables are not changed",
+			patterns: []string{"sessions"},
+			backup:   "INSERT INTO `users` VALUES (1);\n",
+			want:     "INSERT INTO `users` VALUES (1);\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewTableExcluder(tt.patterns)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got strings.Builder
+			for _, line := range strings.SplitAfter(tt.backup, "\n") {
+				if line != "" {
+					got.WriteString(e.Rewrite(line))
+				}
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("Rewrite() =\n%s\nwant\n%s", got.String(), tt.want)
+			}
+
+			if skipped := e.Skipped(); !reflect.DeepEqual(skipped, tt.wantSkipped) {
+				t.Errorf("Skipped() = %v, want %v", skipped, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestValidateExcludeTables(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		wantErr  bool
+	}{
+		{
+			name:     "tables and globs are valid",
+			patterns: []string{"sessions", "*_searchindex", "cache_[0-9]"},
+		},
+		{
+			name:     "empty tables are invalid",
+			patterns: []string{""},
+			wantErr:  true,
+		},
+		{
+			name:     "schema qualified tables are invalid",
+			patterns: []string{"public.sessions"},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid globs are invalid",
+			patterns: []string{"sessions["},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateExcludeTables(tt.patterns); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateExcludeTables() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
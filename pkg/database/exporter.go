@@ -0,0 +1,234 @@
+package database
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+)
+
+var (
+	MySQLExportCommand    = "mysqldump"
+	PostgresExportCommand = "pg_dump"
+)
+
+// Exporter is an interface that is designed to export a database from a
+// database server into a backup.
+type Exporter interface {
+	Export(opts *ExportOptions, finder func(engine, version string) (string, error)) error
+}
+
+// ExportOptions are used to create a new exporter.
+// It contains all of the information needed to run an export.
+type ExportOptions struct {
+	// Compressed writes the backup with gzip, it is only used for plain
+	// sql backups since archives are already compressed.
+	Compressed   bool
+	Engine       string
+	Version      string
+	Hostname     string
+	Port         string
+	DatabaseName string
+	// File is the path the backup is written to, for directory format
+	// archives it is the directory.
+	File string
+	// Format is the Postgres archive format (custom or directory), it is
+	// empty for plain sql backups.
+	Format string
+	// Socket is the path to a unix socket used to connect instead of the
+	// hostname, for Postgres this is the directory containing the socket.
+	Socket string
+	// User and Password are used to connect to the database server, they
+	// default to the nitro user when empty, see Credentials.
+	User     string
+	Password string
+	// Stdout receives the backup instead of the file, it is used to stream
+	// the backup to the client.
+	Stdout io.Writer
+}
+
+type exporter struct{}
+
+// NewExporter returns a new database exporter.
+func NewExporter() *exporter {
+	return &exporter{}
+}
+
+// Export performs the export operation for a database.
+func (exporter *exporter) Export(opts *ExportOptions, find func(engine, version string) (string, error)) error {
+	// ensure there are options
+	if opts == nil {
+		return fmt.Errorf("no options were provider")
+	}
+
+	// validate all of the options
+	if err := ValidateExport(opts); err != nil {
+		return err
+	}
+
+	// find the export tool
+	tool, err := find(opts.Engine, opts.Version)
+	if err != nil {
+		return err
+	}
+
+	c := exec.Command(tool, exportCommand(opts)...)
+	c.Env = environ(opts.connection())
+
+	// keep the errors to report the reason the command failed
+	stderr := &bytes.Buffer{}
+	c.Stderr = stderr
+
+	// the backup is written to stdout when it is streamed or compressed
+	out := opts.Stdout
+	if out == nil && opts.Compressed {
+		f, err := os.Create(opts.File)
+		if err != nil {
+			return fmt.Errorf("unable to create the backup %s, %w", opts.File, err)
+		}
+		defer f.Close()
+
+		out = f
+	}
+
+	// plain sql backups are compressed as they are written
+	var gz *gzip.Writer
+	if opts.Compressed {
+		gz = gzip.NewWriter(out)
+		out = gz
+	}
+
+	if out != nil {
+		c.Stdout = out
+	}
+
+	if err := c.Run(); err != nil {
+		if opts.Stdout == nil && opts.Compressed {
+			os.Remove(opts.File)
+		}
+
+		return exportError(err, stderr.String())
+	}
+
+	if gz != nil {
+		return gz.Close()
+	}
+
+	return nil
+}
+
+// exportError returns the error with the first error from the export tool.
+func exportError(err error, output string) error {
+	if msg := firstError(output); msg != "" {
+		return fmt.Errorf("%w, %s", err, msg)
+	}
+
+	return err
+}
+
+// connection returns the options used to connect to the database server.
+func (opts *ExportOptions) connection() *ImportOptions {
+	return &ImportOptions{
+		Engine:   opts.Engine,
+		Hostname: opts.Hostname,
+		Port:     opts.Port,
+		Socket:   opts.Socket,
+		User:     opts.User,
+		Password: opts.Password,
+	}
+}
+
+// exportCommand takes the options and returns the arguments for the export tool. When
+// the backup is compressed or streamed, the backup is written to stdout instead of the file.
+func exportCommand(opts *ExportOptions) []string {
+	args := connection(opts.connection())
+	stdout := opts.Compressed || opts.Stdout != nil
+
+	switch opts.Engine {
+	case "postgres":
+		args = append(args, "--dbname="+opts.DatabaseName, "--no-owner")
+
+		if opts.Format != "" {
+			args = append(args, "--format="+opts.Format)
+		}
+
+		if !stdout {
+			args = append(args, "--file="+opts.File)
+		}
+	default:
+		// dump a consistent snapshot without locking the tables
+		args = append(args, "--single-transaction", "--routines", "--triggers")
+
+		if !stdout {
+			args = append(args, "--result-file="+opts.File)
+		}
+
+		args = append(args, opts.DatabaseName)
+	}
+
+	return args
+}
+
+// ValidateExport takes export options and returns an error if the options are
+// missing details needed to run the export.
+func ValidateExport(opts *ExportOptions) error {
+	if err := Validate(opts.connection()); err != nil {
+		return fmt.Errorf("export options are invalid, %w", err)
+	}
+
+	if opts.DatabaseName == "" {
+		return fmt.Errorf("export options is missing the database")
+	}
+
+	if opts.File == "" && opts.Stdout == nil {
+		return fmt.Errorf("export options is missing the file")
+	}
+
+	switch opts.Format {
+	case "":
+	case "custom", "directory":
+		if opts.Engine != "postgres" {
+			return fmt.Errorf("the %s format is only supported for postgres", opts.Format)
+		}
+
+		if opts.Compressed {
+			return fmt.Errorf("%s format archives are already compressed", opts.Format)
+		}
+
+		// directory archives are written as a file per table
+		if opts.Format == "directory" && opts.Stdout != nil {
+			return fmt.Errorf("directory format archives cannot be streamed")
+		}
+	default:
+		return fmt.Errorf("unknown export format %q, use custom or directory", opts.Format)
+	}
+
+	return nil
+}
+
+// DefaultExportToolFinder is a tool that is used to find the executable path
+// to the export tool such as mysqldump or pg_dump. It is a func that is
+// provided to the Exporter.Export func. It will return the path to the
+// executable or an error if the command is not found
+func DefaultExportToolFinder(engine, version string) (string, error) {
+	switch engine {
+	case "postgres":
+		t, err := exec.LookPath(PostgresExportCommand)
+		if err != nil {
+			return "", fmt.Errorf("unable to find the `%q` export tool", PostgresExportCommand)
+		}
+
+		return t, nil
+	case "mysql":
+		t, err := exec.LookPath(MySQLExportCommand)
+		if err != nil {
+			return "", fmt.Errorf("unable to find the `%q` export tool", MySQLExportCommand)
+		}
+
+		return t, nil
+	}
+
+	return "", fmt.Errorf("unknown engine %q and version %q options provided", engine, version)
+}
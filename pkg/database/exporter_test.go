@@ -0,0 +1,140 @@
+package database
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_exportCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *ExportOptions
+		want []string
+	}{
+		{
+			name: "mysql writes the backup to the file",
+			opts: &ExportOptions{Engine: "mysql", Hostname: "mysql-8.0-3306.database.nitro", Port: "3306", DatabaseName: "project", File: "/tmp/project.sql"},
+			want: []string{"--user=nitro", "--host=mysql-8.0-3306.database.nitro", "--port=3306", "--single-transaction", "--routines", "--triggers", "--result-file=/tmp/project.sql", "project"},
+		},
+		{
+			name: "mysql writes compressed backups to stdout",
+			opts: &ExportOptions{Engine: "mysql", Hostname: "mysql-8.0-3306.database.nitro", Port: "3306", DatabaseName: "project", File: "/tmp/project.sql.gz", Compressed: true},
+			want: []string{"--user=nitro", "--host=mysql-8.0-3306.database.nitro", "--port=3306", "--single-transaction", "--routines", "--triggers", "project"},
+		},
+		{
+			name: "streamed backups are written to stdout",
+			opts: &ExportOptions{Engine: "postgres", Hostname: "postgres-13-5432.database.nitro", Port: "5432", DatabaseName: "project", Format: "custom", Stdout: ioutil.Discard},
+			want: []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "--dbname=project", "--no-owner", "--format=custom"},
+		},
+		{
+			name: "postgres writes the backup to the file",
+			opts: &ExportOptions{Engine: "postgres", Hostname: "postgres-13-5432.database.nitro", Port: "5432", DatabaseName: "project", File: "/tmp/project.sql"},
+			want: []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "--dbname=project", "--no-owner", "--file=/tmp/project.sql"},
+		},
+		{
+			name: "postgres archives use the format",
+			opts: &ExportOptions{Engine: "postgres", Hostname: "postgres-13-5432.database.nitro", Port: "5432", DatabaseName: "project", File: "/tmp/project.dump", Format: "custom", User: "craft", Password: "secret"},
+			want: []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=craft", "--dbname=project", "--no-owner", "--format=custom", "--file=/tmp/project.dump"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exportCommand(tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("exportCommand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateExport(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    *ExportOptions
+		wantErr bool
+	}{
+		{
+			name: "plain backups are valid",
+			opts: &ExportOptions{Engine: "mysql", Hostname: "127.0.0.1", Port: "3306", DatabaseName: "project", File: "project.sql", Compressed: true},
+		},
+		{
+			name:    "the database is required",
+			opts:    &ExportOptions{Engine: "mysql", Hostname: "127.0.0.1", Port: "3306", File: "project.sql"},
+			wantErr: true,
+		},
+		{
+			name:    "the file is required",
+			opts:    &ExportOptions{Engine: "mysql", Hostname: "127.0.0.1", Port: "3306", DatabaseName: "project"},
+			wantErr: true,
+		},
+		{
+			name:    "formats are only supported for postgres",
+			opts:    &ExportOptions{Engine: "mysql", Hostname: "127.0.0.1", Port: "3306", DatabaseName: "project", File: "project.dump", Format: "custom"},
+			wantErr: true,
+		},
+		{
+			name:    "archives cannot be compressed",
+			opts:    &ExportOptions{Engine: "postgres", Hostname: "127.0.0.1", Port: "5432", DatabaseName: "project", File: "project.dump", Format: "custom", Compressed: true},
+			wantErr: true,
+		},
+		{
+			name:    "unknown formats are invalid",
+			opts:    &ExportOptions{Engine: "postgres", Hostname: "127.0.0.1", Port: "5432", DatabaseName: "project", File: "project.tar", Format: "tar"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateExport(tt.opts); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateExport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExporter_ExportCompressed(t *testing.T) {
+	echo, err := exec.LookPath("echo")
+	if err != nil {
+		t.Skip("echo is not available")
+	}
+
+	dir, err := ioutil.TempDir("", "nitro-export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	opts := &ExportOptions{Engine: "mysql", Hostname: "127.0.0.1", Port: "3306", DatabaseName: "project", File: filepath.Join(dir, "project.sql.gz"), Compressed: true}
+
+	// echo writes the arguments to stdout in place of the backup
+	find := func(engine, version string) (string, error) { return echo, nil }
+	if err := NewExporter().Export(opts, find); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(opts.File)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join(exportCommand(opts), " ") + "\n"
+	if string(got) != want {
+		t.Errorf("expected the compressed backup to contain %q, got %q", want, got)
+	}
+}
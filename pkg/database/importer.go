@@ -1,17 +1,35 @@
 package database
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/craftcms/nitro/pkg/pathexists"
 )
 
 var (
-	MySQLImportCommand    = "mysql"
-	PostgresImportCommand = "psql"
+	MySQLImportCommand     = "mysql"
+	PostgresImportCommand  = "psql"
+	PostgresRestoreCommand = "pg_restore"
+
+	// MySQLSocket is the default path to the MySQL socket in the database containers
+	MySQLSocket = "/var/run/mysqld/mysqld.sock"
+	// PostgresSocket is the default directory for the Postgres socket in the database containers
+	PostgresSocket = "/var/run/postgresql"
+
+	// DefaultUser and DefaultPassword are the credentials the database containers are created with
+	DefaultUser     = "nitro"
+	DefaultPassword = "nitro"
 )
 
 // Importer is an interface that is designed to import a database backup
@@ -31,6 +49,107 @@ type ImportOptions struct {
 	Port            string
 	DatabaseName    string
 	File            string
+	// Validate will import the file into a temporary database, that is
+	// removed after the import, to check the backup for errors without
+	// changing the target database.
+	Validate bool
+	// Analyze will update the table statistics after the import.
+	Analyze bool
+	// Socket is the path to a unix socket used to connect instead of the
+	// hostname, which is faster for a server on the same machine, e.g. with
+	// db import --remote. For Postgres this is the directory containing the
+	// socket.
+	Socket string
+	// Jobs is the number of parallel jobs used by pg_restore, it is only
+	// used for custom and directory format Postgres archives.
+	Jobs int
+	// Format is the Postgres archive format (custom or directory) of the
+	// file, it is empty for plain sql backups and set by the importer.
+	Format string
+	// OnError is stop to end the import at the first error or continue to
+	// skip the statements that fail. When it is empty the import tool uses
+	// its default, mysql stops and psql and pg_restore continue.
+	OnError string
+	// Errors is the number of errors skipped when continuing on errors, it
+	// is set by the importer.
+	Errors int
+	// Output is the output of the import tool, including warnings, it is
+	// set by the importer.
+	Output string
+	// FromPrefix and ToPrefix rewrite the table prefix (e.g. prod_ to craft_)
+	// of a plain sql backup before it is imported, see PrefixRewriter for the
+	// limitations of the textual rewrite.
+	FromPrefix string
+	ToPrefix   string
+	// RenameFrom is the name of the database in a plain sql backup (e.g. in
+	// USE or \connect statements) to replace with RenameTo, which defaults to
+	// the database being imported into, see DatabaseRenamer.
+	RenameFrom string
+	RenameTo   string
+	// ExcludeTables are the patterns (e.g. sessions or *_searchindex) of the
+	// tables to remove from a plain sql backup before it is imported, see
+	// TableExcluder for the limitations of the textual filter.
+	ExcludeTables []string
+	// Excluded are the tables removed from the backup, it is set by the
+	// importer.
+	Excluded []string
+	// Stdin is a plain sql backup that is piped into the import tool instead
+	// of importing the File, so the backup does not need to be written to
+	// disk first. Archives cannot be imported from Stdin.
+	Stdin io.Reader
+	// User and Password are used to connect to the database server, they
+	// default to the nitro user when empty, see Credentials.
+	User     string
+	Password string
+}
+
+const (
+	// OnErrorStop ends the import at the first error.
+	OnErrorStop = "stop"
+
+	// OnErrorContinue skips the statements that fail and imports the rest of the backup.
+	OnErrorContinue = "continue"
+)
+
+// ArchiveFormat takes a path and returns the pg_dump archive format, custom
+// for files created with -Fc and directory for directories created with -Fd.
+// It returns an empty string for plain sql backups.
+func ArchiveFormat(path string) string {
+	if pathexists.IsDirectory(path) {
+		if pathexists.IsFile(filepath.Join(path, "toc.dat")) {
+			return "directory"
+		}
+
+		return ""
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	// custom format archives start with the magic string PGDMP
+	magic := make([]byte, 5)
+	if _, err := io.ReadFull(f, magic); err != nil {
+		return ""
+	}
+
+	if string(magic) == "PGDMP" {
+		return "custom"
+	}
+
+	return ""
+}
+
+// DefaultSocket takes an engine and returns the default socket path used
+// by the engine in the database containers.
+func DefaultSocket(engine string) string {
+	if engine == "postgres" {
+		return PostgresSocket
+	}
+
+	return MySQLSocket
 }
 
 type importer struct{}
@@ -53,49 +172,434 @@ func (importer *importer) Import(opts *ImportOptions, find func(engine, version
 		return err
 	}
 
-	// check to verify the path exists and is a file
-	if !pathexists.IsFile(opts.File) {
+	// postgres archives are restored with pg_restore
+	if opts.Engine == "postgres" && opts.Stdin == nil {
+		opts.Format = ArchiveFormat(opts.File)
+	}
+
+	// check to verify the path exists and is a file, or a directory format archive
+	if !pathexists.IsFile(opts.File) && opts.Format != "directory" && opts.Stdin == nil {
 		return fmt.Errorf("unable to file the file %s", opts.File)
 	}
 
+	// remove the statements for the excluded tables, before the table prefix is rewritten
+	var excluder *TableExcluder
+	if len(opts.ExcludeTables) > 0 {
+		if opts.Format != "" {
+			return fmt.Errorf("tables can only be excluded from plain sql backups, not %s format archives", opts.Format)
+		}
+
+		var err error
+		excluder, err = NewTableExcluder(opts.ExcludeTables)
+		if err != nil {
+			return err
+		}
+
+		cleanup, err := rewriteBackup(opts, "nitro-import-exclude-", excluder.Rewrite)
+		if err != nil {
+			return fmt.Errorf("unable to exclude the tables, %w", err)
+		}
+		defer cleanup()
+	}
+
+	// rewrite the table prefix into a new file next to the backup, or as the backup is streamed
+	if opts.FromPrefix != "" {
+		if opts.Format != "" {
+			return fmt.Errorf("the table prefix can only be rewritten for plain sql backups, not %s format archives", opts.Format)
+		}
+
+		r, err := NewPrefixRewriter(opts.FromPrefix, opts.ToPrefix)
+		if err != nil {
+			return err
+		}
+
+		cleanup, err := rewriteBackup(opts, "nitro-import-prefix-", r.Rewrite)
+		if err != nil {
+			return fmt.Errorf("unable to rewrite the table prefix, %w", err)
+		}
+		defer cleanup()
+	}
+
 	// find the import tool
 	tool, err := find(opts.Engine, opts.Version)
 	if err != nil {
 		return err
 	}
 
-	// generate the commands to execute
-	var createCommand, importCommand []string
-	switch opts.Engine {
-	case "postgres":
-		createCommand = []string{fmt.Sprintf("--host=%s", opts.Hostname), "--port=" + opts.Port, "--username=nitro", fmt.Sprintf(`-c CREATE DATABASE %s;`, opts.DatabaseName)}
-		importCommand = []string{fmt.Sprintf("--host=%s", opts.Hostname), "--port=" + opts.Port, "--username=nitro", opts.DatabaseName, "--file=" + opts.File}
-	default:
-		createCommand = []string{"--user=nitro", fmt.Sprintf("--host=%s", opts.Hostname), "-pnitro", fmt.Sprintf(`-e CREATE DATABASE IF NOT EXISTS %s;`, opts.DatabaseName)}
-		// https://dev.mysql.com/doc/refman/8.0/en/mysql-command-options.html
-		importCommand = []string{"--user=nitro", fmt.Sprintf("--host=%s", opts.Hostname), "-pnitro", opts.DatabaseName, fmt.Sprintf(`-e source %s`, opts.File)}
+	// archives use pg_restore to import, which is installed alongside psql
+	importTool := tool
+	if opts.Format != "" {
+		importTool, err = restoreTool(tool)
+		if err != nil {
+			return err
+		}
+	}
+
+	// use a throwaway database when validating
+	db := opts.DatabaseName
+	if opts.Validate {
+		db = fmt.Sprintf("nitro_validate_%d", time.Now().UnixNano())
+	}
+
+	// rename the database in the backup so it is imported into the target database
+	if opts.RenameFrom != "" && !opts.Validate {
+		if opts.Format != "" {
+			return fmt.Errorf("the database can only be renamed for plain sql backups, not %s format archives", opts.Format)
+		}
+
+		to := opts.RenameTo
+		if to == "" {
+			to = db
+		}
+
+		r, err := NewDatabaseRenamer(opts.Engine, opts.RenameFrom, to)
+		if err != nil {
+			return err
+		}
+
+		cleanup, err := rewriteBackup(opts, "nitro-import-rename-", r.Rewrite)
+		if err != nil {
+			return fmt.Errorf("unable to rename the database, %w", err)
+		}
+		defer cleanup()
+	}
+
+	// comment out the statements that select or change a database so a plain sql
+	// backup cannot switch from the throwaway database to the real one
+	if opts.Validate && opts.Format == "" {
+		cleanup, err := rewriteBackup(opts, "nitro-import-validate-", StripDatabaseStatements(opts.Engine))
+		if err != nil {
+			return fmt.Errorf("unable to remove the database statements, %w", err)
+		}
+		defer cleanup()
 	}
 
+	// generate the commands to execute
+	createCommand, importCommand, dropCommand := commands(opts, db)
+
 	// if there is a create command, lets create the database
 	if createCommand != nil {
-		if err := importer.exec(tool, createCommand); err != nil {
+		if err := importer.exec(tool, environ(opts), createCommand); err != nil {
 			// do not exit on error with the crate command - the error could be "Database already exists"
 			fmt.Println(err)
 		}
 	}
 
-	// import the database
-	if err := importer.exec(tool, importCommand); err != nil {
+	// always remove the throwaway database
+	if opts.Validate {
+		defer importer.exec(tool, environ(opts), dropCommand)
+	}
+
+	// import the database and keep the output to report the warnings
+	opts.Output, err = importer.execOutput(importTool, environ(opts), importCommand, opts.Stdin)
+
+	if excluder != nil {
+		opts.Excluded = excluder.Skipped()
+	}
+
+	// the tools exit with a non-zero status when errors were skipped, so that
+	// is only an error when the output only has errors for statements
+	if continueOnError(opts) {
+		opts.Errors = countErrors(opts.Output)
+
+		if err != nil {
+			if msg := fatalError(opts.Output); msg != "" {
+				return fmt.Errorf("unable to import the backup, %s", msg)
+			}
+
+			if opts.Errors > 0 {
+				return nil
+			}
+		}
+	}
+
+	return err
+}
+
+// Summary returns the message for a successful import, such as the number of errors
+// skipped or the tables that were excluded.
+func Summary(opts *ImportOptions) string {
+	msg := fmt.Sprintf("Imported database %q", opts.DatabaseName)
+	if opts.FromPrefix != "" {
+		msg = fmt.Sprintf("%s with the table prefix %q replaced by %q", msg, opts.FromPrefix, opts.ToPrefix)
+	}
+
+	if opts.RenameFrom != "" && !opts.Validate {
+		msg = fmt.Sprintf("%s from %q in the backup", msg, opts.RenameFrom)
+	}
+
+	if len(opts.ExcludeTables) > 0 && !opts.Validate {
+		switch len(opts.Excluded) {
+		case 0:
+			msg = fmt.Sprintf("%s, no tables matched the excluded tables", msg)
+		default:
+			msg = fmt.Sprintf("%s without the tables %s", msg, strings.Join(opts.Excluded, ", "))
+		}
+	}
+
+	switch {
+	case opts.Validate:
+		msg = "Validated backup without errors"
+	case opts.Errors == 1:
+		msg = fmt.Sprintf("%s and skipped 1 error", msg)
+	case opts.Errors > 1:
+		msg = fmt.Sprintf("%s and skipped %d errors", msg, opts.Errors)
+	}
+
+	return msg
+}
+
+// continueOnError returns true if the import should skip the statements that fail, a
+// validation always stops at the first error.
+func continueOnError(opts *ImportOptions) bool {
+	return opts.OnError == OnErrorContinue && !opts.Validate
+}
+
+// Analyze updates the table statistics for the imported database so the query
+// plans match what would be used in production. MySQL uses ANALYZE TABLE on
+// all of the tables and Postgres uses VACUUM ANALYZE.
+func (importer *importer) Analyze(opts *ImportOptions, find func(engine, version string) (string, error)) error {
+	// validate all of the options
+	if err := Validate(opts); err != nil {
+		return err
+	}
+
+	// find the tool
+	tool, err := find(opts.Engine, opts.Version)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	// postgres analyzes all of the tables in the database
+	if opts.Engine == "postgres" {
+		return importer.exec(tool, environ(opts), analyzeCommand(opts, nil))
+	}
+
+	// get all of the tables in the database
+	out, err := importer.output(tool, environ(opts), tablesCommand(opts))
+	if err != nil {
+		return err
+	}
+
+	// there is nothing to analyze
+	tables := strings.Fields(out)
+	if len(tables) == 0 {
+		return nil
+	}
+
+	return importer.exec(tool, environ(opts), analyzeCommand(opts, tables))
+}
+
+// tablesCommand returns the arguments to list the tables of the database for MySQL.
+func tablesCommand(opts *ImportOptions) []string {
+	return append(connection(opts), "--skip-column-names", "--silent", fmt.Sprintf(`-e SELECT table_name FROM information_schema.tables WHERE table_schema = %s AND table_type = 'BASE TABLE';`, QuoteString(opts.Engine, opts.DatabaseName)))
+}
+
+// analyzeCommand returns the arguments to update the statistics of the tables, MySQL
+// uses ANALYZE TABLE on the tables and Postgres uses VACUUM ANALYZE on the database.
+func analyzeCommand(opts *ImportOptions, tables []string) []string {
+	if opts.Engine == "postgres" {
+		return append(connection(opts), opts.DatabaseName, "-c VACUUM ANALYZE;")
+	}
+
+	var quoted []string
+	for _, t := range tables {
+		quoted = append(quoted, QuoteIdentifier(opts.Engine, t))
+	}
+
+	return append(connection(opts), opts.DatabaseName, fmt.Sprintf(`-e ANALYZE TABLE %s;`, strings.Join(quoted, ", ")))
 }
 
-func (importer *importer) exec(tool string, commands []string) error {
+// commands takes the options and the database to import into and returns the
+// arguments to create the database, import the file, and drop the database.
+func commands(opts *ImportOptions, db string) (create, imp, drop []string) {
+	switch opts.Engine {
+	case "postgres":
+		create = append(connection(opts), fmt.Sprintf(`-c CREATE DATABASE %s;`, db))
+		imp = append(connection(opts), db)
+		if opts.Stdin == nil {
+			imp = append(imp, "--file="+opts.File)
+		}
+		drop = append(connection(opts), fmt.Sprintf(`-c DROP DATABASE IF EXISTS %s;`, db))
+
+		switch {
+		case opts.Validate:
+			// stop on the first error and don't commit anything
+			imp = append(imp, "--single-transaction", "--set=ON_ERROR_STOP=1")
+		case continueOnError(opts):
+			imp = append(imp, "--set=ON_ERROR_STOP=0")
+		case opts.OnError == OnErrorStop:
+			imp = append(imp, "--set=ON_ERROR_STOP=1")
+		}
+
+		// archives are restored with pg_restore
+		if opts.Format != "" {
+			imp = append(connection(opts), "--dbname="+db, "--no-owner")
+
+			switch {
+			case opts.Validate:
+				// parallel jobs can't be used with a single transaction
+				imp = append(imp, "--single-transaction", "--exit-on-error")
+			case opts.OnError == OnErrorStop:
+				imp = append(imp, "--exit-on-error")
+			}
+
+			if opts.Jobs > 0 && !opts.Validate {
+				imp = append(imp, fmt.Sprintf("--jobs=%d", opts.Jobs))
+			}
+
+			imp = append(imp, opts.File)
+		}
+	default:
+		create = append(connection(opts), fmt.Sprintf(`-e CREATE DATABASE IF NOT EXISTS %s;`, db))
+		// https://dev.mysql.com/doc/refman/8.0/en/mysql-command-options.html
+		// show the warnings after each statement so they can be reported
+		imp = append(connection(opts), "--show-warnings")
+		if continueOnError(opts) {
+			imp = append(imp, "--force")
+		}
+
+		// a streamed backup is read from stdin
+		imp = append(imp, db)
+		if opts.Stdin == nil {
+			imp = append(imp, fmt.Sprintf(`-e source %s`, opts.File))
+		}
+		drop = append(connection(opts), fmt.Sprintf(`-e DROP DATABASE IF EXISTS %s;`, db))
+	}
+
+	return create, imp, drop
+}
+
+// connection returns the arguments to connect to the database server using
+// the socket if it is set or the hostname.
+func connection(opts *ImportOptions) []string {
+	switch opts.Engine {
+	case "postgres":
+		// psql uses the host option for the socket directory
+		host := opts.Hostname
+		if opts.Socket != "" {
+			host = opts.Socket
+		}
+
+		return []string{fmt.Sprintf("--host=%s", host), "--port=" + opts.Port, "--username=" + connectUser(opts)}
+	default:
+		args := []string{"--user=" + connectUser(opts)}
+
+		switch {
+		case opts.Socket != "":
+			args = append(args, fmt.Sprintf("--socket=%s", opts.Socket))
+		case opts.Port != "":
+			args = append(args, fmt.Sprintf("--host=%s", opts.Hostname), "--port="+opts.Port)
+		default:
+			args = append(args, fmt.Sprintf("--host=%s", opts.Hostname))
+		}
+
+		return args
+	}
+}
+
+// connectUser returns the user to connect with, or nitro when the options do not set a user.
+func connectUser(opts *ImportOptions) string {
+	if opts.User == "" {
+		return DefaultUser
+	}
+
+	return opts.User
+}
+
+// connectPassword returns the password to connect with, or nitro when the options do not set a user.
+func connectPassword(opts *ImportOptions) string {
+	if opts.User == "" && opts.Password == "" {
+		return DefaultPassword
+	}
+
+	return opts.Password
+}
+
+// environ returns the environment for the import tools, so the password is not in the
+// arguments of the process. psql and pg_restore read the password from PGPASSWORD and
+// mysql reads it from MYSQL_PWD. It returns nil to use the current environment when
+// postgres uses the default credentials.
+func environ(opts *ImportOptions) []string {
+	switch opts.Engine {
+	case "postgres":
+		if opts.User == "" && opts.Password == "" {
+			return nil
+		}
+
+		return append(os.Environ(), "PGPASSWORD="+opts.Password)
+	default:
+		return append(os.Environ(), "MYSQL_PWD="+connectPassword(opts))
+	}
+}
+
+// Credentials takes the engine and the environment of a database container (e.g. from
+// docker inspect) and returns the user and password to connect with. It returns empty
+// strings, which use the nitro user, when the environment does not set the credentials.
+func Credentials(engine string, env []string) (user, password string) {
+	vars := map[string]string{}
+	for _, e := range env {
+		if sp := strings.SplitN(e, "=", 2); len(sp) == 2 {
+			vars[sp[0]] = sp[1]
+		}
+	}
+
+	switch engine {
+	case "postgres":
+		if vars["POSTGRES_USER"] == "" && vars["POSTGRES_PASSWORD"] == "" {
+			return "", ""
+		}
+
+		// the image uses postgres when the user is not set
+		user = vars["POSTGRES_USER"]
+		if user == "" {
+			user = "postgres"
+		}
+
+		return user, vars["POSTGRES_PASSWORD"]
+	default:
+		// the image only grants the user access to its own database, so root is used to
+		// create the database for the import when the root password is known
+		switch {
+		case vars["MYSQL_ROOT_PASSWORD"] != "":
+			return "root", vars["MYSQL_ROOT_PASSWORD"]
+		case vars["MYSQL_ALLOW_EMPTY_PASSWORD"] != "":
+			return "root", ""
+		case vars["MYSQL_USER"] != "":
+			return vars["MYSQL_USER"], vars["MYSQL_PASSWORD"]
+		}
+
+		return "", ""
+	}
+}
+
+// output runs the tool and returns the output of the command.
+func (importer *importer) output(tool string, env, commands []string) (string, error) {
+	stderr := &bytes.Buffer{}
+
 	c := exec.Command(tool, commands...)
+	c.Env = env
+	c.Stderr = stderr
 
-	c.Stderr = ioutil.Discard
+	out, err := c.Output()
+	if err != nil {
+		if msg := firstError(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w, %s", err, msg)
+		}
+
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+func (importer *importer) exec(tool string, env, commands []string) error {
+	c := exec.Command(tool, commands...)
+	c.Env = env
+
+	// keep the errors to report the reason the command failed
+	stderr := &bytes.Buffer{}
+	c.Stderr = stderr
 	c.Stdout = ioutil.Discard
 
 	if err := c.Start(); err != nil {
@@ -106,6 +610,10 @@ func (importer *importer) exec(tool string, commands []string) error {
 		if exiterr, ok := err.(*exec.ExitError); ok {
 			// The program has exited with an exit code != 0
 			if status, ok := exiterr.Sys().(syscall.WaitStatus); ok {
+				if msg := firstError(stderr.String()); msg != "" {
+					return fmt.Errorf("Exit Status: %d, %s", status.ExitStatus(), msg)
+				}
+
 				return fmt.Errorf("Exit Status: %d", status.ExitStatus())
 			}
 		} else {
@@ -116,6 +624,193 @@ func (importer *importer) exec(tool string, commands []string) error {
 	return nil
 }
 
+// execOutput runs the import tool, with the stdin when it is not nil, and returns the
+// combined output, which includes the warnings from the tool, even when the command
+// is successful. The env is the environment for the tool, nil uses the current environment.
+func (importer *importer) execOutput(tool string, env, commands []string, stdin io.Reader) (string, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := exec.CommandContext(ctx, tool, commands...)
+	c.Env = env
+
+	// closing stdin would look like the end of the backup to the tool, so the tool is
+	// killed when the backup cannot be read, e.g. when an upload is interrupted
+	var killer *killReader
+	if stdin != nil {
+		killer = &killReader{r: stdin, kill: func() {
+			cancel()
+			c.Process.Kill()
+		}}
+		c.Stdin = killer
+	}
+
+	// psql writes a command tag for every statement to stdout, so only the start of
+	// stdout is kept with the errors and warnings
+	stderr := &bytes.Buffer{}
+	stdout := &limitBuffer{max: MaxStdout}
+	c.Stderr = stderr
+	c.Stdout = stdout
+
+	err := c.Run()
+	out := stderr.String() + stdout.String()
+	if killer != nil && killer.err != nil {
+		return out, fmt.Errorf("the import was stopped because the backup could not be read, %w", killer.err)
+	}
+
+	if err != nil {
+		if msg := firstError(out); msg != "" {
+			return out, fmt.Errorf("%w, %s", err, msg)
+		}
+
+		return out, err
+	}
+
+	return out, nil
+}
+
+// MaxStdout is the most output, in bytes, kept from the stdout of an import tool.
+const MaxStdout = 64 * 1024
+
+// limitBuffer keeps the lines written to it until it has max bytes and discards
+// the rest.
+type limitBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (l *limitBuffer) Write(p []byte) (int, error) {
+	if l.truncated {
+		return len(p), nil
+	}
+
+	if l.buf.Len()+len(p) > l.max {
+		l.buf.Write(p[:l.max-l.buf.Len()])
+		l.truncated = true
+
+		return len(p), nil
+	}
+
+	return l.buf.Write(p)
+}
+
+// String returns the output, a truncated output ends at the last complete line.
+func (l *limitBuffer) String() string {
+	if !l.truncated {
+		return l.buf.String()
+	}
+
+	out := l.buf.String()
+	if i := strings.LastIndex(out, "\n"); i >= 0 {
+		out = out[:i+1]
+	} else {
+		out = ""
+	}
+
+	return out + "[the output was truncated]\n"
+}
+
+// killReader reads the stdin of an import tool and kills the tool before returning an
+// error other than io.EOF, so the tool does not import an incomplete backup. The kill
+// is sent before the stdin is closed.
+type killReader struct {
+	r    io.Reader
+	kill func()
+	err  error
+}
+
+func (k *killReader) Read(p []byte) (int, error) {
+	n, err := k.r.Read(p)
+	if err != nil && err != io.EOF && k.err == nil {
+		k.err = err
+		k.kill()
+	}
+
+	return n, err
+}
+
+// statementError matches the errors for a statement in the backup, such as
+// "ERROR 1064 (42000) at line 3" from mysql, "psql:backup.sql:12: ERROR:" from psql,
+// and "could not execute query" from pg_restore. The mysql client errors (e.g. 2013
+// for a lost connection) use codes from 2000 and are not statement errors.
+var statementError = regexp.MustCompile(`^ERROR 1\d{3} \([0-9A-Z]+\) at line \d+|^psql:[^:]*:\d+: ERROR:|could not execute query`)
+
+// countErrors takes the error output from an import tool and returns the number of
+// errors for statements in the backup.
+func countErrors(output string) int {
+	count := 0
+	for _, line := range strings.Split(output, "\n") {
+		if statementError.MatchString(strings.TrimSpace(line)) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// fatalError takes the output from an import tool and returns the first error that
+// is not for a statement in the backup, such as access denied or unable to connect,
+// or an empty string.
+func fatalError(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.Contains(line, "ERROR") && !statementError.MatchString(line) {
+			return line
+		}
+	}
+
+	return ""
+}
+
+// warningLocation matches the parts of a warning that change for each statement, such as
+// the psql file and line prefix and the row or line number.
+var warningLocation = regexp.MustCompile(`^psql:[^:]*:\d+: |\s+at (row|line) \d+`)
+
+// Warnings takes the output from an import tool and returns the warnings and the unique
+// warnings, the password warning from mysql is ignored.
+func Warnings(output string) (warnings []string, unique []string) {
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.Contains(strings.ToLower(line), "warning") || strings.Contains(line, "Using a password on the command line") {
+			continue
+		}
+
+		warnings = append(warnings, line)
+
+		key := warningLocation.ReplaceAllString(line, "")
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, key)
+		}
+	}
+
+	return warnings, unique
+}
+
+// firstError takes the error output from an import tool and returns the first
+// error, ignoring warnings such as using a password on the command line.
+func firstError(output string) string {
+	var first string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.Contains(line, "[Warning]") || strings.HasPrefix(line, "Warning:") {
+			continue
+		}
+
+		if strings.Contains(line, "ERROR") {
+			return line
+		}
+
+		if first == "" {
+			first = line
+		}
+	}
+
+	return first
+}
+
 // Validate takes import options and returns an
 // error if the options are missing details
 // we need to run the import.
@@ -133,13 +828,28 @@ func Validate(opts *ImportOptions) error {
 		return fmt.Errorf("import options is missing the port")
 	}
 
-	if opts.Hostname == "" {
+	if opts.Hostname == "" && opts.Socket == "" {
 		return fmt.Errorf("import options is missing the hostname")
 	}
 
 	return nil
 }
 
+// restoreTool takes the path to psql and returns the path to pg_restore, using the
+// same directory as psql so the versions match.
+func restoreTool(psql string) (string, error) {
+	if t := filepath.Join(filepath.Dir(psql), PostgresRestoreCommand); pathexists.IsFile(t) {
+		return t, nil
+	}
+
+	t, err := exec.LookPath(PostgresRestoreCommand)
+	if err != nil {
+		return "", fmt.Errorf("unable to find the `%q` import tool", PostgresRestoreCommand)
+	}
+
+	return t, nil
+}
+
 // DefaultImportToolFinder is a tool that is used to find the executable path
 // to the import tool such as mysql or psql. It is a func that is provided
 // to the Importer.Import func. It will return the path to the executable
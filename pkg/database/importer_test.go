@@ -1,7 +1,14 @@
 package database
 
 import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -93,3 +100,517 @@ func TestDefaultImportToolFinder(t *testing.T) {
 		})
 	}
 }
+
+func TestCountErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   int
+	}{
+		{
+			name:   "mysql errors are counted and warnings are ignored",
+			output: "mysql: [Warning] Using a password on the command line interface can be insecure.\nERROR 1064 (42000) at line 3: You have an error in your SQL syntax\nERROR 1146 (42S02) at line 9: Table 'example.users' doesn't exist\n",
+			want:   2,
+		},
+		{
+			name:   "postgres errors are counted",
+			output: "psql:/tmp/backup.sql:12: ERROR:  syntax error at or near \"CREAT\"\n",
+			want:   1,
+		},
+		{
+			name:   "pg_restore errors are counted",
+			output: "pg_restore: error: could not execute query: ERROR:  relation \"users\" already exists\npg_restore: warning: errors ignored on restore: 1\n",
+			want:   1,
+		},
+		{
+			name:   "errors that are not for a statement are not counted",
+			output: "ERROR 1045 (28000): Access denied for user 'nitro'@'localhost' (using password: YES)\nERROR 2013 (HY000) at line 40: Lost connection to MySQL server during query\n",
+			want:   0,
+		},
+		{
+			name:   "empty output has no errors",
+			output: "",
+			want:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countErrors(tt.output); got != tt.want {
+				t.Errorf("countErrors() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	tests := []struct {
+		name         string
+		output       string
+		wantWarnings int
+		wantUnique   []string
+	}{
+		{
+			name:         "mysql warnings at different rows are grouped",
+			output:       "mysql: [Warning] Using a password on the command line interface can be insecure.\nWarning (Code 1366): Incorrect integer value: '' for column 'id' at row 1\nWarning (Code 1366): Incorrect integer value: '' for column 'id' at row 2\nWarning (Code 1265): Data truncated for column 'title' at row 4\n",
+			wantWarnings: 3,
+			wantUnique:   []string{"Warning (Code 1366): Incorrect integer value: '' for column 'id'", "Warning (Code 1265): Data truncated for column 'title'"},
+		},
+		{
+			name:         "postgres warnings at different lines are grouped",
+			output:       "psql:/tmp/backup.sql:12: WARNING:  no privileges were granted for \"public\"\npsql:/tmp/backup.sql:40: WARNING:  no privileges were granted for \"public\"\n",
+			wantWarnings: 2,
+			wantUnique:   []string{"WARNING:  no privileges were granted for \"public\""},
+		},
+		{
+			name:   "output without warnings returns nothing",
+			output: "SET\nCREATE TABLE\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, unique := Warnings(tt.output)
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("Warnings() warnings = %d, want %d", len(warnings), tt.wantWarnings)
+			}
+			if !reflect.DeepEqual(unique, tt.wantUnique) {
+				t.Errorf("Warnings() unique = %q, want %q", unique, tt.wantUnique)
+			}
+		})
+	}
+}
+
+func TestFirstError(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name:   "mysql password warnings are ignored",
+			output: "mysql: [Warning] Using a password on the command line interface can be insecure.\nERROR 1064 (42000) at line 3: You have an error in your SQL syntax\n",
+			want:   "ERROR 1064 (42000) at line 3: You have an error in your SQL syntax",
+		},
+		{
+			name:   "postgres errors are returned",
+			output: "psql:/tmp/backup.sql:12: ERROR:  syntax error at or near \"CREAT\"\n",
+			want:   "psql:/tmp/backup.sql:12: ERROR:  syntax error at or near \"CREAT\"",
+		},
+		{
+			name:   "the first line is returned when there is no error prefix",
+			output: "unable to connect\nsecond line\n",
+			want:   "unable to connect",
+		},
+		{
+			name:   "empty output returns an empty string",
+			output: "",
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := firstError(tt.output); got != tt.want {
+				t.Errorf("firstError() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArchiveFormat(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "toc.dat"), []byte("PGDMP"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{
+			name: "custom format archives are detected",
+			path: "./testdata/postgres-custom.dump",
+			want: "custom",
+		},
+		{
+			name: "directory format archives are detected",
+			path: dir,
+			want: "directory",
+		},
+		{
+			name: "plain sql backups are not archives",
+			path: "./testdata/postgres-backup.sql",
+			want: "",
+		},
+		{
+			name: "directories without a toc are not archives",
+			path: "./testdata",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ArchiveFormat(tt.path); got != tt.want {
+				t.Errorf("ArchiveFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_commands(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       *ImportOptions
+		wantCreate []string
+		wantImport []string
+		wantDrop   []string
+	}{
+		{
+			name:       "mysql connects using the hostname",
+			opts:       &ImportOptions{Engine: "mysql", Hostname: "mysql-8.0-3306.database.nitro", Port: "3306", File: "/tmp/backup.sql"},
+			wantCreate: []string{"--user=nitro", "--host=mysql-8.0-3306.database.nitro", "--port=3306", "-e CREATE DATABASE IF NOT EXISTS example;"},
+			wantImport: []string{"--user=nitro", "--host=mysql-8.0-3306.database.nitro", "--port=3306", "--show-warnings", "example", "-e source /tmp/backup.sql"},
+			wantDrop:   []string{"--user=nitro", "--host=mysql-8.0-3306.database.nitro", "--port=3306", "-e DROP DATABASE IF EXISTS example;"},
+		},
+		{
+			name:       "mysql connects using the socket when set",
+			opts:       &ImportOptions{Engine: "mysql", Hostname: "mysql-8.0-3306.database.nitro", Port: "3306", File: "/tmp/backup.sql", Socket: DefaultSocket("mysql")},
+			wantCreate: []string{"--user=nitro", "--socket=/var/run/mysqld/mysqld.sock", "-e CREATE DATABASE IF NOT EXISTS example;"},
+			wantImport: []string{"--user=nitro", "--socket=/var/run/mysqld/mysqld.sock", "--show-warnings", "example", "-e source /tmp/backup.sql"},
+			wantDrop:   []string{"--user=nitro", "--socket=/var/run/mysqld/mysqld.sock", "-e DROP DATABASE IF EXISTS example;"},
+		},
+		{
+			name:       "postgres connects using the hostname",
+			opts:       &ImportOptions{Engine: "postgres", Hostname: "postgres-13-5432.database.nitro", Port: "5432", File: "/tmp/backup.sql"},
+			wantCreate: []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "-c CREATE DATABASE example;"},
+			wantImport: []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "example", "--file=/tmp/backup.sql"},
+			wantDrop:   []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "-c DROP DATABASE IF EXISTS example;"},
+		},
+		{
+			name:       "postgres stops at the first error when asked",
+			opts:       &ImportOptions{Engine: "postgres", Hostname: "postgres-13-5432.database.nitro", Port: "5432", File: "/tmp/backup.sql", OnError: OnErrorStop},
+			wantCreate: []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "-c CREATE DATABASE example;"},
+			wantImport: []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "example", "--file=/tmp/backup.sql", "--set=ON_ERROR_STOP=1"},
+			wantDrop:   []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "-c DROP DATABASE IF EXISTS example;"},
+		},
+		{
+			name:       "postgres connects using the socket directory when set",
+			opts:       &ImportOptions{Engine: "postgres", Hostname: "postgres-13-5432.database.nitro", Port: "5432", File: "/tmp/backup.sql", Socket: DefaultSocket("postgres"), Validate: true},
+			wantCreate: []string{"--host=/var/run/postgresql", "--port=5432", "--username=nitro", "-c CREATE DATABASE example;"},
+			wantImport: []string{"--host=/var/run/postgresql", "--port=5432", "--username=nitro", "example", "--file=/tmp/backup.sql", "--single-transaction", "--set=ON_ERROR_STOP=1"},
+			wantDrop:   []string{"--host=/var/run/postgresql", "--port=5432", "--username=nitro", "-c DROP DATABASE IF EXISTS example;"},
+		},
+		{
+			name:       "postgres custom format archives use parallel jobs",
+			opts:       &ImportOptions{Engine: "postgres", Hostname: "postgres-13-5432.database.nitro", Port: "5432", File: "/tmp/backup.dump", Format: "custom", Jobs: 4},
+			wantCreate: []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "-c CREATE DATABASE example;"},
+			wantImport: []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "--dbname=example", "--no-owner", "--jobs=4", "/tmp/backup.dump"},
+			wantDrop:   []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "-c DROP DATABASE IF EXISTS example;"},
+		},
+		{
+			name:       "postgres archives ignore jobs when validating",
+			opts:       &ImportOptions{Engine: "postgres", Hostname: "postgres-13-5432.database.nitro", Port: "5432", File: "/tmp/backup", Format: "directory", Jobs: 4, Validate: true},
+			wantCreate: []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "-c CREATE DATABASE example;"},
+			wantImport: []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "--dbname=example", "--no-owner", "--single-transaction", "--exit-on-error", "/tmp/backup"},
+			wantDrop:   []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "-c DROP DATABASE IF EXISTS example;"},
+		},
+		{
+			name:       "mysql forces the import when continuing on errors",
+			opts:       &ImportOptions{Engine: "mysql", Hostname: "mysql-8.0-3306.database.nitro", Port: "3306", File: "/tmp/backup.sql", OnError: OnErrorContinue},
+			wantCreate: []string{"--user=nitro", "--host=mysql-8.0-3306.database.nitro", "--port=3306", "-e CREATE DATABASE IF NOT EXISTS example;"},
+			wantImport: []string{"--user=nitro", "--host=mysql-8.0-3306.database.nitro", "--port=3306", "--show-warnings", "--force", "example", "-e source /tmp/backup.sql"},
+			wantDrop:   []string{"--user=nitro", "--host=mysql-8.0-3306.database.nitro", "--port=3306", "-e DROP DATABASE IF EXISTS example;"},
+		},
+		{
+			name:       "postgres does not stop when continuing on errors",
+			opts:       &ImportOptions{Engine: "postgres", Hostname: "postgres-13-5432.database.nitro", Port: "5432", File: "/tmp/backup.sql", OnError: OnErrorContinue},
+			wantCreate: []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "-c CREATE DATABASE example;"},
+			wantImport: []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "example", "--file=/tmp/backup.sql", "--set=ON_ERROR_STOP=0"},
+			wantDrop:   []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "-c DROP DATABASE IF EXISTS example;"},
+		},
+		{
+			name:       "postgres archives do not exit when continuing on errors",
+			opts:       &ImportOptions{Engine: "postgres", Hostname: "postgres-13-5432.database.nitro", Port: "5432", File: "/tmp/backup.dump", Format: "custom", OnError: OnErrorContinue},
+			wantCreate: []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "-c CREATE DATABASE example;"},
+			wantImport: []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "--dbname=example", "--no-owner", "/tmp/backup.dump"},
+			wantDrop:   []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "-c DROP DATABASE IF EXISTS example;"},
+		},
+		{
+			name:       "mysql reads streamed backups from stdin",
+			opts:       &ImportOptions{Engine: "mysql", Hostname: "mysql-8.0-3306.database.nitro", Port: "3306", Stdin: strings.NewReader("SELECT 1;")},
+			wantCreate: []string{"--user=nitro", "--host=mysql-8.0-3306.database.nitro", "--port=3306", "-e CREATE DATABASE IF NOT EXISTS example;"},
+			wantImport: []string{"--user=nitro", "--host=mysql-8.0-3306.database.nitro", "--port=3306", "--show-warnings", "example"},
+			wantDrop:   []string{"--user=nitro", "--host=mysql-8.0-3306.database.nitro", "--port=3306", "-e DROP DATABASE IF EXISTS example;"},
+		},
+		{
+			name:       "postgres reads streamed backups from stdin",
+			opts:       &ImportOptions{Engine: "postgres", Hostname: "postgres-13-5432.database.nitro", Port: "5432", Stdin: strings.NewReader("SELECT 1;")},
+			wantCreate: []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "-c CREATE DATABASE example;"},
+			wantImport: []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "example"},
+			wantDrop:   []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "-c DROP DATABASE IF EXISTS example;"},
+		},
+		{
+			name:       "mysql connects with the credentials when set",
+			opts:       &ImportOptions{Engine: "mysql", Hostname: "mysql-8.0-3306.database.nitro", Port: "3306", File: "/tmp/backup.sql", User: "root", Password: "secret"},
+			wantCreate: []string{"--user=root", "--host=mysql-8.0-3306.database.nitro", "--port=3306", "-e CREATE DATABASE IF NOT EXISTS example;"},
+			wantImport: []string{"--user=root", "--host=mysql-8.0-3306.database.nitro", "--port=3306", "--show-warnings", "example", "-e source /tmp/backup.sql"},
+			wantDrop:   []string{"--user=root", "--host=mysql-8.0-3306.database.nitro", "--port=3306", "-e DROP DATABASE IF EXISTS example;"},
+		},
+		{
+			name:       "validating always stops on errors",
+			opts:       &ImportOptions{Engine: "postgres", Hostname: "postgres-13-5432.database.nitro", Port: "5432", File: "/tmp/backup.sql", OnError: OnErrorContinue, Validate: true},
+			wantCreate: []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "-c CREATE DATABASE example;"},
+			wantImport: []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "example", "--file=/tmp/backup.sql", "--single-transaction", "--set=ON_ERROR_STOP=1"},
+			wantDrop:   []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "-c DROP DATABASE IF EXISTS example;"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			create, imp, drop := commands(tt.opts, "example")
+			if !reflect.DeepEqual(create, tt.wantCreate) {
+				t.Errorf("commands() create = %v, want %v", create, tt.wantCreate)
+			}
+			if !reflect.DeepEqual(imp, tt.wantImport) {
+				t.Errorf("commands() import = %v, want %v", imp, tt.wantImport)
+			}
+			if !reflect.DeepEqual(drop, tt.wantDrop) {
+				t.Errorf("commands() drop = %v, want %v", drop, tt.wantDrop)
+			}
+		})
+	}
+}
+
+func Test_analyzeCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        *ImportOptions
+		tables      []string
+		wantTables  []string
+		wantAnalyze []string
+	}{
+		{
+			name:        "mysql lists the tables of the database and analyzes them",
+			opts:        &ImportOptions{Engine: "mysql", Hostname: "mysql-8.0-3306.database.nitro", Port: "3306", DatabaseName: "example"},
+			tables:      []string{"craft_users", "craft_entries"},
+			wantTables:  []string{"--user=nitro", "--host=mysql-8.0-3306.database.nitro", "--port=3306", "--skip-column-names", "--silent", "-e SELECT table_name FROM information_schema.tables WHERE table_schema = 'example' AND table_type = 'BASE TABLE';"},
+			wantAnalyze: []string{"--user=nitro", "--host=mysql-8.0-3306.database.nitro", "--port=3306", "example", "-e ANALYZE TABLE `craft_users`, `craft_entries`;"},
+		},
+		{
+			name:        "mysql quotes the database and tables",
+			opts:        &ImportOptions{Engine: "mysql", Hostname: "mysql-8.0-3306.database.nitro", Port: "3306", DatabaseName: "it's"},
+			tables:      []string{"odd`name"},
+			wantTables:  []string{"--user=nitro", "--host=mysql-8.0-3306.database.nitro", "--port=3306", "--skip-column-names", "--silent", "-e SELECT table_name FROM information_schema.tables WHERE table_schema = 'it''s' AND table_type = 'BASE TABLE';"},
+			wantAnalyze: []string{"--user=nitro", "--host=mysql-8.0-3306.database.nitro", "--port=3306", "it's", "-e ANALYZE TABLE `odd``name`;"},
+		},
+		{
+			name:        "postgres vacuums the database",
+			opts:        &ImportOptions{Engine: "postgres", Hostname: "postgres-13-5432.database.nitro", Port: "5432", DatabaseName: "example"},
+			wantAnalyze: []string{"--host=postgres-13-5432.database.nitro", "--port=5432", "--username=nitro", "example", "-c VACUUM ANALYZE;"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantTables != nil {
+				if got := tablesCommand(tt.opts); !reflect.DeepEqual(got, tt.wantTables) {
+					t.Errorf("tablesCommand() = %q, want %q", got, tt.wantTables)
+				}
+			}
+
+			if got := analyzeCommand(tt.opts, tt.tables); !reflect.DeepEqual(got, tt.wantAnalyze) {
+				t.Errorf("analyzeCommand() = %q, want %q", got, tt.wantAnalyze)
+			}
+		})
+	}
+}
+
+func TestCredentials(t *testing.T) {
+	tests := []struct {
+		name         string
+		engine       string
+		env          []string
+		wantUser     string
+		wantPassword string
+	}{
+		{
+			name:         "mysql prefers root when the root password is set",
+			engine:       "mysql",
+			env:          []string{"MYSQL_ROOT_PASSWORD=root", "MYSQL_DATABASE=nitro", "MYSQL_USER=craft", "MYSQL_PASSWORD=secret"},
+			wantUser:     "root",
+			wantPassword: "root",
+		},
+		{
+			name:         "mysql uses the user from the environment without a root password",
+			engine:       "mysql",
+			env:          []string{"MYSQL_RANDOM_ROOT_PASSWORD=yes", "MYSQL_DATABASE=nitro", "MYSQL_USER=craft", "MYSQL_PASSWORD=secret"},
+			wantUser:     "craft",
+			wantPassword: "secret",
+		},
+		{
+			name:     "mysql uses root without a password when empty passwords are allowed",
+			engine:   "mysql",
+			env:      []string{"MYSQL_ALLOW_EMPTY_PASSWORD=yes", "MYSQL_USER=craft", "MYSQL_PASSWORD=secret"},
+			wantUser: "root",
+		},
+		{
+			name:         "mysql uses root when there is only a root password",
+			engine:       "mysql",
+			env:          []string{"MYSQL_ROOT_PASSWORD=secret"},
+			wantUser:     "root",
+			wantPassword: "secret",
+		},
+		{
+			name:         "postgres uses the user from the environment",
+			engine:       "postgres",
+			env:          []string{"POSTGRES_USER=craft", "POSTGRES_DB=nitro", "POSTGRES_PASSWORD=secret"},
+			wantUser:     "craft",
+			wantPassword: "secret",
+		},
+		{
+			name:         "postgres uses the default image user when only the password is set",
+			engine:       "postgres",
+			env:          []string{"POSTGRES_PASSWORD=secret"},
+			wantUser:     "postgres",
+			wantPassword: "secret",
+		},
+		{
+			name:   "containers without credentials use the defaults",
+			engine: "mysql",
+			env:    []string{"PATH=/usr/local/bin"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, password := Credentials(tt.engine, tt.env)
+			if user != tt.wantUser || password != tt.wantPassword {
+				t.Errorf("Credentials() = %q, %q, want %q, %q", user, password, tt.wantUser, tt.wantPassword)
+			}
+		})
+	}
+}
+
+// failingReader returns the data and then the error instead of io.EOF.
+type failingReader struct {
+	data []byte
+	err  error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, r.err
+	}
+
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+
+	return n, nil
+}
+
+func TestImporter_execOutputKillsTheToolOnReadErrors(t *testing.T) {
+	importer := &importer{}
+
+	// the tool only reports finished when it reaches the end of its input
+	stdin := &failingReader{data: []byte("SELECT 1;\n"), err: errors.New("the stream was interrupted")}
+	out, err := importer.execOutput("sh", nil, []string{"-c", "cat > /dev/null; echo finished"}, stdin)
+	if err == nil || !strings.Contains(err.Error(), "the stream was interrupted") {
+		t.Fatalf("expected the read error to be returned, got %v", err)
+	}
+
+	if strings.Contains(out, "finished") {
+		t.Errorf("expected the tool to be killed before reaching the end of the input, got %q", out)
+	}
+}
+
+func TestImporter_execOutputLimitsStdout(t *testing.T) {
+	importer := &importer{}
+
+	// psql writes a command tag for every statement
+	out, err := importer.execOutput("sh", nil, []string{"-c", fmt.Sprintf("i=0; while [ $i -lt %d ]; do echo INSERT 0 1; i=$((i+1)); done; echo 'ERROR:  failed' >&2", MaxStdout/10)}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(out) > MaxStdout+100 {
+		t.Errorf("expected the stdout to be limited to %d bytes, got %d", MaxStdout, len(out))
+	}
+
+	if !strings.HasPrefix(out, "ERROR:  failed\n") || !strings.HasSuffix(out, "INSERT 0 1\n[the output was truncated]\n") {
+		t.Errorf("expected the errors and the complete lines of stdout, got %q...%q", out[:30], out[len(out)-40:])
+	}
+}
+
+func TestFatalError(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name:   "statement errors are not fatal",
+			output: "ERROR 1064 (42000) at line 3: You have an error in your SQL syntax\npsql:/tmp/backup.sql:12: ERROR:  syntax error at or near \"CREAT\"\n",
+		},
+		{
+			name:   "access denied is fatal",
+			output: "ERROR 1064 (42000) at line 3: You have an error in your SQL syntax\nERROR 1045 (28000): Access denied for user 'nitro'@'localhost' (using password: YES)\n",
+			want:   "ERROR 1045 (28000): Access denied for user 'nitro'@'localhost' (using password: YES)",
+		},
+		{
+			name:   "connection errors are fatal",
+			output: "ERROR 2002 (HY000): Can't connect to local MySQL server through socket '/var/run/mysqld/mysqld.sock' (2)\n",
+			want:   "ERROR 2002 (HY000): Can't connect to local MySQL server through socket '/var/run/mysqld/mysqld.sock' (2)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fatalError(tt.output); got != tt.want {
+				t.Errorf("fatalError() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnviron(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *ImportOptions
+		want string
+	}{
+		{
+			name: "mysql uses the default password",
+			opts: &ImportOptions{Engine: "mysql"},
+			want: "MYSQL_PWD=nitro",
+		},
+		{
+			name: "mysql uses the password from the credentials",
+			opts: &ImportOptions{Engine: "mysql", User: "root", Password: "secret"},
+			want: "MYSQL_PWD=secret",
+		},
+		{
+			name: "mysql uses an empty password without prompting",
+			opts: &ImportOptions{Engine: "mysql", User: "root"},
+			want: "MYSQL_PWD=",
+		},
+		{
+			name: "postgres uses the password from the credentials",
+			opts: &ImportOptions{Engine: "postgres", User: "craft", Password: "secret"},
+			want: "PGPASSWORD=secret",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := environ(tt.opts)
+			if got := env[len(env)-1]; got != tt.want {
+				t.Errorf("environ() = %q, want %q", got, tt.want)
+			}
+
+			for _, arg := range connection(tt.opts) {
+				if strings.HasPrefix(arg, "-p") || strings.Contains(arg, "secret") {
+					t.Errorf("expected the password to not be in the arguments, got %q", arg)
+				}
+			}
+		})
+	}
+}
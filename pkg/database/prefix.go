@@ -0,0 +1,84 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// prefixPattern matches the characters allowed in a table prefix.
+var prefixPattern = regexp.MustCompile(`^[A-Za-z0-9_]*$`)
+
+// ValidatePrefixes checks the table prefixes to rewrite during an import, the
+// prefix to replace is required and the new prefix can be empty to remove it.
+func ValidatePrefixes(from, to string) error {
+	if from == "" {
+		return fmt.Errorf("the prefix to replace cannot be empty")
+	}
+
+	for _, p := range []string{from, to} {
+		if !prefixPattern.MatchString(p) {
+			return fmt.Errorf("the table prefix %q can only contain letters, numbers, and underscores", p)
+		}
+	}
+
+	return nil
+}
+
+// PrefixRewriter replaces the table prefix in the lines of a sql backup. It is a
+// textual rewrite, not a sql parser, so it changes the identifiers that:
+//
+//   - are quoted with backticks or double quotes (e.g. `prod_users` or "prod_users")
+//   - are qualified with a schema (e.g. public.prod_users or 'public.prod_users_id_seq')
+//   - follow a keyword such as TABLE, INTO, REFERENCES, or INDEX
+//
+// Values in the data that match one of those forms are changed too, and identifiers
+// in other places (e.g. bare column references in views or triggers) are not.
+type PrefixRewriter struct {
+	to       string
+	patterns []*regexp.Regexp
+}
+
+// NewPrefixRewriter returns a rewriter that replaces the from prefix with the to prefix.
+func NewPrefixRewriter(from, to string) (*PrefixRewriter, error) {
+	if err := ValidatePrefixes(from, to); err != nil {
+		return nil, err
+	}
+
+	p := regexp.QuoteMeta(from)
+
+	return &PrefixRewriter{
+		to: to,
+		patterns: []*regexp.Regexp{
+			regexp.MustCompile("([`\"])" + p),
+			regexp.MustCompile(`([A-Za-z0-9_]\.)` + p),
+			regexp.MustCompile(`(?i)(\b(?:TABLE|TABLES|ONLY|INTO|REFERENCES|UPDATE|FROM|JOIN|EXISTS|INDEX|CONSTRAINT|SEQUENCE|VIEW|COPY|ON)\s+)` + p),
+		},
+	}, nil
+}
+
+// Rewrite returns the line with the table prefix replaced.
+func (r *PrefixRewriter) Rewrite(line string) string {
+	// the prefixes are validated, so the new prefix cannot contain a $ expansion
+	for _, p := range r.patterns {
+		line = p.ReplaceAllString(line, "${1}"+r.to)
+	}
+
+	return line
+}
+
+// RewritePrefix takes a plain sql backup and streams it, line by line, into a
+// temporary file with the table prefix replaced. It returns the path to the new
+// file, which is created in dir or the default temp directory when dir is empty.
+func RewritePrefix(file, dir, from, to string) (string, error) {
+	r, err := NewPrefixRewriter(from, to)
+	if err != nil {
+		return "", err
+	}
+
+	rewritten, err := rewriteFile(file, dir, "nitro-import-prefix-", r.Rewrite)
+	if err != nil {
+		return "", fmt.Errorf("unable to rewrite the table prefix, %w", err)
+	}
+
+	return rewritten, nil
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestPrefixRewriter_Rewrite(t *testing.T) {
+	tests := []struct {
+		name string
+		to   string
+		line string
+		want string
+	}{
+		{
+			name: "mysql quoted identifiers are replaced",
+			to:   "craft_",
+			line: "CREATE TABLE `prod_users` (\n",
+			want: "CREATE TABLE `craft_users` (\n",
+		},
+		{
+			name: "mysql inserts and foreign keys are replaced",
+			to:   "craft_",
+			line: "ALTER TABLE `prod_entries` ADD CONSTRAINT `prod_entries_authorId_fk` FOREIGN KEY (`authorId`) REFERENCES `prod_users` (`id`);\n",
+			want: "ALTER TABLE `craft_entries` ADD CONSTRAINT `craft_entries_authorId_fk` FOREIGN KEY (`authorId`) REFERENCES `craft_users` (`id`);\n",
+		},
+		{
+			name: "postgres schema qualified identifiers are replaced",
+			to:   "craft_",
+			line: "ALTER TABLE ONLY public.prod_users ALTER COLUMN id SET DEFAULT nextval('public.prod_users_id_seq'::regclass);\n",
+			want: "ALTER TABLE ONLY public.craft_users ALTER COLUMN id SET DEFAULT nextval('public.craft_users_id_seq'::regclass);\n",
+		},
+		{
+			name: "identifiers after keywords are replaced",
+			to:   "craft_",
+			line: "insert into prod_users values (1, 'admin');\n",
+			want: "insert into craft_users values (1, 'admin');\n",
+		},
+		{
+			name: "the prefix can be removed",
+			to:   "",
+			line: "INSERT INTO `prod_users` VALUES (1,'admin');\n",
+			want: "INSERT INTO `users` VALUES (1,'admin');\n",
+		},
+		{
+			name: "values that are not identifiers are not changed",
+			to:   "craft_",
+			line: "INSERT INTO `prod_users` VALUES (1,'prod_admin','prod_ is the old prefix');\n",
+			want: "INSERT INTO `craft_users` VALUES (1,'prod_admin','prod_ is the old prefix');\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewPrefixRewriter("prod_", tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := r.Rewrite(tt.line); got != tt.want {
+				t.Errorf("Rewrite() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePrefixes(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		wantErr bool
+	}{
+		{
+			name: "prefixes with letters, numbers, and underscores are valid",
+			from: "prod2_",
+			to:   "craft_",
+		},
+		{
+			name: "the new prefix can be empty",
+			from: "prod_",
+		},
+		{
+			name:    "the prefix to replace is required",
+			to:      "craft_",
+			wantErr: true,
+		},
+		{
+			name:    "prefixes with other characters are invalid",
+			from:    "prod_",
+			to:      "$1_",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidatePrefixes(tt.from, tt.to); (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePrefixes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRewritePrefix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-prefix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the last line does not end with a new line
+	content := "DROP TABLE IF EXISTS `prod_users`;\nCREATE TABLE `prod_users` (`id` int NOT NULL);\nINSERT INTO `prod_users` VALUES (1);"
+	file := dir + "/backup.sql"
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rewritten, err := RewritePrefix(file, dir, "prod_", "craft_")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ioutil.ReadFile(rewritten)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "DROP TABLE IF EXISTS `craft_users`;\nCREATE TABLE `craft_users` (`id` int NOT NULL);\nINSERT INTO `craft_users` VALUES (1);"
+	if string(got) != want {
+		t.Errorf("RewritePrefix() = %q, want %q", got, want)
+	}
+}
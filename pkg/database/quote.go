@@ -0,0 +1,26 @@
+package database
+
+import "strings"
+
+// QuoteIdentifier returns the name of a database, table, or user quoted for the engine, so
+// it can be used in a statement. Mysql uses backticks and postgres uses double quotes, and
+// the quote character in the name is escaped by doubling it.
+func QuoteIdentifier(engine, name string) string {
+	quote := `"`
+	if engine == "mysql" || engine == "mariadb" {
+		quote = "`"
+	}
+
+	return quote + strings.ReplaceAll(name, quote, quote+quote) + quote
+}
+
+// QuoteString returns the value quoted as a string literal for the engine, so it can be
+// used in a statement. The single quote is escaped by doubling it, and mysql also treats
+// backslashes as escapes.
+func QuoteString(engine, value string) string {
+	if engine == "mysql" || engine == "mariadb" {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+	}
+
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
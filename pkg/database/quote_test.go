@@ -0,0 +1,73 @@
+package database
+
+import "testing"
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		name   string
+		engine string
+		input  string
+		want   string
+	}{
+		{
+			name:   "mysql uses backticks",
+			engine: "mysql",
+			input:  "craft_db",
+			want:   "`craft_db`",
+		},
+		{
+			name:   "mysql backticks are escaped",
+			engine: "mysql",
+			input:  "craft`; DROP DATABASE nitro; --",
+			want:   "`craft``; DROP DATABASE nitro; --`",
+		},
+		{
+			name:   "postgres uses double quotes",
+			engine: "postgres",
+			input:  `craft"db`,
+			want:   `"craft""db"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := QuoteIdentifier(tt.engine, tt.input); got != tt.want {
+				t.Errorf("QuoteIdentifier() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteString(t *testing.T) {
+	tests := []struct {
+		name   string
+		engine string
+		input  string
+		want   string
+	}{
+		{
+			name:   "quotes are escaped",
+			engine: "postgres",
+			input:  "it's",
+			want:   "'it''s'",
+		},
+		{
+			name:   "postgres backslashes are not escaped",
+			engine: "postgres",
+			input:  `craft\db`,
+			want:   `'craft\db'`,
+		},
+		{
+			name:   "mysql backslashes are escaped",
+			engine: "mysql",
+			input:  `craft\' OR 1=1 -- `,
+			want:   `'craft\\'' OR 1=1 -- '`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := QuoteString(tt.engine, tt.input); got != tt.want {
+				t.Errorf("QuoteString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
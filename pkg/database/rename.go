@@ -0,0 +1,123 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// databaseStatement matches the lines of a backup that select, create, or change a
+// database, such as the statements written by mysqldump --databases and pg_dump
+// --create or pg_dumpall.
+var databaseStatement = regexp.MustCompile(`(?i)^\s*(USE\s|\\connect\s|\\c\s|-- Current Database:|(CREATE|DROP|ALTER)\s+(DATABASE|SCHEMA)\s|COMMENT ON DATABASE\s|(GRANT|REVOKE)\s.*\sON DATABASE\s)`)
+
+// createOrDrop matches the statements that create or remove a database, Postgres
+// imports into a database that already exists and cannot drop the open database.
+var createOrDrop = regexp.MustCompile(`(?i)^\s*(CREATE|DROP)\s+DATABASE\s`)
+
+// mysqlDatabaseStatement and postgresDatabaseStatement match the statements of a backup
+// that select, create, or change a database for each engine. A Postgres schema is part
+// of the database, so only MySQL schema statements are matched.
+var (
+	mysqlDatabaseStatement    = regexp.MustCompile(`(?i)^\s*(USE\s|(CREATE|DROP|ALTER)\s+(DATABASE|SCHEMA)\s)`)
+	postgresDatabaseStatement = regexp.MustCompile(`(?i)^\s*(\\connect\s|\\c\s|(CREATE|DROP|ALTER)\s+DATABASE\s|COMMENT ON DATABASE\s|(GRANT|REVOKE)\s.*\sON DATABASE\s)`)
+)
+
+// StripDatabaseStatements returns a rewrite func that comments out the statements in a
+// backup for the engine that select, create, or change a database, so the backup is
+// imported into the database it is connected to, e.g. when validating a backup.
+func StripDatabaseStatements(engine string) func(line string) string {
+	statement := mysqlDatabaseStatement
+	if engine == "postgres" {
+		statement = postgresDatabaseStatement
+	}
+
+	return func(line string) string {
+		if !statement.MatchString(line) {
+			return line
+		}
+
+		return "-- " + line
+	}
+}
+
+// ValidateRenameFrom checks the name of the database in a backup to rename, the name
+// is matched as text so it cannot contain spaces, quotes, backslashes, or semicolons.
+func ValidateRenameFrom(name string) error {
+	if name == "" {
+		return fmt.Errorf("the database to rename cannot be empty")
+	}
+
+	if strings.ContainsAny(name, " \t\r\n`\"';\\") {
+		return fmt.Errorf("the database to rename %q cannot contain spaces, quotes, backslashes, or semicolons", name)
+	}
+
+	return nil
+}
+
+// DatabaseRenamer replaces the name of a database in the statements of a backup that
+// select or create the database, e.g. USE `production` and \connect production, so
+// the backup is imported into a database with another name. The name is replaced on
+// those lines wherever it is a whole word, quoted or not, which includes an owner or
+// role with the same name as the database.
+//
+// For Postgres, the CREATE DATABASE and DROP DATABASE statements for the database
+// are commented out since the import connects to the database before the backup runs.
+type DatabaseRenamer struct {
+	engine  string
+	to      string
+	pattern *regexp.Regexp
+}
+
+// NewDatabaseRenamer returns a renamer that replaces the from database with the to
+// database in a backup for the engine (e.g. mysql or postgres).
+func NewDatabaseRenamer(engine, from, to string) (*DatabaseRenamer, error) {
+	if err := ValidateRenameFrom(from); err != nil {
+		return nil, err
+	}
+
+	if to == "" {
+		return nil, fmt.Errorf("the database to rename %q to cannot be empty", from)
+	}
+
+	return &DatabaseRenamer{
+		engine:  engine,
+		to:      to,
+		pattern: regexp.MustCompile("(^|[\\s`\"'=])" + regexp.QuoteMeta(from) + "([\\s`\"';]|$)"),
+	}, nil
+}
+
+// Rewrite returns the line with the database renamed, lines that do not select or
+// create a database are not changed.
+func (r *DatabaseRenamer) Rewrite(line string) string {
+	if !databaseStatement.MatchString(line) || !r.pattern.MatchString(line) {
+		return line
+	}
+
+	if r.engine == "postgres" && createOrDrop.MatchString(line) {
+		return "-- " + line
+	}
+
+	return r.pattern.ReplaceAllStringFunc(line, func(m string) string {
+		sm := r.pattern.FindStringSubmatch(m)
+
+		return sm[1] + r.to + sm[2]
+	})
+}
+
+// RenameDatabase takes a plain sql backup and streams it, line by line, into a
+// temporary file with the database renamed. It returns the path to the new file,
+// which is created in dir or the default temp directory when dir is empty.
+func RenameDatabase(file, dir, engine, from, to string) (string, error) {
+	r, err := NewDatabaseRenamer(engine, from, to)
+	if err != nil {
+		return "", err
+	}
+
+	rewritten, err := rewriteFile(file, dir, "nitro-import-rename-", r.Rewrite)
+	if err != nil {
+		return "", fmt.Errorf("unable to rename the database, %w", err)
+	}
+
+	return rewritten, nil
+}
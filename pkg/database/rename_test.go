@@ -0,0 +1,194 @@
+package database
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDatabaseRenamer_Rewrite(t *testing.T) {
+	tests := []struct {
+		name   string
+		engine string
+		line   string
+		want   string
+	}{
+		{
+			name:   "mysql use statements are renamed",
+			engine: "mysql",
+			line:   "USE `production`;\n",
+			want:   "USE `craft`;\n",
+		},
+		{
+			name:   "mysql create statements are renamed",
+			engine: "mysql",
+			line:   "CREATE DATABASE /*!32312 IF NOT EXISTS*/ `production` /*!40100 DEFAULT CHARACTER SET utf8mb4 */;\n",
+			want:   "CREATE DATABASE /*!32312 IF NOT EXISTS*/ `craft` /*!40100 DEFAULT CHARACTER SET utf8mb4 */;\n",
+		},
+		{
+			name:   "mysql dump comments are renamed",
+			engine: "mysql",
+			line:   "-- Current Database: `production`\n",
+			want:   "-- Current Database: `craft`\n",
+		},
+		{
+			name:   "postgres connect statements are renamed",
+			engine: "postgres",
+			line:   "\\connect production\n",
+			want:   "\\connect craft\n",
+		},
+		{
+			name:   "postgres connect statements with a connection string are renamed",
+			engine: "postgres",
+			line:   "\\connect -reuse-previous=on \"dbname='production'\"\n",
+			want:   "\\connect -reuse-previous=on \"dbname='craft'\"\n",
+		},
+		{
+			name:   "postgres create statements are commented out",
+			engine: "postgres",
+			line:   "CREATE DATABASE production WITH TEMPLATE = template0 ENCODING = 'UTF8';\n",
+			want:   "-- CREATE DATABASE production WITH TEMPLATE = template0 ENCODING = 'UTF8';\n",
+		},
+		{
+			name:   "names that contain the database are not renamed",
+			engine: "mysql",
+			line:   "USE `production_archive`;\n",
+			want:   "USE `production_archive`;\n",
+		},
+		{
+			name:   "data is not renamed",
+			engine: "mysql",
+			line:   "INSERT INTO `sites` VALUES (1,'production');\n",
+			want:   "INSERT INTO `sites` VALUES (1,'production');\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewDatabaseRenamer(tt.engine, "production", "craft")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := r.Rewrite(tt.line); got != tt.want {
+				t.Errorf("Rewrite() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripDatabaseStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		engine string
+		line   string
+		want   string
+	}{
+		{
+			name:   "mysql use statements are commented out",
+			engine: "mysql",
+			line:   "USE `production`;\n",
+			want:   "-- USE `production`;\n",
+		},
+		{
+			name:   "mysql create statements are commented out",
+			engine: "mysql",
+			line:   "CREATE DATABASE /*!32312 IF NOT EXISTS*/ `production` /*!40100 DEFAULT CHARACTER SET utf8mb4 */;\n",
+			want:   "-- CREATE DATABASE /*!32312 IF NOT EXISTS*/ `production` /*!40100 DEFAULT CHARACTER SET utf8mb4 */;\n",
+		},
+		{
+			name:   "postgres connect statements are commented out",
+			engine: "postgres",
+			line:   "\\connect production\n",
+			want:   "-- \\connect production\n",
+		},
+		{
+			name:   "postgres alter database statements are commented out",
+			engine: "postgres",
+			line:   "ALTER DATABASE production OWNER TO nitro;\n",
+			want:   "-- ALTER DATABASE production OWNER TO nitro;\n",
+		},
+		{
+			name:   "postgres schema statements are not changed",
+			engine: "postgres",
+			line:   "CREATE SCHEMA craft;\n",
+			want:   "CREATE SCHEMA craft;\n",
+		},
+		{
+			name:   "data is not changed",
+			engine: "mysql",
+			line:   "INSERT INTO `sites` VALUES (1,'USE production');\n",
+			want:   "INSERT INTO `sites` VALUES (1,'USE production');\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripDatabaseStatements(tt.engine)(tt.line); got != tt.want {
+				t.Errorf("StripDatabaseStatements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRenameFrom(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		wantErr bool
+	}{
+		{
+			name: "names with dashes are valid",
+			from: "craft-production",
+		},
+		{
+			name:    "empty names are invalid",
+			wantErr: true,
+		},
+		{
+			name:    "names with quotes are invalid",
+			from:    "production`",
+			wantErr: true,
+		},
+		{
+			name:    "names with spaces are invalid",
+			from:    "craft production",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateRenameFrom(tt.from); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRenameFrom() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenameDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-rename")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := "CREATE DATABASE /*!32312 IF NOT EXISTS*/ `production`;\n\nUSE `production`;\nINSERT INTO `sites` VALUES (1,'production');\n"
+	file := filepath.Join(dir, "backup.sql")
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	renamed, err := RenameDatabase(file, dir, "mysql", "production", "craft")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ioutil.ReadFile(renamed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "CREATE DATABASE /*!32312 IF NOT EXISTS*/ `craft`;\n\nUSE `craft`;\nINSERT INTO `sites` VALUES (1,'production');\n"
+	if string(got) != want {
+		t.Errorf("RenameDatabase() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// rewriteBackup rewrites the lines of the backup in the options with the rewrite func. A
+// streamed backup is rewritten as it is read and a file is rewritten into a new file next
+// to the backup. It returns a func to close the stream or remove the new file.
+func rewriteBackup(opts *ImportOptions, pattern string, rewrite func(line string) string) (func(), error) {
+	if opts.Stdin != nil {
+		stdin := rewriteReader(opts.Stdin, rewrite)
+		opts.Stdin = stdin
+
+		return func() { stdin.Close() }, nil
+	}
+
+	rewritten, err := rewriteFile(opts.File, filepath.Dir(opts.File), pattern, rewrite)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.File = rewritten
+
+	return func() { os.Remove(rewritten) }, nil
+}
+
+// rewriteReader returns a reader with the lines of src rewritten by the rewrite func as
+// they are read. Closing the reader stops the rewrite and waits until src is no longer
+// read, so the rest of src can be read after the reader is closed.
+func rewriteReader(src io.Reader, rewrite func(line string) string) io.ReadCloser {
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		pw.CloseWithError(rewriteLines(pw, src, rewrite))
+	}()
+
+	return &rewrittenReader{PipeReader: pr, done: done}
+}
+
+// rewrittenReader is the reader returned by rewriteReader.
+type rewrittenReader struct {
+	*io.PipeReader
+	done chan struct{}
+}
+
+// Close closes the pipe and waits for the rewrite to stop.
+func (r *rewrittenReader) Close() error {
+	err := r.PipeReader.Close()
+	<-r.done
+
+	return err
+}
+
+// rewriteFile streams the lines of a plain sql backup through the rewrite func into a
+// temporary file, with the pattern as the name, and returns the path to the new file.
+// The file is created in dir or the default temp directory when dir is empty.
+func rewriteFile(file, dir, pattern string, rewrite func(line string) string) (string, error) {
+	src, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	temp, err := ioutil.TempFile(dir, pattern)
+	if err != nil {
+		return "", err
+	}
+	defer temp.Close()
+
+	if err := rewriteLines(temp, src, rewrite); err != nil {
+		os.Remove(temp.Name())
+
+		return "", err
+	}
+
+	return temp.Name(), nil
+}
+
+// rewriteLines writes the lines from src into dst through the rewrite func, the lines
+// are read whole since extended inserts can be longer than a bufio.Scanner allows.
+func rewriteLines(dst io.Writer, src io.Reader, rewrite func(line string) string) error {
+	br := bufio.NewReader(src)
+	bw := bufio.NewWriter(dst)
+
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			if _, err := bw.WriteString(rewrite(line)); err != nil {
+				return err
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
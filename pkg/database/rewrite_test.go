@@ -0,0 +1,30 @@
+package database
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestRewriteReader(t *testing.T) {
+	r, err := NewPrefixRewriter("prod_", "craft_")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the last line does not end with a new line
+	src := strings.NewReader("CREATE TABLE `prod_users` (`id` int NOT NULL);\nINSERT INTO `prod_users` VALUES (1);")
+
+	stdin := rewriteReader(src, r.Rewrite)
+	defer stdin.Close()
+
+	got, err := ioutil.ReadAll(stdin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "CREATE TABLE `craft_users` (`id` int NOT NULL);\nINSERT INTO `craft_users` VALUES (1);"
+	if string(got) != want {
+		t.Errorf("rewriteReader() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,10 @@
+-- MySQL dump converted for a migration
+CREATE TABLE users (
+  id integer NOT NULL AUTO_INCREMENT,
+  email varchar(255) NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE SEQUENCE users_id_seq START WITH 1 INCREMENT BY 1;
+
+ALTER TABLE users OWNER TO nitro;
@@ -0,0 +1,9 @@
+-- MySQL dump 10.13  Distrib 8.0.22, for Linux (x86_64)
+-- generated with mysqldump
+CREATE TABLE `sequences` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) NOT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT INTO `sequences` VALUES (1,'SEQUENCE');
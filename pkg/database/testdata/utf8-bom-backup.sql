@@ -0,0 +1,8 @@
+﻿
+-- MySQL dump 10.17  Distrib 10.3.18-MariaDB, for debian-linux-gnu (x86_64)
+--
+-- Host: localhost    Database: production
+-- ------------------------------------------------------
+-- Server version	10.3.18-MariaDB-1:10.3.18+maria~xenial-log
+
+-- etc ...INSERT INTO `entries` VALUES (1,'café 🚀');
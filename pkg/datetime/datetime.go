@@ -3,6 +3,7 @@ package datetime
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -29,3 +30,25 @@ func prepend(s string) string {
 
 	return s
 }
+
+// ParseSince takes a duration before now (e.g. 36h or 7d), a date (e.g. 2021-03-01),
+// or an RFC3339 time and returns the time.
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if strings.HasSuffix(value, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && days > 0 {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unable to parse %q, use a duration (e.g. 36h or 7d) or a date (e.g. 2021-03-01)", value)
+}
@@ -33,3 +33,57 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2021, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:  "durations are before now",
+			value: "36h",
+			want:  time.Date(2021, 3, 9, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "days are before now",
+			value: "7d",
+			want:  time.Date(2021, 3, 3, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "dates are the start of the day",
+			value: "2021-03-01",
+			want:  time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "times can be rfc3339",
+			value: "2021-03-01T08:30:00Z",
+			want:  time.Date(2021, 3, 1, 8, 30, 0, 0, time.UTC),
+		},
+		{
+			name:    "negative durations return an error",
+			value:   "-1h",
+			wantErr: true,
+		},
+		{
+			name:    "unknown values return an error",
+			value:   "yesterday",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSince(tt.value, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSince() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseSince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package dockerhost
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/errcode"
+	"github.com/craftcms/nitro/pkg/pathexists"
+)
+
+var (
+	// Host is set by the --docker-host flag to connect to a specific Docker daemon
+	Host string
+
+	// Context is set by the --context flag to connect to the Docker daemon of a Docker context
+	Context string
+
+	// PingTimeout is how long to wait for the Docker daemon when checking the connection
+	PingTimeout = 5 * time.Second
+)
+
+// Endpoint is the Docker daemon to connect to and the directory with the TLS
+// certificates, if there are any.
+type Endpoint struct {
+	Host   string
+	TLSDir string
+}
+
+// contextMeta is the subset of the Docker context metadata used to find the daemon.
+type contextMeta struct {
+	Name      string `json:"Name"`
+	Endpoints map[string]struct {
+		Host string `json:"Host"`
+	} `json:"Endpoints"`
+}
+
+// FromArgs takes the command line arguments and returns the values of the --docker-host
+// and --context flags. The docker client is created before the flags are parsed, so the
+// flags are read from the arguments.
+func FromArgs(args []string) (host, ctx string) {
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+
+		// everything after -- is an argument for another command
+		if a == "--" {
+			break
+		}
+
+		for _, f := range []struct {
+			name  string
+			value *string
+		}{
+			{"--docker-host", &host},
+			{"--context", &ctx},
+		} {
+			switch {
+			case strings.HasPrefix(a, f.name+"="):
+				*f.value = strings.TrimPrefix(a, f.name+"=")
+			case a == f.name && i+1 < len(args):
+				*f.value = args[i+1]
+				i++
+			}
+		}
+	}
+
+	return host, ctx
+}
+
+// Resolve takes the Docker config directory, the host, and the context and returns the
+// endpoint to connect to. The host takes priority over the context, and the context
+// falls back to the DOCKER_CONTEXT environment variable. It returns nil when neither is
+// set, or the context is default, so the client uses the environment.
+func Resolve(dir, host, ctx string) (*Endpoint, error) {
+	if host != "" {
+		return endpoint(host, "")
+	}
+
+	if ctx == "" {
+		ctx = os.Getenv("DOCKER_CONTEXT")
+	}
+
+	if ctx == "" || ctx == "default" {
+		return nil, nil
+	}
+
+	// the context metadata is stored in a directory named with the digest of the name
+	digest := sha256.Sum256([]byte(ctx))
+	id := hex.EncodeToString(digest[:])
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "contexts", "meta", id, "meta.json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("unable to find the docker context %q, run `docker context ls` to view the contexts", ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the docker context %q, %w", ctx, err)
+	}
+
+	meta := contextMeta{}
+	if err := json.Unmarshal(b, &meta); err != nil {
+		return nil, fmt.Errorf("unable to parse the docker context %q, %w", ctx, err)
+	}
+
+	docker, ok := meta.Endpoints["docker"]
+	if !ok || docker.Host == "" {
+		return nil, fmt.Errorf("the docker context %q does not have a docker endpoint", ctx)
+	}
+
+	tls := filepath.Join(dir, "contexts", "tls", id, "docker")
+	if !pathexists.IsDirectory(tls) {
+		tls = ""
+	}
+
+	return endpoint(docker.Host, tls)
+}
+
+// endpoint returns an error if the host is not a scheme the docker client supports.
+func endpoint(host, tls string) (*Endpoint, error) {
+	switch {
+	case strings.HasPrefix(host, "ssh://"):
+		return nil, fmt.Errorf("ssh hosts are not supported, forward the docker socket (e.g. ssh -L) and use the local socket instead of %s", host)
+	case strings.HasPrefix(host, "unix://"), strings.HasPrefix(host, "tcp://"), strings.HasPrefix(host, "npipe://"):
+	default:
+		return nil, fmt.Errorf("the docker host %q must use unix://, tcp://, or npipe://", host)
+	}
+
+	return &Endpoint{Host: host, TLSDir: tls}, nil
+}
+
+// ConfigDir returns the Docker config directory, using the DOCKER_CONFIG environment
+// variable if it is set.
+func ConfigDir(home string) string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+
+	return filepath.Join(home, ".docker")
+}
+
+// Opts returns the options to create the docker client for the endpoint, the endpoint
+// overrides the host from the environment.
+func Opts(e *Endpoint) []client.Opt {
+	opts := []client.Opt{client.FromEnv}
+	if e == nil {
+		return opts
+	}
+
+	opts = append(opts, client.WithHost(e.Host))
+
+	if e.TLSDir != "" {
+		opts = append(opts, client.WithTLSClientConfig(
+			filepath.Join(e.TLSDir, "ca.pem"),
+			filepath.Join(e.TLSDir, "cert.pem"),
+			filepath.Join(e.TLSDir, "key.pem"),
+		))
+	}
+
+	return opts
+}
+
+// Remote returns the hostname of the docker daemon when it runs on another machine, and
+// an empty string for sockets and loopback addresses. Without an endpoint the DOCKER_HOST
+// environment variable is used.
+func Remote(e *Endpoint) string {
+	host := os.Getenv("DOCKER_HOST")
+	if e != nil {
+		host = e.Host
+	}
+
+	if !strings.HasPrefix(host, "tcp://") {
+		return ""
+	}
+
+	u, err := url.Parse(host)
+	if err != nil {
+		return ""
+	}
+
+	hostname := u.Hostname()
+	if hostname == "localhost" {
+		return ""
+	}
+
+	if ip := net.ParseIP(hostname); ip != nil && ip.IsLoopback() {
+		return ""
+	}
+
+	return hostname
+}
+
+// CheckRunning verifies the docker daemon is running and returns a friendly error, with
+// a hint for the operating system (e.g. darwin), when the daemon cannot be reached.
+func CheckRunning(ctx context.Context, docker client.CommonAPIClient, goos string) error {
+	ctx, cancel := context.WithTimeout(ctx, PingTimeout)
+	defer cancel()
+
+	if _, err := docker.Ping(ctx); err != nil {
+		return errcode.WithCode(errcode.ErrDockerNotRunning, fmt.Errorf("Docker doesn’t appear to be running — please start Docker and try again.\n%s", hint(goos, err)))
+	}
+
+	return nil
+}
+
+// hint returns the steps to start docker for the operating system, or to fix the
+// permissions when the socket could not be opened.
+func hint(goos string, err error) string {
+	if strings.Contains(err.Error(), "permission denied") {
+		return "Your user can’t access the Docker socket, add it to the docker group with `sudo usermod -aG docker $USER` and log in again."
+	}
+
+	switch goos {
+	case "darwin":
+		return "Open Docker Desktop from the Applications folder and wait for the whale icon in the menu bar to stop animating."
+	case "windows":
+		return "Open Docker Desktop from the Start menu and wait for it to report that Docker is running."
+	default:
+		return "Start the Docker daemon with `sudo systemctl start docker`, or open Docker Desktop if you use it."
+	}
+}
+
+// Ping verifies the docker daemon for the endpoint can be reached.
+func Ping(ctx context.Context, docker client.APIClient, e *Endpoint) error {
+	ctx, cancel := context.WithTimeout(ctx, PingTimeout)
+	defer cancel()
+
+	if _, err := docker.Ping(ctx); err != nil {
+		return errcode.WithCode(errcode.ErrDockerNotRunning, fmt.Errorf("unable to connect to the docker daemon at %s, check the --docker-host or --context, %w", e.Host, err))
+	}
+
+	return nil
+}
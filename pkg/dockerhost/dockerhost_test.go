@@ -0,0 +1,235 @@
+package dockerhost
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+func TestFromArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantHost    string
+		wantContext string
+	}{
+		{
+			name: "no flags return empty values",
+			args: []string{"apply"},
+		},
+		{
+			name:     "flags with a separate value",
+			args:     []string{"--docker-host", "tcp://192.168.64.2:2376", "apply"},
+			wantHost: "tcp://192.168.64.2:2376",
+		},
+		{
+			name:        "flags with an equals value",
+			args:        []string{"apply", "--context=colima"},
+			wantContext: "colima",
+		},
+		{
+			name: "flags after -- are ignored",
+			args: []string{"composer", "--", "--context", "colima"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, ctx := FromArgs(tt.args)
+			if host != tt.wantHost {
+				t.Errorf("FromArgs() host = %q, want %q", host, tt.wantHost)
+			}
+			if ctx != tt.wantContext {
+				t.Errorf("FromArgs() context = %q, want %q", ctx, tt.wantContext)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-docker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// create the metadata for the colima context like the docker cli
+	digest := sha256.Sum256([]byte("colima"))
+	meta := filepath.Join(dir, "contexts", "meta", hex.EncodeToString(digest[:]))
+	if err := os.MkdirAll(meta, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := `{"Name":"colima","Metadata":{"Description":"colima"},"Endpoints":{"docker":{"Host":"unix:///Users/oli/.colima/default/docker.sock","SkipTLSVerify":false}}}`
+	if err := ioutil.WriteFile(filepath.Join(meta, "meta.json"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		host    string
+		ctx     string
+		want    *Endpoint
+		wantErr bool
+	}{
+		{
+			name: "no host or context uses the environment",
+		},
+		{
+			name: "the default context uses the environment",
+			ctx:  "default",
+		},
+		{
+			name: "the host takes priority over the context",
+			host: "tcp://192.168.64.2:2376",
+			ctx:  "colima",
+			want: &Endpoint{Host: "tcp://192.168.64.2:2376"},
+		},
+		{
+			name: "contexts use the docker endpoint",
+			ctx:  "colima",
+			want: &Endpoint{Host: "unix:///Users/oli/.colima/default/docker.sock"},
+		},
+		{
+			name:    "missing contexts return an error",
+			ctx:     "missing",
+			wantErr: true,
+		},
+		{
+			name:    "ssh hosts return an error",
+			host:    "ssh://oli@remote",
+			wantErr: true,
+		},
+		{
+			name:    "hosts without a scheme return an error",
+			host:    "192.168.64.2:2376",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(dir, tt.host, tt.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resolve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type mockPingClient struct {
+	client.CommonAPIClient
+
+	err error
+}
+
+func (m *mockPingClient) Ping(ctx context.Context) (types.Ping, error) {
+	return types.Ping{}, m.err
+}
+
+func TestCheckRunning(t *testing.T) {
+	tests := []struct {
+		name     string
+		goos     string
+		err      error
+		wantHint string
+	}{
+		{
+			name: "running daemons return nil",
+			goos: "darwin",
+		},
+		{
+			name:     "macOS suggests Docker Desktop",
+			goos:     "darwin",
+			err:      errors.New("Cannot connect to the Docker daemon at unix:///var/run/docker.sock. Is the docker daemon running?"),
+			wantHint: "Applications folder",
+		},
+		{
+			name:     "linux suggests systemctl",
+			goos:     "linux",
+			err:      errors.New("Cannot connect to the Docker daemon at unix:///var/run/docker.sock. Is the docker daemon running?"),
+			wantHint: "systemctl start docker",
+		},
+		{
+			name:     "permission errors suggest the docker group",
+			goos:     "linux",
+			err:      errors.New("dial unix /var/run/docker.sock: connect: permission denied"),
+			wantHint: "docker group",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckRunning(context.Background(), &mockPingClient{err: tt.err}, tt.goos)
+			if tt.wantHint == "" {
+				if err != nil {
+					t.Errorf("CheckRunning() error = %v, want nil", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), "Docker doesn’t appear to be running") || !strings.Contains(err.Error(), tt.wantHint) {
+				t.Errorf("CheckRunning() error = %v, want a hint containing %q", err, tt.wantHint)
+			}
+		})
+	}
+}
+
+func TestRemote(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint *Endpoint
+		env      string
+		want     string
+	}{
+		{
+			name: "the default daemon is local",
+		},
+		{
+			name:     "sockets are local",
+			endpoint: &Endpoint{Host: "unix:///Users/oli/.colima/docker.sock"},
+		},
+		{
+			name:     "loopback addresses are local",
+			endpoint: &Endpoint{Host: "tcp://127.0.0.1:2375"},
+		},
+		{
+			name:     "tcp hosts use the hostname",
+			endpoint: &Endpoint{Host: "tcp://192.168.64.2:2376"},
+			want:     "192.168.64.2",
+		},
+		{
+			name: "the environment is used without an endpoint",
+			env:  "tcp://docker.example.com:2376",
+			want: "docker.example.com",
+		},
+		{
+			name:     "the endpoint overrides the environment",
+			endpoint: &Endpoint{Host: "unix:///var/run/docker.sock"},
+			env:      "tcp://docker.example.com:2376",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := os.Getenv("DOCKER_HOST")
+			os.Setenv("DOCKER_HOST", tt.env)
+			defer os.Setenv("DOCKER_HOST", env)
+
+			if got := Remote(tt.endpoint); got != tt.want {
+				t.Errorf("Remote() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
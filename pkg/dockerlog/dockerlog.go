@@ -0,0 +1,321 @@
+// Package dockerlog wraps a docker client to write the docker API calls, with the
+// key arguments and how long each call took, as debug messages for the --verbose flag.
+package dockerlog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	networktypes "github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/registry"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// Client is a docker client that writes the calls nitro makes to the docker API as
+// debug messages. Calls that are not used by nitro are passed to the docker client
+// without a message.
+type Client struct {
+	client.CommonAPIClient
+
+	debug terminal.Debugger
+	now   func() time.Time
+}
+
+// New returns a client that writes the calls to the docker client as debug messages,
+// the debugger only writes the messages when terminal.Verbose is set.
+func New(docker client.CommonAPIClient, debug terminal.Debugger) *Client {
+	return &Client{CommonAPIClient: docker, debug: debug, now: time.Now}
+}
+
+// log writes the method, arguments, duration, and error of a call.
+func (c *Client) log(start time.Time, method string, err error, args ...string) {
+	msg := fmt.Sprintf("docker %s", method)
+	if len(args) > 0 {
+		msg = fmt.Sprintf("%s %s", msg, strings.Join(args, " "))
+	}
+
+	msg = fmt.Sprintf("%s (%s)", msg, c.now().Sub(start).Round(time.Millisecond))
+	if err != nil {
+		msg = fmt.Sprintf("%s error: %s", msg, err)
+	}
+
+	c.debug.Debug(msg)
+}
+
+// filterArgs returns the filters as key=value, sorted so the messages are stable.
+func filterArgs(f filters.Args) string {
+	var args []string
+	for _, k := range f.Keys() {
+		for _, v := range f.Get(k) {
+			args = append(args, k+"="+v)
+		}
+	}
+
+	sort.Strings(args)
+
+	return "[" + strings.Join(args, ",") + "]"
+}
+
+func (c *Client) Ping(ctx context.Context) (types.Ping, error) {
+	start := c.now()
+	p, err := c.CommonAPIClient.Ping(ctx)
+	c.log(start, "Ping", err)
+
+	return p, err
+}
+
+func (c *Client) ServerVersion(ctx context.Context) (types.Version, error) {
+	start := c.now()
+	v, err := c.CommonAPIClient.ServerVersion(ctx)
+	c.log(start, "ServerVersion", err)
+
+	return v, err
+}
+
+func (c *Client) Events(ctx context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error) {
+	c.log(c.now(), "Events", nil, filterArgs(options.Filters))
+
+	return c.CommonAPIClient.Events(ctx, options)
+}
+
+func (c *Client) ContainerAttach(ctx context.Context, container string, options types.ContainerAttachOptions) (types.HijackedResponse, error) {
+	start := c.now()
+	resp, err := c.CommonAPIClient.ContainerAttach(ctx, container, options)
+	c.log(start, "ContainerAttach", err, container)
+
+	return resp, err
+}
+
+func (c *Client) ContainerCreate(ctx context.Context, config *containertypes.Config, hostConfig *containertypes.HostConfig, networkingConfig *networktypes.NetworkingConfig, platform *specs.Platform, containerName string) (containertypes.ContainerCreateCreatedBody, error) {
+	start := c.now()
+	resp, err := c.CommonAPIClient.ContainerCreate(ctx, config, hostConfig, networkingConfig, platform, containerName)
+
+	var image string
+	if config != nil {
+		image = config.Image
+	}
+
+	c.log(start, "ContainerCreate", err, "name="+containerName, "image="+image)
+
+	return resp, err
+}
+
+func (c *Client) ContainerExecAttach(ctx context.Context, execID string, config types.ExecStartCheck) (types.HijackedResponse, error) {
+	start := c.now()
+	resp, err := c.CommonAPIClient.ContainerExecAttach(ctx, execID, config)
+	c.log(start, "ContainerExecAttach", err, execID)
+
+	return resp, err
+}
+
+func (c *Client) ContainerExecCreate(ctx context.Context, container string, config types.ExecConfig) (types.IDResponse, error) {
+	start := c.now()
+	resp, err := c.CommonAPIClient.ContainerExecCreate(ctx, container, config)
+	c.log(start, "ContainerExecCreate", err, container, fmt.Sprintf("%q", config.Cmd))
+
+	return resp, err
+}
+
+func (c *Client) ContainerExecInspect(ctx context.Context, execID string) (types.ContainerExecInspect, error) {
+	start := c.now()
+	resp, err := c.CommonAPIClient.ContainerExecInspect(ctx, execID)
+	c.log(start, "ContainerExecInspect", err, execID)
+
+	return resp, err
+}
+
+func (c *Client) ContainerExecStart(ctx context.Context, execID string, config types.ExecStartCheck) error {
+	start := c.now()
+	err := c.CommonAPIClient.ContainerExecStart(ctx, execID, config)
+	c.log(start, "ContainerExecStart", err, execID)
+
+	return err
+}
+
+func (c *Client) ContainerInspect(ctx context.Context, container string) (types.ContainerJSON, error) {
+	start := c.now()
+	resp, err := c.CommonAPIClient.ContainerInspect(ctx, container)
+	c.log(start, "ContainerInspect", err, container)
+
+	return resp, err
+}
+
+func (c *Client) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	start := c.now()
+	resp, err := c.CommonAPIClient.ContainerList(ctx, options)
+	c.log(start, "ContainerList", err, filterArgs(options.Filters), fmt.Sprintf("all=%t", options.All))
+
+	return resp, err
+}
+
+func (c *Client) ContainerLogs(ctx context.Context, container string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+	start := c.now()
+	resp, err := c.CommonAPIClient.ContainerLogs(ctx, container, options)
+	c.log(start, "ContainerLogs", err, container)
+
+	return resp, err
+}
+
+func (c *Client) ContainerRemove(ctx context.Context, container string, options types.ContainerRemoveOptions) error {
+	start := c.now()
+	err := c.CommonAPIClient.ContainerRemove(ctx, container, options)
+	c.log(start, "ContainerRemove", err, container)
+
+	return err
+}
+
+func (c *Client) ContainerRename(ctx context.Context, container, newContainerName string) error {
+	start := c.now()
+	err := c.CommonAPIClient.ContainerRename(ctx, container, newContainerName)
+	c.log(start, "ContainerRename", err, container, newContainerName)
+
+	return err
+}
+
+func (c *Client) ContainerRestart(ctx context.Context, container string, timeout *time.Duration) error {
+	start := c.now()
+	err := c.CommonAPIClient.ContainerRestart(ctx, container, timeout)
+	c.log(start, "ContainerRestart", err, container)
+
+	return err
+}
+
+func (c *Client) ContainerStatPath(ctx context.Context, container, path string) (types.ContainerPathStat, error) {
+	start := c.now()
+	resp, err := c.CommonAPIClient.ContainerStatPath(ctx, container, path)
+	c.log(start, "ContainerStatPath", err, container, path)
+
+	return resp, err
+}
+
+func (c *Client) ContainerStart(ctx context.Context, container string, options types.ContainerStartOptions) error {
+	start := c.now()
+	err := c.CommonAPIClient.ContainerStart(ctx, container, options)
+	c.log(start, "ContainerStart", err, container)
+
+	return err
+}
+
+func (c *Client) ContainerStop(ctx context.Context, container string, timeout *time.Duration) error {
+	start := c.now()
+	err := c.CommonAPIClient.ContainerStop(ctx, container, timeout)
+	c.log(start, "ContainerStop", err, container)
+
+	return err
+}
+
+func (c *Client) ContainerUpdate(ctx context.Context, container string, updateConfig containertypes.UpdateConfig) (containertypes.ContainerUpdateOKBody, error) {
+	start := c.now()
+	resp, err := c.CommonAPIClient.ContainerUpdate(ctx, container, updateConfig)
+	c.log(start, "ContainerUpdate", err, container)
+
+	return resp, err
+}
+
+func (c *Client) CopyFromContainer(ctx context.Context, container, srcPath string) (io.ReadCloser, types.ContainerPathStat, error) {
+	start := c.now()
+	r, stat, err := c.CommonAPIClient.CopyFromContainer(ctx, container, srcPath)
+	c.log(start, "CopyFromContainer", err, container, srcPath)
+
+	return r, stat, err
+}
+
+func (c *Client) CopyToContainer(ctx context.Context, container, path string, content io.Reader, options types.CopyToContainerOptions) error {
+	start := c.now()
+	err := c.CommonAPIClient.CopyToContainer(ctx, container, path, content, options)
+	c.log(start, "CopyToContainer", err, container, path)
+
+	return err
+}
+
+func (c *Client) ImageInspectWithRaw(ctx context.Context, image string) (types.ImageInspect, []byte, error) {
+	start := c.now()
+	resp, raw, err := c.CommonAPIClient.ImageInspectWithRaw(ctx, image)
+	c.log(start, "ImageInspect", err, image)
+
+	return resp, raw, err
+}
+
+func (c *Client) ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
+	start := c.now()
+	resp, err := c.CommonAPIClient.ImageList(ctx, options)
+	c.log(start, "ImageList", err, filterArgs(options.Filters))
+
+	return resp, err
+}
+
+func (c *Client) ImagePull(ctx context.Context, ref string, options types.ImagePullOptions) (io.ReadCloser, error) {
+	start := c.now()
+	resp, err := c.CommonAPIClient.ImagePull(ctx, ref, options)
+	c.log(start, "ImagePull", err, ref)
+
+	return resp, err
+}
+
+func (c *Client) ImageSearch(ctx context.Context, term string, options types.ImageSearchOptions) ([]registry.SearchResult, error) {
+	start := c.now()
+	resp, err := c.CommonAPIClient.ImageSearch(ctx, term, options)
+	c.log(start, "ImageSearch", err, term)
+
+	return resp, err
+}
+
+func (c *Client) NetworkCreate(ctx context.Context, name string, options types.NetworkCreate) (types.NetworkCreateResponse, error) {
+	start := c.now()
+	resp, err := c.CommonAPIClient.NetworkCreate(ctx, name, options)
+	c.log(start, "NetworkCreate", err, name)
+
+	return resp, err
+}
+
+func (c *Client) NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error) {
+	start := c.now()
+	resp, err := c.CommonAPIClient.NetworkList(ctx, options)
+	c.log(start, "NetworkList", err, filterArgs(options.Filters))
+
+	return resp, err
+}
+
+func (c *Client) NetworkRemove(ctx context.Context, network string) error {
+	start := c.now()
+	err := c.CommonAPIClient.NetworkRemove(ctx, network)
+	c.log(start, "NetworkRemove", err, network)
+
+	return err
+}
+
+func (c *Client) VolumeCreate(ctx context.Context, options volumetypes.VolumeCreateBody) (types.Volume, error) {
+	start := c.now()
+	resp, err := c.CommonAPIClient.VolumeCreate(ctx, options)
+	c.log(start, "VolumeCreate", err, options.Name)
+
+	return resp, err
+}
+
+func (c *Client) VolumeList(ctx context.Context, filter filters.Args) (volumetypes.VolumeListOKBody, error) {
+	start := c.now()
+	resp, err := c.CommonAPIClient.VolumeList(ctx, filter)
+	c.log(start, "VolumeList", err, filterArgs(filter))
+
+	return resp, err
+}
+
+func (c *Client) VolumeRemove(ctx context.Context, volumeID string, force bool) error {
+	start := c.now()
+	err := c.CommonAPIClient.VolumeRemove(ctx, volumeID, force)
+	c.log(start, "VolumeRemove", err, volumeID)
+
+	return err
+}
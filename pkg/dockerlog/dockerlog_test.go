@@ -0,0 +1,91 @@
+package dockerlog
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+)
+
+type mockClient struct {
+	client.CommonAPIClient
+
+	err error
+}
+
+func (m *mockClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	return []types.Container{{ID: "abc"}}, m.err
+}
+
+func (m *mockClient) ContainerStart(ctx context.Context, container string, options types.ContainerStartOptions) error {
+	return m.err
+}
+
+type spyDebugger struct {
+	messages []string
+}
+
+func (s *spyDebugger) Debug(msg ...string) {
+	s.messages = append(s.messages, strings.Join(msg, " "))
+}
+
+func TestClient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		call func(c *Client)
+		want []string
+	}{
+		{
+			name: "lists write the filters",
+			call: func(c *Client) {
+				filter := filters.NewArgs()
+				filter.Add("label", "com.craftcms.nitro=true")
+				filter.Add("name", "nitro-network")
+
+				containers, _ := c.ContainerList(context.Background(), types.ContainerListOptions{All: true, Filters: filter})
+				if len(containers) != 1 {
+					t.Errorf("expected the containers from the docker client, got %v", containers)
+				}
+			},
+			want: []string{"docker ContainerList [label=com.craftcms.nitro=true,name=nitro-network] all=true (250ms)"},
+		},
+		{
+			name: "errors are written",
+			err:  errors.New("no such container"),
+			call: func(c *Client) {
+				if err := c.ContainerStart(context.Background(), "craft-dev.nitro", types.ContainerStartOptions{}); err == nil {
+					t.Error("expected the error from the docker client")
+				}
+			},
+			want: []string{"docker ContainerStart craft-dev.nitro (250ms) error: no such container"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spy := &spyDebugger{}
+
+			c := New(&mockClient{err: tt.err}, spy)
+
+			// each call takes 250ms
+			now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+			c.now = func() time.Time {
+				now = now.Add(250 * time.Millisecond)
+
+				return now
+			}
+
+			tt.call(c)
+
+			if !reflect.DeepEqual(spy.messages, tt.want) {
+				t.Errorf("expected messages %q, got %q", tt.want, spy.messages)
+			}
+		})
+	}
+}
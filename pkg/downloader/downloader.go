@@ -2,6 +2,7 @@ package downloader
 
 import (
 	"archive/zip"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -9,6 +10,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/craftcms/nitro/pkg/tempdir"
+	"github.com/craftcms/nitro/pkg/terminal"
 )
 
 // Getter is an interface for getting the contents of a url
@@ -67,6 +71,76 @@ func (d *Downloader) Get(url, dir string) error {
 	return nil
 }
 
+// FileOptions are used to download a single file.
+type FileOptions struct {
+	// Username and Password are used for basic auth when the username is set
+	Username string
+	Password string
+	// Progress reports the bytes downloaded, the total is -1 if the size
+	// is unknown.
+	Progress terminal.Progress
+	// TempDir is the directory to download the file into, the default
+	// temp directory is used when empty.
+	TempDir string
+}
+
+// File takes a url and downloads the file into a temp file, following
+// any redirects. It returns the path to the temp file which should be
+// removed by the caller.
+func (d *Downloader) File(ctx context.Context, url string, opts FileOptions) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if opts.Username != "" {
+		req.SetBasicAuth(opts.Username, opts.Password)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	// check the response code
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to download %s. status: %d", url, resp.StatusCode)
+	}
+
+	// make sure the download fits in the temp directory
+	if err := tempdir.Check(opts.TempDir, resp.ContentLength); err != nil {
+		return "", err
+	}
+
+	// create a temp file
+	file, err := ioutil.TempFile(tempdir.Dir(opts.TempDir), "nitro-file-download-")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	progress := opts.Progress
+	if progress == nil {
+		progress = terminal.NoProgress{}
+	}
+
+	progress.Start(resp.ContentLength)
+
+	// copy the download into the new file
+	_, err = io.Copy(terminal.ProgressWriter(file, progress), resp.Body)
+
+	progress.Finish()
+
+	if err != nil {
+		os.Remove(file.Name())
+
+		return "", fmt.Errorf("unable to copy the file, %w", err)
+	}
+
+	return file.Name(), nil
+}
+
 func unzip(file *os.File, dir string) error {
 	// extract the zip
 	r, err := zip.OpenReader(file.Name())
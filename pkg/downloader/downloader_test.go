@@ -0,0 +1,106 @@
+package downloader
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestDownloader_File(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/backup.sql", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("CREATE TABLE example (id int);"))
+	})
+	mux.HandleFunc("/latest", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/backup.sql", http.StatusFound)
+	})
+	mux.HandleFunc("/private.sql", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "nitro" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Write([]byte("CREATE TABLE example (id int);"))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		opts    FileOptions
+		wantErr bool
+	}{
+		{
+			name: "files are downloaded",
+			path: "/backup.sql",
+		},
+		{
+			name: "redirects are followed",
+			path: "/latest",
+		},
+		{
+			name: "basic auth credentials are sent",
+			path: "/private.sql",
+			opts: FileOptions{Username: "nitro", Password: "secret"},
+		},
+		{
+			name:    "missing credentials return an error",
+			path:    "/private.sql",
+			wantErr: true,
+		},
+		{
+			name:    "missing files return an error",
+			path:    "/missing.sql",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Downloader{client: srv.Client()}
+
+			progress := &recordProgress{}
+			tt.opts.Progress = progress
+
+			got, err := d.File(context.TODO(), srv.URL+tt.path, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("File() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+			defer os.Remove(got)
+
+			content, err := ioutil.ReadFile(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(content) != "CREATE TABLE example (id int);" {
+				t.Errorf("unexpected content %q", content)
+			}
+
+			if progress.written != int64(len(content)) {
+				t.Errorf("expected progress to report %d bytes, got %d", len(content), progress.written)
+			}
+
+			if !progress.finished {
+				t.Error("expected the progress to be finished")
+			}
+		})
+	}
+}
+
+// recordProgress records the bytes added to the progress.
+type recordProgress struct {
+	written  int64
+	finished bool
+}
+
+func (p *recordProgress) Start(total int64) {}
+func (p *recordProgress) Add(n int64)       { p.written += n }
+func (p *recordProgress) Finish()           { p.finished = true }
@@ -71,3 +71,27 @@ func EnvExists(file, key string) bool {
 
 	return false
 }
+
+// Get takes an existing env file and key and returns the value of the env var, without
+// surrounding quotes. It returns an empty string if the env var is not defined and
+// ErrNoEnvFile if the file does not exist.
+func Get(file, key string) (string, error) {
+	f, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return "", ErrNoEnvFile
+	}
+	if err != nil {
+		return "", err
+	}
+
+	for _, txt := range strings.Split(string(f), "\n") {
+		sp := strings.SplitN(strings.TrimSpace(txt), "=", 2)
+		if len(sp) != 2 || strings.TrimSpace(sp[0]) != key {
+			continue
+		}
+
+		return strings.Trim(strings.TrimSpace(sp[1]), `"'`), nil
+	}
+
+	return "", nil
+}
@@ -118,3 +118,43 @@ func TestEnvExists(t *testing.T) {
 		})
 	}
 }
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		key     string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "existing env vars return the value",
+			file: "testdata/env-example-golden",
+			key:  "DB_SERVER",
+			want: "postgres-13-5432",
+		},
+		{
+			name: "missing env vars return an empty string",
+			file: "testdata/env-example-golden",
+			key:  "MISSING",
+		},
+		{
+			name:    "missing files return an error",
+			file:    "testdata/env-example-not-here",
+			key:     "DB_SERVER",
+			wantErr: ErrNoEnvFile,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Get(tt.file, tt.key)
+			if err != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Get() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
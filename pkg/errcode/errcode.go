@@ -0,0 +1,99 @@
+// Package errcode defines the stable error codes, and exit codes, of the command line. It
+// has no dependencies so the packages returning the errors do not depend on the output.
+package errcode
+
+import "errors"
+
+// Code is a stable error code, and exit code, for a failure that wrapping tools (e.g. CI
+// scripts and editor integrations) can branch on instead of matching the error text.
+// The codes, and the exit codes they map to, are part of the command line contract:
+//
+//	exit code  error code           meaning
+//	0                               the command was successful
+//	1          error                any other error
+//	10         docker_not_running   the docker daemon could not be reached
+//	11         no_proxy             the proxy container does not exist
+//	12         site_exists          a site with the hostname already exists
+//	13         port_in_use          a port needed by the command is already in use
+//	14         no_config            there is no config file, run nitro init
+//
+// New codes can be added, but existing codes are not renamed or renumbered.
+type Code struct {
+	Name string
+	Exit int
+}
+
+// Error returns the name of the code so a Code can be used as a target for errors.Is.
+func (c Code) Error() string {
+	return c.Name
+}
+
+var (
+	// ErrGeneral is the code for errors without a more specific code
+	ErrGeneral = Code{Name: "error", Exit: 1}
+
+	// ErrDockerNotRunning is the code when the docker daemon could not be reached
+	ErrDockerNotRunning = Code{Name: "docker_not_running", Exit: 10}
+
+	// ErrNoProxy is the code when the proxy container does not exist
+	ErrNoProxy = Code{Name: "no_proxy", Exit: 11}
+
+	// ErrSiteExists is the code when a site with the hostname already exists
+	ErrSiteExists = Code{Name: "site_exists", Exit: 12}
+
+	// ErrPortInUse is the code when a port needed by the command is already in use
+	ErrPortInUse = Code{Name: "port_in_use", Exit: 13}
+
+	// ErrNoConfig is the code when there is no config file
+	ErrNoConfig = Code{Name: "no_config", Exit: 14}
+)
+
+// codedError is an error with a Code.
+type codedError struct {
+	code Code
+	err  error
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+// Is returns true when the target is the code of the error, so errors.Is(err, ErrNoProxy)
+// is true for any error wrapped with the code.
+func (e *codedError) Is(target error) bool {
+	c, ok := target.(Code)
+
+	return ok && c == e.code
+}
+
+// WithCode returns the error with the code, the message of the error is not changed.
+func WithCode(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &codedError{code: code, err: err}
+}
+
+// CodeOf returns the code of the error, or ErrGeneral when the error does not have a code.
+func CodeOf(err error) Code {
+	var ce *codedError
+	if errors.As(err, &ce) {
+		return ce.code
+	}
+
+	return ErrGeneral
+}
+
+// ExitCode returns the exit code for the error, which is 0 when the error is nil.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	return CodeOf(err).Exit
+}
@@ -0,0 +1,59 @@
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode Code
+		wantExit int
+	}{
+		{
+			name:     "nil errors exit with zero",
+			wantCode: ErrGeneral,
+			wantExit: 0,
+		},
+		{
+			name:     "errors without a code are general errors",
+			err:      errors.New("something went wrong"),
+			wantCode: ErrGeneral,
+			wantExit: 1,
+		},
+		{
+			name:     "errors with a code use the exit code",
+			err:      WithCode(ErrPortInUse, errors.New("it appears port 80, is already in use")),
+			wantCode: ErrPortInUse,
+			wantExit: 13,
+		},
+		{
+			name:     "wrapped errors keep the code",
+			err:      fmt.Errorf("unable to start, %w", WithCode(ErrDockerNotRunning, errors.New("docker is not running"))),
+			wantCode: ErrDockerNotRunning,
+			wantExit: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.wantExit {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.wantExit)
+			}
+
+			if tt.err == nil {
+				return
+			}
+
+			if got := CodeOf(tt.err); got != tt.wantCode {
+				t.Errorf("CodeOf() = %v, want %v", got, tt.wantCode)
+			}
+
+			if tt.wantCode != ErrGeneral && !errors.Is(tt.err, tt.wantCode) {
+				t.Errorf("expected errors.Is to match the code %v", tt.wantCode)
+			}
+		})
+	}
+}
@@ -1,45 +1,157 @@
 package filetype
 
 import (
+	"bytes"
+	"compress/gzip"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
 	"os"
+	"unicode/utf8"
 )
 
+// sniffLen is the number of bytes used to detect the file type
+const sniffLen = 512
+
+// Detail is the detailed result of detecting a files type. The kind
+// is one of text, zip, gzip (a single gzip compressed file), tgz (a
+// gzip compressed tar archive), tar (an uncompressed tar archive),
+// bzip2, xz, or pgdump (a postgres custom format archive).
+// Confident is false when the kind could not be verified, such as a
+// gzip file that could not be read or text with invalid UTF-8.
+type Detail struct {
+	Kind      string
+	MIME      string
+	Confident bool
+}
+
 // Determine takes a file path and will determine
 // if the file is plain, zip, or a tar type of
 // file. If the path is not found it will return
 // an error.
 func Determine(file string) (string, error) {
+	detail, err := DetermineDetailed(file)
+	if err != nil {
+		return "", err
+	}
+
+	// gzip files have always been reported as tar
+	switch detail.Kind {
+	case "gzip", "tgz":
+		return "tar", nil
+	}
+
+	return detail.Kind, nil
+}
+
+// DetermineDetailed takes a file path and returns the kind, MIME type,
+// and confidence of the detection using the magic bytes of the file.
+// Gzip files are inspected to determine if they contain a tar archive.
+// If the path is not found or the type is unknown it will return an
+// error.
+func DetermineDetailed(file string) (*Detail, error) {
 	// stat the file to make sure it exists
 	stat, err := os.Stat(file)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// make sure its not a directory
 	if stat.IsDir() {
-		return "", fmt.Errorf("file provided is a directory")
+		return nil, fmt.Errorf("file provided is a directory")
 	}
 
-	// read the contents
-	data, err := ioutil.ReadFile(file)
+	f, err := os.Open(file)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	defer f.Close()
+
+	// read the start of the file
+	data, err := readHead(f)
+	if err != nil {
+		return nil, err
 	}
 
 	// detect the type
 	kind := http.DetectContentType(data)
 
-	switch kind {
-	case "text/plain; charset=utf-8":
-		return "text", nil
-	case "application/zip":
-		return "zip", nil
-	case "application/x-gzip":
-		return "tar", nil
+	switch {
+	case kind == "application/zip":
+		return &Detail{Kind: "zip", MIME: kind, Confident: true}, nil
+	case kind == "application/x-gzip":
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		// look inside of the gzip for a tar archive
+		r, err := gzip.NewReader(f)
+		if err != nil {
+			return &Detail{Kind: "gzip", MIME: kind, Confident: false}, nil
+		}
+		defer r.Close()
+
+		inner, err := readHead(r)
+		if err != nil {
+			return &Detail{Kind: "gzip", MIME: kind, Confident: false}, nil
+		}
+
+		if isTar(inner) {
+			return &Detail{Kind: "tgz", MIME: kind, Confident: true}, nil
+		}
+
+		return &Detail{Kind: "gzip", MIME: kind, Confident: true}, nil
+	case bytes.HasPrefix(data, []byte("PGDMP")):
+		// custom format archives are binary, so they are not detected as text
+		return &Detail{Kind: "pgdump", MIME: "application/octet-stream", Confident: true}, nil
+	case isBzip2(data):
+		return &Detail{Kind: "bzip2", MIME: "application/x-bzip2", Confident: true}, nil
+	case bytes.HasPrefix(data, xzMagic):
+		return &Detail{Kind: "xz", MIME: "application/x-xz", Confident: true}, nil
+	case isTar(data):
+		return &Detail{Kind: "tar", MIME: "application/x-tar", Confident: true}, nil
+	case kind == "text/plain; charset=utf-8":
+		// the sniffed data may end in the middle of a rune
+		return &Detail{Kind: "text", MIME: kind, Confident: utf8.Valid(trimPartialRune(data))}, nil
+	}
+
+	return nil, fmt.Errorf("unknown file type: %s", kind)
+}
+
+// readHead reads up to sniffLen bytes from the reader.
+func readHead(r io.Reader) ([]byte, error) {
+	data := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, data)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+
+	return data[:n], nil
+}
+
+// xzMagic is the magic at the start of the xz stream header.
+var xzMagic = []byte{0xFD, '7', 'z', 'X', 'Z', 0x00}
+
+// isBzip2 checks for the bzip2 magic followed by the block size (1-9).
+func isBzip2(data []byte) bool {
+	return len(data) >= 4 && bytes.HasPrefix(data, []byte("BZh")) && data[3] >= '1' && data[3] <= '9'
+}
+
+// isTar checks for the ustar magic in the tar header.
+func isTar(data []byte) bool {
+	return len(data) >= 262 && bytes.Equal(data[257:262], []byte("ustar"))
+}
+
+func trimPartialRune(data []byte) []byte {
+	for i := 0; i < utf8.UTFMax && i < len(data); i++ {
+		if utf8.RuneStart(data[len(data)-1-i]) {
+			if !utf8.FullRune(data[len(data)-1-i:]) {
+				return data[:len(data)-1-i]
+			}
+
+			break
+		}
 	}
 
-	return "", fmt.Errorf("unknown file type: %s", kind)
+	return data
 }
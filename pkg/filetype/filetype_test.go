@@ -2,6 +2,7 @@ package filetype
 
 import (
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -75,3 +76,75 @@ func TestDetermine(t *testing.T) {
 		})
 	}
 }
+
+func TestDetermineDetailed(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    *Detail
+		wantErr bool
+	}{
+		{
+			name: "gzip compressed tar archives return tgz",
+			file: filepath.Join("testdata", "tarfile.tar.gz"),
+			want: &Detail{Kind: "tgz", MIME: "application/x-gzip", Confident: true},
+		},
+		{
+			name: "gzip compressed sql files return gzip",
+			file: filepath.Join("testdata", "backup.sql.gz"),
+			want: &Detail{Kind: "gzip", MIME: "application/x-gzip", Confident: true},
+		},
+		{
+			name: "uncompressed tar archives return tar",
+			file: filepath.Join("testdata", "backup.tar"),
+			want: &Detail{Kind: "tar", MIME: "application/x-tar", Confident: true},
+		},
+		{
+			name: "zip files return zip",
+			file: filepath.Join("testdata", "example.zip"),
+			want: &Detail{Kind: "zip", MIME: "application/zip", Confident: true},
+		},
+		{
+			name: "sql files return text",
+			file: filepath.Join("testdata", "backup.sql"),
+			want: &Detail{Kind: "text", MIME: "text/plain; charset=utf-8", Confident: true},
+		},
+		{
+			name: "bzip2 compressed sql files return bzip2",
+			file: filepath.Join("testdata", "backup.sql.bz2"),
+			want: &Detail{Kind: "bzip2", MIME: "application/x-bzip2", Confident: true},
+		},
+		{
+			name: "xz compressed sql files return xz",
+			file: filepath.Join("testdata", "backup.sql.xz"),
+			want: &Detail{Kind: "xz", MIME: "application/x-xz", Confident: true},
+		},
+		{
+			name: "postgres custom format archives return pgdump",
+			file: filepath.Join("testdata", "backup.dump"),
+			want: &Detail{Kind: "pgdump", MIME: "application/octet-stream", Confident: true},
+		},
+		{
+			name:    "binary files return an error",
+			file:    filepath.Join("testdata", "binary.bin"),
+			wantErr: true,
+		},
+		{
+			name:    "directory returns error",
+			file:    filepath.Join("testdata"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetermineDetailed(tt.file)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DetermineDetailed() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetermineDetailed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package imagepull
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+var (
+	// NoPull is set by the --no-pull flag to skip pulling images
+	NoPull bool
+
+	// ErrNotLocal is returned when pulling is disabled and the image is not available locally
+	ErrNotLocal = errors.New("the image is not available locally and pulling images is disabled")
+
+	// pulls are the running pulls, which are shared between concurrent requests for the same image
+	pulls   = make(map[string]*pullCall)
+	pullsMu sync.Mutex
+)
+
+// pullCall is a running pull that is closed when the pull completes.
+type pullCall struct {
+	done chan struct{}
+	err  error
+}
+
+// Disabled returns true if pulling images is disabled using the --no-pull
+// flag or the NITRO_NO_PULL environment variable.
+func Disabled() bool {
+	return NoPull || os.Getenv("NITRO_NO_PULL") == "true"
+}
+
+// Pull takes an image and will pull it from the registry. If pulling is disabled,
+// it verifies the image is available locally and returns ErrNotLocal if it is not.
+// Concurrent pulls of the same image share one pull.
+func Pull(ctx context.Context, docker client.ImageAPIClient, image string) error {
+	if Disabled() {
+		filter := filters.NewArgs()
+		filter.Add("reference", image)
+
+		images, err := docker.ImageList(ctx, types.ImageListOptions{Filters: filter})
+		if err != nil {
+			return fmt.Errorf("unable to get a list of images, %w", err)
+		}
+
+		if len(images) == 0 {
+			return fmt.Errorf("%w: %s, load it with `docker load` or remove --no-pull", ErrNotLocal, image)
+		}
+
+		return nil
+	}
+
+	// concurrent requests for the same image wait on the first pull
+	c, _ := start(docker, image)
+
+	return wait(ctx, c)
+}
+
+// PullWithProgress pulls the image like Pull and writes the progress to the output. When
+// the image is already being pulled, only the first request writes the progress and the
+// others wait for it silently.
+func PullWithProgress(ctx context.Context, docker client.ImageAPIClient, output terminal.Outputer, image string) error {
+	if Disabled() {
+		return Pull(ctx, docker, image)
+	}
+
+	c, started := start(docker, image)
+	if !started {
+		return wait(ctx, c)
+	}
+
+	output.Pending("pulling", image)
+
+	if err := wait(ctx, c); err != nil {
+		output.Warning()
+
+		return err
+	}
+
+	output.Done()
+
+	return nil
+}
+
+// start returns the running pull of the image, or starts one, and returns true when the
+// pull was started by this request. The pull is shared, so it uses its own context and
+// is not canceled with the context of the request that started it.
+func start(docker client.ImageAPIClient, image string) (*pullCall, bool) {
+	pullsMu.Lock()
+	defer pullsMu.Unlock()
+
+	if c, ok := pulls[image]; ok {
+		return c, false
+	}
+
+	c := &pullCall{done: make(chan struct{})}
+	pulls[image] = c
+
+	go func() {
+		c.err = pull(context.Background(), docker, image)
+
+		pullsMu.Lock()
+		delete(pulls, image)
+		pullsMu.Unlock()
+
+		close(c.done)
+	}()
+
+	return c, true
+}
+
+// wait returns the result of the pull, or the error of the context when the request is
+// canceled before the pull completes.
+func wait(ctx context.Context, c *pullCall) error {
+	select {
+	case <-c.done:
+		return c.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func pull(ctx context.Context, docker client.ImageAPIClient, image string) error {
+	rdr, err := docker.ImagePull(ctx, image, types.ImagePullOptions{All: false})
+	if err != nil {
+		return fmt.Errorf("unable to pull the image %s, %w", image, err)
+	}
+	defer rdr.Close()
+
+	// read the output to pull the image
+	buf := &bytes.Buffer{}
+	if _, err := buf.ReadFrom(rdr); err != nil {
+		return fmt.Errorf("unable to read the output from pulling the image %s, %w", image, err)
+	}
+
+	return nil
+}
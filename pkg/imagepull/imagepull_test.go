@@ -0,0 +1,170 @@
+package imagepull
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+type mockDockerClient struct {
+	client.ImageAPIClient
+	images []types.ImageSummary
+	pulled []string
+
+	// wait blocks the pull until it is closed
+	wait chan struct{}
+	mu   sync.Mutex
+}
+
+func (c *mockDockerClient) ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
+	return c.images, nil
+}
+
+func (c *mockDockerClient) ImagePull(ctx context.Context, ref string, options types.ImagePullOptions) (io.ReadCloser, error) {
+	c.mu.Lock()
+	c.pulled = append(c.pulled, ref)
+	c.mu.Unlock()
+
+	if c.wait != nil {
+		<-c.wait
+	}
+
+	return ioutil.NopCloser(strings.NewReader("pulled")), nil
+}
+
+func TestPull(t *testing.T) {
+	tests := []struct {
+		name       string
+		noPull     bool
+		images     []types.ImageSummary
+		wantPulled int
+		wantErr    error
+	}{
+		{
+			name:       "images are pulled by default",
+			wantPulled: 1,
+		},
+		{
+			name:   "local images are used when pulling is disabled",
+			noPull: true,
+			images: []types.ImageSummary{{ID: "image"}},
+		},
+		{
+			name:    "missing images return an error when pulling is disabled",
+			noPull:  true,
+			wantErr: ErrNotLocal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			NoPull = tt.noPull
+			defer func() { NoPull = false }()
+
+			docker := &mockDockerClient{images: tt.images}
+
+			err := Pull(context.TODO(), docker, "craftcms/nitro:7.4")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Pull() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if len(docker.pulled) != tt.wantPulled {
+				t.Errorf("expected %d pulls, got %d", tt.wantPulled, len(docker.pulled))
+			}
+		})
+	}
+}
+
+func TestPullSharesConcurrentPulls(t *testing.T) {
+	docker := &mockDockerClient{wait: make(chan struct{})}
+
+	// the requests join the pull while the first pull is blocked
+	first, started := start(docker, "craftcms/nitro-proxy:2.0.0")
+	if !started {
+		t.Fatal("expected the first request to start the pull")
+	}
+
+	for i := 0; i < 4; i++ {
+		c, started := start(docker, "craftcms/nitro-proxy:2.0.0")
+		if started || c != first {
+			t.Fatal("expected the request to join the running pull")
+		}
+	}
+
+	close(docker.wait)
+
+	if err := wait(context.TODO(), first); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(docker.pulled) != 1 {
+		t.Errorf("expected 1 pull, got %d", len(docker.pulled))
+	}
+}
+
+func TestPullIsNotCanceledWithTheFirstRequest(t *testing.T) {
+	docker := &mockDockerClient{wait: make(chan struct{})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first, _ := start(docker, "craftcms/nitro-proxy:2.0.0")
+	cancel()
+
+	// the canceled request stops waiting
+	if err := wait(ctx, first); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the canceled request to return context.Canceled, got %v", err)
+	}
+
+	// the other requests still get the result of the pull
+	c, started := start(docker, "craftcms/nitro-proxy:2.0.0")
+	if started {
+		t.Fatal("expected the request to join the running pull")
+	}
+
+	close(docker.wait)
+
+	if err := wait(context.TODO(), c); err != nil {
+		t.Fatalf("expected the shared pull to succeed, got %v", err)
+	}
+}
+
+func TestPullWithProgress(t *testing.T) {
+	docker := &mockDockerClient{wait: make(chan struct{})}
+
+	// another request is already pulling the image
+	first, _ := start(docker, "craftcms/nitro-proxy:2.0.0")
+
+	// the request joins the running pull without writing the progress, it is canceled
+	// so it returns without waiting for the pull
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	buf := &bytes.Buffer{}
+	if err := PullWithProgress(ctx, docker, terminal.NewWithWriter(buf), "craftcms/nitro-proxy:2.0.0"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("expected only the first request to write the progress, got %q", buf.String())
+	}
+
+	close(docker.wait)
+	<-first.done
+
+	// a new pull writes the progress
+	if err := PullWithProgress(context.TODO(), docker, terminal.NewWithWriter(buf), "craftcms/nitro-proxy:2.0.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "pulling craftcms/nitro-proxy:2.0.0") {
+		t.Errorf("expected the progress to be written, got %q", buf.String())
+	}
+}
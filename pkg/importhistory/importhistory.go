@@ -0,0 +1,111 @@
+package importhistory
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+var (
+	// FileName is the file, in the config directory, used to record the imports
+	FileName = "imports.json"
+
+	// Limit is the number of imports to keep in the history
+	Limit = 50
+
+	// Window is how long an import is considered recent when checking for duplicates
+	Window = 24 * time.Hour
+)
+
+// Record is an import of a backup into a database.
+type Record struct {
+	Hash     string    `json:"hash"`
+	Hostname string    `json:"hostname"`
+	Database string    `json:"database"`
+	File     string    `json:"file"`
+	Imported time.Time `json:"imported"`
+}
+
+// Hash returns the sha256 checksum of the backup file.
+func Hash(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("unable to hash the backup, %w", err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Find returns the most recent import of the backup, by hash, into the database since
+// the time. It returns nil if the backup has not been imported.
+func Find(home, hash, hostname, database string, since time.Time) (*Record, error) {
+	records, err := load(home)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(records) - 1; i >= 0; i-- {
+		r := records[i]
+		if r.Hash == hash && r.Hostname == hostname && r.Database == database && r.Imported.After(since) {
+			return &r, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Add records the import and removes the oldest imports over the Limit.
+func Add(home string, r Record) error {
+	records, err := load(home)
+	if err != nil {
+		return err
+	}
+
+	records = append(records, r)
+	if len(records) > Limit {
+		records = records[len(records)-Limit:]
+	}
+
+	b, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(file(home), b, 0600)
+}
+
+func load(home string) ([]Record, error) {
+	b, err := ioutil.ReadFile(file(home))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	if err := json.Unmarshal(b, &records); err != nil {
+		return nil, fmt.Errorf("unable to read the import history, %w", err)
+	}
+
+	return records, nil
+}
+
+func file(home string) string {
+	return filepath.Join(home, config.DirectoryName, FileName)
+}
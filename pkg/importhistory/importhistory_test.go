@@ -0,0 +1,113 @@
+package importhistory
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+func TestFind(t *testing.T) {
+	home, err := ioutil.TempDir("", "nitro-imports")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	if err := os.Mkdir(filepath.Join(home, config.DirectoryName), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	records := []Record{
+		{Hash: "abc", Hostname: "mysql-8.0-3306.database.nitro", Database: "project", Imported: now.Add(-48 * time.Hour)},
+		{Hash: "abc", Hostname: "mysql-8.0-3306.database.nitro", Database: "project", Imported: now.Add(-time.Hour)},
+		{Hash: "def", Hostname: "mysql-8.0-3306.database.nitro", Database: "other", Imported: now.Add(-time.Hour)},
+	}
+	for _, r := range records {
+		if err := Add(home, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		hash     string
+		database string
+		since    time.Time
+		want     *time.Time
+	}{
+		{
+			name:     "returns the most recent import of the backup into the database",
+			hash:     "abc",
+			database: "project",
+			since:    now.Add(-24 * time.Hour),
+			want:     &records[1].Imported,
+		},
+		{
+			name:     "imports into other databases are ignored",
+			hash:     "abc",
+			database: "other",
+			since:    now.Add(-24 * time.Hour),
+		},
+		{
+			name:     "imports before the time are ignored",
+			hash:     "abc",
+			database: "project",
+			since:    now.Add(-time.Minute),
+		},
+		{
+			name:     "unknown backups are not found",
+			hash:     "xyz",
+			database: "project",
+			since:    now.Add(-24 * time.Hour),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Find(home, tt.hash, "mysql-8.0-3306.database.nitro", tt.database, tt.since)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			switch {
+			case tt.want == nil && got != nil:
+				t.Errorf("expected no import to be found, got %v", got)
+			case tt.want != nil && got == nil:
+				t.Error("expected an import to be found")
+			case tt.want != nil && !got.Imported.Equal(*tt.want):
+				t.Errorf("expected the import from %v, got %v", *tt.want, got.Imported)
+			}
+		})
+	}
+}
+
+func TestAddKeepsTheLimit(t *testing.T) {
+	home, err := ioutil.TempDir("", "nitro-imports")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	if err := os.Mkdir(filepath.Join(home, config.DirectoryName), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < Limit+5; i++ {
+		if err := Add(home, Record{Hash: "abc", Imported: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records, err := load(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != Limit {
+		t.Errorf("expected %d records, got %d", Limit, len(records))
+	}
+}
@@ -0,0 +1,158 @@
+package logfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// File is a log file that is rotated once it reaches the max size. When rotated, the file
+// is renamed with a number (e.g. nitro.log.1), the older files are renamed with the next
+// number, and the files beyond the number to keep are removed.
+type File struct {
+	path    string
+	maxSize int64
+	keep    int
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+}
+
+// Open opens the file for appending, creating the file and its directory if they do not
+// exist. A max size of 0 disables the rotation, and keep is the number of rotated files
+// to keep in addition to the file.
+func Open(path string, maxSize int64, keep int) (*File, error) {
+	if maxSize < 0 {
+		return nil, fmt.Errorf("the max size of the log file must not be negative, got %d", maxSize)
+	}
+
+	if keep < 0 {
+		return nil, fmt.Errorf("the number of log files to keep must not be negative, got %d", keep)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("unable to create the directory for the log file, %w", err)
+	}
+
+	l := &File{path: path, maxSize: maxSize, keep: keep}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Write writes to the file and rotates the file first if the write would make the file
+// larger than the max size. Each write is kept in a single file, so writing whole lines
+// does not split a line across files. When the rotation fails, the write is appended to
+// the current file and the rotation error is returned.
+func (l *File) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var rotateErr error
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(p)) > l.maxSize {
+		rotateErr = l.rotate()
+	}
+
+	n, err := l.f.Write(p)
+	l.size += int64(n)
+
+	if err == nil {
+		err = rotateErr
+	}
+
+	return n, err
+}
+
+// Name returns the path to the file.
+func (l *File) Name() string {
+	return l.path
+}
+
+// Close closes the file.
+func (l *File) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.f.Close()
+}
+
+// open opens the file for appending and sets the size to the current size of the file.
+func (l *File) open() error {
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to open the log file, %w", err)
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+
+		return fmt.Errorf("unable to get the size of the log file, %w", err)
+	}
+
+	l.f = f
+	l.size = stat.Size()
+
+	return nil
+}
+
+// rotate closes the file, renames the file and the rotated files, and opens a new file.
+// The file is opened again when closing or renaming fails, so the writes continue to the
+// file that could not be rotated.
+func (l *File) rotate() error {
+	err := l.f.Close()
+	switch err {
+	case nil:
+		err = l.shift()
+	default:
+		err = fmt.Errorf("unable to close the log file, %w", err)
+	}
+
+	if openErr := l.open(); openErr != nil {
+		return openErr
+	}
+
+	return err
+}
+
+// shift removes the oldest rotated file and renames the file and the rotated files to
+// the next number.
+func (l *File) shift() error {
+	// the oldest file is removed
+	if err := os.Remove(rotated(l.path, l.keep)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to remove the oldest log file, %w", err)
+	}
+
+	for i := l.keep - 1; i > 0; i-- {
+		if err := os.Rename(rotated(l.path, i), rotated(l.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to rotate the log file, %w", err)
+		}
+	}
+
+	switch l.keep {
+	case 0:
+		if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to remove the log file, %w", err)
+		}
+	default:
+		if err := os.Rename(l.path, rotated(l.path, 1)); err != nil {
+			return fmt.Errorf("unable to rotate the log file, %w", err)
+		}
+	}
+
+	return nil
+}
+
+// rotated returns the path of the rotated file with the number, or the path when the
+// number is 0.
+func rotated(path string, n int) string {
+	if n == 0 {
+		return path
+	}
+
+	return fmt.Sprintf("%s.%d", path, n)
+}
@@ -0,0 +1,135 @@
+package logfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFile_Write(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxSize int64
+		keep    int
+		lines   []string
+		want    map[string]string
+	}{
+		{
+			name:  "files without a max size are not rotated",
+			lines: []string{"one\n", "two\n", "three\n"},
+			want:  map[string]string{"nitro.log": "one\ntwo\nthree\n"},
+		},
+		{
+			name:    "files are rotated when the write is larger than the max size",
+			maxSize: 8,
+			keep:    2,
+			lines:   []string{"one\n", "two\n", "three\n", "four\n"},
+			want: map[string]string{
+				"nitro.log":   "four\n",
+				"nitro.log.1": "three\n",
+				"nitro.log.2": "one\ntwo\n",
+			},
+		},
+		{
+			name:    "only the number of files to keep are kept",
+			maxSize: 4,
+			keep:    1,
+			lines:   []string{"one\n", "two\n", "three\n"},
+			want: map[string]string{
+				"nitro.log":   "three\n",
+				"nitro.log.1": "two\n",
+			},
+		},
+		{
+			name:    "files are truncated when none are kept",
+			maxSize: 4,
+			lines:   []string{"one\n", "two\n"},
+			want:    map[string]string{"nitro.log": "two\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "nitro-logfile")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			f, err := Open(filepath.Join(dir, "logs", "nitro.log"), tt.maxSize, tt.keep)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, l := range tt.lines {
+				if _, err := f.Write([]byte(l)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := f.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			files, err := ioutil.ReadDir(filepath.Join(dir, "logs"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(files) != len(tt.want) {
+				t.Errorf("expected %d files, got %d", len(tt.want), len(files))
+			}
+
+			for name, want := range tt.want {
+				got, err := ioutil.ReadFile(filepath.Join(dir, "logs", name))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if string(got) != want {
+					t.Errorf("expected %s to be %q, got %q", name, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestFile_WriteWhenTheRotationFails(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-logfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "nitro.log")
+	f, err := Open(path, 4, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// a directory in place of the rotated file can't be replaced
+	if err := os.MkdirAll(filepath.Join(dir, "nitro.log.1", "logs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, l := range []string{"one\n", "two\n", "three\n"} {
+		n, err := f.Write([]byte(l))
+		if n != len(l) {
+			t.Errorf("expected to write %d bytes, got %d (%v)", len(l), n, err)
+		}
+	}
+
+	if _, err := f.Write([]byte("four\n")); err == nil {
+		t.Error("expected the rotation error to be returned")
+	}
+
+	got, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "one\ntwo\nthree\nfour\n"; string(got) != want {
+		t.Errorf("expected the writes to continue to the file %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,103 @@
+package phpext
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// Core are the extensions bundled with PHP, they are installed with docker-php-ext-install
+	Core = []string{
+		"bcmath",
+		"bz2",
+		"calendar",
+		"dba",
+		"enchant",
+		"exif",
+		"gd",
+		"gettext",
+		"gmp",
+		"imap",
+		"interbase",
+		"intl",
+		"ldap",
+		"mysqli",
+		"oci8",
+		"odbc",
+		"pcntl",
+		"pdo_dblib",
+		"pdo_firebird",
+		"pdo_oci",
+		"pdo_odbc",
+		"pdo_sqlite",
+		"recode",
+		"shmop",
+		"snmp",
+		"soap",
+		"sockets",
+		"sysvmsg",
+		"sysvsem",
+		"sysvshm",
+		"tidy",
+		"wddx",
+		"xmlrpc",
+		"xsl",
+		"zend_test",
+		"zip",
+	}
+
+	// PECL are the extensions that are installed with pecl and enabled with docker-php-ext-enable
+	PECL = []string{
+		"apcu",
+		"igbinary",
+		"imagick",
+		"memcached",
+		"mongodb",
+		"redis",
+	}
+)
+
+// Available returns the sorted list of extensions that can be enabled for a site.
+func Available() []string {
+	var extensions []string
+	extensions = append(extensions, Core...)
+	extensions = append(extensions, PECL...)
+
+	sort.Strings(extensions)
+
+	return extensions
+}
+
+// Validate returns an error if the extension is not one of the available extensions.
+func Validate(extension string) error {
+	for _, e := range Available() {
+		if e == extension {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown extension %q, use one of %s", extension, strings.Join(Available(), ", "))
+}
+
+// InstallCommand returns the command to run in a site container to install and enable the
+// extension. Extensions that are already loaded, such as the ones included in the image,
+// are skipped.
+func InstallCommand(extension string) []string {
+	install := "docker-php-ext-install " + extension
+	if isPECL(extension) {
+		install = fmt.Sprintf("pecl install %s && docker-php-ext-enable %s", extension, extension)
+	}
+
+	return []string{"sh", "-c", fmt.Sprintf("php -m | grep -qix %s || (%s)", extension, install)}
+}
+
+func isPECL(extension string) bool {
+	for _, e := range PECL {
+		if e == extension {
+			return true
+		}
+	}
+
+	return false
+}
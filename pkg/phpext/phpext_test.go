@@ -0,0 +1,52 @@
+package phpext
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		extension string
+		wantErr   bool
+	}{
+		{name: "core extensions are valid", extension: "bcmath"},
+		{name: "pecl extensions are valid", extension: "imagick"},
+		{name: "unknown extensions return an error", extension: "nope", wantErr: true},
+		{name: "extensions are case sensitive", extension: "Redis", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.extension); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInstallCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		extension string
+		want      []string
+	}{
+		{
+			name:      "core extensions use docker-php-ext-install",
+			extension: "gd",
+			want:      []string{"sh", "-c", "php -m | grep -qix gd || (docker-php-ext-install gd)"},
+		},
+		{
+			name:      "pecl extensions are installed and enabled",
+			extension: "redis",
+			want:      []string{"sh", "-c", "php -m | grep -qix redis || (pecl install redis && docker-php-ext-enable redis)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InstallCommand(tt.extension); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InstallCommand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,5 +1,10 @@
 package phpversions
 
+import (
+	"fmt"
+	"time"
+)
+
 // Versions is the known PHP versions we support
 var Versions = []string{
 	"8.0",
@@ -9,3 +14,62 @@ var Versions = []string{
 	"7.1",
 	"7.0",
 }
+
+// Release contains the details about a PHP version from the PHP release schedule
+// (https://www.php.net/supported-versions.php).
+type Release struct {
+	Version     string
+	Released    time.Time
+	SecurityEOL time.Time
+	Recommended bool
+}
+
+// Releases contains the release details for each of the Versions.
+var Releases = map[string]Release{
+	"8.0": {Version: "8.0", Released: date(2020, 11, 26), SecurityEOL: date(2023, 11, 26), Recommended: true},
+	"7.4": {Version: "7.4", Released: date(2019, 11, 28), SecurityEOL: date(2022, 11, 28)},
+	"7.3": {Version: "7.3", Released: date(2018, 12, 6), SecurityEOL: date(2021, 12, 6)},
+	"7.2": {Version: "7.2", Released: date(2017, 11, 30), SecurityEOL: date(2020, 11, 30)},
+	"7.1": {Version: "7.1", Released: date(2016, 12, 1), SecurityEOL: date(2019, 12, 1)},
+	"7.0": {Version: "7.0", Released: date(2015, 12, 3), SecurityEOL: date(2019, 1, 10)},
+}
+
+// EOL takes a version and returns true if the version no longer
+// receives security updates at the provided time. Unknown
+// versions are not considered end of life.
+func EOL(version string, now time.Time) bool {
+	r, ok := Releases[version]
+	if !ok {
+		return false
+	}
+
+	return !now.Before(r.SecurityEOL)
+}
+
+// Label takes a version and returns the version annotated with its
+// status, such as "8.0 (recommended)" or "7.4 (end of life)", for
+// showing in prompts.
+func Label(version string, now time.Time) string {
+	switch {
+	case EOL(version, now):
+		return fmt.Sprintf("%s (end of life)", version)
+	case Releases[version].Recommended:
+		return fmt.Sprintf("%s (recommended)", version)
+	}
+
+	return version
+}
+
+// Labels returns the labels for all of the Versions.
+func Labels(now time.Time) []string {
+	var labels []string
+	for _, v := range Versions {
+		labels = append(labels, Label(v, now))
+	}
+
+	return labels
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,64 @@
+package phpversions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLabel(t *testing.T) {
+	type args struct {
+		version string
+		now     time.Time
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "recommended versions are annotated",
+			args: args{version: "8.0", now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+			want: "8.0 (recommended)",
+		},
+		{
+			name: "supported versions are not annotated",
+			args: args{version: "7.4", now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+			want: "7.4",
+		},
+		{
+			name: "end of life versions are annotated",
+			args: args{version: "7.2", now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+			want: "7.2 (end of life)",
+		},
+		{
+			name: "versions are end of life on the security end date",
+			args: args{version: "7.4", now: time.Date(2022, 11, 28, 0, 0, 0, 0, time.UTC)},
+			want: "7.4 (end of life)",
+		},
+		{
+			name: "recommended versions that are end of life are annotated as end of life",
+			args: args{version: "8.0", now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+			want: "8.0 (end of life)",
+		},
+		{
+			name: "unknown versions are not annotated",
+			args: args{version: "5.6", now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+			want: "5.6",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Label(tt.args.version, tt.args.now); got != tt.want {
+				t.Errorf("Label() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReleases(t *testing.T) {
+	for _, v := range Versions {
+		if _, ok := Releases[v]; !ok {
+			t.Errorf("expected release details for version %s", v)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+
+	"github.com/craftcms/nitro/pkg/errcode"
 )
 
 // Check takes ports and will check for use against the localhost:port. If any port provided
@@ -17,7 +19,7 @@ func Check(host, port string) error {
 	// create a new listener
 	lis, err := net.Listen("tcp", hostname+":"+port)
 	if err != nil {
-		return fmt.Errorf("it appears port %s, is already in use", port)
+		return errcode.WithCode(errcode.ErrPortInUse, fmt.Errorf("it appears port %s, is already in use", port))
 	}
 
 	// check the close error
@@ -1,36 +1,40 @@
 package prompt
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
+	"time"
 
 	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerfind"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/database"
 	"github.com/craftcms/nitro/pkg/phpversions"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/craftcms/nitro/pkg/validate"
 	"github.com/craftcms/nitro/pkg/webroot"
 	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/spf13/cobra"
 )
 
-// CreateDatabase is used to interactively walk a user through creating a new database. It will return true if the user created a database along
-// with the hostname, database, port, and driver for the database container.
-func CreateDatabase(cmd *cobra.Command, docker client.CommonAPIClient, output terminal.Outputer) (bool, string, string, string, string, error) {
+// CreateDatabase is used to interactively walk a user through creating a new database. It will return true if the user selected a database, true
+// if the database was newly created, along with the hostname, database, port, and driver for the database container. If the database already
+// exists, the user is asked to reuse it or choose a different name.
+func CreateDatabase(cmd *cobra.Command, docker client.CommonAPIClient, output terminal.Outputer) (bool, bool, string, string, string, string, error) {
 	confirm, err := output.Confirm("Add a database for the site?", true, "")
 	if err != nil {
-		return false, "", "", "", "", err
+		return false, false, "", "", "", "", err
 	}
 
 	if !confirm {
-		return false, "", "", "", "", nil
+		return false, false, "", "", "", "", nil
 	}
 
 	// make sure the context is not nil
@@ -39,22 +43,12 @@ func CreateDatabase(cmd *cobra.Command, docker client.CommonAPIClient, output te
 		ctx = context.Background()
 	}
 
-	// add filters to show only the environment and database containers
-	filter := filters.NewArgs()
-	filter.Add("label", containerlabels.Nitro)
-	filter.Add("label", containerlabels.Type+"=database")
-
 	// get a list of all the databases
-	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{Filters: filter, All: true})
+	containers, err := containerfind.Databases(ctx, docker)
 	if err != nil {
-		return false, "", "", "", "", err
+		return false, false, "", "", "", "", err
 	}
 
-	// sort containers by the name
-	sort.SliceStable(containers, func(i, j int) bool {
-		return containers[i].Names[0] < containers[j].Names[0]
-	})
-
 	// get all of the containers as a list
 	var engineOpts []string
 	for _, c := range containers {
@@ -63,35 +57,97 @@ func CreateDatabase(cmd *cobra.Command, docker client.CommonAPIClient, output te
 			for _, command := range cmd.Root().Commands() {
 				if command.Use == "start" {
 					if err := command.RunE(cmd, []string{}); err != nil {
-						return false, "", "", "", "", err
+						return false, false, "", "", "", "", err
 					}
 				}
 			}
 		}
 
-		engineOpts = append(engineOpts, strings.TrimLeft(c.Names[0], "/"))
+		engineOpts = append(engineOpts, containerfind.Name(c))
 	}
 
 	// prompt the user for the engine to add the database
 	var containerID, databaseEngine string
 	selected, err := output.Select(os.Stdin, "Select the database engine: ", engineOpts)
 	if err != nil {
-		return false, "", "", "", "", err
+		return false, false, "", "", "", "", err
 	}
 
 	// set the container id and db engine
 	containerID = containers[selected].ID
 	databaseEngine = containers[selected].Labels[containerlabels.DatabaseCompatibility]
 	if containerID == "" {
-		return false, "", "", "", "", fmt.Errorf("unable to get the container")
+		return false, false, "", "", "", "", fmt.Errorf("unable to get the container")
 	}
 
 	// ask the user for the database to create
-	db, err := output.Ask("Enter the new database name", "", ":", &validate.DatabaseName{})
+	var db string
+	var exists bool
+	for {
+		db, err = output.Ask("Enter the new database name", "", ":", &validate.DatabaseName{})
+		if err != nil {
+			return false, false, "", "", "", "", err
+		}
+
+		// check if the database already exists
+		exists, err = databaseExists(ctx, docker, containerID, databaseEngine, db)
+		if err != nil {
+			return false, false, "", "", "", "", err
+		}
+
+		if !exists {
+			break
+		}
+
+		// ask the user to reuse the database or choose a different name
+		reuse, err := output.Confirm(fmt.Sprintf("The database %s already exists, use it?", db), true, "")
+		if err != nil {
+			return false, false, "", "", "", "", err
+		}
+
+		if reuse {
+			output.Info("Using existing database", db)
+			break
+		}
+	}
+
+	// only create the database if it does not exist
+	if !exists {
+		if err := createDatabase(ctx, docker, containerID, databaseEngine, db, output); err != nil {
+			return false, false, "", "", "", "", err
+		}
+	}
+
+	// get the container hostname
+	hostname := containerfind.Name(containers[selected])
+
+	// get the info from the container
+	info, err := docker.ContainerInspect(ctx, containers[selected].ID)
 	if err != nil {
-		return false, "", "", "", "", err
+		return false, false, "", "", "", "", err
+	}
+
+	var port string
+	for p := range info.NetworkSettings.Ports {
+		if port != "" {
+			break
+		}
+
+		port = p.Port()
 	}
 
+	// set the driver for the database
+	driver := "mysql"
+	if containers[selected].Labels[containerlabels.DatabaseCompatibility] == "postgres" {
+		driver = "pgsql"
+	}
+
+	return true, !exists, hostname, db, port, driver, nil
+}
+
+// createDatabase runs the create database command, and grants privileges if needed, for the engine
+// in the database container.
+func createDatabase(ctx context.Context, docker client.CommonAPIClient, containerID, databaseEngine, db string, output terminal.Outputer) error {
 	output.Pending("creating database", db)
 
 	// set the commands based on the engine type
@@ -112,7 +168,7 @@ func CreateDatabase(cmd *cobra.Command, docker client.CommonAPIClient, output te
 		Cmd:          cmds,
 	})
 	if err != nil {
-		return false, "", "", "", "", err
+		return err
 	}
 
 	// attach to the container
@@ -120,13 +176,13 @@ func CreateDatabase(cmd *cobra.Command, docker client.CommonAPIClient, output te
 		Tty: false,
 	})
 	if err != nil {
-		return false, "", "", "", "", err
+		return err
 	}
 	defer resp.Close()
 
 	// start the exec
 	if err := docker.ContainerExecStart(ctx, e.ID, types.ExecStartCheck{}); err != nil {
-		return false, "", "", "", "", fmt.Errorf("unable to start the container, %w", err)
+		return fmt.Errorf("unable to start the container, %w", err)
 	}
 
 	// check if we should grant privileges
@@ -139,7 +195,7 @@ func CreateDatabase(cmd *cobra.Command, docker client.CommonAPIClient, output te
 			Cmd:          privileges,
 		})
 		if err != nil {
-			return false, "", "", "", "", err
+			return err
 		}
 
 		// attach to the container
@@ -147,13 +203,13 @@ func CreateDatabase(cmd *cobra.Command, docker client.CommonAPIClient, output te
 			Tty: false,
 		})
 		if err != nil {
-			return false, "", "", "", "", err
+			return err
 		}
 		defer resp.Close()
 
 		// start the exec
 		if err := docker.ContainerExecStart(ctx, e.ID, types.ExecStartCheck{}); err != nil {
-			return false, "", "", "", "", fmt.Errorf("unable to start the container, %w", err)
+			return fmt.Errorf("unable to start the container, %w", err)
 		}
 
 		// wait for the container exec to complete
@@ -161,7 +217,7 @@ func CreateDatabase(cmd *cobra.Command, docker client.CommonAPIClient, output te
 		for waiting {
 			resp, err := docker.ContainerExecInspect(ctx, e.ID)
 			if err != nil {
-				return false, "", "", "", "", err
+				return err
 			}
 
 			waiting = resp.Running
@@ -172,31 +228,70 @@ func CreateDatabase(cmd *cobra.Command, docker client.CommonAPIClient, output te
 
 	output.Info("Database added 💪")
 
-	// get the container hostname
-	hostname := strings.TrimLeft(containers[selected].Names[0], "/")
+	return nil
+}
 
-	// get the info from the container
-	info, err := docker.ContainerInspect(ctx, containers[selected].ID)
+// databaseExists queries the database engine in the container to check if the database
+// has already been created.
+func databaseExists(ctx context.Context, docker client.CommonAPIClient, containerID, databaseEngine, db string) (bool, error) {
+	// set the query based on the engine type
+	var cmds []string
+	switch databaseEngine {
+	case "mysql":
+		cmds = []string{"mysql", "-uroot", "-pnitro", "--skip-column-names", "--silent", fmt.Sprintf(`-e SELECT SCHEMA_NAME FROM INFORMATION_SCHEMA.SCHEMATA WHERE SCHEMA_NAME = %s;`, database.QuoteString(databaseEngine, db))}
+	default:
+		cmds = []string{"psql", "--username=nitro", "--host=127.0.0.1", "--tuples-only", "--no-align", fmt.Sprintf(`-c SELECT 1 FROM pg_database WHERE datname = %s;`, database.QuoteString(databaseEngine, db))}
+	}
+
+	// create the exec
+	e, err := docker.ContainerExecCreate(ctx, containerID, types.ExecConfig{
+		AttachStdout: true,
+		AttachStderr: true,
+		Tty:          false,
+		Cmd:          cmds,
+	})
 	if err != nil {
-		return false, "", "", "", "", err
+		return false, err
 	}
 
-	var port string
-	for p := range info.NetworkSettings.Ports {
-		if port != "" {
-			break
+	// attach to the container
+	resp, err := docker.ContainerExecAttach(ctx, e.ID, types.ExecStartCheck{
+		Tty: false,
+	})
+	if err != nil {
+		return false, err
+	}
+	defer resp.Close()
+
+	// start the exec
+	if err := docker.ContainerExecStart(ctx, e.ID, types.ExecStartCheck{}); err != nil {
+		return false, fmt.Errorf("unable to start the container, %w", err)
+	}
+
+	// the engines only write to stdout when the database exists
+	var stdout, stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, &stderr, resp.Reader); err != nil {
+		return false, err
+	}
+
+	// wait for the exec to complete, a failed query should not look like a missing database
+	waiting := true
+	exitCode := 0
+	for waiting {
+		info, err := docker.ContainerExecInspect(ctx, e.ID)
+		if err != nil {
+			return false, err
 		}
 
-		port = p.Port()
+		waiting = info.Running
+		exitCode = info.ExitCode
 	}
 
-	// set the driver for the database
-	driver := "mysql"
-	if containers[selected].Labels[containerlabels.DatabaseCompatibility] == "postgres" {
-		driver = "pgsql"
+	if exitCode != 0 {
+		return false, fmt.Errorf("unable to check if the database %s exists, %s", db, strings.TrimSpace(stderr.String()))
 	}
 
-	return true, hostname, db, port, driver, nil
+	return strings.TrimSpace(stdout.String()) != "", nil
 }
 
 // CreateSite takes the users home directory and the site path and walked the user
@@ -227,10 +322,18 @@ func CreateSite(home, dir string, output terminal.Outputer) (*config.Site, error
 		return nil, err
 	}
 
-	// set the input as the hostname
-	site.Hostname = hostname
+	// set the input as the hostname, internationalized hostnames are stored as punycode
+	site.Hostname, err = validate.Punycode(hostname)
+	if err != nil {
+		return nil, err
+	}
 
-	output.Success("setting hostname to", site.Hostname)
+	switch site.Hostname == hostname {
+	case true:
+		output.Success("setting hostname to", site.Hostname)
+	default:
+		output.Success("setting hostname to", hostname, "("+site.Hostname+")")
+	}
 
 	// set the sites directory but make the path relative
 	siteAbsPath, err := filepath.Abs(dir)
@@ -264,7 +367,7 @@ func CreateSite(home, dir string, output terminal.Outputer) (*config.Site, error
 
 	// prompt for the php version
 	versions := phpversions.Versions
-	selected, err := output.Select(os.Stdin, "Choose a PHP version: ", versions)
+	selected, err := output.Select(os.Stdin, "Choose a PHP version: ", phpversions.Labels(time.Now()))
 	if err != nil {
 		return nil, err
 	}
@@ -274,6 +377,11 @@ func CreateSite(home, dir string, output terminal.Outputer) (*config.Site, error
 
 	output.Success("setting PHP version", site.Version)
 
+	// warn about versions that no longer receive security updates
+	if phpversions.EOL(site.Version, time.Now()) {
+		output.Info("PHP", site.Version, "is end of life and no longer receives security updates")
+	}
+
 	// load the config
 	cfg, err := config.Load(home)
 	if err != nil {
@@ -1,16 +1,23 @@
 package proxycontainer
 
 import (
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	volumetypes "github.com/docker/docker/api/types/volume"
 
 	"github.com/craftcms/nitro/command/version"
+	"github.com/craftcms/nitro/pkg/containerfind"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/errcode"
+	"github.com/craftcms/nitro/pkg/imagepull"
+	"github.com/craftcms/nitro/pkg/portavail"
 	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/pkg/validate"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
@@ -28,17 +35,201 @@ var (
 	ProxyName = "nitro-proxy"
 
 	// ErrNoProxyContainer is returned when the proxy container is not found
-	ErrNoProxyContainer = fmt.Errorf("unable to locate the proxy container")
+	ErrNoProxyContainer = errcode.WithCode(errcode.ErrNoProxy, fmt.Errorf("unable to locate the proxy container"))
+
+	// HTTPPort is the port for HTTP requests
+	HTTPPort = Port{Name: "HTTP", Env: "NITRO_HTTP_PORT", Container: "80"}
+
+	// HTTPSPort is the port for HTTPS requests
+	HTTPSPort = Port{Name: "HTTPS", Env: "NITRO_HTTPS_PORT", Container: "443"}
+
+	// APIPort is the port for the nitrod api
+	APIPort = Port{Name: "API", Env: "NITRO_API_PORT", Container: "5000"}
+
+	// NodePort is the first port for node development servers
+	NodePort = Port{Name: "node", Env: "NITRO_NODE_PORT", Container: "3000"}
+
+	// AltNodePort is the second port for node development servers
+	AltNodePort = Port{Name: "second node", Env: "NITRO_ALT_NODE_PORT", Container: "3001"}
+
+	// HostIP is the address the proxy container binds the HTTP, HTTPS, and node ports to,
+	// another address is only used when it is set with NITRO_PROXY_HOST_IP. The API port
+	// is always bound to the loopback address since the API is not authenticated.
+	HostIP = "127.0.0.1"
+
+	// Ports are the tcp ports the proxy container binds on the host
+	Ports = []Port{HTTPPort, HTTPSPort, APIPort, NodePort, AltNodePort}
+
+	// ExtraPortsEnv is the environment variable with a comma separated list of additional
+	// ports for the proxy container, it replaces the proxy_ports in the config when defined
+	ExtraPortsEnv = "NITRO_PROXY_PORTS"
 )
 
-// Create is used to create a new proxy container for the nitro development environment.
-func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.Outputer, networkID string) error {
+// Port is a tcp port on the proxy container and the environment variable used to
+// change the port it is bound to on the host.
+type Port struct {
+	Name      string
+	Env       string
+	Container string
+}
+
+// HostPort returns the port on the host from the environment variable, or the same
+// port as the container when the variable is not defined.
+func (p Port) HostPort() string {
+	if _, defined := os.LookupEnv(p.Env); defined {
+		return os.Getenv(p.Env)
+	}
+
+	return p.Container
+}
+
+// PortMapping is an additional tcp port the proxy container binds on the host, e.g. for a
+// websocket server or the web interface of a mail catcher. The proxy listens on the
+// container port and sends the requests for each site to the same port on the site.
+type PortMapping struct {
+	Host      string
+	Container string
+}
+
+// String returns the mapping in the host:container format used by docker.
+func (m PortMapping) String() string {
+	return m.Host + ":" + m.Container
+}
+
+// ExtraPorts returns the additional ports for the proxy container from the NITRO_PROXY_PORTS
+// environment variable, or the ports from the config when the variable is not defined. Each
+// port is a container port bound to the same port on the host (e.g. 8025) or a host and
+// container port (e.g. 9025:8025). The ports cannot use a port the proxy already binds.
+func ExtraPorts(configured []string) ([]PortMapping, error) {
+	ports := configured
+	if env, defined := os.LookupEnv(ExtraPortsEnv); defined {
+		ports = nil
+		for _, p := range strings.Split(env, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				ports = append(ports, p)
+			}
+		}
+	}
+
+	// the ports the proxy binds for every environment
+	hosts := make(map[string]string)
+	containers := make(map[string]string)
+	for _, p := range Ports {
+		hosts[p.HostPort()] = p.Name + " port"
+		containers[p.Container] = p.Name + " port"
+	}
+
+	// the caddy admin API and the on demand TLS ask endpoint only listen in the container
+	containers["2019"] = "caddy API"
+	containers["5001"] = "on demand TLS API"
+
+	v := &validate.PortValidator{}
+
+	var mappings []PortMapping
+	for _, p := range ports {
+		m := PortMapping{Host: p, Container: p}
+		if parts := strings.Split(p, ":"); len(parts) == 2 {
+			m = PortMapping{Host: parts[0], Container: parts[1]}
+		}
+
+		for _, port := range []string{m.Host, m.Container} {
+			if err := v.Validate(port); err != nil {
+				return nil, fmt.Errorf("the additional proxy port %q is not valid, %w", p, err)
+			}
+		}
+
+		if used, ok := hosts[m.Host]; ok {
+			return nil, fmt.Errorf("the additional proxy port %q uses host port %s, which is used by the %s", p, m.Host, used)
+		}
+
+		if used, ok := containers[m.Container]; ok {
+			return nil, fmt.Errorf("the additional proxy port %q uses container port %s, which is used by the %s", p, m.Container, used)
+		}
+
+		hosts[m.Host] = fmt.Sprintf("additional port %q", p)
+		containers[m.Container] = fmt.Sprintf("additional port %q", p)
+
+		mappings = append(mappings, m)
+	}
+
+	return mappings, nil
+}
+
+// Image returns the image used for the proxy container. In development (NITRO_DEVELOPMENT=true),
+// NITRO_PROXY_IMAGE can be set to a locally built image to test changes to the proxy. The image
+// must exist locally and be built for the platform of the docker engine.
+func Image(ctx context.Context, docker client.CommonAPIClient) (string, error) {
+	override := os.Getenv("NITRO_PROXY_IMAGE")
+	if override == "" || os.Getenv("NITRO_DEVELOPMENT") != "true" {
+		return ProxyImage, nil
+	}
+
+	info, _, err := docker.ImageInspectWithRaw(ctx, override)
+	if client.IsErrNotFound(err) {
+		return "", fmt.Errorf("the proxy image %s from NITRO_PROXY_IMAGE does not exist locally, build it with `docker build -t %s .`", override, override)
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to inspect the proxy image %s, %w", override, err)
+	}
+
+	// an image built for another platform fails to start or runs emulated
+	server, err := docker.ServerVersion(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to get the docker engine version, %w", err)
+	}
+
+	if want, got := Platform(server.Os, server.Arch), Platform(info.Os, info.Architecture); want != got {
+		return "", fmt.Errorf("the proxy image %s is built for %s but docker is running on %s, build it with `docker build --platform %s -t %s .`", override, got, want, want, override)
+	}
+
+	return override, nil
+}
+
+// Platform returns the platform (e.g. linux/arm64) for the os and architecture, the
+// architectures reported by uname (e.g. aarch64) are converted to the docker names.
+func Platform(goos, arch string) string {
+	switch arch {
+	case "x86_64":
+		arch = "amd64"
+	case "aarch64":
+		arch = "arm64"
+	}
+
+	if goos == "" {
+		goos = "linux"
+	}
+
+	return goos + "/" + arch
+}
+
+// Create is used to create a new proxy container for the nitro development environment. The
+// restart policy (e.g. unless-stopped) is set on the container, an empty policy does not
+// restart the container. When http3 is true, the HTTPS port is also bound over UDP for QUIC.
+// The additional ports (e.g. 8025 or 9025:8025) are bound along with the default ports, see
+// ExtraPorts.
+func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.Outputer, networkID, restartPolicy string, http3 bool, ports []string) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
+
+	if err := (&validate.RestartPolicy{}).Validate(restartPolicy); err != nil {
+		return err
+	}
+
+	extraPorts, err := ExtraPorts(ports)
+	if err != nil {
+		return err
+	}
+
+	// the image can be overridden with a local image in development
+	image, err := Image(ctx, docker)
+	if err != nil {
+		return err
+	}
+
 	filter := filters.NewArgs()
 	filter.Add("label", containerlabels.Nitro+"=true")
-	filter.Add("reference", ProxyImage)
+	filter.Add("reference", image)
 
 	// check for the proxy image
 	images, err := docker.ImageList(ctx, types.ImageListOptions{Filters: filter})
@@ -48,22 +239,12 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 
 	// if there are no local images, pull it
 	if len(images) == 0 && os.Getenv("NITRO_DEVELOPMENT") != "true" {
-		output.Pending("pulling image")
-
-		rdr, err := docker.ImagePull(ctx, ProxyImage, types.ImagePullOptions{All: false})
-		if err != nil {
+		if err := imagepull.PullWithProgress(ctx, docker, output, image); err != nil {
 			return fmt.Errorf("unable to pull the nitro-proxy from docker hub, %w", err)
 		}
-
-		buf := &bytes.Buffer{}
-		if _, err := buf.ReadFrom(rdr); err != nil {
-			return fmt.Errorf("unable to read the output from pulling the image, %w", err)
-		}
-
-		output.Done()
 	}
 
-	filter.Del("reference", ProxyImage)
+	filter.Del("reference", image)
 	// check if the volume needs to be created
 	volumes, err := docker.VolumeList(ctx, filter)
 	if err != nil {
@@ -107,7 +288,7 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 	}
 
 	// remove the reference filter
-	filter.Del("reference", ProxyImage)
+	filter.Del("reference", image)
 
 	// create a filter for the nitro proxy
 	filter.Add("label", containerlabels.Proxy+"=true")
@@ -139,35 +320,12 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 	// if we do not have a proxy, it needs to be create
 	output.Pending("creating proxy")
 
-	// check for a custom HTTP port
-	httpPort := "80"
-	if _, defined := os.LookupEnv("NITRO_HTTP_PORT"); defined {
-		httpPort = os.Getenv("NITRO_HTTP_PORT")
-	}
-
-	// check for a custom HTTPS port
-	httpsPort := "443"
-	if _, defined := os.LookupEnv("NITRO_HTTPS_PORT"); defined {
-		httpsPort = os.Getenv("NITRO_HTTPS_PORT")
-	}
-
-	// check for a custom API port
-	apiPort := "5000"
-	if _, defined := os.LookupEnv("NITRO_API_PORT"); defined {
-		apiPort = os.Getenv("NITRO_API_PORT")
-	}
-
-	// check the first node port
-	nodePort := "3000"
-	if _, defined := os.LookupEnv("NITRO_NODE_PORT"); defined {
-		nodePort = os.Getenv("NITRO_NODE_PORT")
-	}
-
-	// check the second node port
-	altNodePort := "3001"
-	if _, defined := os.LookupEnv("NITRO_ALT_NODE_PORT"); defined {
-		altNodePort = os.Getenv("NITRO_ALT_NODE_PORT")
-	}
+	// check for custom ports
+	httpPort := HTTPPort.HostPort()
+	httpsPort := HTTPSPort.HostPort()
+	apiPort := APIPort.HostPort()
+	nodePort := NodePort.HostPort()
+	altNodePort := AltNodePort.HostPort()
 
 	httpPortNat, err := nat.NewPort("tcp", "80")
 	if err != nil {
@@ -194,17 +352,90 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 		return fmt.Errorf("unable to set the second node port, %w", err)
 	}
 
+	exposedPorts := nat.PortSet{
+		httpPortNat:    struct{}{},
+		httpsPortNat:   struct{}{},
+		apiPortNat:     struct{}{},
+		nodePortNat:    struct{}{},
+		altNodePortNat: struct{}{},
+	}
+
+	portBindings := map[nat.Port][]nat.PortBinding{
+		httpPortNat: {
+			{
+				HostIP:   HostIP,
+				HostPort: httpPort,
+			},
+		},
+		httpsPortNat: {
+			{
+				HostIP:   HostIP,
+				HostPort: httpsPort,
+			},
+		},
+		apiPortNat: {
+			{
+				HostIP:   "127.0.0.1",
+				HostPort: apiPort,
+			},
+		},
+		nodePortNat: {
+			{
+				HostIP:   HostIP,
+				HostPort: nodePort,
+			},
+		},
+		altNodePortNat: {
+			{
+				HostIP:   HostIP,
+				HostPort: altNodePort,
+			},
+		},
+	}
+
+	// bind the HTTPS port over udp for HTTP/3
+	if http3 {
+		http3PortNat, err := nat.NewPort("udp", "443")
+		if err != nil {
+			return fmt.Errorf("unable to set the HTTP/3 port, %w", err)
+		}
+
+		exposedPorts[http3PortNat] = struct{}{}
+		portBindings[http3PortNat] = []nat.PortBinding{
+			{
+				HostIP:   HostIP,
+				HostPort: httpsPort,
+			},
+		}
+	}
+
+	// bind the additional ports, which must be available on the host
+	for _, m := range extraPorts {
+		if err := portavail.Check("", m.Host); err != nil {
+			output.Warning()
+
+			return fmt.Errorf("unable to bind the additional proxy port %s, %w", m, err)
+		}
+
+		portNat, err := nat.NewPort("tcp", m.Container)
+		if err != nil {
+			return fmt.Errorf("unable to set the additional port %s, %w", m, err)
+		}
+
+		exposedPorts[portNat] = struct{}{}
+		portBindings[portNat] = []nat.PortBinding{
+			{
+				HostIP:   HostIP,
+				HostPort: m.Host,
+			},
+		}
+	}
+
 	// create a container
 	resp, err := docker.ContainerCreate(ctx,
 		&container.Config{
-			Image: ProxyImage,
-			ExposedPorts: nat.PortSet{
-				httpPortNat:    struct{}{},
-				httpsPortNat:   struct{}{},
-				apiPortNat:     struct{}{},
-				nodePortNat:    struct{}{},
-				altNodePortNat: struct{}{},
-			},
+			Image:        image,
+			ExposedPorts: exposedPorts,
 			Labels: map[string]string{
 				containerlabels.Nitro:        "true",
 				containerlabels.Type:         "proxy",
@@ -214,7 +445,8 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 			Env: []string{"PGPASSWORD=nitro", "PGUSER=nitro", "NITRO_VERSION=" + version.Version},
 		},
 		&container.HostConfig{
-			NetworkMode: "default",
+			NetworkMode:   "default",
+			RestartPolicy: container.RestartPolicy{Name: restartPolicy},
 			Mounts: []mount.Mount{
 				{
 					Type:   mount.TypeVolume,
@@ -222,38 +454,7 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 					Target: "/data",
 				},
 			},
-			PortBindings: map[nat.Port][]nat.PortBinding{
-				httpPortNat: {
-					{
-						HostIP:   "127.0.0.1",
-						HostPort: httpPort,
-					},
-				},
-				httpsPortNat: {
-					{
-						HostIP:   "127.0.0.1",
-						HostPort: httpsPort,
-					},
-				},
-				apiPortNat: {
-					{
-						HostIP:   "127.0.0.1",
-						HostPort: apiPort,
-					},
-				},
-				nodePortNat: {
-					{
-						HostIP:   "127.0.0.1",
-						HostPort: nodePort,
-					},
-				},
-				altNodePortNat: {
-					{
-						HostIP:   "127.0.0.1",
-						HostPort: altNodePort,
-					},
-				},
-			},
+			PortBindings: portBindings,
 		},
 		&network.NetworkingConfig{
 			EndpointsConfig: map[string]*network.EndpointSettings{
@@ -266,7 +467,7 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 		ProxyName,
 	)
 	if err != nil {
-		return fmt.Errorf("unable to create proxy container: %s\n%w", ProxyImage, err)
+		return fmt.Errorf("unable to create proxy container: %s\n%w", image, err)
 	}
 
 	if err := docker.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
@@ -278,35 +479,130 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 	return nil
 }
 
+// HasHTTP3Port inspects the proxy container and returns true if the HTTPS port is
+// bound over udp for HTTP/3.
+func HasHTTP3Port(ctx context.Context, docker client.ContainerAPIClient, id string) (bool, error) {
+	info, err := docker.ContainerInspect(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("unable to inspect the proxy container: %w", err)
+	}
+
+	if info.HostConfig == nil {
+		return false, nil
+	}
+
+	_, ok := info.HostConfig.PortBindings[nat.Port("443/udp")]
+
+	return ok, nil
+}
+
+// PortMismatches inspects the proxy container and returns a description of each port
+// that is bound to a different port on the host than the environment expects, e.g.
+// when NITRO_HTTP_PORT was changed or an additional port was added after the proxy
+// was created.
+func PortMismatches(ctx context.Context, docker client.ContainerAPIClient, id string, ports []string) ([]string, error) {
+	extraPorts, err := ExtraPorts(ports)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := docker.ContainerInspect(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("unable to inspect the proxy container: %w", err)
+	}
+
+	if info.HostConfig == nil {
+		return nil, nil
+	}
+
+	return mismatches(info.HostConfig.PortBindings, extraPorts), nil
+}
+
+// mismatches compares the port bindings to the expected host port for each of the Ports
+// and the additional ports, and returns the additional ports that are no longer used.
+func mismatches(bindings nat.PortMap, extraPorts []PortMapping) []string {
+	expected := make(map[nat.Port]bool)
+
+	var diffs []string
+	for _, p := range Ports {
+		expected[nat.Port(p.Container+"/tcp")] = true
+
+		bound := hostPort(bindings, p.Container)
+		want := p.HostPort()
+		if bound == want {
+			continue
+		}
+
+		if bound == "" {
+			diffs = append(diffs, fmt.Sprintf("the %s port is not bound, expected %s (%s)", p.Name, want, p.Env))
+
+			continue
+		}
+
+		diffs = append(diffs, fmt.Sprintf("the %s port is bound to %s, expected %s (%s)", p.Name, bound, want, p.Env))
+	}
+
+	for _, m := range extraPorts {
+		expected[nat.Port(m.Container+"/tcp")] = true
+
+		bound := hostPort(bindings, m.Container)
+		if bound == m.Host {
+			continue
+		}
+
+		if bound == "" {
+			diffs = append(diffs, fmt.Sprintf("the additional port %s is not bound", m))
+
+			continue
+		}
+
+		diffs = append(diffs, fmt.Sprintf("the additional port %s is bound to %s", m, bound))
+	}
+
+	var removed []string
+	for port := range bindings {
+		if port.Proto() == "tcp" && !expected[port] {
+			removed = append(removed, fmt.Sprintf("the additional port %s is no longer used", PortMapping{Host: hostPort(bindings, port.Port()), Container: port.Port()}))
+		}
+	}
+
+	sort.Strings(removed)
+
+	return append(diffs, removed...)
+}
+
+// hostPort returns the port on the host bound to the tcp port of the container, or an
+// empty string when the port is not bound.
+func hostPort(bindings nat.PortMap, container string) string {
+	for _, b := range bindings[nat.Port(container+"/tcp")] {
+		if b.HostPort != "" {
+			return b.HostPort
+		}
+	}
+
+	return ""
+}
+
 // FindAndStart will look for the proxy container and verify the container is started. It will return the
 // ErrNoProxyContainer error if it is unable to locate the proxy container. It is NOT responsible for
 // creating the proxy container as that is handled in the initialize package.
 func FindAndStart(ctx context.Context, docker client.ContainerAPIClient) (types.Container, error) {
-	// create the filters for the proxy
-	f := filters.NewArgs()
-	f.Add("label", containerlabels.Type+"=proxy")
-
 	// check if there is an existing container for the nitro-proxy
-	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{Filters: f, All: true})
+	c, err := containerfind.Proxy(ctx, docker)
+	if errors.Is(err, containerfind.ErrNotFound) {
+		return types.Container{}, ErrNoProxyContainer
+	}
 	if err != nil {
 		return types.Container{}, fmt.Errorf("unable to list the containers: %w", err)
 	}
 
-	for _, c := range containers {
-		for _, n := range c.Names {
-			if n == "nitro-proxy" || n == "/nitro-proxy" {
-				// check if it is running
-				if c.State != "running" {
-					if err := docker.ContainerStart(ctx, c.ID, types.ContainerStartOptions{}); err != nil {
-						return types.Container{}, fmt.Errorf("unable to start the proxy container: %w", err)
-					}
-				}
-
-				// return the container
-				return c, nil
-			}
+	// check if it is running
+	if c.State != "running" {
+		if err := docker.ContainerStart(ctx, c.ID, types.ContainerStartOptions{}); err != nil {
+			return types.Container{}, fmt.Errorf("unable to start the proxy container: %w", err)
 		}
 	}
 
-	return types.Container{}, ErrNoProxyContainer
+	// return the container
+	return *c, nil
 }
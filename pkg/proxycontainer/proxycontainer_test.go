@@ -0,0 +1,256 @@
+package proxycontainer
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/go-connections/nat"
+)
+
+type mockImageClient struct {
+	client.CommonAPIClient
+
+	images map[string]types.ImageInspect
+	arch   string
+}
+
+func (m *mockImageClient) ImageInspectWithRaw(ctx context.Context, image string) (types.ImageInspect, []byte, error) {
+	info, ok := m.images[image]
+	if !ok {
+		return types.ImageInspect{}, nil, errdefs.NotFound(os.ErrNotExist)
+	}
+
+	return info, nil, nil
+}
+
+func (m *mockImageClient) ServerVersion(ctx context.Context) (types.Version, error) {
+	return types.Version{Os: "linux", Arch: m.arch}, nil
+}
+
+func TestImage(t *testing.T) {
+	docker := &mockImageClient{
+		arch: "arm64",
+		images: map[string]types.ImageInspect{
+			"nitro-proxy:dev":   {Os: "linux", Architecture: "arm64"},
+			"nitro-proxy:amd64": {Os: "linux", Architecture: "amd64"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		development string
+		override    string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name: "the release image is used by default",
+			want: ProxyImage,
+		},
+		{
+			name:     "the override is ignored outside of development",
+			override: "nitro-proxy:dev",
+			want:     ProxyImage,
+		},
+		{
+			name:        "local images are used in development",
+			development: "true",
+			override:    "nitro-proxy:dev",
+			want:        "nitro-proxy:dev",
+		},
+		{
+			name:        "missing images return an error",
+			development: "true",
+			override:    "nitro-proxy:missing",
+			wantErr:     true,
+		},
+		{
+			name:        "images for another platform return an error",
+			development: "true",
+			override:    "nitro-proxy:amd64",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv("NITRO_DEVELOPMENT", tt.development)
+			defer os.Unsetenv("NITRO_DEVELOPMENT")
+			os.Setenv("NITRO_PROXY_IMAGE", tt.override)
+			defer os.Unsetenv("NITRO_PROXY_IMAGE")
+
+			got, err := Image(context.TODO(), docker)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Image() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Image() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlatform(t *testing.T) {
+	tests := []struct {
+		goos, arch, want string
+	}{
+		{goos: "linux", arch: "amd64", want: "linux/amd64"},
+		{goos: "linux", arch: "x86_64", want: "linux/amd64"},
+		{goos: "linux", arch: "aarch64", want: "linux/arm64"},
+		{arch: "arm64", want: "linux/arm64"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := Platform(tt.goos, tt.arch); got != tt.want {
+				t.Errorf("Platform(%q, %q) = %q, want %q", tt.goos, tt.arch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMismatches(t *testing.T) {
+	bindings := func(http string) nat.PortMap {
+		return nat.PortMap{
+			"80/tcp":   {{HostIP: "127.0.0.1", HostPort: http}},
+			"443/tcp":  {{HostIP: "127.0.0.1", HostPort: "443"}},
+			"443/udp":  {{HostIP: "127.0.0.1", HostPort: "443"}},
+			"5000/tcp": {{HostIP: "127.0.0.1", HostPort: "5000"}},
+			"3000/tcp": {{HostIP: "127.0.0.1", HostPort: "3000"}},
+			"3001/tcp": {{HostIP: "127.0.0.1", HostPort: "3001"}},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		httpPort   string
+		bindings   nat.PortMap
+		extraPorts []PortMapping
+		want       []string
+	}{
+		{
+			name:     "default ports match",
+			bindings: bindings("80"),
+		},
+		{
+			name:     "custom ports match",
+			httpPort: "8080",
+			bindings: bindings("8080"),
+		},
+		{
+			name:     "changed ports are returned",
+			httpPort: "8080",
+			bindings: bindings("80"),
+			want:     []string{"the HTTP port is bound to 80, expected 8080 (NITRO_HTTP_PORT)"},
+		},
+		{
+			name:     "missing ports are returned",
+			bindings: nat.PortMap{"80/tcp": {{HostPort: "80"}}, "443/tcp": {{HostPort: "443"}}, "5000/tcp": {{HostPort: "5000"}}, "3000/tcp": {{HostPort: "3000"}}},
+			want:     []string{"the second node port is not bound, expected 3001 (NITRO_ALT_NODE_PORT)"},
+		},
+		{
+			name:       "additional ports that are not bound are returned",
+			bindings:   bindings("80"),
+			extraPorts: []PortMapping{{Host: "9025", Container: "8025"}},
+			want:       []string{"the additional port 9025:8025 is not bound"},
+		},
+		{
+			name: "additional ports that are no longer used are returned",
+			bindings: func() nat.PortMap {
+				b := bindings("80")
+				b["8025/tcp"] = []nat.PortBinding{{HostIP: "127.0.0.1", HostPort: "8025"}}
+				return b
+			}(),
+			want: []string{"the additional port 8025:8025 is no longer used"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.httpPort != "" {
+				os.Setenv("NITRO_HTTP_PORT", tt.httpPort)
+				defer os.Unsetenv("NITRO_HTTP_PORT")
+			}
+
+			if got := mismatches(tt.bindings, tt.extraPorts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mismatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtraPorts(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured []string
+		env        *string
+		want       []PortMapping
+		wantErr    bool
+	}{
+		{
+			name:       "ports are bound to the same port on the host",
+			configured: []string{"8025"},
+			want:       []PortMapping{{Host: "8025", Container: "8025"}},
+		},
+		{
+			name:       "ports can be bound to a different port on the host",
+			configured: []string{"9025:8025"},
+			want:       []PortMapping{{Host: "9025", Container: "8025"}},
+		},
+		{
+			name:       "the environment replaces the config",
+			configured: []string{"8025"},
+			env:        stringPtr("6001, 9025:8025"),
+			want:       []PortMapping{{Host: "6001", Container: "6001"}, {Host: "9025", Container: "8025"}},
+		},
+		{
+			name:       "invalid ports return an error",
+			configured: []string{"70000"},
+			wantErr:    true,
+		},
+		{
+			name:       "ports used by the proxy return an error",
+			configured: []string{"8080:443"},
+			wantErr:    true,
+		},
+		{
+			name:       "ports used by the caddy API return an error",
+			configured: []string{"2019"},
+			wantErr:    true,
+		},
+		{
+			name:       "host ports used by the proxy return an error",
+			configured: []string{"3000:8025"},
+			wantErr:    true,
+		},
+		{
+			name:       "ports cannot be bound more than once",
+			configured: []string{"8025", "8025:8026"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != nil {
+				os.Setenv(ExtraPortsEnv, *tt.env)
+				defer os.Unsetenv(ExtraPortsEnv)
+			}
+
+			got, err := ExtraPorts(tt.configured)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExtraPorts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtraPorts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,139 @@
+package setup
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/portavail"
+)
+
+// Versions are the database engines and versions that can be provisioned
+// during the setup, the first version is the default for the engine.
+var Versions = map[string][]string{
+	"mariadb":  {"10.5", "10.4", "10.3", "10.2", "10.1", "10"},
+	"mysql":    {"8.0", "5.7", "5.6"},
+	"postgres": {"14", "13", "12", "11", "10", "9"},
+}
+
+// ParseDatabases takes a comma separated list of engine:version pairs (e.g.
+// mysql:8.0,postgres:13) and returns the databases without ports. If the
+// version is omitted, the default version for the engine is used.
+func ParseDatabases(input string) ([]config.Database, error) {
+	var databases []config.Database
+	seen := make(map[string]bool)
+	for _, s := range strings.Split(input, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+
+		sp := strings.SplitN(s, ":", 2)
+		engine := strings.ToLower(sp[0])
+
+		versions, ok := Versions[engine]
+		if !ok {
+			return nil, fmt.Errorf("unknown database engine %q, use mariadb, mysql, or postgres", sp[0])
+		}
+
+		version := versions[0]
+		if len(sp) == 2 {
+			version = sp[1]
+		}
+
+		if !contains(versions, version) {
+			return nil, fmt.Errorf("the %s version %q is not valid, use %s", engine, version, strings.Join(versions, ", "))
+		}
+
+		if seen[engine+version] {
+			return nil, fmt.Errorf("the database %s:%s was provided more than once", engine, version)
+		}
+		seen[engine+version] = true
+
+		databases = append(databases, config.Database{Engine: engine, Version: version})
+	}
+
+	if len(databases) == 0 {
+		return nil, fmt.Errorf("no databases were provided, use engine:version (e.g. mysql:8.0)")
+	}
+
+	return databases, nil
+}
+
+// AddDatabases takes a config and databases and adds any database engines that
+// are not already in the config. Each new database is assigned the next
+// available port for the engine. It returns the databases that were added.
+func AddDatabases(cfg *config.Config, databases []config.Database) ([]config.Database, error) {
+	// track the ports in the config, the containers may not be running yet
+	taken := make(map[string]bool)
+	for _, db := range cfg.Databases {
+		taken[db.Port] = true
+	}
+
+	var added []config.Database
+	for _, db := range databases {
+		if hasDatabase(cfg.Databases, db) {
+			continue
+		}
+
+		port, err := nextPort(defaultPort(db.Engine), taken)
+		if err != nil {
+			return nil, err
+		}
+
+		taken[port] = true
+		db.Port = port
+
+		cfg.Databases = append(cfg.Databases, db)
+		added = append(added, db)
+	}
+
+	return added, nil
+}
+
+func defaultPort(engine string) int {
+	if engine == "postgres" {
+		return postgresDefaultPort
+	}
+
+	return mysqlDefaultPort
+}
+
+// nextPort returns the first port, starting at the port, that is not taken
+// by the config and is available on the host.
+func nextPort(port int, taken map[string]bool) (string, error) {
+	for p := port; p <= 65535; p++ {
+		if taken[strconv.Itoa(p)] {
+			continue
+		}
+
+		if err := portavail.Check("", strconv.Itoa(p)); err != nil {
+			continue
+		}
+
+		return strconv.Itoa(p), nil
+	}
+
+	return "", fmt.Errorf("unable to find an available port starting at %d", port)
+}
+
+func hasDatabase(databases []config.Database, db config.Database) bool {
+	for _, d := range databases {
+		if d.Engine == db.Engine && d.Version == db.Version {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,101 @@
+package setup
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+func TestParseDatabases(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []config.Database
+		wantErr bool
+	}{
+		{
+			name:  "can parse multiple engines",
+			input: "mysql:8.0,postgres:13",
+			want: []config.Database{
+				{Engine: "mysql", Version: "8.0"},
+				{Engine: "postgres", Version: "13"},
+			},
+		},
+		{
+			name:  "uses the default version when omitted",
+			input: "mariadb, postgres",
+			want: []config.Database{
+				{Engine: "mariadb", Version: "10.5"},
+				{Engine: "postgres", Version: "14"},
+			},
+		},
+		{
+			name:  "engines are case insensitive",
+			input: "MySQL:5.7",
+			want: []config.Database{
+				{Engine: "mysql", Version: "5.7"},
+			},
+		},
+		{
+			name:    "unknown engines return an error",
+			input:   "mongodb:4",
+			wantErr: true,
+		},
+		{
+			name:    "unknown versions return an error",
+			input:   "mysql:9.9",
+			wantErr: true,
+		},
+		{
+			name:    "duplicate databases return an error",
+			input:   "mysql:8.0,mysql:8.0",
+			wantErr: true,
+		},
+		{
+			name:    "empty input returns an error",
+			input:   " , ",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDatabases(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseDatabases() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDatabases() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddDatabases(t *testing.T) {
+	cfg := &config.Config{
+		Databases: []config.Database{
+			{Engine: "mysql", Version: "8.0", Port: "3306"},
+		},
+	}
+
+	added, err := AddDatabases(cfg, []config.Database{
+		{Engine: "mysql", Version: "8.0"},
+		{Engine: "mysql", Version: "5.7"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(added) != 1 {
+		t.Fatalf("expected one database to be added, got %d", len(added))
+	}
+
+	if added[0].Port == "" || added[0].Port == "3306" {
+		t.Errorf("expected the added database to use a different port, got %q", added[0].Port)
+	}
+
+	if len(cfg.Databases) != 2 {
+		t.Errorf("expected the config to have 2 databases, got %d", len(cfg.Databases))
+	}
+}
@@ -20,12 +20,49 @@ var (
 // FirstTime is used when there is no configuration file found in a users
 // home/.nitro directory. We do not prompt for input such as memory, cpu,
 // disk space in version 2 as that is defined and managed at the docker
-// level. If anything fails, we return an error.
-func FirstTime(home string, reader io.Reader, output terminal.Outputer) error {
+// level. If databases are provided, the database prompts are skipped
+// and the databases are added with the next available ports. If anything
+// fails, we return an error.
+func FirstTime(home string, reader io.Reader, output terminal.Outputer, databases []config.Database) error {
 	c := config.Config{File: filepath.Join(home, config.DirectoryName, config.FileName)}
 
 	output.Info("Setting up Nitro…")
 
+	switch len(databases) {
+	case 0:
+		if err := promptDatabases(&c, output); err != nil {
+			return err
+		}
+	default:
+		if _, err := AddDatabases(&c, databases); err != nil {
+			return err
+		}
+	}
+
+	redis, err := output.Confirm("Would you like to use Redis?", true, "")
+	if err != nil {
+		return err
+	}
+
+	if redis {
+		output.Pending("adding redis service")
+
+		c.Services.Redis = true
+
+		output.Done()
+	}
+
+	// save the file
+	if err := c.Save(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// promptDatabases asks the user which database engines and versions to add
+// to the config.
+func promptDatabases(c *config.Config, output terminal.Outputer) error {
 	// if this is running on Apple Silicon, we need to prompt for mariadb instead until this issue is resolved: https://docs.docker.com/docker-for-mac/apple-m1/
 	switch runtime.GOARCH == "arm64" || runtime.GOARCH == "arm" {
 	case true:
@@ -42,7 +79,7 @@ func FirstTime(home string, reader io.Reader, output terminal.Outputer) error {
 
 		if mariadb {
 			// prompt for the version
-			opts := []string{"10.5", "10.4", "10.3", "10.2", "10.1", "10"}
+			opts := Versions["mariadb"]
 			selected, err := output.Select(os.Stdin, "Select MariaDB version: ", opts)
 			if err != nil {
 				return err
@@ -78,7 +115,7 @@ func FirstTime(home string, reader io.Reader, output terminal.Outputer) error {
 
 		if mysql {
 			// prompt for the version
-			opts := []string{"8.0", "5.7", "5.6"}
+			opts := Versions["mysql"]
 			selected, err := output.Select(os.Stdin, "Select MySQL version: ", opts)
 			if err != nil {
 				return err
@@ -115,7 +152,7 @@ func FirstTime(home string, reader io.Reader, output terminal.Outputer) error {
 
 	if postgres {
 		// prompt for the version
-		opts := []string{"13", "12", "11", "10", "9"}
+		opts := Versions["postgres"]
 		selected, err := output.Select(os.Stdin, "Select PostgreSQL version: ", opts)
 		if err != nil {
 			return err
@@ -144,23 +181,5 @@ func FirstTime(home string, reader io.Reader, output terminal.Outputer) error {
 		})
 	}
 
-	redis, err := output.Confirm("Would you like to use Redis?", true, "")
-	if err != nil {
-		return err
-	}
-
-	if redis {
-		output.Pending("adding redis service")
-
-		c.Services.Redis = true
-
-		output.Done()
-	}
-
-	// save the file
-	if err := c.Save(); err != nil {
-		return err
-	}
-
 	return nil
 }
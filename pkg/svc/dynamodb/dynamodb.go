@@ -1,13 +1,13 @@
 package dynamodb
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"os"
 	"time"
 
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/imagepull"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -47,17 +47,10 @@ func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID st
 	// if there is not a container, create one
 	if len(containers) == 0 {
 		// pull the image
-		r, err := cli.ImagePull(ctx, Image, types.ImagePullOptions{})
-		if err != nil {
+		if err := imagepull.Pull(ctx, cli, Image); err != nil {
 			return "", "", err
 		}
 
-		// read from the buffer to pull the image
-		buf := &bytes.Buffer{}
-		if _, err := buf.ReadFrom(r); err != nil {
-			return "", "", fmt.Errorf("unable to read output while pulling image, %w", err)
-		}
-
 		// set the nitro env overrides
 		httpPort := "8000"
 		if os.Getenv("NITRO_DYNAMODB_PORT") != "" {
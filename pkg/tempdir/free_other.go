@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package tempdir
+
+// free returns -1 because the free space is not checked on this platform.
+func free(dir string) (int64, error) {
+	return -1, nil
+}
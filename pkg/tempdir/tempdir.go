@@ -0,0 +1,64 @@
+package tempdir
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+var (
+	// ErrNotWritable is returned when the temp directory can't be written to
+	ErrNotWritable = fmt.Errorf("the temp directory is not writable")
+
+	// ErrNoSpace is returned when the temp directory does not have enough free space
+	ErrNoSpace = fmt.Errorf("there is not enough space in the temp directory")
+)
+
+// Dir returns the directory, or the default temp directory when dir is empty.
+func Dir(dir string) string {
+	if dir == "" {
+		return os.TempDir()
+	}
+
+	return dir
+}
+
+// Check takes a directory and the number of bytes that will be written and
+// returns an error if the directory does not exist, is not writable, or does
+// not have enough free space. A size of 0 skips checking the free space.
+func Check(dir string, size int64) error {
+	dir = Dir(dir)
+
+	stat, err := os.Stat(dir)
+	if err != nil || !stat.IsDir() {
+		return fmt.Errorf("the temp directory %s does not exist", dir)
+	}
+
+	// make sure a file can be created in the directory
+	f, err := ioutil.TempFile(dir, "nitro-check-")
+	if err != nil {
+		return fmt.Errorf("%w, %s", ErrNotWritable, dir)
+	}
+	f.Close()
+	os.Remove(f.Name())
+
+	if size <= 0 {
+		return nil
+	}
+
+	available, err := free(dir)
+	if err != nil {
+		return err
+	}
+
+	// the free space is unknown on this platform
+	if available < 0 {
+		return nil
+	}
+
+	if available < size {
+		return fmt.Errorf("%w, %s has %d MB available and %d MB is needed", ErrNoSpace, dir, available/1024/1024, size/1024/1024+1)
+	}
+
+	return nil
+}
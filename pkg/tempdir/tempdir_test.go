@@ -0,0 +1,54 @@
+package tempdir
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitro-tempdir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		dir     string
+		size    int64
+		wantErr error
+	}{
+		{
+			name: "existing directories do not return an error",
+			dir:  dir,
+			size: 1024,
+		},
+		{
+			name:    "missing directories return an error",
+			dir:     filepath.Join(dir, "missing"),
+			wantErr: errors.New("missing"),
+		},
+		{
+			name:    "sizes larger than the free space return an error",
+			dir:     dir,
+			size:    1 << 62,
+			wantErr: ErrNoSpace,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(tt.dir, tt.size)
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr == ErrNoSpace && !errors.Is(err, ErrNoSpace) {
+				t.Errorf("Check() error = %v, want %v", err, ErrNoSpace)
+			}
+		})
+	}
+}
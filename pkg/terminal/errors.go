@@ -0,0 +1,64 @@
+package terminal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/craftcms/nitro/pkg/errcode"
+)
+
+// ErrorFormatEnv is the environment variable used for the error format when the
+// --error-format flag is not set.
+const ErrorFormatEnv = "NITRO_ERROR_FORMAT"
+
+// ErrorFormat is set by the --error-format flag to write errors as text or json
+var ErrorFormat = "text"
+
+// ResolveErrorFormat returns the error format from the flag, or from the ErrorFormatEnv
+// environment variable when the flag is the default text. It returns text and an error
+// when the format is not text or json.
+func ResolveErrorFormat(flag string) (string, error) {
+	format := flag
+	if env := os.Getenv(ErrorFormatEnv); env != "" && format == "text" {
+		format = env
+	}
+
+	if format != "text" && format != "json" {
+		return "text", fmt.Errorf("error-format must be text or json, got %q", format)
+	}
+
+	return format, nil
+}
+
+// ErrorOutput is the json written for an error when the error format is json.
+type ErrorOutput struct {
+	Error     string `json:"error"`
+	ErrorCode string `json:"error_code"`
+	ExitCode  int    `json:"exit_code"`
+}
+
+// WriteError writes the error to the writer in the format, either text (e.g. Error: the
+// message) or json with the error code.
+func WriteError(w io.Writer, err error, format string) {
+	if format != "json" {
+		fmt.Fprintln(w, "Error:", err.Error())
+
+		return
+	}
+
+	code := errcode.CodeOf(err)
+
+	_ = json.NewEncoder(w).Encode(ErrorOutput{Error: err.Error(), ErrorCode: code.Name, ExitCode: code.Exit})
+}
+
+// Exit writes the error to stderr in the ErrorFormat, or the ErrorFormatEnv environment
+// variable, and exits with the exit code for the error. An invalid format writes text.
+func Exit(err error) {
+	format, _ := ResolveErrorFormat(ErrorFormat)
+
+	WriteError(os.Stderr, err, format)
+
+	os.Exit(errcode.ExitCode(err))
+}
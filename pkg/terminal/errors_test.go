@@ -0,0 +1,98 @@
+package terminal
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/errcode"
+)
+
+func TestWriteError(t *testing.T) {
+	err := errcode.WithCode(errcode.ErrNoProxy, errors.New("unable to locate the proxy container"))
+
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{
+			name:   "text errors match the cobra format",
+			format: "text",
+			want:   "Error: unable to locate the proxy container\n",
+		},
+		{
+			name:   "json errors include the error code",
+			format: "json",
+			want:   `{"error":"unable to locate the proxy container","error_code":"no_proxy","exit_code":11}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			WriteError(&buf, err, tt.format)
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("WriteError() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveErrorFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    string
+		env     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "the default is text",
+			flag: "text",
+			want: "text",
+		},
+		{
+			name: "the environment is used without the flag",
+			flag: "text",
+			env:  "json",
+			want: "json",
+		},
+		{
+			name: "the flag overrides the environment",
+			flag: "json",
+			env:  "text",
+			want: "json",
+		},
+		{
+			name:    "invalid environment formats return an error",
+			flag:    "text",
+			env:     "yaml",
+			want:    "text",
+			wantErr: true,
+		},
+		{
+			name:    "invalid flags return an error",
+			flag:    "xml",
+			want:    "text",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := os.Getenv(ErrorFormatEnv)
+			os.Setenv(ErrorFormatEnv, tt.env)
+			defer os.Setenv(ErrorFormatEnv, env)
+
+			got, err := ResolveErrorFormat(tt.flag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveErrorFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ResolveErrorFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
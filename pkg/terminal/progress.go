@@ -0,0 +1,206 @@
+package terminal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	sshterminal "golang.org/x/crypto/ssh/terminal"
+)
+
+// Progress reports the progress of a transfer, such as an upload or download, so
+// the progress is shown the same way for all of the commands.
+type Progress interface {
+	// Start is called before the transfer with the total bytes, which is -1 if
+	// the total is unknown
+	Start(total int64)
+	// Add is called with the number of bytes transferred since the last call
+	Add(n int64)
+	// Finish is called once the transfer is complete or has failed
+	Finish()
+}
+
+// NewProgress returns a progress bar when the writer is a terminal, otherwise the
+// progress is not shown.
+func NewProgress(w io.Writer, label string) Progress {
+	if f, ok := w.(*os.File); ok && sshterminal.IsTerminal(int(f.Fd())) {
+		return NewProgressBar(w, label)
+	}
+
+	return NoProgress{}
+}
+
+// NoProgress is a Progress that does not report anything.
+type NoProgress struct{}
+
+func (NoProgress) Start(total int64) {}
+func (NoProgress) Add(n int64)       {}
+func (NoProgress) Finish()           {}
+
+// ProgressWriter returns a writer that writes to w and adds the bytes written to
+// the progress.
+func ProgressWriter(w io.Writer, p Progress) io.Writer {
+	return &progressWriter{w: w, p: p}
+}
+
+type progressWriter struct {
+	w io.Writer
+	p Progress
+}
+
+func (pw *progressWriter) Write(b []byte) (int, error) {
+	n, err := pw.w.Write(b)
+	pw.p.Add(int64(n))
+
+	return n, err
+}
+
+// barWidth is the number of characters used for the bar
+const barWidth = 30
+
+// progressBar redraws a single line with a bar, the percentage, and the megabytes
+// transferred. When the total is unknown, only the megabytes are shown.
+type progressBar struct {
+	w     io.Writer
+	label string
+
+	mu       sync.Mutex
+	total    int64
+	written  int64
+	reported int64
+}
+
+// NewProgressBar returns a Progress that draws a bar to the writer, it is only
+// redrawn every megabyte or percent to keep the output fast.
+func NewProgressBar(w io.Writer, label string) Progress {
+	return &progressBar{w: w, label: label}
+}
+
+func (b *progressBar) Start(total int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.total = total
+	b.written = 0
+	b.reported = 0
+
+	b.draw()
+}
+
+func (b *progressBar) Add(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.written += n
+
+	step := int64(1024 * 1024)
+	if b.total > 0 && b.total/100 < step {
+		step = b.total / 100
+	}
+
+	if b.written-b.reported < step && b.written != b.total {
+		return
+	}
+
+	b.draw()
+}
+
+func (b *progressBar) Finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.draw()
+
+	fmt.Fprintln(b.w)
+}
+
+func (b *progressBar) draw() {
+	b.reported = b.written
+
+	mb := float64(b.written) / 1024 / 1024
+	if b.total <= 0 {
+		fmt.Fprintf(b.w, "\r  … %s %.1f MB", b.label, mb)
+
+		return
+	}
+
+	percent := float64(b.written) / float64(b.total)
+	if percent > 1 {
+		percent = 1
+	}
+
+	filled := int(percent * barWidth)
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", barWidth-filled)
+
+	fmt.Fprintf(b.w, "\r  … %s [%s] %3.0f%% %.1f of %.1f MB", b.label, bar, percent*100, mb, float64(b.total)/1024/1024)
+}
+
+// lineProgress writes a line for every ten percent, or 100 megabytes when the total is
+// unknown, so the progress is shown in logs and other output that is not a terminal.
+type lineProgress struct {
+	w     io.Writer
+	label string
+
+	mu       sync.Mutex
+	total    int64
+	written  int64
+	reported int64
+}
+
+// NewProgressLines returns a Progress that writes a line to the writer every ten percent
+// of the transfer, or every 100 megabytes when the total is unknown, and when the transfer
+// finishes.
+func NewProgressLines(w io.Writer, label string) Progress {
+	return &lineProgress{w: w, label: label}
+}
+
+func (l *lineProgress) Start(total int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.total = total
+	l.written = 0
+	l.reported = 0
+}
+
+func (l *lineProgress) Add(n int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.written += n
+
+	step := int64(100 * 1024 * 1024)
+	if l.total > 0 {
+		step = l.total / 10
+	}
+
+	if l.written-l.reported < step || l.written == l.total {
+		return
+	}
+
+	l.write()
+}
+
+func (l *lineProgress) Finish() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.written != l.reported || l.written == 0 {
+		l.write()
+	}
+}
+
+func (l *lineProgress) write() {
+	l.reported = l.written
+
+	mb := float64(l.written) / 1024 / 1024
+	if l.total <= 0 {
+		fmt.Fprintf(l.w, "  … %s %.1f MB\n", l.label, mb)
+
+		return
+	}
+
+	fmt.Fprintf(l.w, "  … %s %3.0f%% %.1f of %.1f MB\n", l.label, float64(l.written)/float64(l.total)*100, mb, float64(l.total)/1024/1024)
+}
@@ -0,0 +1,91 @@
+package terminal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProgressBar(t *testing.T) {
+	tests := []struct {
+		name  string
+		total int64
+		want  string
+	}{
+		{
+			name:  "known totals show the bar and percentage",
+			total: 2 * 1024 * 1024,
+			want:  "\r  … uploading [==============================] 100% 2.0 of 2.0 MB\n",
+		},
+		{
+			name:  "unknown totals only show the megabytes",
+			total: -1,
+			want:  "\r  … uploading 2.0 MB\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			p := NewProgressBar(&buf, "uploading")
+
+			p.Start(tt.total)
+			if _, err := ProgressWriter(&bytes.Buffer{}, p).Write(make([]byte, 2*1024*1024)); err != nil {
+				t.Fatal(err)
+			}
+			p.Finish()
+
+			lines := strings.SplitAfter(buf.String(), "\r")
+			if got := "\r" + lines[len(lines)-1]; got != tt.want {
+				t.Errorf("expected the last draw to be %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestProgressLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		total int64
+		want  []string
+	}{
+		{
+			name:  "known totals write a line every ten percent",
+			total: 10 * 1024 * 1024,
+			want: []string{
+				"  … uploading  20% 2.0 of 10.0 MB",
+				"  … uploading  40% 4.0 of 10.0 MB",
+				"  … uploading  60% 6.0 of 10.0 MB",
+				"  … uploading  80% 8.0 of 10.0 MB",
+				"  … uploading 100% 10.0 of 10.0 MB",
+			},
+		},
+		{
+			name:  "unknown totals write a line when finished",
+			total: -1,
+			want:  []string{"  … uploading 10.0 MB"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			p := NewProgressLines(&buf, "uploading")
+
+			p.Start(tt.total)
+			for i := 0; i < 5; i++ {
+				p.Add(2 * 1024 * 1024)
+			}
+			p.Finish()
+
+			got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("expected the lines\n%s\ngot\n%s", strings.Join(tt.want, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+}
+
+func TestNewProgressWithoutTerminal(t *testing.T) {
+	if _, ok := NewProgress(&bytes.Buffer{}, "uploading").(NoProgress); !ok {
+		t.Error("expected no progress when the writer is not a terminal")
+	}
+}
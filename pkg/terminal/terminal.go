@@ -2,17 +2,35 @@ package terminal
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"strconv"
 	"strings"
+
+	sshterminal "golang.org/x/crypto/ssh/terminal"
+)
+
+var (
+	// MaxAttempts is the number of times a prompt is shown when the
+	// input fails validation before returning ErrTooManyAttempts
+	MaxAttempts = 5
+
+	// ErrTooManyAttempts is returned when the input for a prompt fails
+	// validation too many times
+	ErrTooManyAttempts = errors.New("too many invalid attempts")
+
+	// Verbose is set by the --verbose flag to write debug messages, such as
+	// the calls to the docker API
+	Verbose = false
 )
 
 // Outputer is an interface that captures the output to a terminal.
 // It is used to make our output consistent in the command line.
 type Outputer interface {
 	Ask(message, fallback, sep string, validator Validator) (string, error)
+	AskSecret(message, sep string, validator Validator) (string, error)
 	Confirm(message string, fallback bool, sep string) (bool, error)
 	Info(s ...string)
 	Success(s ...string)
@@ -22,6 +40,12 @@ type Outputer interface {
 	Done()
 }
 
+// Debugger is implemented by outputs that write debug messages, which are only
+// written when Verbose is set.
+type Debugger interface {
+	Debug(s ...string)
+}
+
 type Asker interface {
 	Ask(message, fallback, sep string, validator Validator) (string, error)
 }
@@ -32,23 +56,36 @@ type Validator interface {
 	Validate(input string) error
 }
 
-type terminal struct{}
+// terminal writes all of the status output (prompts, progress, and info) to
+// the writer, which defaults to stderr, so the results of a command can be
+// written to stdout and piped to other commands.
+type terminal struct {
+	w  io.Writer
+	in io.Reader
+}
 
-// New returns an Outputer interface
+// New returns an Outputer interface that writes to stderr
 func New() *terminal {
-	return &terminal{}
+	return NewWithWriter(os.Stderr)
+}
+
+// NewWithWriter returns an Outputer interface that writes to the
+// provided writer.
+func NewWithWriter(w io.Writer) *terminal {
+	return &terminal{w: w, in: os.Stdin}
 }
 
 func (t *terminal) Ask(message, fallback, sep string, validator Validator) (string, error) {
 	t.printStrMessage(message, fallback, sep)
 
 	// create a new scanner
-	s := bufio.NewScanner(os.Stdin)
+	s := bufio.NewScanner(t.in)
 
 	// split on lines so we can listen for carriage returns
 	s.Split(bufio.ScanLines)
 
 	var a string
+	var attempts int
 	for s.Scan() {
 		txt := s.Text()
 
@@ -64,6 +101,12 @@ func (t *terminal) Ask(message, fallback, sep string, validator Validator) (stri
 				// there is an error, so display the error and show the message again
 				t.printValidatorError(err)
 
+				// don't prompt forever
+				attempts++
+				if attempts >= MaxAttempts {
+					return "", fmt.Errorf("%w, %s", ErrTooManyAttempts, err)
+				}
+
 				t.printStrMessage(message, fallback, sep)
 
 				continue
@@ -92,6 +135,47 @@ func (t *terminal) Ask(message, fallback, sep string, validator Validator) (stri
 	return a, nil
 }
 
+// AskSecret prompts for sensitive values, such as passwords or tokens, without
+// showing the input when used in a terminal. The input is validated the same
+// as Ask and is returned as an error after too many attempts.
+func (t *terminal) AskSecret(message, sep string, validator Validator) (string, error) {
+	// only disable the echo when the input is a terminal
+	f, ok := t.in.(*os.File)
+	if !ok || !sshterminal.IsTerminal(int(f.Fd())) {
+		return t.Ask(message, "", sep, validator)
+	}
+
+	for attempts := 1; ; attempts++ {
+		t.printStrMessage(message, "", sep)
+
+		b, err := sshterminal.ReadPassword(int(f.Fd()))
+
+		// the newline is not echoed
+		fmt.Fprintln(t.w)
+
+		if err != nil {
+			return "", fmt.Errorf("unable to handle input, %w", err)
+		}
+
+		txt := string(b)
+		if validator != nil {
+			if err := validator.Validate(txt); err != nil {
+				t.printValidatorError(err)
+
+				if attempts >= MaxAttempts {
+					return "", fmt.Errorf("%w, %s", ErrTooManyAttempts, err)
+				}
+
+				continue
+			}
+		}
+
+		if txt != "" {
+			return txt, nil
+		}
+	}
+}
+
 func (t *terminal) Confirm(message string, fallback bool, sep string) (bool, error) {
 	t.printBoolMessage(message, fallback, sep)
 
@@ -109,8 +193,6 @@ func (t *terminal) Confirm(message string, fallback bool, sep string) (bool, err
 		default:
 			return fallback, nil
 		}
-
-		break
 	}
 	if err := s.Err(); err != nil {
 		return fallback, err
@@ -121,44 +203,60 @@ func (t *terminal) Confirm(message string, fallback bool, sep string) (bool, err
 
 func (t *terminal) printBoolMessage(message string, fallback bool, sep string) {
 	if fallback {
-		fmt.Printf("%s [Y/n]%s ", message, sep)
+		fmt.Fprintf(t.w, "%s [Y/n]%s ", message, sep)
 		return
 	}
 
-	fmt.Printf("%s [y/N]%s ", message, sep)
+	fmt.Fprintf(t.w, "%s [y/N]%s ", message, sep)
 }
 
 func (t *terminal) printStrMessage(message, fallback, sep string) {
 	if fallback == "" {
-		fmt.Fprintf(os.Stdout, "%s%s ", message, sep)
+		fmt.Fprintf(t.w, "%s%s ", message, sep)
 		return
 	}
 
-	fmt.Fprintf(os.Stdout, "%s [%s]%s ", message, fallback, sep)
+	fmt.Fprintf(t.w, "%s [%s]%s ", message, fallback, sep)
 }
 
 func (t *terminal) printValidatorError(err error) {
-	fmt.Fprintf(os.Stdin, " \u2717 %s\n", err.Error())
+	fmt.Fprintf(t.w, " \u2717 %s\n", err.Error())
 }
 
 func (t terminal) Info(s ...string) {
-	fmt.Printf("%s\n", strings.Join(s, " "))
+	fmt.Fprintf(t.w, "%s\n", strings.Join(s, " "))
+}
+
+// Debug writes the message when Verbose is set.
+func (t terminal) Debug(s ...string) {
+	if !Verbose {
+		return
+	}
+
+	fmt.Fprintf(t.w, "  debug: %s\n", strings.Join(s, " "))
+}
+
+// Debug writes the message with the output when it writes debug messages.
+func Debug(output Outputer, s ...string) {
+	if d, ok := output.(Debugger); ok {
+		d.Debug(s...)
+	}
 }
 
 func (t terminal) Success(s ...string) {
-	fmt.Printf("  \u2713 %s\n", strings.Join(s, " "))
+	fmt.Fprintf(t.w, "  \u2713 %s\n", strings.Join(s, " "))
 }
 
 func (t terminal) Pending(s ...string) {
-	fmt.Printf("  … %s ", strings.Join(s, " "))
+	fmt.Fprintf(t.w, "  … %s ", strings.Join(s, " "))
 }
 
 func (t terminal) Done() {
-	fmt.Print("\u2713\n")
+	fmt.Fprint(t.w, "\u2713\n")
 }
 
 func (t terminal) Warning() {
-	fmt.Print("\u2717\n")
+	fmt.Fprint(t.w, "\u2717\n")
 }
 
 func (t terminal) Select(r io.Reader, msg string, opts []string) (int, error) {
@@ -168,14 +266,14 @@ func (t terminal) Select(r io.Reader, msg string, opts []string) (int, error) {
 	}
 
 	// show the message
-	fmt.Println(msg)
+	fmt.Fprintln(t.w, msg)
 
 	// show all the options
 	for k, v := range opts {
-		fmt.Printf("  %d. %s\n", k+1, v)
+		fmt.Fprintf(t.w, "  %d. %s\n", k+1, v)
 	}
 
-	fmt.Print("Enter your selection: ")
+	fmt.Fprint(t.w, "Enter your selection: ")
 
 	// create for loop until the input is valid
 	var selection int
@@ -194,13 +292,13 @@ func (t terminal) Select(r io.Reader, msg string, opts []string) (int, error) {
 		s, err := strconv.Atoi(char)
 		if err != nil || len(opts) < s {
 			wait = true
-			fmt.Println("Please choose a valid option:")
+			fmt.Fprintln(t.w, "Please choose a valid option:")
 
 			for k, v := range opts {
-				fmt.Printf("  %d. %s\n", k+1, v)
+				fmt.Fprintf(t.w, "  %d. %s\n", k+1, v)
 			}
 
-			fmt.Print(msg)
+			fmt.Fprint(t.w, msg)
 		} else {
 			// take away one from the selection
 			selection = s - 1
@@ -0,0 +1,81 @@
+package terminal
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewWithWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	term := NewWithWriter(buf)
+
+	term.Info("info", "message")
+	term.Pending("pending")
+	term.Done()
+	term.Success("success")
+	term.Pending("failing")
+	term.Warning()
+
+	want := "info message\n  … pending ✓\n  ✓ success\n  … failing ✗\n"
+	if buf.String() != want {
+		t.Errorf("expected the output to be %q, got %q", want, buf.String())
+	}
+}
+
+func TestSelectWritesOptionsToWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	term := NewWithWriter(buf)
+
+	selected, err := term.Select(strings.NewReader("2\n"), "Select a site:", []string{"one", "two"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if selected != 1 {
+		t.Errorf("expected the selection to be 1, got %d", selected)
+	}
+
+	if !strings.Contains(buf.String(), "  2. two\n") {
+		t.Errorf("expected the options to be written, got %q", buf.String())
+	}
+}
+
+func TestAskStopsAfterTooManyAttempts(t *testing.T) {
+	buf := &bytes.Buffer{}
+	term := NewWithWriter(buf)
+	term.in = strings.NewReader(strings.Repeat("invalid\n", MaxAttempts+1))
+
+	_, err := term.Ask("Enter a value", "", ":", validatorFunc(func(input string) error {
+		return errors.New("not valid")
+	}))
+	if !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("expected the error to be ErrTooManyAttempts, got %v", err)
+	}
+
+	if got := strings.Count(buf.String(), "not valid"); got != MaxAttempts {
+		t.Errorf("expected the validation error to be shown %d times, got %d", MaxAttempts, got)
+	}
+}
+
+func TestAskSecretWithoutTerminal(t *testing.T) {
+	buf := &bytes.Buffer{}
+	term := NewWithWriter(buf)
+	term.in = strings.NewReader("token\n")
+
+	got, err := term.AskSecret("Enter the token", ":", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != "token" {
+		t.Errorf("expected the input to be %q, got %q", "token", got)
+	}
+}
+
+type validatorFunc func(input string) error
+
+func (f validatorFunc) Validate(input string) error {
+	return f(input)
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
 )
 
 type Validator interface {
@@ -33,10 +36,52 @@ func (v *DatabaseName) Validate(input string) error {
 	return nil
 }
 
+// DatabaseUser validates the name of a database user, which is limited to letters, numbers,
+// and underscores so it can be used in the statements that create the user
+type DatabaseUser struct{}
+
+func (v *DatabaseUser) Validate(input string) error {
+	if input == "" {
+		return fmt.Errorf("database user must not be empty")
+	}
+
+	if len(input) > 32 {
+		return fmt.Errorf("database user %q must be 32 characters or less", input)
+	}
+
+	for i, r := range input {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return fmt.Errorf("database user %q must start with a letter and only include letters, numbers, and underscores", input)
+		}
+	}
+
+	return nil
+}
+
+// Password validates the password for a new database user is not empty, so prompts ask
+// again instead of creating the user without a password
+type Password struct{}
+
+func (v *Password) Validate(input string) error {
+	if strings.TrimSpace(input) == "" {
+		return fmt.Errorf("password must not be empty")
+	}
+
+	return nil
+}
+
 // HostnameValidator is used to validate a provided hostname
 type HostnameValidator struct{}
 
 func (v *HostnameValidator) Validate(input string) error {
+	// internationalized hostnames must convert to punycode
+	if _, err := Punycode(input); err != nil {
+		return err
+	}
+
 	// check length
 	if len(input) < 3 {
 		return fmt.Errorf("hostname must be more than 3 characters")
@@ -66,6 +111,22 @@ func (v *IntegerValidator) Validate(input string) error {
 	return nil
 }
 
+// PortValidator validates if the input is a valid tcp port (1-65535)
+type PortValidator struct{}
+
+func (v *PortValidator) Validate(input string) error {
+	port, err := strconv.Atoi(input)
+	if err != nil {
+		return fmt.Errorf("the port %q is not a number", input)
+	}
+
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("the port %d must be between 1 and 65535", port)
+	}
+
+	return nil
+}
+
 // MultipleHostnameValidator validates a comma separated list of hostnames
 type MultipleHostnameValidator struct{}
 
@@ -84,11 +145,173 @@ func (v *MultipleHostnameValidator) Parse(input string) ([]string, error) {
 		if err := hostV.Validate(h); err != nil {
 			return nil, err
 		}
+
+		// store internationalized hostnames as punycode
+		h, err := Punycode(h)
+		if err != nil {
+			return nil, err
+		}
+
 		hosts = append(hosts, h)
 	}
 	return hosts, nil
 }
 
+// Punycode returns the ASCII form of a hostname. Internationalized hostnames
+// (e.g. bücher.nitro) are converted to punycode (e.g. xn--bcher-kva.nitro)
+// and ASCII hostnames are returned as is.
+func Punycode(hostname string) (string, error) {
+	ascii := true
+	for _, r := range hostname {
+		if r > unicode.MaxASCII {
+			ascii = false
+			break
+		}
+	}
+
+	if ascii {
+		return hostname, nil
+	}
+
+	converted, err := idna.Lookup.ToASCII(hostname)
+	if err != nil {
+		return "", fmt.Errorf("hostname %q is not a valid internationalized hostname", hostname)
+	}
+
+	return converted, nil
+}
+
+// Unicode returns the display form of a punycode hostname (e.g. xn--bcher-kva.nitro
+// is displayed as bücher.nitro). If the hostname cannot be converted, it is
+// returned as is.
+func Unicode(hostname string) string {
+	converted, err := idna.Display.ToUnicode(hostname)
+	if err != nil {
+		return hostname
+	}
+
+	return converted
+}
+
+// PathPrefix validates a path prefix used to match requests for a site (e.g. /api)
+type PathPrefix struct{}
+
+func (v *PathPrefix) Validate(input string) error {
+	if !strings.HasPrefix(input, "/") {
+		return fmt.Errorf("path prefix %q must start with a /", input)
+	}
+
+	if input == "/" {
+		return fmt.Errorf("path prefix must not be the root path")
+	}
+
+	// check for spaces
+	if strings.ContainsAny(input, " \t") {
+		return fmt.Errorf("path prefix %q must not include spaces", input)
+	}
+
+	// check for matcher characters
+	if strings.ContainsAny(input, "*?#{}[]") {
+		return fmt.Errorf("path prefix %q must not include wildcards or special characters", input)
+	}
+
+	if strings.Contains(input, "//") {
+		return fmt.Errorf("path prefix %q must not include empty path segments", input)
+	}
+
+	return nil
+}
+
+// HeaderName validates the name of a HTTP header (e.g. Cache-Control)
+type HeaderName struct{}
+
+func (v *HeaderName) Validate(input string) error {
+	if input == "" {
+		return fmt.Errorf("header name must not be empty")
+	}
+
+	// header names are tokens, https://tools.ietf.org/html/rfc7230#section-3.2.6
+	for _, r := range input {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", r):
+		default:
+			return fmt.Errorf("header name %q must not include %q", input, r)
+		}
+	}
+
+	return nil
+}
+
+// MountTarget validates the path in a sites container used as the target of a mount
+type MountTarget struct{}
+
+func (v *MountTarget) Validate(input string) error {
+	if !strings.HasPrefix(input, "/") {
+		return fmt.Errorf("mount target %q must be an absolute path", input)
+	}
+
+	// the root and site paths are already in use
+	switch strings.TrimRight(input, "/") {
+	case "", "/app":
+		return fmt.Errorf("mount target %q is reserved", input)
+	}
+
+	// check for spaces
+	if strings.ContainsAny(input, " \t") {
+		return fmt.Errorf("mount target %q must not include spaces", input)
+	}
+
+	if strings.Contains(input, ":") {
+		return fmt.Errorf("mount target %q must not include a colon", input)
+	}
+
+	return nil
+}
+
+// DatabaseEngine validates the database engine is one of the engines Nitro supports
+type DatabaseEngine struct{}
+
+func (v *DatabaseEngine) Validate(input string) error {
+	switch input {
+	case "mariadb", "mysql", "postgres":
+		return nil
+	}
+
+	return fmt.Errorf("unknown database engine %q, use mariadb, mysql, or postgres", input)
+}
+
+// XdebugMode validates a comma separated list of Xdebug 3 modes (e.g. debug,profile)
+type XdebugMode struct{}
+
+func (v *XdebugMode) Validate(input string) error {
+	if input == "" {
+		return fmt.Errorf("xdebug mode must not be empty")
+	}
+
+	for _, m := range strings.Split(input, ",") {
+		switch m {
+		case "develop", "coverage", "debug", "gcstats", "profile", "trace":
+		default:
+			return fmt.Errorf("unknown xdebug mode %q, use develop, coverage, debug, gcstats, profile, or trace", m)
+		}
+	}
+
+	return nil
+}
+
+// RestartPolicy validates a docker restart policy, an empty policy uses the docker default no
+type RestartPolicy struct{}
+
+func (v *RestartPolicy) Validate(input string) error {
+	switch input {
+	case "", "no", "always", "unless-stopped", "on-failure":
+		return nil
+	default:
+		return fmt.Errorf("unknown restart policy %q, use no, always, unless-stopped, or on-failure", input)
+	}
+}
+
 type PHPVersionValidator struct{}
 
 func (v *PHPVersionValidator) Validate(input string) error {
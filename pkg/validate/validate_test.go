@@ -78,6 +78,20 @@ func TestHostnameValidator_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "internationalized hostnames do not return an err",
+			args: args{
+				input: "bücher.nitro",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid internationalized labels return an err",
+			args: args{
+				input: "-bücher.nitro",
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -89,6 +103,377 @@ func TestHostnameValidator_Validate(t *testing.T) {
 	}
 }
 
+func TestPunycode(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "ascii hostnames are not changed",
+			input: "tutorial.nitro",
+			want:  "tutorial.nitro",
+		},
+		{
+			name:  "internationalized hostnames are converted",
+			input: "bücher.nitro",
+			want:  "xn--bcher-kva.nitro",
+		},
+		{
+			name:    "invalid labels return an err",
+			input:   "-bücher.nitro",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Punycode(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Punycode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Punycode() = %v, want %v", got, tt.want)
+			}
+			if !tt.wantErr && Unicode(got) != tt.input {
+				t.Errorf("Unicode() = %v, want %v", Unicode(got), tt.input)
+			}
+		})
+	}
+}
+
+func TestDatabaseEngine_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:    "known engines do not return an err",
+			input:   "postgres",
+			wantErr: false,
+		},
+		{
+			name:    "unknown engines return an err",
+			input:   "mongodb",
+			wantErr: true,
+		},
+		{
+			name:    "engines are case sensitive",
+			input:   "MySQL",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &DatabaseEngine{}
+			if err := v.Validate(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("DatabaseEngine.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestXdebugMode_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:    "single modes do not return an err",
+			input:   "profile",
+			wantErr: false,
+		},
+		{
+			name:    "combined modes do not return an err",
+			input:   "debug,develop,coverage",
+			wantErr: false,
+		},
+		{
+			name:    "unknown modes return an err",
+			input:   "debug,remote",
+			wantErr: true,
+		},
+		{
+			name:    "empty modes return an err",
+			input:   "debug,",
+			wantErr: true,
+		},
+		{
+			name:    "off returns an err",
+			input:   "off",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &XdebugMode{}
+			if err := v.Validate(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("XdebugMode.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHeaderName_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:    "valid names do not return an err",
+			input:   "Access-Control-Allow-Origin",
+			wantErr: false,
+		},
+		{
+			name:    "custom names do not return an err",
+			input:   "X-Debug_Token",
+			wantErr: false,
+		},
+		{
+			name:    "empty names return an err",
+			input:   "",
+			wantErr: true,
+		},
+		{
+			name:    "spaces return an err",
+			input:   "Cache Control",
+			wantErr: true,
+		},
+		{
+			name:    "colons return an err",
+			input:   "Cache-Control:",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &HeaderName{}
+			if err := v.Validate(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDatabaseUser_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:    "valid users do not return an err",
+			input:   "craft_app2",
+			wantErr: false,
+		},
+		{
+			name:    "empty users return an err",
+			input:   "",
+			wantErr: true,
+		},
+		{
+			name:    "users starting with a number return an err",
+			input:   "2craft",
+			wantErr: true,
+		},
+		{
+			name:    "quotes return an err",
+			input:   "craft'@'%",
+			wantErr: true,
+		},
+		{
+			name:    "long users return an err",
+			input:   "abcdefghijklmnopqrstuvwxyzabcdefg",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &DatabaseUser{}
+			if err := v.Validate(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPassword_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:    "passwords do not return an err",
+			input:   "s3cr3t",
+			wantErr: false,
+		},
+		{
+			name:    "empty passwords return an err",
+			input:   "",
+			wantErr: true,
+		},
+		{
+			name:    "whitespace passwords return an err",
+			input:   "  ",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &Password{}
+			if err := v.Validate(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRestartPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:    "empty policies use the default",
+			input:   "",
+			wantErr: false,
+		},
+		{
+			name:    "docker policies do not return an err",
+			input:   "unless-stopped",
+			wantErr: false,
+		},
+		{
+			name:    "unknown policies return an err",
+			input:   "sometimes",
+			wantErr: true,
+		},
+		{
+			name:    "retry counts return an err",
+			input:   "on-failure:3",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &RestartPolicy{}
+			if err := v.Validate(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMountTarget_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:    "valid targets do not return an err",
+			input:   "/shared",
+			wantErr: false,
+		},
+		{
+			name:    "targets inside the site do not return an err",
+			input:   "/app/vendor/shared/",
+			wantErr: false,
+		},
+		{
+			name:    "relative paths return an err",
+			input:   "shared",
+			wantErr: true,
+		},
+		{
+			name:    "root path returns an err",
+			input:   "/",
+			wantErr: true,
+		},
+		{
+			name:    "site path returns an err",
+			input:   "/app/",
+			wantErr: true,
+		},
+		{
+			name:    "spaces return an err",
+			input:   "/my shared",
+			wantErr: true,
+		},
+		{
+			name:    "colons return an err",
+			input:   "/shared:ro",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &MountTarget{}
+			if err := v.Validate(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("MountTarget.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPathPrefix_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:    "valid prefixes do not return an err",
+			input:   "/api",
+			wantErr: false,
+		},
+		{
+			name:    "nested prefixes do not return an err",
+			input:   "/admin/panel/",
+			wantErr: false,
+		},
+		{
+			name:    "missing leading slash returns an err",
+			input:   "api",
+			wantErr: true,
+		},
+		{
+			name:    "root path returns an err",
+			input:   "/",
+			wantErr: true,
+		},
+		{
+			name:    "wildcards return an err",
+			input:   "/api/*",
+			wantErr: true,
+		},
+		{
+			name:    "spaces return an err",
+			input:   "/my api",
+			wantErr: true,
+		},
+		{
+			name:    "empty segments return an err",
+			input:   "/api//v1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &PathPrefix{}
+			if err := v.Validate(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("PathPrefix.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestPHPVersionValidator_Validate(t *testing.T) {
 	type args struct {
 		input string
@@ -180,3 +565,39 @@ func TestIsBoolean_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestPortValidator_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:  "valid ports do not return an err",
+			input: "8025",
+		},
+		{
+			name:    "ports must be numbers",
+			input:   "http",
+			wantErr: true,
+		},
+		{
+			name:    "zero is not a valid port",
+			input:   "0",
+			wantErr: true,
+		},
+		{
+			name:    "ports above 65535 are not valid",
+			input:   "65536",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &PortValidator{}
+			if err := v.Validate(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("PortValidator.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -205,6 +205,18 @@ type ApplyRequest struct {
 	unknownFields protoimpl.UnknownFields
 
 	Sites map[string]*Site `protobuf:"bytes,1,rep,name=sites,proto3" json:"sites,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// accessLogs will write the access logs for each site to the proxy data volume
+	AccessLogs bool `protobuf:"varint,2,opt,name=accessLogs,proto3" json:"accessLogs,omitempty"`
+	// onDemandTLS issues certificates when a site or subdomain of a site is first requested
+	OnDemandTLS bool `protobuf:"varint,3,opt,name=onDemandTLS,proto3" json:"onDemandTLS,omitempty"`
+	// http3 adds HTTP/3 (QUIC) to the protocols of the HTTPS server
+	Http3 bool `protobuf:"varint,4,opt,name=http3,proto3" json:"http3,omitempty"`
+	// notFoundBody is the HTML shown for hosts that do not match a site, the default page lists the sites
+	NotFoundBody string `protobuf:"bytes,5,opt,name=notFoundBody,proto3" json:"notFoundBody,omitempty"`
+	// returnConfig includes the config sent to Caddy in the response
+	ReturnConfig bool `protobuf:"varint,6,opt,name=returnConfig,proto3" json:"returnConfig,omitempty"`
+	// proxyPorts are the additional ports of the proxy container, each port proxies to the same port on the sites
+	ProxyPorts []int32 `protobuf:"varint,7,rep,packed,name=proxyPorts,proto3" json:"proxyPorts,omitempty"`
 }
 
 func (x *ApplyRequest) Reset() {
@@ -246,6 +258,48 @@ func (x *ApplyRequest) GetSites() map[string]*Site {
 	return nil
 }
 
+func (x *ApplyRequest) GetAccessLogs() bool {
+	if x != nil {
+		return x.AccessLogs
+	}
+	return false
+}
+
+func (x *ApplyRequest) GetOnDemandTLS() bool {
+	if x != nil {
+		return x.OnDemandTLS
+	}
+	return false
+}
+
+func (x *ApplyRequest) GetHttp3() bool {
+	if x != nil {
+		return x.Http3
+	}
+	return false
+}
+
+func (x *ApplyRequest) GetNotFoundBody() string {
+	if x != nil {
+		return x.NotFoundBody
+	}
+	return ""
+}
+
+func (x *ApplyRequest) GetReturnConfig() bool {
+	if x != nil {
+		return x.ReturnConfig
+	}
+	return false
+}
+
+func (x *ApplyRequest) GetProxyPorts() []int32 {
+	if x != nil {
+		return x.ProxyPorts
+	}
+	return nil
+}
+
 type ApplyResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -253,6 +307,11 @@ type ApplyResponse struct {
 
 	Error   bool   `protobuf:"varint,1,opt,name=error,proto3" json:"error,omitempty"`
 	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// sites is the route status for each site in the request
+	Sites []*SiteStatus `protobuf:"bytes,3,rep,name=sites,proto3" json:"sites,omitempty"`
+	// config is the JSON sent to Caddy keyed by the admin API path, it is only set when the
+	// request asks to return the config
+	Config string `protobuf:"bytes,4,opt,name=config,proto3" json:"config,omitempty"`
 }
 
 func (x *ApplyResponse) Reset() {
@@ -301,6 +360,85 @@ func (x *ApplyResponse) GetMessage() string {
 	return ""
 }
 
+func (x *ApplyResponse) GetSites() []*SiteStatus {
+	if x != nil {
+		return x.Sites
+	}
+	return nil
+}
+
+func (x *ApplyResponse) GetConfig() string {
+	if x != nil {
+		return x.Config
+	}
+	return ""
+}
+
+type SiteStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hostname string `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	// status is created, updated, unchanged, or error
+	Status string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// message explains the reason for an error
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *SiteStatus) Reset() {
+	*x = SiteStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protob_nitrod_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SiteStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SiteStatus) ProtoMessage() {}
+
+func (x *SiteStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_protob_nitrod_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SiteStatus.ProtoReflect.Descriptor instead.
+func (*SiteStatus) Descriptor() ([]byte, []int) {
+	return file_protob_nitrod_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SiteStatus) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *SiteStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SiteStatus) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
 type Site struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -309,12 +447,32 @@ type Site struct {
 	Hostname string `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
 	Aliases  string `protobuf:"bytes,2,opt,name=aliases,proto3" json:"aliases,omitempty"`
 	Port     int32  `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	// httpsRedirect will redirect plain HTTP requests for the site to HTTPS
+	HttpsRedirect bool `protobuf:"varint,4,opt,name=httpsRedirect,proto3" json:"httpsRedirect,omitempty"`
+	// paths are routed to an alternate upstream before the site
+	Paths []*SitePath `protobuf:"bytes,5,rep,name=paths,proto3" json:"paths,omitempty"`
+	// maintenance responds to all requests for the site with a 503 and the maintenance page
+	Maintenance bool `protobuf:"varint,6,opt,name=maintenance,proto3" json:"maintenance,omitempty"`
+	// maintenanceBody is the HTML for the maintenance page, a default page is used when empty
+	MaintenanceBody string `protobuf:"bytes,7,opt,name=maintenanceBody,proto3" json:"maintenanceBody,omitempty"`
+	// errorPages handle the errors for the site by status code
+	ErrorPages []*SiteErrorPage `protobuf:"bytes,8,rep,name=errorPages,proto3" json:"errorPages,omitempty"`
+	// clientCa is the PEM encoded CA used to require and verify client certificates, mTLS is off when empty
+	ClientCa string `protobuf:"bytes,9,opt,name=clientCa,proto3" json:"clientCa,omitempty"`
+	// headers are set on the responses for the site
+	Headers map[string]string `protobuf:"bytes,10,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// removeHeaders are removed from the responses for the site
+	RemoveHeaders []string `protobuf:"bytes,11,rep,name=removeHeaders,proto3" json:"removeHeaders,omitempty"`
+	// earlyHints are the Link header values sent in a 103 Early Hints response before the site responds
+	EarlyHints []string `protobuf:"bytes,12,rep,name=earlyHints,proto3" json:"earlyHints,omitempty"`
+	// maxRequestBody is the largest request body in bytes the proxy accepts for the site, the body is not limited when 0
+	MaxRequestBody int64 `protobuf:"varint,13,opt,name=maxRequestBody,proto3" json:"maxRequestBody,omitempty"`
 }
 
 func (x *Site) Reset() {
 	*x = Site{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protob_nitrod_proto_msgTypes[6]
+		mi := &file_protob_nitrod_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -327,7 +485,7 @@ func (x *Site) String() string {
 func (*Site) ProtoMessage() {}
 
 func (x *Site) ProtoReflect() protoreflect.Message {
-	mi := &file_protob_nitrod_proto_msgTypes[6]
+	mi := &file_protob_nitrod_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -340,7 +498,7 @@ func (x *Site) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Site.ProtoReflect.Descriptor instead.
 func (*Site) Descriptor() ([]byte, []int) {
-	return file_protob_nitrod_proto_rawDescGZIP(), []int{6}
+	return file_protob_nitrod_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *Site) GetHostname() string {
@@ -364,6 +522,197 @@ func (x *Site) GetPort() int32 {
 	return 0
 }
 
+func (x *Site) GetHttpsRedirect() bool {
+	if x != nil {
+		return x.HttpsRedirect
+	}
+	return false
+}
+
+func (x *Site) GetPaths() []*SitePath {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+func (x *Site) GetMaintenance() bool {
+	if x != nil {
+		return x.Maintenance
+	}
+	return false
+}
+
+func (x *Site) GetMaintenanceBody() string {
+	if x != nil {
+		return x.MaintenanceBody
+	}
+	return ""
+}
+
+func (x *Site) GetErrorPages() []*SiteErrorPage {
+	if x != nil {
+		return x.ErrorPages
+	}
+	return nil
+}
+
+func (x *Site) GetClientCa() string {
+	if x != nil {
+		return x.ClientCa
+	}
+	return ""
+}
+
+func (x *Site) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *Site) GetRemoveHeaders() []string {
+	if x != nil {
+		return x.RemoveHeaders
+	}
+	return nil
+}
+
+func (x *Site) GetEarlyHints() []string {
+	if x != nil {
+		return x.EarlyHints
+	}
+	return nil
+}
+
+func (x *Site) GetMaxRequestBody() int64 {
+	if x != nil {
+		return x.MaxRequestBody
+	}
+	return 0
+}
+
+type SitePath struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Prefix   string `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Upstream string `protobuf:"bytes,2,opt,name=upstream,proto3" json:"upstream,omitempty"`
+}
+
+func (x *SitePath) Reset() {
+	*x = SitePath{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protob_nitrod_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SitePath) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SitePath) ProtoMessage() {}
+
+func (x *SitePath) ProtoReflect() protoreflect.Message {
+	mi := &file_protob_nitrod_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SitePath.ProtoReflect.Descriptor instead.
+func (*SitePath) Descriptor() ([]byte, []int) {
+	return file_protob_nitrod_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SitePath) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *SitePath) GetUpstream() string {
+	if x != nil {
+		return x.Upstream
+	}
+	return ""
+}
+
+type SiteErrorPage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// status is the 4xx or 5xx status code of the error
+	Status int32 `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	// uri is the path on the site used to handle the error, such as an error route in the app
+	Uri string `protobuf:"bytes,2,opt,name=uri,proto3" json:"uri,omitempty"`
+	// body is the HTML sent with the status, it is used instead of the uri when set
+	Body string `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+}
+
+func (x *SiteErrorPage) Reset() {
+	*x = SiteErrorPage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protob_nitrod_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SiteErrorPage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SiteErrorPage) ProtoMessage() {}
+
+func (x *SiteErrorPage) ProtoReflect() protoreflect.Message {
+	mi := &file_protob_nitrod_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SiteErrorPage.ProtoReflect.Descriptor instead.
+func (*SiteErrorPage) Descriptor() ([]byte, []int) {
+	return file_protob_nitrod_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SiteErrorPage) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *SiteErrorPage) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+func (x *SiteErrorPage) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
 type DatabaseInfo struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -383,12 +732,40 @@ type DatabaseInfo struct {
 	Compressed bool `protobuf:"varint,6,opt,name=compressed,proto3" json:"compressed,omitempty"`
 	// the kind of compression type, e.g. zip or tar
 	CompressionType string `protobuf:"bytes,7,opt,name=compressionType,proto3" json:"compressionType,omitempty"`
+	// validate imports the backup into a temporary database to check for errors (only used during importing)
+	Validate bool `protobuf:"varint,8,opt,name=validate,proto3" json:"validate,omitempty"`
+	// analyze updates the table statistics after importing (only used during importing)
+	Analyze bool `protobuf:"varint,9,opt,name=analyze,proto3" json:"analyze,omitempty"`
+	// keepUpload keeps the uploaded backup in the proxy container for debugging (only used during importing)
+	KeepUpload bool `protobuf:"varint,10,opt,name=keepUpload,proto3" json:"keepUpload,omitempty"`
+	// jobs is the number of parallel jobs used to restore postgres archives (only used during importing)
+	Jobs int32 `protobuf:"varint,11,opt,name=jobs,proto3" json:"jobs,omitempty"`
+	// tmpDir is the directory in the proxy container used to stage the upload (only used during importing)
+	TmpDir string `protobuf:"bytes,12,opt,name=tmpDir,proto3" json:"tmpDir,omitempty"`
+	// size is the size of the upload in bytes, used to check for free space (only used during importing)
+	Size int64 `protobuf:"varint,13,opt,name=size,proto3" json:"size,omitempty"`
+	// onError is stop to end the import at the first error or continue to skip the errors (only used during importing)
+	OnError string `protobuf:"bytes,14,opt,name=onError,proto3" json:"onError,omitempty"`
+	// fromPrefix is the table prefix to replace with toPrefix in a plain sql backup (only used during importing)
+	FromPrefix string `protobuf:"bytes,15,opt,name=fromPrefix,proto3" json:"fromPrefix,omitempty"`
+	// toPrefix is the table prefix to use instead of fromPrefix, it can be empty to remove the prefix (only used during importing)
+	ToPrefix string `protobuf:"bytes,16,opt,name=toPrefix,proto3" json:"toPrefix,omitempty"`
+	// renameFrom is the database in a plain sql backup to rename to renameTo, e.g. in USE or \connect statements (only used during importing)
+	RenameFrom string `protobuf:"bytes,17,opt,name=renameFrom,proto3" json:"renameFrom,omitempty"`
+	// renameTo is the database to use instead of renameFrom, it defaults to the database being imported into (only used during importing)
+	RenameTo string `protobuf:"bytes,18,opt,name=renameTo,proto3" json:"renameTo,omitempty"`
+	// user is the user to connect to the database with when importing, or the user to create and grant access to the new database when adding, it defaults to nitro
+	User string `protobuf:"bytes,19,opt,name=user,proto3" json:"user,omitempty"`
+	// password is the password for the user, it defaults to nitro when importing and is required to create a user when adding
+	Password string `protobuf:"bytes,20,opt,name=password,proto3" json:"password,omitempty"`
+	// excludeTables are the patterns of the tables to remove from a plain sql backup, e.g. *_sessions (only used during importing)
+	ExcludeTables []string `protobuf:"bytes,21,rep,name=excludeTables,proto3" json:"excludeTables,omitempty"`
 }
 
 func (x *DatabaseInfo) Reset() {
 	*x = DatabaseInfo{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protob_nitrod_proto_msgTypes[7]
+		mi := &file_protob_nitrod_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -401,7 +778,7 @@ func (x *DatabaseInfo) String() string {
 func (*DatabaseInfo) ProtoMessage() {}
 
 func (x *DatabaseInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_protob_nitrod_proto_msgTypes[7]
+	mi := &file_protob_nitrod_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -414,7 +791,7 @@ func (x *DatabaseInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DatabaseInfo.ProtoReflect.Descriptor instead.
 func (*DatabaseInfo) Descriptor() ([]byte, []int) {
-	return file_protob_nitrod_proto_rawDescGZIP(), []int{7}
+	return file_protob_nitrod_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *DatabaseInfo) GetEngine() string {
@@ -466,6 +843,104 @@ func (x *DatabaseInfo) GetCompressionType() string {
 	return ""
 }
 
+func (x *DatabaseInfo) GetValidate() bool {
+	if x != nil {
+		return x.Validate
+	}
+	return false
+}
+
+func (x *DatabaseInfo) GetAnalyze() bool {
+	if x != nil {
+		return x.Analyze
+	}
+	return false
+}
+
+func (x *DatabaseInfo) GetKeepUpload() bool {
+	if x != nil {
+		return x.KeepUpload
+	}
+	return false
+}
+
+func (x *DatabaseInfo) GetJobs() int32 {
+	if x != nil {
+		return x.Jobs
+	}
+	return 0
+}
+
+func (x *DatabaseInfo) GetTmpDir() string {
+	if x != nil {
+		return x.TmpDir
+	}
+	return ""
+}
+
+func (x *DatabaseInfo) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DatabaseInfo) GetOnError() string {
+	if x != nil {
+		return x.OnError
+	}
+	return ""
+}
+
+func (x *DatabaseInfo) GetFromPrefix() string {
+	if x != nil {
+		return x.FromPrefix
+	}
+	return ""
+}
+
+func (x *DatabaseInfo) GetToPrefix() string {
+	if x != nil {
+		return x.ToPrefix
+	}
+	return ""
+}
+
+func (x *DatabaseInfo) GetRenameFrom() string {
+	if x != nil {
+		return x.RenameFrom
+	}
+	return ""
+}
+
+func (x *DatabaseInfo) GetRenameTo() string {
+	if x != nil {
+		return x.RenameTo
+	}
+	return ""
+}
+
+func (x *DatabaseInfo) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *DatabaseInfo) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *DatabaseInfo) GetExcludeTables() []string {
+	if x != nil {
+		return x.ExcludeTables
+	}
+	return nil
+}
+
 type AddDatabaseRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -477,7 +952,7 @@ type AddDatabaseRequest struct {
 func (x *AddDatabaseRequest) Reset() {
 	*x = AddDatabaseRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protob_nitrod_proto_msgTypes[8]
+		mi := &file_protob_nitrod_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -490,7 +965,7 @@ func (x *AddDatabaseRequest) String() string {
 func (*AddDatabaseRequest) ProtoMessage() {}
 
 func (x *AddDatabaseRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protob_nitrod_proto_msgTypes[8]
+	mi := &file_protob_nitrod_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -503,7 +978,7 @@ func (x *AddDatabaseRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddDatabaseRequest.ProtoReflect.Descriptor instead.
 func (*AddDatabaseRequest) Descriptor() ([]byte, []int) {
-	return file_protob_nitrod_proto_rawDescGZIP(), []int{8}
+	return file_protob_nitrod_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *AddDatabaseRequest) GetDatabase() *DatabaseInfo {
@@ -524,7 +999,7 @@ type AddDatabaseResponse struct {
 func (x *AddDatabaseResponse) Reset() {
 	*x = AddDatabaseResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protob_nitrod_proto_msgTypes[9]
+		mi := &file_protob_nitrod_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -537,7 +1012,7 @@ func (x *AddDatabaseResponse) String() string {
 func (*AddDatabaseResponse) ProtoMessage() {}
 
 func (x *AddDatabaseResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protob_nitrod_proto_msgTypes[9]
+	mi := &file_protob_nitrod_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -550,7 +1025,7 @@ func (x *AddDatabaseResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddDatabaseResponse.ProtoReflect.Descriptor instead.
 func (*AddDatabaseResponse) Descriptor() ([]byte, []int) {
-	return file_protob_nitrod_proto_rawDescGZIP(), []int{9}
+	return file_protob_nitrod_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *AddDatabaseResponse) GetMessage() string {
@@ -574,7 +1049,7 @@ type ImportDatabaseRequest struct {
 func (x *ImportDatabaseRequest) Reset() {
 	*x = ImportDatabaseRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protob_nitrod_proto_msgTypes[10]
+		mi := &file_protob_nitrod_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -587,7 +1062,7 @@ func (x *ImportDatabaseRequest) String() string {
 func (*ImportDatabaseRequest) ProtoMessage() {}
 
 func (x *ImportDatabaseRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protob_nitrod_proto_msgTypes[10]
+	mi := &file_protob_nitrod_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -600,7 +1075,7 @@ func (x *ImportDatabaseRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ImportDatabaseRequest.ProtoReflect.Descriptor instead.
 func (*ImportDatabaseRequest) Descriptor() ([]byte, []int) {
-	return file_protob_nitrod_proto_rawDescGZIP(), []int{10}
+	return file_protob_nitrod_proto_rawDescGZIP(), []int{13}
 }
 
 func (m *ImportDatabaseRequest) GetPayload() isImportDatabaseRequest_Payload {
@@ -614,34 +1089,276 @@ func (x *ImportDatabaseRequest) GetDatabase() *DatabaseInfo {
 	if x, ok := x.GetPayload().(*ImportDatabaseRequest_Database); ok {
 		return x.Database
 	}
-	return nil
+	return nil
+}
+
+func (x *ImportDatabaseRequest) GetData() []byte {
+	if x, ok := x.GetPayload().(*ImportDatabaseRequest_Data); ok {
+		return x.Data
+	}
+	return nil
+}
+
+type isImportDatabaseRequest_Payload interface {
+	isImportDatabaseRequest_Payload()
+}
+
+type ImportDatabaseRequest_Database struct {
+	Database *DatabaseInfo `protobuf:"bytes,1,opt,name=database,proto3,oneof"`
+}
+
+type ImportDatabaseRequest_Data struct {
+	// data is the data of the file, used in stream to reduce memory usage.
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3,oneof"`
+}
+
+func (*ImportDatabaseRequest_Database) isImportDatabaseRequest_Payload() {}
+
+func (*ImportDatabaseRequest_Data) isImportDatabaseRequest_Payload() {}
+
+type ImportDatabaseResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// output is the output of the import tool, including the warnings
+	Output string `protobuf:"bytes,2,opt,name=output,proto3" json:"output,omitempty"`
+}
+
+func (x *ImportDatabaseResponse) Reset() {
+	*x = ImportDatabaseResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protob_nitrod_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportDatabaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportDatabaseResponse) ProtoMessage() {}
+
+func (x *ImportDatabaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protob_nitrod_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportDatabaseResponse.ProtoReflect.Descriptor instead.
+func (*ImportDatabaseResponse) Descriptor() ([]byte, []int) {
+	return file_protob_nitrod_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ImportDatabaseResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ImportDatabaseResponse) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+type ExportDatabaseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// database is the database to export, the backup is compressed with gzip when compressed is true
+	Database *DatabaseInfo `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	// format is the postgres archive format to export (custom), a plain sql backup is exported when empty
+	Format string `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+}
+
+func (x *ExportDatabaseRequest) Reset() {
+	*x = ExportDatabaseRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protob_nitrod_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportDatabaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportDatabaseRequest) ProtoMessage() {}
+
+func (x *ExportDatabaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protob_nitrod_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportDatabaseRequest.ProtoReflect.Descriptor instead.
+func (*ExportDatabaseRequest) Descriptor() ([]byte, []int) {
+	return file_protob_nitrod_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ExportDatabaseRequest) GetDatabase() *DatabaseInfo {
+	if x != nil {
+		return x.Database
+	}
+	return nil
+}
+
+func (x *ExportDatabaseRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+type ExportDatabaseResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Payload:
+	//	*ExportDatabaseResponse_Database
+	//	*ExportDatabaseResponse_Data
+	Payload isExportDatabaseResponse_Payload `protobuf_oneof:"payload"`
+}
+
+func (x *ExportDatabaseResponse) Reset() {
+	*x = ExportDatabaseResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protob_nitrod_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportDatabaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportDatabaseResponse) ProtoMessage() {}
+
+func (x *ExportDatabaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protob_nitrod_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportDatabaseResponse.ProtoReflect.Descriptor instead.
+func (*ExportDatabaseResponse) Descriptor() ([]byte, []int) {
+	return file_protob_nitrod_proto_rawDescGZIP(), []int{16}
+}
+
+func (m *ExportDatabaseResponse) GetPayload() isExportDatabaseResponse_Payload {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (x *ExportDatabaseResponse) GetDatabase() *DatabaseInfo {
+	if x, ok := x.GetPayload().(*ExportDatabaseResponse_Database); ok {
+		return x.Database
+	}
+	return nil
+}
+
+func (x *ExportDatabaseResponse) GetData() []byte {
+	if x, ok := x.GetPayload().(*ExportDatabaseResponse_Data); ok {
+		return x.Data
+	}
+	return nil
+}
+
+type isExportDatabaseResponse_Payload interface {
+	isExportDatabaseResponse_Payload()
+}
+
+type ExportDatabaseResponse_Database struct {
+	// database is sent in the first message and describes the backup
+	Database *DatabaseInfo `protobuf:"bytes,1,opt,name=database,proto3,oneof"`
+}
+
+type ExportDatabaseResponse_Data struct {
+	// data is the data of the backup, used in stream to reduce memory usage.
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3,oneof"`
+}
+
+func (*ExportDatabaseResponse_Database) isExportDatabaseResponse_Payload() {}
+
+func (*ExportDatabaseResponse_Data) isExportDatabaseResponse_Payload() {}
+
+type RemoveDatabaseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Database *DatabaseInfo `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+}
+
+func (x *RemoveDatabaseRequest) Reset() {
+	*x = RemoveDatabaseRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protob_nitrod_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (x *ImportDatabaseRequest) GetData() []byte {
-	if x, ok := x.GetPayload().(*ImportDatabaseRequest_Data); ok {
-		return x.Data
-	}
-	return nil
+func (x *RemoveDatabaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-type isImportDatabaseRequest_Payload interface {
-	isImportDatabaseRequest_Payload()
-}
+func (*RemoveDatabaseRequest) ProtoMessage() {}
 
-type ImportDatabaseRequest_Database struct {
-	Database *DatabaseInfo `protobuf:"bytes,1,opt,name=database,proto3,oneof"`
+func (x *RemoveDatabaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protob_nitrod_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-type ImportDatabaseRequest_Data struct {
-	// data is the data of the file, used in stream to reduce memory usage.
-	Data []byte `protobuf:"bytes,2,opt,name=data,proto3,oneof"`
+// Deprecated: Use RemoveDatabaseRequest.ProtoReflect.Descriptor instead.
+func (*RemoveDatabaseRequest) Descriptor() ([]byte, []int) {
+	return file_protob_nitrod_proto_rawDescGZIP(), []int{17}
 }
 
-func (*ImportDatabaseRequest_Database) isImportDatabaseRequest_Payload() {}
-
-func (*ImportDatabaseRequest_Data) isImportDatabaseRequest_Payload() {}
+func (x *RemoveDatabaseRequest) GetDatabase() *DatabaseInfo {
+	if x != nil {
+		return x.Database
+	}
+	return nil
+}
 
-type ImportDatabaseResponse struct {
+type RemoveDatabaseResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -649,23 +1366,23 @@ type ImportDatabaseResponse struct {
 	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
 }
 
-func (x *ImportDatabaseResponse) Reset() {
-	*x = ImportDatabaseResponse{}
+func (x *RemoveDatabaseResponse) Reset() {
+	*x = RemoveDatabaseResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protob_nitrod_proto_msgTypes[11]
+		mi := &file_protob_nitrod_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ImportDatabaseResponse) String() string {
+func (x *RemoveDatabaseResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ImportDatabaseResponse) ProtoMessage() {}
+func (*RemoveDatabaseResponse) ProtoMessage() {}
 
-func (x *ImportDatabaseResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protob_nitrod_proto_msgTypes[11]
+func (x *RemoveDatabaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protob_nitrod_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -676,43 +1393,50 @@ func (x *ImportDatabaseResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ImportDatabaseResponse.ProtoReflect.Descriptor instead.
-func (*ImportDatabaseResponse) Descriptor() ([]byte, []int) {
-	return file_protob_nitrod_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use RemoveDatabaseResponse.ProtoReflect.Descriptor instead.
+func (*RemoveDatabaseResponse) Descriptor() ([]byte, []int) {
+	return file_protob_nitrod_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *ImportDatabaseResponse) GetMessage() string {
+func (x *RemoveDatabaseResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-type RemoveDatabaseRequest struct {
+type ProxyAPIRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Database *DatabaseInfo `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	// method is the HTTP method to use (e.g. GET or POST)
+	Method string `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	// path is the Caddy admin API path (e.g. /config/apps/http)
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// body is the optional request body sent to the Caddy admin API
+	Body []byte `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	// write allows the methods that change the proxy config (e.g. POST or DELETE)
+	Write bool `protobuf:"varint,4,opt,name=write,proto3" json:"write,omitempty"`
 }
 
-func (x *RemoveDatabaseRequest) Reset() {
-	*x = RemoveDatabaseRequest{}
+func (x *ProxyAPIRequest) Reset() {
+	*x = ProxyAPIRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protob_nitrod_proto_msgTypes[12]
+		mi := &file_protob_nitrod_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RemoveDatabaseRequest) String() string {
+func (x *ProxyAPIRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemoveDatabaseRequest) ProtoMessage() {}
+func (*ProxyAPIRequest) ProtoMessage() {}
 
-func (x *RemoveDatabaseRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protob_nitrod_proto_msgTypes[12]
+func (x *ProxyAPIRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protob_nitrod_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -723,43 +1447,65 @@ func (x *RemoveDatabaseRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemoveDatabaseRequest.ProtoReflect.Descriptor instead.
-func (*RemoveDatabaseRequest) Descriptor() ([]byte, []int) {
-	return file_protob_nitrod_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use ProxyAPIRequest.ProtoReflect.Descriptor instead.
+func (*ProxyAPIRequest) Descriptor() ([]byte, []int) {
+	return file_protob_nitrod_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *RemoveDatabaseRequest) GetDatabase() *DatabaseInfo {
+func (x *ProxyAPIRequest) GetMethod() string {
 	if x != nil {
-		return x.Database
+		return x.Method
+	}
+	return ""
+}
+
+func (x *ProxyAPIRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ProxyAPIRequest) GetBody() []byte {
+	if x != nil {
+		return x.Body
 	}
 	return nil
 }
 
-type RemoveDatabaseResponse struct {
+func (x *ProxyAPIRequest) GetWrite() bool {
+	if x != nil {
+		return x.Write
+	}
+	return false
+}
+
+type ProxyAPIResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	StatusCode int32  `protobuf:"varint,1,opt,name=statusCode,proto3" json:"statusCode,omitempty"`
+	Body       []byte `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
 }
 
-func (x *RemoveDatabaseResponse) Reset() {
-	*x = RemoveDatabaseResponse{}
+func (x *ProxyAPIResponse) Reset() {
+	*x = ProxyAPIResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protob_nitrod_proto_msgTypes[13]
+		mi := &file_protob_nitrod_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RemoveDatabaseResponse) String() string {
+func (x *ProxyAPIResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemoveDatabaseResponse) ProtoMessage() {}
+func (*ProxyAPIResponse) ProtoMessage() {}
 
-func (x *RemoveDatabaseResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protob_nitrod_proto_msgTypes[13]
+func (x *ProxyAPIResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protob_nitrod_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -770,16 +1516,23 @@ func (x *RemoveDatabaseResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemoveDatabaseResponse.ProtoReflect.Descriptor instead.
-func (*RemoveDatabaseResponse) Descriptor() ([]byte, []int) {
-	return file_protob_nitrod_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use ProxyAPIResponse.ProtoReflect.Descriptor instead.
+func (*ProxyAPIResponse) Descriptor() ([]byte, []int) {
+	return file_protob_nitrod_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *RemoveDatabaseResponse) GetMessage() string {
+func (x *ProxyAPIResponse) GetStatusCode() int32 {
 	if x != nil {
-		return x.Message
+		return x.StatusCode
 	}
-	return ""
+	return 0
+}
+
+func (x *ProxyAPIResponse) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
 }
 
 var File_protob_nitrod_proto protoreflect.FileDescriptor
@@ -794,91 +1547,210 @@ var file_protob_nitrod_proto_rawDesc = []byte{
 	0x73, 0x74, 0x22, 0x2b, 0x0a, 0x0f, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
 	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22,
-	0x8d, 0x01, 0x0a, 0x0c, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0xcd, 0x02, 0x0a, 0x0c, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
 	0x12, 0x35, 0x0a, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
 	0x1f, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x52, 0x65,
 	0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x53, 0x69, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
-	0x52, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x1a, 0x46, 0x0a, 0x0a, 0x53, 0x69, 0x74, 0x65, 0x73,
+	0x52, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x63, 0x63, 0x65, 0x73,
+	0x73, 0x4c, 0x6f, 0x67, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x61, 0x63, 0x63,
+	0x65, 0x73, 0x73, 0x4c, 0x6f, 0x67, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x6f, 0x6e, 0x44, 0x65, 0x6d,
+	0x61, 0x6e, 0x64, 0x54, 0x4c, 0x53, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x6f, 0x6e,
+	0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x54, 0x4c, 0x53, 0x12, 0x14, 0x0a, 0x05, 0x68, 0x74, 0x74,
+	0x70, 0x33, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x68, 0x74, 0x74, 0x70, 0x33, 0x12,
+	0x22, 0x0a, 0x0c, 0x6e, 0x6f, 0x74, 0x46, 0x6f, 0x75, 0x6e, 0x64, 0x42, 0x6f, 0x64, 0x79, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6e, 0x6f, 0x74, 0x46, 0x6f, 0x75, 0x6e, 0x64, 0x42,
+	0x6f, 0x64, 0x79, 0x12, 0x22, 0x0a, 0x0c, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x72, 0x65, 0x74, 0x75, 0x72,
+	0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x78, 0x79,
+	0x50, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x05, 0x52, 0x0a, 0x70, 0x72, 0x6f,
+	0x78, 0x79, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x1a, 0x46, 0x0a, 0x0a, 0x53, 0x69, 0x74, 0x65, 0x73,
 	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x22, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
 	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e,
 	0x53, 0x69, 0x74, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
-	0x3f, 0x0a, 0x0d, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x22, 0x50, 0x0a, 0x04, 0x53, 0x69, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74,
+	0x81, 0x01, 0x0a, 0x0d, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x28, 0x0a, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x53, 0x69, 0x74, 0x65, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x22, 0x5a, 0x0a, 0x0a, 0x53, 0x69, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22,
+	0x9c, 0x04, 0x0a, 0x04, 0x53, 0x69, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74,
 	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74,
 	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x18,
 	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x12, 0x12,
 	0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x6f,
-	0x72, 0x74, 0x22, 0xd6, 0x01, 0x0a, 0x0c, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x49,
-	0x6e, 0x66, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x06, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76,
-	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65,
-	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73,
-	0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73,
-	0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73,
-	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73,
-	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x18,
-	0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65,
-	0x64, 0x12, 0x28, 0x0a, 0x0f, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
-	0x54, 0x79, 0x70, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x6f, 0x6d, 0x70,
-	0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x22, 0x46, 0x0a, 0x12, 0x41,
-	0x64, 0x64, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x30, 0x0a, 0x08, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x44, 0x61, 0x74,
-	0x61, 0x62, 0x61, 0x73, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x62,
-	0x61, 0x73, 0x65, 0x22, 0x2f, 0x0a, 0x13, 0x41, 0x64, 0x64, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61,
-	0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x22, 0x6c, 0x0a, 0x15, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x44, 0x61,
-	0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x32, 0x0a,
-	0x08, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x14, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73,
-	0x65, 0x49, 0x6e, 0x66, 0x6f, 0x48, 0x00, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73,
-	0x65, 0x12, 0x14, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48,
-	0x00, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x42, 0x09, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f,
-	0x61, 0x64, 0x22, 0x32, 0x0a, 0x16, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x44, 0x61, 0x74, 0x61,
-	0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07,
-	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x49, 0x0a, 0x15, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65,
-	0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x30, 0x0a, 0x08, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x14, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x62,
-	0x61, 0x73, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73,
-	0x65, 0x22, 0x32, 0x0a, 0x16, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62,
-	0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x32, 0xa4, 0x03, 0x0a, 0x05, 0x4e, 0x69, 0x74, 0x72, 0x6f, 0x12,
-	0x33, 0x0a, 0x04, 0x50, 0x69, 0x6e, 0x67, 0x12, 0x13, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64,
-	0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x6e,
-	0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x00, 0x12, 0x36, 0x0a, 0x05, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x12, 0x14, 0x2e,
-	0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x41, 0x70, 0x70,
-	0x6c, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x07,
-	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64,
-	0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x17, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x48, 0x0a, 0x0b, 0x41, 0x64,
-	0x64, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x12, 0x1a, 0x2e, 0x6e, 0x69, 0x74, 0x72,
-	0x6f, 0x64, 0x2e, 0x41, 0x64, 0x64, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x41,
-	0x64, 0x64, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x00, 0x12, 0x53, 0x0a, 0x0e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x44, 0x61,
-	0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x12, 0x1d, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e,
-	0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x49,
+	0x72, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x68, 0x74, 0x74, 0x70, 0x73, 0x52, 0x65, 0x64, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x68, 0x74, 0x74, 0x70, 0x73,
+	0x52, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x12, 0x26, 0x0a, 0x05, 0x70, 0x61, 0x74, 0x68,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64,
+	0x2e, 0x53, 0x69, 0x74, 0x65, 0x50, 0x61, 0x74, 0x68, 0x52, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73,
+	0x12, 0x20, 0x0a, 0x0b, 0x6d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x6d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e,
+	0x63, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x6d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63,
+	0x65, 0x42, 0x6f, 0x64, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x6d, 0x61, 0x69,
+	0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x42, 0x6f, 0x64, 0x79, 0x12, 0x35, 0x0a, 0x0a,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x50, 0x61, 0x67, 0x65, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x15, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x53, 0x69, 0x74, 0x65, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x50, 0x61, 0x67, 0x65, 0x52, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x50, 0x61,
+	0x67, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x61, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x61, 0x12,
+	0x33, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x53, 0x69, 0x74, 0x65, 0x2e, 0x48,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x68, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x48, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x61,
+	0x72, 0x6c, 0x79, 0x48, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a,
+	0x65, 0x61, 0x72, 0x6c, 0x79, 0x48, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x6d, 0x61,
+	0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x42, 0x6f, 0x64, 0x79, 0x18, 0x0d, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0e, 0x6d, 0x61, 0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x42, 0x6f,
+	0x64, 0x79, 0x1a, 0x3a, 0x0a, 0x0c, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x3e,
+	0x0a, 0x08, 0x53, 0x69, 0x74, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72,
+	0x65, 0x66, 0x69, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x72, 0x65, 0x66,
+	0x69, 0x78, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x22, 0x4d,
+	0x0a, 0x0d, 0x53, 0x69, 0x74, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x50, 0x61, 0x67, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x69, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x69, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64,
+	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x22, 0xd4, 0x04,
+	0x0a, 0x0c, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x16,
+	0x0a, 0x06, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x70, 0x6f, 0x72, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x1a, 0x0a, 0x08, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a,
+	0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x12, 0x28, 0x0a, 0x0f,
+	0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61,
+	0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61,
+	0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x7a, 0x65, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x7a, 0x65, 0x12, 0x1e, 0x0a, 0x0a,
+	0x6b, 0x65, 0x65, 0x70, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0a, 0x6b, 0x65, 0x65, 0x70, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x12, 0x0a, 0x04,
+	0x6a, 0x6f, 0x62, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x6a, 0x6f, 0x62, 0x73,
+	0x12, 0x16, 0x0a, 0x06, 0x74, 0x6d, 0x70, 0x44, 0x69, 0x72, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x74, 0x6d, 0x70, 0x44, 0x69, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65,
+	0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x6f, 0x6e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f,
+	0x6e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x66, 0x72, 0x6f, 0x6d, 0x50, 0x72,
+	0x65, 0x66, 0x69, 0x78, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x72, 0x6f, 0x6d,
+	0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x6f, 0x50, 0x72, 0x65, 0x66,
+	0x69, 0x78, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x6f, 0x50, 0x72, 0x65, 0x66,
+	0x69, 0x78, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x46, 0x72, 0x6f, 0x6d,
+	0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x46, 0x72,
+	0x6f, 0x6d, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x54, 0x6f, 0x18, 0x12,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x54, 0x6f, 0x12, 0x12,
+	0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x13, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73,
+	0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x14,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x24,
+	0x0a, 0x0d, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x18,
+	0x15, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x54, 0x61,
+	0x62, 0x6c, 0x65, 0x73, 0x22, 0x46, 0x0a, 0x12, 0x41, 0x64, 0x64, 0x44, 0x61, 0x74, 0x61, 0x62,
+	0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x30, 0x0a, 0x08, 0x64, 0x61,
+	0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6e,
+	0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x22, 0x2f, 0x0a, 0x13,
+	0x41, 0x64, 0x64, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x6c, 0x0a,
+	0x15, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x08, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61,
+	0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f,
+	0x64, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x48, 0x00,
+	0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x04, 0x64, 0x61,
+	0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61,
+	0x42, 0x09, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x4a, 0x0a, 0x16, 0x49,
 	0x6d, 0x70, 0x6f, 0x72, 0x74, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x51, 0x0a, 0x0e, 0x52, 0x65, 0x6d,
-	0x6f, 0x76, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x12, 0x1d, 0x2e, 0x6e, 0x69,
-	0x74, 0x72, 0x6f, 0x64, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62,
-	0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x6e, 0x69, 0x74,
-	0x72, 0x6f, 0x64, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61,
-	0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x09, 0x5a, 0x07,
-	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x61, 0x0a, 0x15, 0x45, 0x78, 0x70, 0x6f, 0x72,
+	0x74, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x30, 0x0a, 0x08, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x44, 0x61, 0x74, 0x61,
+	0x62, 0x61, 0x73, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61,
+	0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x22, 0x6d, 0x0a, 0x16, 0x45, 0x78,
+	0x70, 0x6f, 0x72, 0x74, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x08, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e,
+	0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x48, 0x00, 0x52, 0x08,
+	0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x42, 0x09,
+	0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x49, 0x0a, 0x15, 0x52, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x30, 0x0a, 0x08, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x44, 0x61,
+	0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61,
+	0x62, 0x61, 0x73, 0x65, 0x22, 0x32, 0x0a, 0x16, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x44, 0x61,
+	0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x67, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x78,
+	0x79, 0x41, 0x50, 0x49, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6d,
+	0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x74,
+	0x68, 0x6f, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x77,
+	0x72, 0x69, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x77, 0x72, 0x69, 0x74,
+	0x65, 0x22, 0x46, 0x0a, 0x10, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x41, 0x50, 0x49, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43,
+	0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x32, 0xba, 0x04, 0x0a, 0x05, 0x4e, 0x69,
+	0x74, 0x72, 0x6f, 0x12, 0x33, 0x0a, 0x04, 0x50, 0x69, 0x6e, 0x67, 0x12, 0x13, 0x2e, 0x6e, 0x69,
+	0x74, 0x72, 0x6f, 0x64, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x14, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x36, 0x0a, 0x05, 0x41, 0x70, 0x70, 0x6c,
+	0x79, 0x12, 0x14, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x41, 0x70, 0x70, 0x6c, 0x79,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64,
+	0x2e, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x3c, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x2e, 0x6e, 0x69,
+	0x74, 0x72, 0x6f, 0x64, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x48,
+	0x0a, 0x0b, 0x41, 0x64, 0x64, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x12, 0x1a, 0x2e,
+	0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x41, 0x64, 0x64, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61,
+	0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x6e, 0x69, 0x74, 0x72,
+	0x6f, 0x64, 0x2e, 0x41, 0x64, 0x64, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x53, 0x0a, 0x0e, 0x49, 0x6d, 0x70, 0x6f,
+	0x72, 0x74, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x12, 0x1d, 0x2e, 0x6e, 0x69, 0x74,
+	0x72, 0x6f, 0x64, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61,
+	0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x6e, 0x69, 0x74, 0x72,
+	0x6f, 0x64, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x53, 0x0a,
+	0x0e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x12,
+	0x1d, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x44,
+	0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e,
+	0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x44, 0x61,
+	0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x30, 0x01, 0x12, 0x51, 0x0a, 0x0e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x44, 0x61, 0x74, 0x61,
+	0x62, 0x61, 0x73, 0x65, 0x12, 0x1d, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x52, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x52, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3f, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x41, 0x50,
+	0x49, 0x12, 0x17, 0x2e, 0x6e, 0x69, 0x74, 0x72, 0x6f, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x78, 0x79,
+	0x41, 0x50, 0x49, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x6e, 0x69, 0x74,
+	0x72, 0x6f, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x41, 0x50, 0x49, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x09, 0x5a, 0x07, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -893,7 +1765,7 @@ func file_protob_nitrod_proto_rawDescGZIP() []byte {
 	return file_protob_nitrod_proto_rawDescData
 }
 
-var file_protob_nitrod_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_protob_nitrod_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
 var file_protob_nitrod_proto_goTypes = []interface{}{
 	(*PingRequest)(nil),            // 0: nitrod.PingRequest
 	(*PingResponse)(nil),           // 1: nitrod.PingResponse
@@ -901,39 +1773,57 @@ var file_protob_nitrod_proto_goTypes = []interface{}{
 	(*VersionResponse)(nil),        // 3: nitrod.VersionResponse
 	(*ApplyRequest)(nil),           // 4: nitrod.ApplyRequest
 	(*ApplyResponse)(nil),          // 5: nitrod.ApplyResponse
-	(*Site)(nil),                   // 6: nitrod.Site
-	(*DatabaseInfo)(nil),           // 7: nitrod.DatabaseInfo
-	(*AddDatabaseRequest)(nil),     // 8: nitrod.AddDatabaseRequest
-	(*AddDatabaseResponse)(nil),    // 9: nitrod.AddDatabaseResponse
-	(*ImportDatabaseRequest)(nil),  // 10: nitrod.ImportDatabaseRequest
-	(*ImportDatabaseResponse)(nil), // 11: nitrod.ImportDatabaseResponse
-	(*RemoveDatabaseRequest)(nil),  // 12: nitrod.RemoveDatabaseRequest
-	(*RemoveDatabaseResponse)(nil), // 13: nitrod.RemoveDatabaseResponse
-	nil,                            // 14: nitrod.ApplyRequest.SitesEntry
+	(*SiteStatus)(nil),             // 6: nitrod.SiteStatus
+	(*Site)(nil),                   // 7: nitrod.Site
+	(*SitePath)(nil),               // 8: nitrod.SitePath
+	(*SiteErrorPage)(nil),          // 9: nitrod.SiteErrorPage
+	(*DatabaseInfo)(nil),           // 10: nitrod.DatabaseInfo
+	(*AddDatabaseRequest)(nil),     // 11: nitrod.AddDatabaseRequest
+	(*AddDatabaseResponse)(nil),    // 12: nitrod.AddDatabaseResponse
+	(*ImportDatabaseRequest)(nil),  // 13: nitrod.ImportDatabaseRequest
+	(*ImportDatabaseResponse)(nil), // 14: nitrod.ImportDatabaseResponse
+	(*ExportDatabaseRequest)(nil),  // 15: nitrod.ExportDatabaseRequest
+	(*ExportDatabaseResponse)(nil), // 16: nitrod.ExportDatabaseResponse
+	(*RemoveDatabaseRequest)(nil),  // 17: nitrod.RemoveDatabaseRequest
+	(*RemoveDatabaseResponse)(nil), // 18: nitrod.RemoveDatabaseResponse
+	(*ProxyAPIRequest)(nil),        // 19: nitrod.ProxyAPIRequest
+	(*ProxyAPIResponse)(nil),       // 20: nitrod.ProxyAPIResponse
+	nil,                            // 21: nitrod.ApplyRequest.SitesEntry
+	nil,                            // 22: nitrod.Site.HeadersEntry
 }
 var file_protob_nitrod_proto_depIdxs = []int32{
-	14, // 0: nitrod.ApplyRequest.sites:type_name -> nitrod.ApplyRequest.SitesEntry
-	7,  // 1: nitrod.AddDatabaseRequest.database:type_name -> nitrod.DatabaseInfo
-	7,  // 2: nitrod.ImportDatabaseRequest.database:type_name -> nitrod.DatabaseInfo
-	7,  // 3: nitrod.RemoveDatabaseRequest.database:type_name -> nitrod.DatabaseInfo
-	6,  // 4: nitrod.ApplyRequest.SitesEntry.value:type_name -> nitrod.Site
-	0,  // 5: nitrod.Nitro.Ping:input_type -> nitrod.PingRequest
-	4,  // 6: nitrod.Nitro.Apply:input_type -> nitrod.ApplyRequest
-	2,  // 7: nitrod.Nitro.Version:input_type -> nitrod.VersionRequest
-	8,  // 8: nitrod.Nitro.AddDatabase:input_type -> nitrod.AddDatabaseRequest
-	10, // 9: nitrod.Nitro.ImportDatabase:input_type -> nitrod.ImportDatabaseRequest
-	12, // 10: nitrod.Nitro.RemoveDatabase:input_type -> nitrod.RemoveDatabaseRequest
-	1,  // 11: nitrod.Nitro.Ping:output_type -> nitrod.PingResponse
-	5,  // 12: nitrod.Nitro.Apply:output_type -> nitrod.ApplyResponse
-	3,  // 13: nitrod.Nitro.Version:output_type -> nitrod.VersionResponse
-	9,  // 14: nitrod.Nitro.AddDatabase:output_type -> nitrod.AddDatabaseResponse
-	11, // 15: nitrod.Nitro.ImportDatabase:output_type -> nitrod.ImportDatabaseResponse
-	13, // 16: nitrod.Nitro.RemoveDatabase:output_type -> nitrod.RemoveDatabaseResponse
-	11, // [11:17] is the sub-list for method output_type
-	5,  // [5:11] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	21, // 0: nitrod.ApplyRequest.sites:type_name -> nitrod.ApplyRequest.SitesEntry
+	6,  // 1: nitrod.ApplyResponse.sites:type_name -> nitrod.SiteStatus
+	8,  // 2: nitrod.Site.paths:type_name -> nitrod.SitePath
+	9,  // 3: nitrod.Site.errorPages:type_name -> nitrod.SiteErrorPage
+	22, // 4: nitrod.Site.headers:type_name -> nitrod.Site.HeadersEntry
+	10, // 5: nitrod.AddDatabaseRequest.database:type_name -> nitrod.DatabaseInfo
+	10, // 6: nitrod.ImportDatabaseRequest.database:type_name -> nitrod.DatabaseInfo
+	10, // 7: nitrod.ExportDatabaseRequest.database:type_name -> nitrod.DatabaseInfo
+	10, // 8: nitrod.ExportDatabaseResponse.database:type_name -> nitrod.DatabaseInfo
+	10, // 9: nitrod.RemoveDatabaseRequest.database:type_name -> nitrod.DatabaseInfo
+	7,  // 10: nitrod.ApplyRequest.SitesEntry.value:type_name -> nitrod.Site
+	0,  // 11: nitrod.Nitro.Ping:input_type -> nitrod.PingRequest
+	4,  // 12: nitrod.Nitro.Apply:input_type -> nitrod.ApplyRequest
+	2,  // 13: nitrod.Nitro.Version:input_type -> nitrod.VersionRequest
+	11, // 14: nitrod.Nitro.AddDatabase:input_type -> nitrod.AddDatabaseRequest
+	13, // 15: nitrod.Nitro.ImportDatabase:input_type -> nitrod.ImportDatabaseRequest
+	15, // 16: nitrod.Nitro.ExportDatabase:input_type -> nitrod.ExportDatabaseRequest
+	17, // 17: nitrod.Nitro.RemoveDatabase:input_type -> nitrod.RemoveDatabaseRequest
+	19, // 18: nitrod.Nitro.ProxyAPI:input_type -> nitrod.ProxyAPIRequest
+	1,  // 19: nitrod.Nitro.Ping:output_type -> nitrod.PingResponse
+	5,  // 20: nitrod.Nitro.Apply:output_type -> nitrod.ApplyResponse
+	3,  // 21: nitrod.Nitro.Version:output_type -> nitrod.VersionResponse
+	12, // 22: nitrod.Nitro.AddDatabase:output_type -> nitrod.AddDatabaseResponse
+	14, // 23: nitrod.Nitro.ImportDatabase:output_type -> nitrod.ImportDatabaseResponse
+	16, // 24: nitrod.Nitro.ExportDatabase:output_type -> nitrod.ExportDatabaseResponse
+	18, // 25: nitrod.Nitro.RemoveDatabase:output_type -> nitrod.RemoveDatabaseResponse
+	20, // 26: nitrod.Nitro.ProxyAPI:output_type -> nitrod.ProxyAPIResponse
+	19, // [19:27] is the sub-list for method output_type
+	11, // [11:19] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_protob_nitrod_proto_init() }
@@ -1015,7 +1905,7 @@ func file_protob_nitrod_proto_init() {
 			}
 		}
 		file_protob_nitrod_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Site); i {
+			switch v := v.(*SiteStatus); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1027,7 +1917,7 @@ func file_protob_nitrod_proto_init() {
 			}
 		}
 		file_protob_nitrod_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DatabaseInfo); i {
+			switch v := v.(*Site); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1039,7 +1929,7 @@ func file_protob_nitrod_proto_init() {
 			}
 		}
 		file_protob_nitrod_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddDatabaseRequest); i {
+			switch v := v.(*SitePath); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1051,7 +1941,7 @@ func file_protob_nitrod_proto_init() {
 			}
 		}
 		file_protob_nitrod_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddDatabaseResponse); i {
+			switch v := v.(*SiteErrorPage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1063,7 +1953,7 @@ func file_protob_nitrod_proto_init() {
 			}
 		}
 		file_protob_nitrod_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ImportDatabaseRequest); i {
+			switch v := v.(*DatabaseInfo); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1075,7 +1965,7 @@ func file_protob_nitrod_proto_init() {
 			}
 		}
 		file_protob_nitrod_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ImportDatabaseResponse); i {
+			switch v := v.(*AddDatabaseRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1087,7 +1977,7 @@ func file_protob_nitrod_proto_init() {
 			}
 		}
 		file_protob_nitrod_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RemoveDatabaseRequest); i {
+			switch v := v.(*AddDatabaseResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1099,6 +1989,66 @@ func file_protob_nitrod_proto_init() {
 			}
 		}
 		file_protob_nitrod_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportDatabaseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protob_nitrod_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportDatabaseResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protob_nitrod_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportDatabaseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protob_nitrod_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportDatabaseResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protob_nitrod_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveDatabaseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protob_nitrod_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RemoveDatabaseResponse); i {
 			case 0:
 				return &v.state
@@ -1110,18 +2060,46 @@ func file_protob_nitrod_proto_init() {
 				return nil
 			}
 		}
+		file_protob_nitrod_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProxyAPIRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protob_nitrod_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProxyAPIResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
-	file_protob_nitrod_proto_msgTypes[10].OneofWrappers = []interface{}{
+	file_protob_nitrod_proto_msgTypes[13].OneofWrappers = []interface{}{
 		(*ImportDatabaseRequest_Database)(nil),
 		(*ImportDatabaseRequest_Data)(nil),
 	}
+	file_protob_nitrod_proto_msgTypes[16].OneofWrappers = []interface{}{
+		(*ExportDatabaseResponse_Database)(nil),
+		(*ExportDatabaseResponse_Data)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_protob_nitrod_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   15,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -1157,8 +2135,12 @@ type NitroClient interface {
 	AddDatabase(ctx context.Context, in *AddDatabaseRequest, opts ...grpc.CallOption) (*AddDatabaseResponse, error)
 	// ImportDatabase is used to stream a database backup from the client to the proxy.
 	ImportDatabase(ctx context.Context, opts ...grpc.CallOption) (Nitro_ImportDatabaseClient, error)
+	// ExportDatabase is used to stream a database backup from the proxy to the client.
+	ExportDatabase(ctx context.Context, in *ExportDatabaseRequest, opts ...grpc.CallOption) (Nitro_ExportDatabaseClient, error)
 	// RemoveDatabase handles connecting to a database and removing the database from the engine
 	RemoveDatabase(ctx context.Context, in *RemoveDatabaseRequest, opts ...grpc.CallOption) (*RemoveDatabaseResponse, error)
+	// ProxyAPI passes a request through to the Caddy admin API and returns the response
+	ProxyAPI(ctx context.Context, in *ProxyAPIRequest, opts ...grpc.CallOption) (*ProxyAPIResponse, error)
 }
 
 type nitroClient struct {
@@ -1239,6 +2221,38 @@ func (x *nitroImportDatabaseClient) CloseAndRecv() (*ImportDatabaseResponse, err
 	return m, nil
 }
 
+func (c *nitroClient) ExportDatabase(ctx context.Context, in *ExportDatabaseRequest, opts ...grpc.CallOption) (Nitro_ExportDatabaseClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Nitro_serviceDesc.Streams[1], "/nitrod.Nitro/ExportDatabase", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &nitroExportDatabaseClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Nitro_ExportDatabaseClient interface {
+	Recv() (*ExportDatabaseResponse, error)
+	grpc.ClientStream
+}
+
+type nitroExportDatabaseClient struct {
+	grpc.ClientStream
+}
+
+func (x *nitroExportDatabaseClient) Recv() (*ExportDatabaseResponse, error) {
+	m := new(ExportDatabaseResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *nitroClient) RemoveDatabase(ctx context.Context, in *RemoveDatabaseRequest, opts ...grpc.CallOption) (*RemoveDatabaseResponse, error) {
 	out := new(RemoveDatabaseResponse)
 	err := c.cc.Invoke(ctx, "/nitrod.Nitro/RemoveDatabase", in, out, opts...)
@@ -1248,6 +2262,15 @@ func (c *nitroClient) RemoveDatabase(ctx context.Context, in *RemoveDatabaseRequ
 	return out, nil
 }
 
+func (c *nitroClient) ProxyAPI(ctx context.Context, in *ProxyAPIRequest, opts ...grpc.CallOption) (*ProxyAPIResponse, error) {
+	out := new(ProxyAPIResponse)
+	err := c.cc.Invoke(ctx, "/nitrod.Nitro/ProxyAPI", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NitroServer is the server API for Nitro service.
 type NitroServer interface {
 	// Ping returns pong when the API is online
@@ -1260,8 +2283,12 @@ type NitroServer interface {
 	AddDatabase(context.Context, *AddDatabaseRequest) (*AddDatabaseResponse, error)
 	// ImportDatabase is used to stream a database backup from the client to the proxy.
 	ImportDatabase(Nitro_ImportDatabaseServer) error
+	// ExportDatabase is used to stream a database backup from the proxy to the client.
+	ExportDatabase(*ExportDatabaseRequest, Nitro_ExportDatabaseServer) error
 	// RemoveDatabase handles connecting to a database and removing the database from the engine
 	RemoveDatabase(context.Context, *RemoveDatabaseRequest) (*RemoveDatabaseResponse, error)
+	// ProxyAPI passes a request through to the Caddy admin API and returns the response
+	ProxyAPI(context.Context, *ProxyAPIRequest) (*ProxyAPIResponse, error)
 }
 
 // UnimplementedNitroServer can be embedded to have forward compatible implementations.
@@ -1283,9 +2310,15 @@ func (*UnimplementedNitroServer) AddDatabase(context.Context, *AddDatabaseReques
 func (*UnimplementedNitroServer) ImportDatabase(Nitro_ImportDatabaseServer) error {
 	return status.Errorf(codes.Unimplemented, "method ImportDatabase not implemented")
 }
+func (*UnimplementedNitroServer) ExportDatabase(*ExportDatabaseRequest, Nitro_ExportDatabaseServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportDatabase not implemented")
+}
 func (*UnimplementedNitroServer) RemoveDatabase(context.Context, *RemoveDatabaseRequest) (*RemoveDatabaseResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveDatabase not implemented")
 }
+func (*UnimplementedNitroServer) ProxyAPI(context.Context, *ProxyAPIRequest) (*ProxyAPIResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProxyAPI not implemented")
+}
 
 func RegisterNitroServer(s *grpc.Server, srv NitroServer) {
 	s.RegisterService(&_Nitro_serviceDesc, srv)
@@ -1389,6 +2422,27 @@ func (x *nitroImportDatabaseServer) Recv() (*ImportDatabaseRequest, error) {
 	return m, nil
 }
 
+func _Nitro_ExportDatabase_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportDatabaseRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NitroServer).ExportDatabase(m, &nitroExportDatabaseServer{stream})
+}
+
+type Nitro_ExportDatabaseServer interface {
+	Send(*ExportDatabaseResponse) error
+	grpc.ServerStream
+}
+
+type nitroExportDatabaseServer struct {
+	grpc.ServerStream
+}
+
+func (x *nitroExportDatabaseServer) Send(m *ExportDatabaseResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Nitro_RemoveDatabase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RemoveDatabaseRequest)
 	if err := dec(in); err != nil {
@@ -1407,6 +2461,24 @@ func _Nitro_RemoveDatabase_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Nitro_ProxyAPI_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProxyAPIRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NitroServer).ProxyAPI(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nitrod.Nitro/ProxyAPI",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NitroServer).ProxyAPI(ctx, req.(*ProxyAPIRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Nitro_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "nitrod.Nitro",
 	HandlerType: (*NitroServer)(nil),
@@ -1431,6 +2503,10 @@ var _Nitro_serviceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveDatabase",
 			Handler:    _Nitro_RemoveDatabase_Handler,
 		},
+		{
+			MethodName: "ProxyAPI",
+			Handler:    _Nitro_ProxyAPI_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1438,6 +2514,11 @@ var _Nitro_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Nitro_ImportDatabase_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "ExportDatabase",
+			Handler:       _Nitro_ExportDatabase_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "protob/nitrod.proto",
 }